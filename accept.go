@@ -0,0 +1,66 @@
+package forgeron
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// mediaRangePattern matches a media range of an Accept header, e.g. "text/html" or "*/*"
+var mediaRangePattern = regexp.MustCompile(`^(?:\*/\*|[a-z0-9!#$&^_.+-]+/(?:\*|[a-z0-9!#$&^_.+-]+))$`)
+
+// chromiumOnlyMediaTypes lists media types only advertised by Chromium browsers
+var chromiumOnlyMediaTypes = []string{"application/signed-exchange", "image/apng"}
+
+// validateAccept checks that an Accept header value is syntactically valid
+func validateAccept(accept string) error {
+	if strings.TrimSpace(accept) == "" {
+		return fmt.Errorf("accept header cannot be empty")
+	}
+	for _, item := range strings.Split(accept, ",") {
+		parts := strings.Split(strings.TrimSpace(item), ";")
+		if !mediaRangePattern.MatchString(strings.ToLower(strings.TrimSpace(parts[0]))) {
+			return fmt.Errorf("invalid media range '%s' in accept header", parts[0])
+		}
+		for _, param := range parts[1:] {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || name == "" || value == "" {
+				return fmt.Errorf("invalid parameter '%s' in accept header", param)
+			}
+			if name == "q" {
+				q, err := strconv.ParseFloat(value, 64)
+				if err != nil || q < 0 || q > 1 {
+					return fmt.Errorf("invalid quality '%s' in accept header", value)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// acceptBrowsers returns the browsers that plausibly send the Accept header value
+func acceptBrowsers(accept string, browsers []Browser) []Browser {
+	lower := strings.ToLower(accept)
+	chromiumOnly := slices.ContainsFunc(chromiumOnlyMediaTypes, func(mediaType string) bool {
+		return strings.Contains(lower, mediaType)
+	})
+	if !chromiumOnly {
+		return browsers
+	}
+	return slices.DeleteFunc(slices.Clone(browsers), func(browser Browser) bool {
+		return browser != Chrome && browser != Edge
+	})
+}
+
+// setAccept replaces the sampled Accept header, keeping its key so the header keeps its position
+func setAccept(headers map[string]string, accept string) {
+	for key := range headers {
+		if strings.EqualFold(key, "accept") {
+			headers[key] = accept
+			return
+		}
+	}
+	headers["Accept"] = accept
+}
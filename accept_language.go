@@ -0,0 +1,76 @@
+package forgeron
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// maxAcceptLanguages caps the number of languages of the Accept-Language header
+const maxAcceptLanguages = 10
+
+// acceptLanguage formats the Accept-Language header of the locale model. Browsers add the language of a regional
+// locale after it when it is missing, and weigh the languages differently:
+//
+//	Chromium: fr-FR,fr;q=0.9,en-US;q=0.8,en;q=0.7   (steps of 0.1, down to 0.1)
+//	Firefox:  fr-FR,fr;q=0.8,en-US;q=0.5,en;q=0.3   (spread between 1 and 0)
+//	Safari:   fr-FR,fr;q=0.9                        (only the first locale)
+func (m localeModel) acceptLanguage() string {
+	languages := m.acceptLanguages()
+	parts := make([]string, len(languages))
+	for i, language := range languages {
+		if i == 0 {
+			parts[i] = language
+			continue
+		}
+		if m.engine == geckoEngine {
+			parts[i] = language + ";q=" + geckoQuality(i, len(languages))
+		} else {
+			parts[i] = fmt.Sprintf("%s;q=%.1f", language, max(1-float64(i)*0.1, 0.1))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// acceptLanguages returns the languages of the Accept-Language header, in order
+func (m localeModel) acceptLanguages() []string {
+	locales := m.locales
+	if m.engine == webKitEngine && len(locales) > 1 {
+		locales = locales[:1]
+	}
+	languages := withBaseLanguages(locales)
+	if len(languages) > maxAcceptLanguages {
+		languages = languages[:maxAcceptLanguages]
+	}
+	return languages
+}
+
+// geckoQuality returns the q-value Firefox gives to the language at index i of n, with two decimals from ten
+// languages on
+func geckoQuality(i, n int) string {
+	q := float64(n-i) / float64(n)
+	// Rounded half up like Firefox, fmt rounds halves to even
+	if n < 10 {
+		return fmt.Sprintf("%.1f", math.Round(q*10)/10)
+	}
+	return fmt.Sprintf("%.2f", math.Round(q*100)/100)
+}
+
+// withBaseLanguages adds the language of each regional locale after the last locale of that language, unless the
+// language is already listed, e.g. [fr-FR en-US] gives [fr-FR fr en-US en]
+func withBaseLanguages(locales []string) []string {
+	result := make([]string, 0, len(locales))
+	for i, locale := range locales {
+		result = append(result, locale)
+		base, _, regional := strings.Cut(locale, "-")
+		if !regional || slices.Contains(locales, base) || slices.Contains(result, base) {
+			continue
+		}
+		if slices.ContainsFunc(locales[i+1:], func(next string) bool { return strings.HasPrefix(next, base+"-") }) {
+			continue
+		}
+		result = append(result, base)
+	}
+	return result
+}
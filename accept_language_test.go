@@ -0,0 +1,103 @@
+package forgeron
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAcceptLanguage(t *testing.T) {
+	const (
+		chrome  = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36"
+		firefox = "Mozilla/5.0 (X11; Linux x86_64; rv:147.0) Gecko/20100101 Firefox/147.0"
+		safari  = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/26.2 Safari/605.1.15"
+		crios   = "Mozilla/5.0 (iPhone; CPU iPhone OS 18_7 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/144.0.7559.95 Mobile/15E148 Safari/604.1"
+	)
+	tests := []struct {
+		name      string
+		userAgent string
+		locales   []string
+		want      string
+	}{
+		{"chrome single", chrome, []string{"en-US"}, "en-US,en;q=0.9"},
+		{"chrome chain", chrome, []string{"fr-FR", "en-US"}, "fr-FR,fr;q=0.9,en-US;q=0.8,en;q=0.7"},
+		{"chrome base listed", chrome, []string{"pt-BR", "pt", "en-US", "en"}, "pt-BR,pt;q=0.9,en-US;q=0.8,en;q=0.7"},
+		{"chrome language only", chrome, []string{"de"}, "de"},
+		{"firefox single", firefox, []string{"en-US"}, "en-US,en;q=0.5"},
+		{"firefox chain", firefox, []string{"fr-FR", "en-US"}, "fr-FR,fr;q=0.8,en-US;q=0.5,en;q=0.3"},
+		{"safari first locale only", safari, []string{"fr-FR", "en-US"}, "fr-FR,fr;q=0.9"},
+		{"safari language only", safari, []string{"ja", "en-US"}, "ja"},
+		{"chrome on ios", crios, []string{"de-DE", "en-US"}, "de-DE,de;q=0.9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newLocaleModel(tt.userAgent, tt.locales).acceptLanguage(); got != tt.want {
+				t.Errorf("acceptLanguage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAcceptLanguageLimit(t *testing.T) {
+	locales := []string{"en-US", "en-GB", "fr-FR", "de-DE", "es-ES", "it-IT", "pt-BR", "nl-NL"}
+	chrome := newLocaleModel("Chrome/144.0.0.0", locales).acceptLanguage()
+	if n := strings.Count(chrome, ",") + 1; n != maxAcceptLanguages {
+		t.Errorf("acceptLanguage() = %q has %d languages, want %d", chrome, n, maxAcceptLanguages)
+	}
+	if !strings.HasSuffix(chrome, "it-IT;q=0.1") {
+		t.Errorf("acceptLanguage() = %q, want q-values down to 0.1", chrome)
+	}
+	firefox := newLocaleModel("Firefox/147.0", locales).acceptLanguage()
+	if !strings.HasPrefix(firefox, "en-US,en-GB;q=0.90,en;q=0.80,") {
+		t.Errorf("acceptLanguage() = %q, want two decimals for ten languages", firefox)
+	}
+}
+
+func TestWithBaseLanguages(t *testing.T) {
+	tests := []struct {
+		locales []string
+		want    []string
+	}{
+		{[]string{"fr-FR", "en-US"}, []string{"fr-FR", "fr", "en-US", "en"}},
+		{[]string{"en-US", "en-GB", "de"}, []string{"en-US", "en-GB", "en", "de"}},
+		{[]string{"zh-Hant-TW"}, []string{"zh-Hant-TW", "zh"}},
+		{[]string{"fr-CA", "fr", "en-US"}, []string{"fr-CA", "fr", "en-US", "en"}},
+	}
+	for _, tt := range tests {
+		if got := withBaseLanguages(tt.locales); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("withBaseLanguages(%v) = %v, want %v", tt.locales, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateHeadersAcceptLanguagePerBrowser(t *testing.T) {
+	hgen, err := NewHeaderGenerator()
+	if err != nil {
+		t.Fatalf("NewHeaderGenerator() error = %v", err)
+	}
+	tests := []struct {
+		browser Browser
+		os      OS
+		want    string
+	}{
+		{Chrome, Windows, "fr-FR,fr;q=0.9,en-US;q=0.8,en;q=0.7"},
+		{Firefox, Windows, "fr-FR,fr;q=0.8,en-US;q=0.5,en;q=0.3"},
+		{Safari, MacOS, "fr-FR,fr;q=0.9"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.browser), func(t *testing.T) {
+			headers, err := hgen.GenerateHeaders(HeaderConstraints{
+				Browsers: []Browser{tt.browser},
+				OS:       []OS{tt.os},
+				Locales:  []string{"fr-FR", "en-US"},
+				Strict:   true,
+			})
+			if err != nil {
+				t.Fatalf("GenerateHeaders() error = %v", err)
+			}
+			if got := headerValue(headers, "accept-language"); got != tt.want {
+				t.Errorf("Accept-Language = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
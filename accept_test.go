@@ -0,0 +1,59 @@
+package forgeron
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestValidateAccept(t *testing.T) {
+	tests := []struct {
+		accept  string
+		wantErr bool
+	}{
+		{"application/json", false},
+		{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", false},
+		{"application/signed-exchange;v=b3;q=0.7", false},
+		{"", true},
+		{"json", true},
+		{"text/html;q=2", true},
+		{"text/html;q", true},
+	}
+	for _, tt := range tests {
+		if err := validateAccept(tt.accept); (err != nil) != tt.wantErr {
+			t.Errorf("validateAccept(%q) error = %v, wantErr %v", tt.accept, err, tt.wantErr)
+		}
+	}
+}
+
+func TestAcceptBrowsers(t *testing.T) {
+	all := []Browser{Chrome, Firefox, Safari, Edge}
+	if got := acceptBrowsers("application/json", all); !slices.Equal(got, all) {
+		t.Errorf("acceptBrowsers() = %v, want all browsers", got)
+	}
+	if got := acceptBrowsers("text/html,image/apng,*/*;q=0.8", all); !slices.Equal(got, []Browser{Chrome, Edge}) {
+		t.Errorf("acceptBrowsers() = %v, want Chromium browsers", got)
+	}
+}
+
+func TestGenerateHeadersAcceptOverride(t *testing.T) {
+	gen, err := NewHeaderGenerator()
+	if err != nil {
+		t.Fatalf("NewHeaderGenerator() error = %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		headers, err := gen.GenerateHeaders(HeaderConstraints{Accept: "application/json"})
+		if err != nil {
+			t.Fatalf("GenerateHeaders() error = %v", err)
+		}
+		if headers["Accept"] != "application/json" {
+			t.Fatalf("Accept = %q, want application/json", headers["Accept"])
+		}
+		if _, ok := headers["accept"]; ok {
+			t.Fatal("Accept header was duplicated")
+		}
+	}
+
+	if _, err := gen.GenerateHeaders(HeaderConstraints{Browsers: []Browser{Firefox}, Accept: "text/html,image/apng"}); err == nil {
+		t.Error("expected error for a Chromium-only Accept with Firefox")
+	}
+}
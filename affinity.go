@@ -0,0 +1,135 @@
+package forgeron
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// AffinityKey is the logical session a request belongs to. Crawler instances sharing an identity pool pick the
+// same identity for the same key, e.g. to log in to a domain with an account through a proxy.
+type AffinityKey struct {
+	Domain  string `json:"domain"`
+	Account string `json:"account,omitempty"`
+	Proxy   string `json:"proxy,omitempty"`
+}
+
+// hash hashes the key, with the domain lowercased. Fields are length prefixed so that no two keys collide.
+func (k AffinityKey) hash() uint64 {
+	canonical := fmt.Sprintf("%d:%s|%d:%s|%d:%s", len(k.Domain), strings.ToLower(k.Domain), len(k.Account), k.Account, len(k.Proxy), k.Proxy)
+	sum := sha256.Sum256([]byte(canonical))
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+// IdentityID returns a stable identifier of a fingerprint, the hex SHA-256 of its JSON, equal across processes
+// and forgeron instances holding the same identity
+func IdentityID(fp *Fingerprint) (string, error) {
+	sum, err := identitySum(fp)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// identitySum returns the SHA-256 of the JSON of a fingerprint
+func identitySum(fp *Fingerprint) ([32]byte, error) {
+	if fp == nil {
+		return [32]byte{}, fmt.Errorf("fingerprint is required")
+	}
+	data, err := json.Marshal(fp)
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("failed to encode fingerprint: %w", err)
+	}
+	return sha256.Sum256(data), nil
+}
+
+// affinityIdentity is an identity of an affinity pool
+type affinityIdentity struct {
+	sum  [32]byte
+	hash uint64
+	fp   *Fingerprint
+}
+
+// AffinityPool maps affinity keys to the identities of a shared pool with rendezvous hashing. Instances loading
+// the same identities, in any order, map each key to the same identity without coordinating, and adding or
+// removing an identity only moves the keys of that identity. It is safe for concurrent use.
+type AffinityPool struct {
+	identities []affinityIdentity
+}
+
+// NewAffinityPool creates a pool of the identities, e.g. the ones read with ImportNDJSON. Duplicates are
+// counted once.
+func NewAffinityPool(fingerprints []*Fingerprint) (*AffinityPool, error) {
+	p := &AffinityPool{}
+	seen := make(map[[32]byte]bool, len(fingerprints))
+	for i, fp := range fingerprints {
+		sum, err := identitySum(fp)
+		if err != nil {
+			return nil, fmt.Errorf("identity %d: %w", i, err)
+		}
+		if seen[sum] {
+			continue
+		}
+		seen[sum] = true
+		p.identities = append(p.identities, affinityIdentity{sum: sum, hash: binary.BigEndian.Uint64(sum[:8]), fp: fp.Clone()})
+	}
+	if len(p.identities) == 0 {
+		return nil, fmt.Errorf("affinity pool needs at least one identity")
+	}
+	return p, nil
+}
+
+// Get returns a copy of the identity of the key
+func (p *AffinityPool) Get(key AffinityKey) *Fingerprint {
+	keyHash := key.hash()
+	best := p.identities[0]
+	for _, identity := range p.identities[1:] {
+		if compareAffinity(keyHash, identity, best) < 0 {
+			best = identity
+		}
+	}
+	return best.fp.Clone()
+}
+
+// Candidates returns copies of the n identities of the key in order of preference, the first one being the one
+// of Get. Moving down the list when an identity is blocked keeps instances in agreement.
+func (p *AffinityPool) Candidates(key AffinityKey, n int) []*Fingerprint {
+	keyHash := key.hash()
+	ranked := slices.Clone(p.identities)
+	slices.SortFunc(ranked, func(a, b affinityIdentity) int { return compareAffinity(keyHash, a, b) })
+	n = min(max(n, 0), len(ranked))
+	fingerprints := make([]*Fingerprint, n)
+	for i, identity := range ranked[:n] {
+		fingerprints[i] = identity.fp.Clone()
+	}
+	return fingerprints
+}
+
+// Len returns the number of identities of the pool
+func (p *AffinityPool) Len() int {
+	return len(p.identities)
+}
+
+// compareAffinity orders identities by decreasing rendezvous weight for the key, ties broken by their hash
+func compareAffinity(keyHash uint64, a, b affinityIdentity) int {
+	if sa, sb := affinityScore(keyHash, a.hash), affinityScore(keyHash, b.hash); sa != sb {
+		if sa > sb {
+			return -1
+		}
+		return 1
+	}
+	return bytes.Compare(a.sum[:], b.sum[:])
+}
+
+// affinityScore is the rendezvous weight of an identity for a key, the SplitMix64 finalizer of their hashes
+func affinityScore(keyHash, identityHash uint64) uint64 {
+	z := keyHash ^ identityHash
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
@@ -0,0 +1,114 @@
+package forgeron
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// affinityFingerprints returns n distinct identities, told apart by their user agent in the tests
+func affinityFingerprints(t *testing.T, n int) []*Fingerprint {
+	t.Helper()
+	gen := newGeneratorOrFatal(t, WithRandomSource(NewSeededSource(1)))
+	fingerprints := make([]*Fingerprint, n)
+	for i := range fingerprints {
+		fp, err := gen.Generate()
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		fp.Navigator.UserAgent += fmt.Sprintf(" identity/%d", i)
+		fp.setHeader("User-Agent", fp.Navigator.UserAgent)
+		fingerprints[i] = fp
+	}
+	return fingerprints
+}
+
+// newAffinityPoolOrFatal creates an affinity pool of the fingerprints
+func newAffinityPoolOrFatal(t *testing.T, fingerprints []*Fingerprint) *AffinityPool {
+	t.Helper()
+	pool, err := NewAffinityPool(fingerprints)
+	if err != nil {
+		t.Fatalf("NewAffinityPool() error = %v", err)
+	}
+	return pool
+}
+
+func TestAffinityPool(t *testing.T) {
+	fingerprints := affinityFingerprints(t, 20)
+	pool := newAffinityPoolOrFatal(t, fingerprints)
+	reversed := slices.Clone(fingerprints)
+	slices.Reverse(reversed)
+	// Another instance loading the identities in another order, with a duplicate
+	other := newAffinityPoolOrFatal(t, append(reversed, fingerprints[3]))
+	if other.Len() != 20 {
+		t.Errorf("Len() = %d, want duplicates counted once", other.Len())
+	}
+
+	counts := make(map[string]int)
+	for i := range 2000 {
+		key := AffinityKey{Domain: "shop.example.com", Account: fmt.Sprintf("user%d", i), Proxy: "10.0.0.1:8080"}
+		got := pool.Get(key)
+		if want := other.Get(key); got.Navigator.UserAgent != want.Navigator.UserAgent {
+			t.Fatalf("Get(%+v) = %q on one instance, %q on the other", key, got.Navigator.UserAgent, want.Navigator.UserAgent)
+		}
+		if upper := pool.Get(AffinityKey{Domain: "SHOP.example.com", Account: key.Account, Proxy: key.Proxy}); upper.Navigator.UserAgent != got.Navigator.UserAgent {
+			t.Errorf("Get() depends on the case of the domain")
+		}
+		counts[got.Navigator.UserAgent]++
+	}
+	for ua, count := range counts {
+		if count < 50 || count > 150 {
+			t.Errorf("%q serves %d of 2000 keys, want about 100", ua, count)
+		}
+	}
+
+	got := pool.Get(AffinityKey{Domain: "example.com"})
+	got.Navigator.UserAgent = "changed"
+	if pool.Get(AffinityKey{Domain: "example.com"}).Navigator.UserAgent == "changed" {
+		t.Error("Get() returned the pooled identity instead of a copy")
+	}
+}
+
+func TestAffinityPoolRemoval(t *testing.T) {
+	fingerprints := affinityFingerprints(t, 10)
+	pool := newAffinityPoolOrFatal(t, fingerprints)
+	removed := fingerprints[4].Navigator.UserAgent
+	smaller := newAffinityPoolOrFatal(t, slices.Delete(slices.Clone(fingerprints), 4, 5))
+
+	for i := range 500 {
+		key := AffinityKey{Domain: "example.com", Account: fmt.Sprint(i)}
+		before, after := pool.Get(key), smaller.Get(key)
+		candidates := pool.Candidates(key, 2)
+		if candidates[0].Navigator.UserAgent != before.Navigator.UserAgent {
+			t.Fatalf("Candidates()[0] = %q, want the identity of Get %q", candidates[0].Navigator.UserAgent, before.Navigator.UserAgent)
+		}
+		switch {
+		case before.Navigator.UserAgent != removed && after.Navigator.UserAgent != before.Navigator.UserAgent:
+			t.Errorf("key %v moved from %q to %q although its identity was kept", key, before.Navigator.UserAgent, after.Navigator.UserAgent)
+		case before.Navigator.UserAgent == removed && after.Navigator.UserAgent != candidates[1].Navigator.UserAgent:
+			t.Errorf("key %v moved to %q, want its second candidate %q", key, after.Navigator.UserAgent, candidates[1].Navigator.UserAgent)
+		}
+	}
+}
+
+func TestNewAffinityPoolInvalid(t *testing.T) {
+	if _, err := NewAffinityPool(nil); err == nil {
+		t.Error("NewAffinityPool(nil) error = nil, want an error")
+	}
+	if _, err := NewAffinityPool([]*Fingerprint{{}, nil}); err == nil {
+		t.Error("NewAffinityPool() error = nil for a nil identity")
+	}
+}
+
+func TestIdentityID(t *testing.T) {
+	fingerprints := affinityFingerprints(t, 2)
+	first, err := IdentityID(fingerprints[0])
+	if err != nil {
+		t.Fatalf("IdentityID() error = %v", err)
+	}
+	again, _ := IdentityID(fingerprints[0].Clone())
+	second, _ := IdentityID(fingerprints[1])
+	if len(first) != 64 || first != again || first == second {
+		t.Errorf("IdentityID() = %q, %q for a copy and %q for another identity", first, again, second)
+	}
+}
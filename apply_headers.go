@@ -0,0 +1,142 @@
+package forgeron
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// HeaderOrderKey and PHeaderOrderKey are the http.Header keys fhttp and its forks read the header and pseudo-header
+// orders from. net/http rejects them as invalid header names.
+const (
+	HeaderOrderKey  = "Header-Order:"
+	PHeaderOrderKey = "PHeader-Order:"
+)
+
+// ApplyOption configures ApplyHeaders
+type ApplyOption func(*applyOptions)
+
+// applyOptions are the settings of ApplyHeaders
+type applyOptions struct {
+	keepExisting bool
+	orderKeys    bool
+	clientHints  []ClientHint
+	jar          http.CookieJar
+	splitCookies bool
+}
+
+// WithKeepExisting keeps the headers already set on the request, e.g. the Accept of an API client, instead of
+// replacing them with the generated ones
+func WithKeepExisting(keep bool) ApplyOption {
+	return func(o *applyOptions) {
+		o.keepExisting = keep
+	}
+}
+
+// WithOrderKeys sets the header order of the browser under HeaderOrderKey and its pseudo-header order under
+// PHeaderOrderKey, for fhttp clients. Leave it unset with net/http, which fails on these keys.
+func WithOrderKeys(enabled bool) ApplyOption {
+	return func(o *applyOptions) {
+		o.orderKeys = enabled
+	}
+}
+
+// WithHighEntropyHints adds the high-entropy client hints a server asked for with Accept-CH, see ParseAcceptCH
+func WithHighEntropyHints(hints ...ClientHint) ApplyOption {
+	return func(o *applyOptions) {
+		o.clientHints = hints
+	}
+}
+
+// WithCookieJar sets the Cookie header of the cookies of the jar for the request URL, formatted as the browser
+// sends them, see Fingerprint.CookieHeader. Leave http.Client.Jar unset, it would add the cookies again.
+func WithCookieJar(jar http.CookieJar) ApplyOption {
+	return func(o *applyOptions) {
+		o.jar = jar
+	}
+}
+
+// WithSplitCookies sends a Cookie header per cookie when the browser does, as Firefox over HTTP/2, instead of a
+// single header joined with "; ". Only set it for a transport sending the request over HTTP/2, HTTP/1.1 allows a
+// single Cookie header (RFC 6265, section 5.4).
+func WithSplitCookies(split bool) ApplyOption {
+	return func(o *applyOptions) {
+		o.splitCookies = split
+	}
+}
+
+// ApplyHeaders sets the generated headers of the fingerprint on the request in place: User-Agent, client hints,
+// Accept values, Sec-Fetch metadata and the others the browser sends, so existing code adopts an identity with one
+// call per request. The Host stays the one of req.Host or the URL, as net/http ignores a Host header, and only takes
+// its place in the order of WithOrderKeys. The generated Accept-Encoding disables the transparent decompression of
+// net/http, responses must be decoded by the caller.
+func ApplyHeaders(req *http.Request, fp *Fingerprint, opts ...ApplyOption) {
+	var o applyOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if req.Header == nil {
+		req.Header = make(http.Header)
+	}
+
+	existing := make(map[string]bool, len(req.Header))
+	for name := range req.Header {
+		existing[http.CanonicalHeaderKey(name)] = true
+	}
+	set := func(name, value string) {
+		key := http.CanonicalHeaderKey(name)
+		if key == "Host" || (o.keepExisting && existing[key]) {
+			return
+		}
+		req.Header.Set(key, value)
+	}
+	for name, value := range fp.Headers {
+		set(name, value)
+	}
+	hints := fp.ClientHintHeaders(o.clientHints...)
+	for _, hint := range o.clientHints {
+		if value, ok := hints[string(hint)]; ok {
+			set(string(hint), value)
+		}
+	}
+
+	if o.jar != nil && req.URL != nil && !(o.keepExisting && existing["Cookie"]) {
+		pairs := cookiePairs(o.jar.Cookies(req.URL))
+		switch {
+		case len(pairs) == 0:
+		case o.splitCookies && fp.splitsCookies():
+			req.Header["Cookie"] = pairs
+		default:
+			req.Header.Set("Cookie", strings.Join(pairs, "; "))
+		}
+	}
+
+	if o.orderKeys {
+		req.Header[HeaderOrderKey] = requestHeaderOrder(req.Header, fp.headerOrder())
+		req.Header[PHeaderOrderKey] = fp.PseudoHeaderOrder()
+	}
+}
+
+// requestHeaderOrder returns the lowercase names of the headers of a request in the order of the browser, with the
+// host where the browser sends it. Headers the browser order misses follow, sorted by name.
+func requestHeaderOrder(header http.Header, order []string) []string {
+	var names []string
+	for _, name := range order {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, ":") || slices.Contains(names, name) {
+			continue
+		}
+		if name == "host" || len(header.Values(name)) > 0 {
+			names = append(names, name)
+		}
+	}
+	rest := len(names)
+	for key := range header {
+		name := strings.ToLower(key)
+		if key != HeaderOrderKey && key != PHeaderOrderKey && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names[rest:])
+	return names
+}
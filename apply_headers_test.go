@@ -0,0 +1,92 @@
+package forgeron
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestApplyHeaders(t *testing.T) {
+	gen := newGeneratorOrFatal(t, WithRandomSource(NewSeededSource(5)))
+	fp, err := gen.Generate(WithHeaderConstraints(HeaderConstraints{Browsers: []Browser{Chrome}, OS: []OS{Windows}, HTTPVersion: HTTP1}))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com/api", nil)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	ApplyHeaders(req, fp, WithHighEntropyHints(HintArch))
+
+	if got := req.Header.Get("User-Agent"); got != fp.Navigator.UserAgent {
+		t.Errorf("User-Agent = %q, want %q", got, fp.Navigator.UserAgent)
+	}
+	if got, want := req.Header.Get("Accept"), headerValue(fp.Headers, "accept"); got != want {
+		t.Errorf("Accept = %q, want the generated %q", got, want)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer token" {
+		t.Errorf("Authorization = %q, want it kept", got)
+	}
+	if got, want := req.Header.Get("Sec-Ch-Ua-Arch"), `"`+fp.Navigator.UserAgentData.Architecture+`"`; got != want {
+		t.Errorf("Sec-Ch-Ua-Arch = %q, want %q", got, want)
+	}
+	if req.Header.Get("Host") != "" || len(req.Header[HeaderOrderKey]) > 0 {
+		t.Errorf("headers = %v, want no Host header nor order keys", req.Header)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "https://example.com/api", nil)
+	req.Header.Set("Accept", "application/json")
+	ApplyHeaders(req, fp, WithKeepExisting(true), WithOrderKeys(true))
+	if got := req.Header.Get("Accept"); got != "application/json" {
+		t.Errorf("Accept = %q, want the existing one kept", got)
+	}
+	order := req.Header[HeaderOrderKey]
+	if len(order) == 0 || order[0] != "host" {
+		t.Fatalf("header order = %v, want the Chrome HTTP/1 order starting with host", order)
+	}
+	if slices.Index(order, "sec-ch-ua") > slices.Index(order, "user-agent") {
+		t.Errorf("header order = %v, want sec-ch-ua before user-agent", order)
+	}
+	if got := req.Header[PHeaderOrderKey]; !slices.Equal(got, fp.PseudoHeaderOrder()) {
+		t.Errorf("pseudo-header order = %v, want %v", got, fp.PseudoHeaderOrder())
+	}
+}
+
+func TestApplyHeadersCookieJar(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com/", nil)
+	jar := newCookieJarOrFatal(t, req.URL, &http.Cookie{Name: "a", Value: "1"}, &http.Cookie{Name: "b", Value: "2"})
+	tests := []struct {
+		userAgent string
+		headers   map[string]string
+		split     bool
+		want      []string
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36", map[string]string{"accept": "*/*"}, true, []string{"a=1; b=2"}},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:147.0) Gecko/20100101 Firefox/147.0", map[string]string{"Connection": "keep-alive"}, true, []string{"a=1; b=2"}},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:147.0) Gecko/20100101 Firefox/147.0", map[string]string{"accept": "*/*"}, false, []string{"a=1; b=2"}},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:147.0) Gecko/20100101 Firefox/147.0", map[string]string{"accept": "*/*"}, true, []string{"a=1", "b=2"}},
+	}
+	for _, tt := range tests {
+		fp := &Fingerprint{Headers: tt.headers, Navigator: NavigatorFingerprint{UserAgent: tt.userAgent}}
+		req.Header = nil
+		ApplyHeaders(req, fp, WithCookieJar(jar), WithSplitCookies(tt.split))
+		if got := req.Header["Cookie"]; !slices.Equal(got, tt.want) {
+			t.Errorf("Cookie = %q, want %q", got, tt.want)
+		}
+	}
+
+	req.Header = http.Header{"Cookie": {"c=3"}}
+	ApplyHeaders(req, &Fingerprint{}, WithCookieJar(jar), WithKeepExisting(true))
+	if got := req.Header.Get("Cookie"); got != "c=3" {
+		t.Errorf("Cookie = %q, want the existing one kept", got)
+	}
+}
+
+func TestRequestHeaderOrder(t *testing.T) {
+	header := http.Header{"User-Agent": {"ua"}, "Cookie": {"a=b"}, "X-Custom": {"1"}, "Accept": {"*/*"}}
+	order := []string{":method", ":authority", "Host", "Accept", "User-Agent", "Cookie"}
+	want := []string{"host", "accept", "user-agent", "cookie", "x-custom"}
+	if got := requestHeaderOrder(header, order); !slices.Equal(got, want) {
+		t.Errorf("requestHeaderOrder() = %v, want %v", got, want)
+	}
+}
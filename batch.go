@@ -0,0 +1,120 @@
+package forgeron
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// ErrBatchNotUnique is returned by GenerateBatch when not enough unique fingerprints could be generated,
+// e.g. when the constraints allow fewer user agents than requested
+var ErrBatchNotUnique = errors.New("not enough unique fingerprints")
+
+// batchAttemptsPerFingerprint bounds the generations of a batch requiring unique fingerprints
+const batchAttemptsPerFingerprint = 20
+
+// BatchUniqueness is what GenerateBatch keeps unique among the fingerprints of a batch
+type BatchUniqueness int
+
+const (
+	// AllowDuplicates keeps every generated fingerprint
+	AllowDuplicates BatchUniqueness = iota
+	// UniqueUserAgent generates fingerprints with distinct user agents
+	UniqueUserAgent
+	// UniqueUserAgentScreen generates fingerprints with distinct user agent and screen combinations
+	UniqueUserAgentScreen
+)
+
+// key returns the key identifying duplicates of the fingerprint, empty when duplicates are allowed
+func (u BatchUniqueness) key(fp *Fingerprint) string {
+	switch u {
+	case UniqueUserAgent:
+		return fp.Navigator.UserAgent
+	case UniqueUserAgentScreen:
+		screen := fp.Screen
+		return fp.Navigator.UserAgent + "|" + strconv.Itoa(screen.Width) + "x" + strconv.Itoa(screen.Height) + "@" +
+			strconv.FormatFloat(screen.DevicePixelRatio, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+// WithBatchUniqueness sets what GenerateBatch keeps unique among the fingerprints of a batch
+func WithBatchUniqueness(uniqueness BatchUniqueness) FingerprintOption {
+	return func(g *FingerprintGenerator) {
+		g.batchUniqueness = uniqueness
+	}
+}
+
+// GenerateBatch generates n fingerprints concurrently, e.g. to pre-warm a pool of identities. The options are
+// applied once for the whole batch. Fingerprints are generated in no particular order, so a seeded source does
+// not reproduce a batch, and a relaxation report or sampling trace is not filled by batches.
+func (g *FingerprintGenerator) GenerateBatch(n int, opts ...FingerprintOption) ([]*Fingerprint, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("batch size cannot be negative")
+	}
+	gen := g.withOptions(opts)
+	if gen == g {
+		c := *g
+		gen = &c
+	}
+	// Workers generate concurrently, a shared report or trace would race
+	gen.relaxationReport = nil
+	if gen.samplingTrace != nil {
+		gen = gen.withOptions([]FingerprintOption{WithSamplingTrace(nil)})
+	}
+	uniqueness := gen.batchUniqueness
+
+	attempts := n
+	if uniqueness != AllowDuplicates {
+		attempts = n * batchAttemptsPerFingerprint
+	}
+	var (
+		mu           sync.Mutex
+		fingerprints = make([]*Fingerprint, 0, n)
+		seen         = make(map[string]bool)
+		started      int
+		firstErr     error
+		wg           sync.WaitGroup
+	)
+	// next reserves a generation, until the batch is full, failed or out of attempts
+	next := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(fingerprints) == n || firstErr != nil || started == attempts {
+			return false
+		}
+		started++
+		return true
+	}
+	for range min(runtime.GOMAXPROCS(0), n) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for next() {
+				fp, err := gen.Generate()
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+					}
+				} else if key := uniqueness.key(fp); len(fingerprints) < n && (key == "" || !seen[key]) {
+					seen[key] = true
+					fingerprints = append(fingerprints, fp)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if len(fingerprints) < n {
+		return fingerprints, fmt.Errorf("%w: %d of %d generated in %d attempts", ErrBatchNotUnique, len(fingerprints), n, attempts)
+	}
+	return fingerprints, nil
+}
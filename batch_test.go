@@ -0,0 +1,67 @@
+package forgeron
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGenerateBatch(t *testing.T) {
+	gen := newGeneratorOrFatal(t)
+	fingerprints, err := gen.GenerateBatch(20, WithHeaderConstraints(HeaderConstraints{Browsers: []Browser{Firefox}}))
+	if err != nil {
+		t.Fatalf("GenerateBatch() error = %v", err)
+	}
+	if len(fingerprints) != 20 {
+		t.Fatalf("GenerateBatch() returned %d fingerprints, want 20", len(fingerprints))
+	}
+	for _, fp := range fingerprints {
+		if info := parseUserAgent(fp.Navigator.UserAgent); info.Browser != Firefox {
+			t.Errorf("user agent %q is not Firefox", fp.Navigator.UserAgent)
+		}
+	}
+
+	if fingerprints, err := gen.GenerateBatch(0); err != nil || len(fingerprints) != 0 {
+		t.Errorf("GenerateBatch(0) = %d fingerprints, %v", len(fingerprints), err)
+	}
+	if _, err := gen.GenerateBatch(-1); err == nil {
+		t.Error("GenerateBatch(-1) error = nil")
+	}
+}
+
+func TestGenerateBatchUniqueness(t *testing.T) {
+	gen := newGeneratorOrFatal(t)
+	fingerprints, err := gen.GenerateBatch(10, WithBatchUniqueness(UniqueUserAgentScreen))
+	if err != nil {
+		t.Fatalf("GenerateBatch() error = %v", err)
+	}
+	seen := make(map[string]bool)
+	for _, fp := range fingerprints {
+		key := UniqueUserAgentScreen.key(fp)
+		if seen[key] {
+			t.Errorf("duplicate fingerprint %s", key)
+		}
+		seen[key] = true
+	}
+
+	// A single browser version on a single OS has too few user agents
+	narrow := WithHeaderConstraints(HeaderConstraints{
+		BrowserSpecs: []*BrowserSpec{{Name: Chrome, MinVersion: 144, MaxVersion: 144}},
+		OS:           []OS{Windows},
+		Devices:      []Device{Desktop},
+	})
+	fingerprints, err = gen.GenerateBatch(50, narrow, WithBatchUniqueness(UniqueUserAgent))
+	if !errors.Is(err, ErrBatchNotUnique) {
+		t.Fatalf("GenerateBatch() error = %v, want ErrBatchNotUnique", err)
+	}
+	if len(fingerprints) == 0 || len(fingerprints) >= 50 {
+		t.Errorf("GenerateBatch() returned %d fingerprints, want the unique ones", len(fingerprints))
+	}
+}
+
+func TestGenerateBatchReportsErrors(t *testing.T) {
+	gen := newGeneratorOrFatal(t)
+	strict := WithHeaderConstraints(HeaderConstraints{Browsers: []Browser{"netscape"}, Strict: true})
+	if _, err := gen.GenerateBatch(5, strict); err == nil {
+		t.Error("GenerateBatch() error = nil for constraints that cannot be satisfied")
+	}
+}
@@ -3,7 +3,11 @@ package forgeron
 import (
 	"encoding/json"
 	"fmt"
-	"math/rand"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
 )
 
 // node represents a node in the Bayesian network
@@ -14,12 +18,25 @@ type node struct {
 	ConditionalProbs map[string]interface{} `json:"conditionalProbabilities"`
 	parents          []*node
 	children         []*node
+	// values are the values of the node in sorted order, indexed by valueIndex
+	values     []string
+	valueIndex map[string]int32
+	// table is the compiled ConditionalProbs, which are dropped once compiled
+	table *probabilityTable
 }
 
 // bayesianNetwork represents the entire network
 type bayesianNetwork struct {
 	NodesInSamplingOrder []*node
 	NodesByName          map[string]*node
+	logger               *slog.Logger
+	random               RandomSource
+	tracer               networkTracer
+	// frontiers lists, for each depth of the sampling order, the nodes sampled before it that the nodes left
+	// depend on, computed once the network is loaded
+	frontiers [][]string
+	// schema is the data schema stamped on the network definition
+	schema dataSchema
 }
 
 // newBayesianNetwork creates a new Bayesian network
@@ -40,12 +57,72 @@ func (bn *bayesianNetwork) loadNetwork(data []byte) error {
 
 	// Add nodes to network
 	for i := range networkDef.Nodes {
-		node := &networkDef.Nodes[i]
-		bn.NodesByName[node.Name] = node
-		bn.NodesInSamplingOrder = append(bn.NodesInSamplingOrder, node)
+		bn.addNode(&networkDef.Nodes[i])
 	}
+	bn.link()
+	return bn.compile()
+}
 
-	// Set up parent-child relationships
+// decodeNetwork loads a network definition from a JSON stream, compiling each node as soon as it is decoded so
+// only the raw probabilities of a single node are held in memory
+func (bn *bayesianNetwork) decodeNetwork(r io.Reader) error {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("error decoding JSON: %v", err)
+		}
+		if key != "nodes" {
+			var skipped json.RawMessage
+			if err := dec.Decode(&skipped); err != nil {
+				return fmt.Errorf("error decoding JSON: %v", err)
+			}
+			continue
+		}
+		if err := expectDelim(dec, '['); err != nil {
+			return err
+		}
+		for dec.More() {
+			node := &node{}
+			if err := dec.Decode(node); err != nil {
+				return fmt.Errorf("error decoding JSON: %v", err)
+			}
+			if err := node.compile(); err != nil {
+				return err
+			}
+			bn.addNode(node)
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			return err
+		}
+	}
+	bn.link()
+	return nil
+}
+
+// expectDelim reads the next JSON token, failing unless it is the delimiter
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("error decoding JSON: %v", err)
+	}
+	if token != delim {
+		return fmt.Errorf("error decoding JSON: expected %s, got %v", delim, token)
+	}
+	return nil
+}
+
+// addNode adds a node at the end of the sampling order
+func (bn *bayesianNetwork) addNode(node *node) {
+	bn.NodesByName[node.Name] = node
+	bn.NodesInSamplingOrder = append(bn.NodesInSamplingOrder, node)
+}
+
+// link sets up the parent-child relationships of the nodes and the sampling frontiers
+func (bn *bayesianNetwork) link() {
 	for _, node := range bn.NodesByName {
 		for _, parentName := range node.ParentNames {
 			if parent, exists := bn.NodesByName[parentName]; exists {
@@ -54,90 +131,98 @@ func (bn *bayesianNetwork) loadNetwork(data []byte) error {
 			}
 		}
 	}
-	return nil
+	bn.frontiers = bn.computeFrontiers()
 }
 
-// getProbabilitiesGivenKnownValues extracts unconditional probabilities of node values given parent values
-func (n *node) getProbabilitiesGivenKnownValues(parentValues map[string]string) map[string]float64 {
-	probabilities := n.ConditionalProbs
-	for _, parentName := range n.ParentNames {
-		parentValue := parentValues[parentName]
-		if deeper, ok := probabilities["deeper"].(map[string]interface{}); ok {
-			if next, exists := deeper[parentValue]; exists {
-				probabilities = next.(map[string]interface{})
-			} else {
-				probabilities = probabilities["skip"].(map[string]interface{})
-			}
+// compile compiles the conditional probability tables of the nodes
+func (bn *bayesianNetwork) compile() error {
+	for _, node := range bn.NodesInSamplingOrder {
+		if err := node.compile(); err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
-	result := make(map[string]float64)
-	for k, v := range probabilities {
-		if prob, ok := v.(float64); ok {
-			result[k] = prob
+// computeFrontiers returns, for each depth of the sampling order, the names of the nodes sampled before that depth
+// which are parents of a node sampled at or after it. Nodes only read the values of their parents, so whether the
+// nodes left can be sampled only depends on the values of the frontier.
+func (bn *bayesianNetwork) computeFrontiers() [][]string {
+	position := make(map[string]int, len(bn.NodesInSamplingOrder))
+	for i, node := range bn.NodesInSamplingOrder {
+		position[node.Name] = i
+	}
+	frontiers := make([][]string, len(bn.NodesInSamplingOrder)+1)
+	for depth := range frontiers {
+		seen := make(map[string]bool)
+		for _, node := range bn.NodesInSamplingOrder[depth:] {
+			for _, parentName := range node.ParentNames {
+				if i, ok := position[parentName]; ok && i < depth && !seen[parentName] {
+					seen[parentName] = true
+					frontiers[depth] = append(frontiers[depth], parentName)
+				}
+			}
 		}
 	}
-	return result
+	return frontiers
 }
 
-// sampleRandomValueFromPossibilities randomly samples from given values using probabilities
-func (n *node) sampleRandomValueFromPossibilities(possibleValues []string, probabilities map[string]float64) string {
-	anchor := rand.Float64()
+// sample randomly samples from the conditional distribution given parent values
+func (n *node) sample(src RandomSource, parentValues map[string]string) string {
+	leaf := n.lookup(parentValues)
+	if leaf == nil || len(leaf.values) == 0 {
+		return ""
+	}
+	// Leaf values are sorted, so a seeded source samples the same ones
+	anchor := randomFloat(src)
 	cumulativeProbability := 0.0
-	for _, value := range possibleValues {
-		cumulativeProbability += probabilities[value]
+	for i, index := range leaf.values {
+		cumulativeProbability += leaf.probabilities[i]
 		if cumulativeProbability > anchor {
-			return value
+			return n.values[index]
 		}
 	}
-	return possibleValues[0]
-}
-
-// sample randomly samples from the conditional distribution given parent values
-func (n *node) sample(parentValues map[string]string) string {
-	probabilities := n.getProbabilitiesGivenKnownValues(parentValues)
-	possibleValues := make([]string, 0, len(probabilities))
-	for value := range probabilities {
-		possibleValues = append(possibleValues, value)
-	}
-	return n.sampleRandomValueFromPossibilities(possibleValues, probabilities)
+	return n.values[leaf.values[0]]
 }
 
-// sampleAccordingToRestrictions samples with restrictions on possible values
+// sampleAccordingToRestrictions samples with restrictions on possible values, in the order of the possibilities
 func (n *node) sampleAccordingToRestrictions(
+	src RandomSource,
 	parentValues map[string]string,
 	valuePossibilities []string,
 	bannedValues []string,
 ) (string, bool) {
-	probabilities := n.getProbabilitiesGivenKnownValues(parentValues)
-	validValues := make([]string, 0)
-
-	// Create a map of banned values for quick lookup
-	bannedMap := make(map[string]struct{})
-	for _, v := range bannedValues {
-		bannedMap[v] = struct{}{}
+	leaf := n.lookup(parentValues)
+	probability := func(value string) (float64, bool) {
+		if slices.Contains(bannedValues, value) {
+			return 0, false
+		}
+		return leaf.probability(n, value)
 	}
 
-	// Filter valid values
-	for _, value := range valuePossibilities {
-		if _, isBanned := bannedMap[value]; !isBanned {
-			if _, hasProb := probabilities[value]; hasProb {
-				validValues = append(validValues, value)
-			}
+	// Find a valid value before drawing, so sources are not drawn from when sampling fails
+	first := -1
+	for i, value := range valuePossibilities {
+		if _, ok := probability(value); ok {
+			first = i
+			break
 		}
 	}
-
-	if len(validValues) == 0 {
+	if first < 0 {
 		return "", false
 	}
 
-	// Create probability map for valid values
-	validProbs := make(map[string]float64)
-	for _, value := range validValues {
-		validProbs[value] = probabilities[value]
+	anchor := randomFloat(src)
+	cumulativeProbability := 0.0
+	for _, value := range valuePossibilities[first:] {
+		if p, ok := probability(value); ok {
+			cumulativeProbability += p
+			if cumulativeProbability > anchor {
+				return value, true
+			}
+		}
 	}
-
-	return n.sampleRandomValueFromPossibilities(validValues, validProbs), true
+	return valuePossibilities[first], true
 }
 
 // generateSample generates a random sample from the network
@@ -150,9 +235,12 @@ func (bn *bayesianNetwork) generateSample(inputValues map[string]string) map[str
 		sample[k] = v
 	}
 
+	src := bn.tracer.source(bn.random)
 	for _, node := range bn.NodesInSamplingOrder {
 		if _, exists := sample[node.Name]; !exists {
-			sample[node.Name] = node.sample(sample)
+			value := node.sample(src, sample)
+			bn.tracer.record(node, value, sample, src)
+			sample[node.Name] = value
 		}
 	}
 	return sample
@@ -162,56 +250,135 @@ func (bn *bayesianNetwork) generateSample(inputValues map[string]string) map[str
 func (bn *bayesianNetwork) generateConsistentSampleWhenPossible(
 	valuePossibilities map[string][]string,
 ) (map[string]string, bool) {
-	return bn.recursivelyGenerateConsistentSampleWhenPossible(
-		make(map[string]string),
-		valuePossibilities,
-		0,
-	)
+	return bn.generateConsistentSampleWithinLimit(valuePossibilities, nil)
 }
 
+// generateConsistentSampleWithinLimit generates a sample consistent with value restrictions, giving up
+// once the sampling limit is exhausted. A nil limit is unlimited.
+func (bn *bayesianNetwork) generateConsistentSampleWithinLimit(
+	valuePossibilities map[string][]string,
+	limit *samplingLimit,
+) (map[string]string, bool) {
+	// A node restricted to no value can never be sampled, fail before backtracking through the network
+	for _, possibilities := range valuePossibilities {
+		if possibilities != nil && len(possibilities) == 0 {
+			return nil, false
+		}
+	}
+
+	// Only collect backtracking statistics when they are logged
+	var stats *backtrackStats
+	if debugEnabled(bn.logger) {
+		stats = &backtrackStats{}
+	}
+	frontiers := bn.frontiers
+	if frontiers == nil {
+		frontiers = bn.computeFrontiers()
+	}
+	search := &consistentSearch{
+		valuePossibilities: valuePossibilities,
+		frontiers:          frontiers,
+		infeasible:         make(map[string]bool),
+		stats:              stats,
+		limit:              limit,
+	}
+	sample, ok := bn.recursivelyGenerateConsistentSampleWhenPossible(make(map[string]string), 0, search)
+	if stats != nil && (stats.bans > 0 || !ok) {
+		bn.logger.Debug("constrained sampling backtracked",
+			"success", ok,
+			"bans", stats.bans,
+			"deadEnds", stats.deadEnds,
+			"pruned", stats.pruned,
+			"banned", stats.banned,
+			"budgetExhausted", limit.exhausted(),
+		)
+	}
+	return sample, ok
+}
+
+// consistentSearch is the state of a constrained sampling shared by the recursion
+type consistentSearch struct {
+	valuePossibilities map[string][]string
+	frontiers          [][]string
+	// infeasible holds the partial samples known to have no consistent completion, keyed by infeasibleKey,
+	// so dead branches are not explored again under other values of unrelated nodes
+	infeasible map[string]bool
+	stats      *backtrackStats
+	limit      *samplingLimit
+}
+
+// infeasibleKey identifies the nodes left to sample at depth by the frontier values they depend on
+func (s *consistentSearch) infeasibleKey(depth int, sampleSoFar map[string]string) string {
+	var b strings.Builder
+	b.WriteString(strconv.Itoa(depth))
+	for _, name := range s.frontiers[depth] {
+		b.WriteByte(0)
+		b.WriteString(sampleSoFar[name])
+	}
+	return b.String()
+}
+
+// recursivelyGenerateConsistentSampleWhenPossible samples the nodes from depth on, backtracking through the values
+// of a node when the nodes after it cannot be sampled. Failures are memoized on the frontier values, so heavily
+// constrained samplings stay linear in the dead branches instead of exploring them again for every combination of
+// the values sampled in between.
 func (bn *bayesianNetwork) recursivelyGenerateConsistentSampleWhenPossible(
 	sampleSoFar map[string]string,
-	valuePossibilities map[string][]string,
 	depth int,
+	search *consistentSearch,
 ) (map[string]string, bool) {
 	if depth == len(bn.NodesInSamplingOrder) {
 		return sampleSoFar, true
 	}
 
+	// Keys are only built once a branch failed, sampling without backtracking does not pay for them
+	if len(search.infeasible) > 0 && search.infeasible[search.infeasibleKey(depth, sampleSoFar)] {
+		search.stats.prune()
+		return nil, false
+	}
+
 	node := bn.NodesInSamplingOrder[depth]
 	bannedValues := make([]string, 0)
+	src := bn.tracer.source(bn.random)
 
 	for {
-		possibilities := valuePossibilities[node.Name]
+		if search.limit.exhausted() {
+			return nil, false
+		}
+		possibilities := search.valuePossibilities[node.Name]
 		if possibilities == nil {
 			possibilities = node.PossibleValues
 		}
 
 		sampleValue, ok := node.sampleAccordingToRestrictions(
+			src,
 			sampleSoFar,
 			possibilities,
 			bannedValues,
 		)
 
 		if !ok {
+			search.stats.deadEnd()
 			break
 		}
 
+		step := bn.tracer.record(node, sampleValue, sampleSoFar, src)
 		sampleSoFar[node.Name] = sampleValue
-		nextSample, success := bn.recursivelyGenerateConsistentSampleWhenPossible(
-			sampleSoFar,
-			valuePossibilities,
-			depth+1,
-		)
+		nextSample, success := bn.recursivelyGenerateConsistentSampleWhenPossible(sampleSoFar, depth+1, search)
 
 		if success {
 			return nextSample, true
 		}
 
+		search.stats.ban(node.Name, sampleValue)
+		bn.tracer.reject(step)
+		search.limit.backtrack()
 		bannedValues = append(bannedValues, sampleValue)
 		delete(sampleSoFar, node.Name)
 	}
 
+	// Every value was tried, the nodes left cannot be sampled given the frontier
+	search.infeasible[search.infeasibleKey(depth, sampleSoFar)] = true
 	return nil, false
 }
 
@@ -222,44 +389,18 @@ func (bn *bayesianNetwork) getProbability(nodeName string, value string, evidenc
 		return 0.0
 	}
 
-	// If no parents, return marginal probability
-	if len(node.parents) == 0 {
-		if prob, ok := node.ConditionalProbs[value].(float64); ok {
-			return prob
-		}
-		return 0.0
-	}
-
-	// Get parent values from evidence
-	parentValues := make([]string, len(node.parents))
-	for i, parent := range node.parents {
-		if val, exists := evidence[parent.Name]; exists {
-			parentValues[i] = val
-		} else {
-			return 0.0 // Missing evidence for parent
+	// Navigate through the conditional probability table, every parent must be known
+	table := node.table
+	for _, parent := range node.parents {
+		parentValue, exists := evidence[parent.Name]
+		if !exists || table == nil || table.deeper == nil {
+			return 0.0
 		}
+		table = table.deeper[parentValue]
 	}
 
-	// Navigate through conditional probability structure
-	current := node.ConditionalProbs
-	for _, parentValue := range parentValues {
-		if deeper, ok := current["deeper"].(map[string]interface{}); ok {
-			if next, exists := deeper[parentValue]; exists {
-				current = next.(map[string]interface{})
-			} else {
-				return 0.0 // Invalid parent value
-			}
-		} else {
-			return 0.0 // Invalid structure
-		}
-	}
-
-	// Get the final probability
-	if prob, ok := current[value].(float64); ok {
-		return prob
-	}
-
-	return 0.0
+	probability, _ := table.probability(node, value)
+	return probability
 }
 
 // infer calculates the probability distribution for a node given evidence
@@ -276,3 +417,31 @@ func (bn *bayesianNetwork) infer(nodeName string, evidence map[string]string) ma
 
 	return distribution
 }
+
+// parentValuesLeadingTo returns the values of a parent for which the node can take a value accepted by the predicate
+func (n *node) parentValuesLeadingTo(parentName string, accept func(string) bool) map[string]bool {
+	result := make(map[string]bool)
+	var walk func(table *probabilityTable, depth int, parentValue string)
+	walk = func(table *probabilityTable, depth int, parentValue string) {
+		if table.deeper == nil {
+			for i, index := range table.values {
+				if table.probabilities[i] > 0 && accept(n.values[index]) {
+					result[parentValue] = true
+					return
+				}
+			}
+			return
+		}
+		for key, next := range table.deeper {
+			if n.ParentNames[depth] == parentName {
+				walk(next, depth+1, key)
+			} else {
+				walk(next, depth+1, parentValue)
+			}
+		}
+	}
+	if n.table != nil {
+		walk(n.table, 0, "")
+	}
+	return result
+}
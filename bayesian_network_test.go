@@ -1,6 +1,13 @@
 package forgeron
 
 import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -48,6 +55,9 @@ func createTestNetwork() *bayesianNetwork {
 	nodeB.parents = []*node{nodeA}
 	nodeA.children = []*node{nodeB}
 
+	if err := network.compile(); err != nil {
+		panic(err)
+	}
 	return network
 }
 
@@ -99,14 +109,14 @@ func TestSample(t *testing.T) {
 
 	// Test sampling from node A
 	parentValues := map[string]string{}
-	value := network.NodesByName["A"].sample(parentValues)
+	value := network.NodesByName["A"].sample(nil, parentValues)
 	if value != "a1" && value != "a2" {
 		t.Errorf("sample() returned invalid value: %v", value)
 	}
 
 	// Test sampling from node B given A=a1
 	parentValues = map[string]string{"A": "a1"}
-	value = network.NodesByName["B"].sample(parentValues)
+	value = network.NodesByName["B"].sample(nil, parentValues)
 	if value != "b1" && value != "b2" {
 		t.Errorf("sample() returned invalid value: %v", value)
 	}
@@ -163,3 +173,115 @@ func TestGenerateConsistentSampleWhenPossible(t *testing.T) {
 		t.Error("Should fail with impossible restrictions")
 	}
 }
+
+func TestGenerateConsistentSampleWithEmptyRestriction(t *testing.T) {
+	network := createTestNetwork()
+
+	if _, success := network.generateConsistentSampleWhenPossible(map[string][]string{"B": {}}); success {
+		t.Error("Should fail when a node is restricted to no value")
+	}
+}
+
+// createChainNetwork creates a network whose last node Z only takes z2 when the first node A is a2, with n unrelated
+// binary nodes sampled in between
+func createChainNetwork(n int) *bayesianNetwork {
+	network := newBayesianNetwork()
+	add := func(node *node) {
+		network.NodesByName[node.Name] = node
+		network.NodesInSamplingOrder = append(network.NodesInSamplingOrder, node)
+	}
+	add(&node{Name: "A", PossibleValues: []string{"a1", "a2"}, ConditionalProbs: map[string]interface{}{"a1": 0.99, "a2": 0.01}})
+	for i := 0; i < n; i++ {
+		add(&node{
+			Name:             fmt.Sprintf("B%d", i),
+			PossibleValues:   []string{"b1", "b2"},
+			ConditionalProbs: map[string]interface{}{"b1": 0.5, "b2": 0.5},
+		})
+	}
+	add(&node{
+		Name:           "Z",
+		ParentNames:    []string{"A"},
+		PossibleValues: []string{"z1", "z2"},
+		ConditionalProbs: map[string]interface{}{"deeper": map[string]interface{}{
+			"a1": map[string]interface{}{"z1": 1.0},
+			"a2": map[string]interface{}{"z2": 1.0},
+		}},
+	})
+	if err := network.compile(); err != nil {
+		panic(err)
+	}
+	return network
+}
+
+func TestComputeFrontiers(t *testing.T) {
+	frontiers := createChainNetwork(2).computeFrontiers()
+	want := [][]string{nil, {"A"}, {"A"}, {"A"}, nil}
+	if !reflect.DeepEqual(frontiers, want) {
+		t.Errorf("computeFrontiers() = %v, want %v", frontiers, want)
+	}
+}
+
+// TestConsistentSamplingMemoizesDeadBranches checks a dead branch is explored once rather than once per combination
+// of the values sampled before it: without memoization, finding that A must be a2 takes 2^30 backtracks
+func TestConsistentSamplingMemoizesDeadBranches(t *testing.T) {
+	network := createChainNetwork(30)
+	for i := 0; i < 20; i++ {
+		limit := samplingBudget{maxBacktracks: 100}.start()
+		sample, ok := network.generateConsistentSampleWithinLimit(map[string][]string{"Z": {"z2"}}, limit)
+		if !ok {
+			t.Fatal("Failed to generate a consistent sample within 100 backtracks")
+		}
+		if sample["A"] != "a2" || sample["Z"] != "z2" {
+			t.Fatalf("sample = %v, want A=a2 and Z=z2", sample)
+		}
+	}
+}
+
+func TestDecodeNetwork(t *testing.T) {
+	for _, file := range []string{headerNetworkFile, inputNetworkFile, fingerprintNetworkFile} {
+		zipData, err := fs.ReadFile(embeddedData(), file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		zipReader, err := zip.NewReader(bytes.NewReader(zipData), int64(len(zipData)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		definition, err := zipReader.File[0].Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(definition)
+		definition.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		loaded, decoded := newBayesianNetwork(), newBayesianNetwork()
+		if err := loaded.loadNetwork(data); err != nil {
+			t.Fatalf("loadNetwork(%s) error = %v", file, err)
+		}
+		if err := decoded.decodeNetwork(bytes.NewReader(data)); err != nil {
+			t.Fatalf("decodeNetwork(%s) error = %v", file, err)
+		}
+		if len(decoded.NodesInSamplingOrder) != len(loaded.NodesInSamplingOrder) {
+			t.Fatalf("%s: decoded %d nodes, want %d", file, len(decoded.NodesInSamplingOrder), len(loaded.NodesInSamplingOrder))
+		}
+		for i, want := range loaded.NodesInSamplingOrder {
+			got := decoded.NodesInSamplingOrder[i]
+			if got.Name != want.Name || !reflect.DeepEqual(got.values, want.values) || !reflect.DeepEqual(got.table, want.table) ||
+				len(got.parents) != len(want.parents) || len(got.children) != len(want.children) {
+				t.Errorf("%s: decoded node %s differs from the loaded one", file, want.Name)
+			}
+		}
+		if !reflect.DeepEqual(decoded.frontiers, loaded.frontiers) {
+			t.Errorf("%s: decoded frontiers differ from the loaded ones", file)
+		}
+	}
+
+	for _, data := range []string{`[]`, `{"nodes": {}}`, `{"nodes": [{"name": 1}]}`, `{"nodes": [`} {
+		if err := newBayesianNetwork().decodeNetwork(strings.NewReader(data)); err == nil {
+			t.Errorf("decodeNetwork(%s) error = nil", data)
+		}
+	}
+}
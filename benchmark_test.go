@@ -0,0 +1,125 @@
+package forgeron
+
+import "testing"
+
+// benchmarkConstraints are representative header constraints used by the benchmarks
+var benchmarkConstraints = []struct {
+	name        string
+	constraints HeaderConstraints
+}{
+	{"defaults", HeaderConstraints{}},
+	{"browsers", HeaderConstraints{Browsers: []Browser{"chrome", "firefox"}, OS: []OS{"windows"}}},
+	{"specs", HeaderConstraints{BrowserSpecs: []*BrowserSpec{{Name: "chrome", MinVersion: 130}, {Name: "safari", HTTPVersion: "2"}}}},
+}
+
+func BenchmarkPrepareConstraints(b *testing.B) {
+	gen, err := NewHeaderGenerator()
+	if err != nil {
+		b.Fatalf("NewHeaderGenerator() error = %v", err)
+	}
+	for _, bc := range benchmarkConstraints {
+		options, err := gen.mergeOptions(bc.constraints)
+		if err != nil {
+			b.Fatalf("mergeOptions() error = %v", err)
+		}
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := gen.prepareConstraints(options); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGenerateHeaders(b *testing.B) {
+	gen, err := NewHeaderGenerator()
+	if err != nil {
+		b.Fatalf("NewHeaderGenerator() error = %v", err)
+	}
+	for _, bc := range benchmarkConstraints {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := gen.GenerateHeaders(bc.constraints); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGenerate(b *testing.B) {
+	gen, err := NewFingerprintGenerator()
+	if err != nil {
+		b.Fatalf("NewFingerprintGenerator() error = %v", err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := gen.Generate(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGenerateConstrained(b *testing.B) {
+	gen, err := NewFingerprintGenerator()
+	if err != nil {
+		b.Fatalf("NewFingerprintGenerator() error = %v", err)
+	}
+	minWidth, maxWidth := 2560, 2560
+	for _, bc := range []struct {
+		name string
+		opts []FingerprintOption
+	}{
+		{"browser version os device", []FingerprintOption{WithHeaderConstraints(HeaderConstraints{
+			BrowserSpecs: []*BrowserSpec{{Name: Chrome, MinVersion: 140, MaxVersion: 142}},
+			OS:           []OS{Linux},
+			Devices:      []Device{Desktop},
+		})}},
+		{"screen", []FingerprintOption{WithHeaderConstraints(HeaderConstraints{
+			Browsers: []Browser{Firefox},
+			OS:       []OS{MacOS},
+		}), WithScreen(&Screen{MinWidth: &minWidth, MaxWidth: &maxWidth})}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := gen.Generate(bc.opts...); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkNewFingerprintGenerator(b *testing.B) {
+	for _, shared := range []bool{false, true} {
+		name := "own"
+		if shared {
+			name = "shared"
+		}
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := NewFingerprintGenerator(WithSharedNetworks(shared)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGenerateBatch(b *testing.B) {
+	gen, err := NewFingerprintGenerator()
+	if err != nil {
+		b.Fatalf("NewFingerprintGenerator() error = %v", err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := gen.GenerateBatch(100); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
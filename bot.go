@@ -0,0 +1,89 @@
+package forgeron
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// BotIdentity describes an honest crawler identity
+type BotIdentity struct {
+	// Name is the crawler product token, e.g. "ExampleBot"
+	Name string
+	// Version is the crawler version, e.g. "1.0"
+	Version string
+	// InfoURL is the page describing the crawler and how to opt out
+	InfoURL string
+	// Email is sent in the From header so site owners can reach the operator
+	Email string
+	// Rendering adds the Chrome token of a rendering crawler using a recent Chrome version
+	Rendering bool
+}
+
+// botNamePattern restricts bot names to valid product tokens
+var botNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Validate validates the bot identity
+func (b BotIdentity) Validate() error {
+	if !botNamePattern.MatchString(b.Name) {
+		return fmt.Errorf("invalid bot name '%s'", b.Name)
+	}
+	if b.Version != "" && strings.ContainsAny(b.Version, " ;()/") {
+		return fmt.Errorf("invalid bot version '%s'", b.Version)
+	}
+	if b.InfoURL == "" {
+		return fmt.Errorf("an info URL is required so site owners can identify the bot")
+	}
+	return nil
+}
+
+// userAgent builds the Googlebot-like user agent of the bot
+func (b BotIdentity) userAgent(chromeVersion string) string {
+	product := b.Name
+	if b.Version != "" {
+		product += "/" + b.Version
+	}
+	if chromeVersion == "" {
+		return fmt.Sprintf("Mozilla/5.0 (compatible; %s; +%s)", product, b.InfoURL)
+	}
+	return fmt.Sprintf("Mozilla/5.0 AppleWebKit/537.36 (KHTML, like Gecko; compatible; %s; +%s) Chrome/%s Safari/537.36",
+		product, b.InfoURL, chromeVersion)
+}
+
+// GenerateBotHeaders generates honest crawler headers that identify the bot instead of mimicking a browser, in the
+// HTTP/1 navigation order of Chrome, which crawlers are built on. Headers Chrome does not send, like From, follow.
+func (g *HeaderGenerator) GenerateBotHeaders(bot BotIdentity) (OrderedHeaders, error) {
+	if err := bot.Validate(); err != nil {
+		return nil, err
+	}
+
+	chromeVersion := ""
+	if bot.Rendering {
+		chromeVersion = g.latestChromeVersion()
+	}
+
+	headers := map[string]string{
+		"User-Agent":      bot.userAgent(chromeVersion),
+		"Accept":          "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+		"Accept-Encoding": "gzip, deflate, br",
+	}
+	if bot.Email != "" {
+		headers["From"] = bot.Email
+	}
+	return orderHeaders(headers, g.OrderFor(Chrome, HTTP1, Navigation)), nil
+}
+
+// latestChromeVersion returns one of the most recent Chrome versions in the data, formatted like Googlebot does
+func (g *HeaderGenerator) latestChromeVersion() string {
+	latest := 0
+	for _, browser := range g.uniqueBrowsers {
+		if browser.Name != nil && *browser.Name == "chrome" && len(browser.Version) > 0 && browser.Version[0] > latest {
+			latest = browser.Version[0]
+		}
+	}
+	if latest == 0 {
+		return ""
+	}
+	// Rendering crawlers lag a little behind the latest stable release
+	return fmt.Sprintf("%d.0.%d.%d", latest-randomIntn(g.random, 2), 6000+randomIntn(g.random, 1000), randomIntn(g.random, 200))
+}
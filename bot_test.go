@@ -0,0 +1,47 @@
+package forgeron
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestGenerateBotHeaders(t *testing.T) {
+	hgen, err := NewHeaderGenerator()
+	if err != nil {
+		t.Fatalf("NewHeaderGenerator() error = %v", err)
+	}
+
+	bot := BotIdentity{Name: "ExampleBot", Version: "1.0", InfoURL: "https://example.com/bot", Email: "bot@example.com"}
+	headers, err := hgen.GenerateBotHeaders(bot)
+	if err != nil {
+		t.Fatalf("GenerateBotHeaders() error = %v", err)
+	}
+	if want := "Mozilla/5.0 (compatible; ExampleBot/1.0; +https://example.com/bot)"; headers.Get("User-Agent") != want {
+		t.Errorf("User-Agent = %q, want %q", headers.Get("User-Agent"), want)
+	}
+	if headers.Get("From") != "bot@example.com" {
+		t.Errorf("From = %q, want bot@example.com", headers.Get("From"))
+	}
+	var names []string
+	for _, header := range headers {
+		names = append(names, header.Name)
+	}
+	if want := []string{"User-Agent", "Accept", "Accept-Encoding", "From"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("header order = %v, want %v", names, want)
+	}
+
+	bot.Rendering = true
+	headers, err = hgen.GenerateBotHeaders(bot)
+	if err != nil {
+		t.Fatalf("GenerateBotHeaders() error = %v", err)
+	}
+	pattern := regexp.MustCompile(`^Mozilla/5\.0 AppleWebKit/537\.36 \(KHTML, like Gecko; compatible; ExampleBot/1\.0; \+https://example\.com/bot\) Chrome/\d+\.0\.\d+\.\d+ Safari/537\.36$`)
+	if !pattern.MatchString(headers.Get("User-Agent")) {
+		t.Errorf("unexpected rendering User-Agent: %q", headers.Get("User-Agent"))
+	}
+
+	if _, err := hgen.GenerateBotHeaders(BotIdentity{Name: "Bad Bot", InfoURL: "https://example.com"}); err == nil {
+		t.Error("expected error for invalid bot name")
+	}
+}
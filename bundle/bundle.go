@@ -0,0 +1,75 @@
+// Package bundle exports everything a downstream system needs to impersonate one identity, so fingerprints
+// can be handed off to executors that are not written in Go.
+//
+// ExportProfile writes the following files to a directory:
+//
+//	fingerprint.json  the fingerprint
+//	injector.js       the script applying the fingerprint, to run before any page script
+//	headers.json      the headers in the order the browser sends them, as [{"name": ..., "value": ...}]
+//	tls.json          the TLS ClientHello of the browser
+//	h2.json           the HTTP/2 settings, window update and pseudo-header order of the browser
+//	launch-args.txt   the Chromium command line flags matching the fingerprint, one per line
+//
+// SaveReplay and LoadReplay store a Replay, the versioned record of an identity as it was sent, to debug blocks.
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ta0uf19/forgeron"
+	"github.com/ta0uf19/forgeron/injector"
+)
+
+// ExportProfile writes the profile bundle of the fingerprint to dir, creating it if needed.
+// The files hold a whole identity, so they are only readable by the current user.
+func ExportProfile(fp *forgeron.Fingerprint, dir string) error {
+	if fp == nil {
+		return fmt.Errorf("fingerprint is required")
+	}
+	script, err := injector.Script(fp)
+	if err != nil {
+		return err
+	}
+
+	files := map[string]any{
+		"fingerprint.json": fp,
+		"headers.json":     fp.OrderedHeaders(),
+		"tls.json":         fp.TLSFingerprint(),
+		"h2.json":          fp.HTTP2Profile(),
+	}
+	contents := map[string][]byte{
+		"injector.js":     []byte(script),
+		"launch-args.txt": []byte(strings.Join(LaunchArgs(fp), "\n") + "\n"),
+	}
+	for name, value := range files {
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", name, err)
+		}
+		contents[name] = append(data, '\n')
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create profile directory: %w", err)
+	}
+	for name, data := range contents {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// LaunchArgs returns the Chromium command line flags matching the window, language and user agent of the fingerprint
+func LaunchArgs(fp *forgeron.Fingerprint) []string {
+	return []string{
+		fmt.Sprintf("--window-size=%d,%d", fp.Screen.OuterWidth, fp.Screen.OuterHeight),
+		"--lang=" + fp.Navigator.Language,
+		"--user-agent=" + fp.Navigator.UserAgent,
+		"--disable-blink-features=AutomationControlled",
+	}
+}
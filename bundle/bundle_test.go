@@ -0,0 +1,74 @@
+package bundle
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ta0uf19/forgeron"
+	"github.com/ta0uf19/forgeron/forgerontest"
+)
+
+func TestExportProfile(t *testing.T) {
+	fp := forgerontest.Fingerprint()
+	dir := filepath.Join(t.TempDir(), "profile")
+	if err := ExportProfile(fp, dir); err != nil {
+		t.Fatalf("ExportProfile() error = %v", err)
+	}
+
+	for _, name := range []string{"fingerprint.json", "injector.js", "headers.json", "tls.json", "h2.json", "launch-args.txt"} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("missing %s: %v", name, err)
+		}
+		if info.Mode().Perm() != 0o600 {
+			t.Errorf("%s mode = %v, want 0600", name, info.Mode().Perm())
+		}
+	}
+
+	var exported forgeron.Fingerprint
+	readJSON(t, filepath.Join(dir, "fingerprint.json"), &exported)
+	if exported.Navigator.UserAgent != fp.Navigator.UserAgent {
+		t.Errorf("fingerprint.json user agent = %q, want %q", exported.Navigator.UserAgent, fp.Navigator.UserAgent)
+	}
+
+	var headers forgeron.OrderedHeaders
+	readJSON(t, filepath.Join(dir, "headers.json"), &headers)
+	if len(headers) != len(fp.Headers) || headers.Get("User-Agent") != fp.Navigator.UserAgent {
+		t.Errorf("headers.json = %v", headers)
+	}
+
+	var h2 forgeron.HTTP2Profile
+	readJSON(t, filepath.Join(dir, "h2.json"), &h2)
+	if h2.Akamai != fp.HTTP2Profile().Akamai {
+		t.Errorf("h2.json akamai = %q, want %q", h2.Akamai, fp.HTTP2Profile().Akamai)
+	}
+
+	args, err := os.ReadFile(filepath.Join(dir, "launch-args.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(args), "--user-agent="+fp.Navigator.UserAgent+"\n") {
+		t.Errorf("launch-args.txt = %s", args)
+	}
+}
+
+func TestExportProfileRequiresFingerprint(t *testing.T) {
+	if err := ExportProfile(nil, t.TempDir()); err == nil {
+		t.Error("ExportProfile(nil) error = nil")
+	}
+}
+
+// readJSON decodes a JSON file of the bundle
+func readJSON(t *testing.T, path string, v any) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("failed to decode %s: %v", filepath.Base(path), err)
+	}
+}
@@ -0,0 +1,169 @@
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ta0uf19/forgeron"
+)
+
+// ReplayVersion is the version of the replay format written by SaveReplay. LoadReplay reads it and every earlier
+// version, and refuses the files of newer releases.
+const ReplayVersion = 1
+
+// Replay is a full request identity as it was sent: the fingerprint, its headers in send order, the TLS ClientHello
+// and the HTTP/2 preface, with the seed and request it was generated from. The recorded values are replayed as they
+// are, never derived again, so a replay loaded by a newer forgeron sends byte-identical headers even when the data
+// or the header orders changed since.
+type Replay struct {
+	Version     int                   `json:"version"`
+	Fingerprint *forgeron.Fingerprint `json:"fingerprint"`
+	// Headers are the headers in the order they were sent, with the case they were sent with
+	Headers forgeron.OrderedHeaders `json:"headers"`
+	TLS     forgeron.TLSFingerprint `json:"tls"`
+	HTTP2   forgeron.HTTP2Profile   `json:"h2"`
+	// Seed and Request regenerate the identity, see Verify. Seed is zero and Request nil when unknown.
+	Seed    int64                     `json:"seed,omitempty"`
+	Request *forgeron.GenerateRequest `json:"request,omitempty"`
+}
+
+// NewReplay records the identity of the fingerprint, generated with request from a source seeded with seed, e.g. the
+// Seed of a FixedGenerator. Pass a zero seed and a nil request when the identity cannot be regenerated.
+func NewReplay(fp *forgeron.Fingerprint, seed int64, request *forgeron.GenerateRequest) (*Replay, error) {
+	if fp == nil {
+		return nil, fmt.Errorf("fingerprint is required")
+	}
+	return &Replay{
+		Version:     ReplayVersion,
+		Fingerprint: fp.Clone(),
+		Headers:     fp.OrderedHeaders(),
+		TLS:         fp.TLSFingerprint(),
+		HTTP2:       fp.HTTP2Profile(),
+		Seed:        seed,
+		Request:     request,
+	}, nil
+}
+
+// SaveReplay writes the replay to w as indented JSON
+func SaveReplay(w io.Writer, r *Replay) error {
+	if r == nil || r.Fingerprint == nil {
+		return fmt.Errorf("replay fingerprint is required")
+	}
+	saved := *r
+	saved.Version = ReplayVersion
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode replay: %w", err)
+	}
+	if _, err := w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write replay: %w", err)
+	}
+	return nil
+}
+
+// LoadReplay reads a replay written by SaveReplay
+func LoadReplay(r io.Reader) (*Replay, error) {
+	var replay Replay
+	if err := json.NewDecoder(r).Decode(&replay); err != nil {
+		return nil, fmt.Errorf("failed to decode replay: %w", err)
+	}
+	switch {
+	case replay.Version <= 0:
+		return nil, fmt.Errorf("replay has no format version")
+	case replay.Version > ReplayVersion:
+		return nil, fmt.Errorf("replay format version %d is newer than the supported version %d, upgrade forgeron", replay.Version, ReplayVersion)
+	case replay.Fingerprint == nil:
+		return nil, fmt.Errorf("replay has no fingerprint")
+	}
+	return &replay, nil
+}
+
+// SaveReplayFile writes the replay to path, only readable by the current user as it holds a whole identity
+func SaveReplayFile(path string, r *Replay) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create replay file: %w", err)
+	}
+	if err := SaveReplay(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadReplayFile reads a replay written by SaveReplayFile
+func LoadReplayFile(path string) (*Replay, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open replay file: %w", err)
+	}
+	defer f.Close()
+	return LoadReplay(f)
+}
+
+// Apply sets the recorded headers on the request in place, with their recorded names. The Host stays the one of
+// req.Host or the URL. With orderKeys, the recorded header and pseudo-header orders are set under
+// forgeron.HeaderOrderKey and forgeron.PHeaderOrderKey for fhttp clients, net/http fails on these keys.
+func (r *Replay) Apply(req *http.Request, orderKeys bool) {
+	if req.Header == nil {
+		req.Header = make(http.Header)
+	}
+	var order []string
+	for _, header := range r.Headers {
+		order = append(order, strings.ToLower(header.Name))
+		if strings.EqualFold(header.Name, "host") {
+			continue
+		}
+		for key := range req.Header {
+			if strings.EqualFold(key, header.Name) {
+				delete(req.Header, key)
+			}
+		}
+		req.Header[header.Name] = []string{header.Value}
+	}
+	if orderKeys {
+		req.Header[forgeron.HeaderOrderKey] = order
+		req.Header[forgeron.PHeaderOrderKey] = slices.Clone(r.HTTP2.PseudoHeaderOrder)
+	}
+}
+
+// Verify regenerates the identity with the provider from the recorded seed and request, and returns how the
+// regenerated headers, TLS and HTTP/2 fingerprints differ from the recorded ones, nil when this release
+// reproduces the replay. It tells whether a block followed a change of forgeron or of the target.
+func (r *Replay) Verify(provider forgeron.FingerprintProvider) ([]string, error) {
+	if r.Request == nil || r.Seed == 0 {
+		return nil, fmt.Errorf("replay has no seed and request to regenerate the identity from")
+	}
+	opts := append([]forgeron.FingerprintOption{forgeron.WithRandomSource(forgeron.NewSeededSource(r.Seed))}, r.Request.Options()...)
+	fp, err := provider.Generate(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to regenerate identity: %w", err)
+	}
+
+	var diffs []string
+	headers := fp.OrderedHeaders()
+	for i := range max(len(headers), len(r.Headers)) {
+		var got, want forgeron.HeaderKV
+		if i < len(headers) {
+			got = headers[i]
+		}
+		if i < len(r.Headers) {
+			want = r.Headers[i]
+		}
+		if got != want {
+			diffs = append(diffs, fmt.Sprintf("header %d: %s: %q, recorded %s: %q", i, got.Name, got.Value, want.Name, want.Value))
+		}
+	}
+	if got, want := fp.TLSFingerprint().JA4(), r.TLS.JA4(); got != want {
+		diffs = append(diffs, fmt.Sprintf("tls: JA4 %s, recorded %s", got, want))
+	}
+	if got, want := fp.HTTP2Profile().Akamai, r.HTTP2.Akamai; got != want {
+		diffs = append(diffs, fmt.Sprintf("h2: Akamai %s, recorded %s", got, want))
+	}
+	return diffs, nil
+}
@@ -0,0 +1,134 @@
+package bundle
+
+import (
+	"bytes"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ta0uf19/forgeron"
+	"github.com/ta0uf19/forgeron/forgerontest"
+)
+
+func TestReplayRoundTrip(t *testing.T) {
+	fp := forgerontest.Fingerprint()
+	replay, err := NewReplay(fp, 42, &forgeron.GenerateRequest{Constraints: forgeron.Constraints{HeaderConstraints: forgeron.HeaderConstraints{Browsers: []forgeron.Browser{forgeron.Chrome}}}})
+	if err != nil {
+		t.Fatalf("NewReplay() error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "replay.json")
+	if err := SaveReplayFile(path, replay); err != nil {
+		t.Fatalf("SaveReplayFile() error = %v", err)
+	}
+	loaded, err := LoadReplayFile(path)
+	if err != nil {
+		t.Fatalf("LoadReplayFile() error = %v", err)
+	}
+
+	if loaded.Version != ReplayVersion || loaded.Seed != 42 || loaded.Request == nil {
+		t.Errorf("LoadReplayFile() = version %d, seed %d, request %v", loaded.Version, loaded.Seed, loaded.Request)
+	}
+	if !reflect.DeepEqual(loaded.Headers, replay.Headers) {
+		t.Errorf("headers = %v, want %v", loaded.Headers, replay.Headers)
+	}
+	if !reflect.DeepEqual(loaded.TLS, replay.TLS) || !reflect.DeepEqual(loaded.HTTP2, replay.HTTP2) {
+		t.Errorf("transport = %+v %+v, want %+v %+v", loaded.TLS, loaded.HTTP2, replay.TLS, replay.HTTP2)
+	}
+	if loaded.Fingerprint.Navigator.UserAgent != fp.Navigator.UserAgent {
+		t.Errorf("user agent = %q, want %q", loaded.Fingerprint.Navigator.UserAgent, fp.Navigator.UserAgent)
+	}
+}
+
+func TestReplayApplyRecordedHeaders(t *testing.T) {
+	// Headers a release no longer generates or orders the same way are still replayed as recorded
+	replay := &Replay{
+		Version:     ReplayVersion,
+		Fingerprint: forgerontest.Fingerprint(),
+		Headers: forgeron.OrderedHeaders{
+			{Name: "sec-ch-ua", Value: `"Chromium";v="120"`},
+			{Name: "Host", Value: "recorded.example.com"},
+			{Name: "X-Legacy", Value: "1"},
+			{Name: "User-Agent", Value: "Recorded/1.0"},
+		},
+		HTTP2: forgeron.HTTP2Profile{PseudoHeaderOrder: []string{":method", ":authority", ":scheme", ":path"}},
+	}
+	var buf bytes.Buffer
+	if err := SaveReplay(&buf, replay); err != nil {
+		t.Fatalf("SaveReplay() error = %v", err)
+	}
+	loaded, err := LoadReplay(&buf)
+	if err != nil {
+		t.Fatalf("LoadReplay() error = %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com/", nil)
+	req.Header.Set("User-Agent", "Go-http-client/1.1")
+	loaded.Apply(req, true)
+
+	want := http.Header{
+		"sec-ch-ua":              {`"Chromium";v="120"`},
+		"X-Legacy":               {"1"},
+		"User-Agent":             {"Recorded/1.0"},
+		forgeron.HeaderOrderKey:  {"sec-ch-ua", "host", "x-legacy", "user-agent"},
+		forgeron.PHeaderOrderKey: {":method", ":authority", ":scheme", ":path"},
+	}
+	if !reflect.DeepEqual(req.Header, want) {
+		t.Errorf("Apply() headers = %v, want %v", req.Header, want)
+	}
+}
+
+func TestLoadReplayErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"newer version", `{"version": 2, "fingerprint": {}}`, "newer than the supported version"},
+		{"no version", `{"fingerprint": {}}`, "no format version"},
+		{"no fingerprint", `{"version": 1}`, "no fingerprint"},
+		{"not json", `version`, "failed to decode"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadReplay(strings.NewReader(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadReplay() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplayVerify(t *testing.T) {
+	gen, err := forgeron.NewFingerprintGenerator()
+	if err != nil {
+		t.Fatalf("NewFingerprintGenerator() error = %v", err)
+	}
+	request := forgeron.NewGenerateRequest(forgeron.WithConstraints(forgeron.Constraints{HeaderConstraints: forgeron.HeaderConstraints{Browsers: []forgeron.Browser{forgeron.Firefox}}}))
+	fixed, err := forgeron.NewFixedGenerator(gen, 7, request.Options()...)
+	if err != nil {
+		t.Fatalf("NewFixedGenerator() error = %v", err)
+	}
+	replay, err := NewReplay(fixed.Fingerprint(), fixed.Seed(), &request)
+	if err != nil {
+		t.Fatalf("NewReplay() error = %v", err)
+	}
+
+	diffs, err := replay.Verify(gen)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if len(diffs) != 0 {
+		t.Errorf("Verify() = %v, want no differences", diffs)
+	}
+
+	replay.Headers[0].Value = "tampered"
+	if diffs, _ := replay.Verify(gen); len(diffs) != 1 || !strings.Contains(diffs[0], "tampered") {
+		t.Errorf("Verify() = %v, want the tampered header", diffs)
+	}
+
+	if _, err := (&Replay{}).Verify(gen); err == nil {
+		t.Error("Verify() error = nil without seed and request")
+	}
+}
@@ -0,0 +1,46 @@
+package forgeron
+
+import (
+	"slices"
+	"strings"
+)
+
+// chromeOSToken identifies ChromeOS user agents
+const chromeOSToken = "CrOS"
+
+// networkOSValue maps a public OS name to the value recorded in the input network.
+// ChromeOS identities are recorded without an operating system.
+func networkOSValue(os OS) string {
+	if os == ChromeOS {
+		return missingValueToken
+	}
+	return string(os)
+}
+
+// isChromeOSUserAgent returns true for ChromeOS user agents
+func isChromeOSUserAgent(userAgent string) bool {
+	return strings.Contains(userAgent, chromeOSToken)
+}
+
+// applyChromeOSConsistency aligns platform values and fonts of ChromeOS fingerprints with the user agent
+func applyChromeOSConsistency(fp *Fingerprint) {
+	if !isChromeOSUserAgent(fp.Navigator.UserAgent) {
+		return
+	}
+
+	// ChromeOS reports a Linux platform to scripts and "Chrome OS" through client hints
+	fp.Navigator.Platform = "Linux x86_64"
+	fp.Navigator.OSCpu = nil
+	if fp.Navigator.UserAgentData != nil {
+		fp.Navigator.UserAgentData.Platform = "Chrome OS"
+	}
+	for k := range fp.Headers {
+		if strings.EqualFold(k, "sec-ch-ua-platform") {
+			fp.Headers[k] = `"Chrome OS"`
+		}
+	}
+
+	fp.Fonts = slices.DeleteFunc(fp.Fonts, func(font string) bool {
+		return slices.Contains(windowsOnlyFonts, font)
+	})
+}
@@ -0,0 +1,222 @@
+// Package client implements the forgeron provider interfaces on top of a remote forgeron service,
+// so applications can share a central fingerprint service instead of loading the networks in every process.
+//
+// The client talks JSON over HTTP:
+//
+//	POST /fingerprint  {"request": GenerateRequest, "count": n, "seed": s}  ->  {"fingerprints": [...]}
+//	POST /headers      {"constraints": HeaderConstraints, "seed": s}        ->  {"headers": {...}}
+//
+// A non-zero seed makes the service generate the same response for the same request. Errors are reported with
+// a non-2xx status and a {"error": "..."} body.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ta0uf19/forgeron"
+)
+
+// FingerprintRequest is the body of a /fingerprint request
+type FingerprintRequest struct {
+	Request forgeron.GenerateRequest `json:"request"`
+	Count   int                      `json:"count"`
+	Seed    int64                    `json:"seed,omitempty"`
+}
+
+// FingerprintResponse is the body of a /fingerprint response
+type FingerprintResponse struct {
+	Fingerprints []*forgeron.Fingerprint `json:"fingerprints"`
+}
+
+// HeadersRequest is the body of a /headers request
+type HeadersRequest struct {
+	Constraints forgeron.HeaderConstraints `json:"constraints"`
+	Seed        int64                      `json:"seed,omitempty"`
+}
+
+// HeadersResponse is the body of a /headers response
+type HeadersResponse struct {
+	Headers map[string]string `json:"headers"`
+}
+
+// ErrorResponse is the body of a failed request
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Client is a remote forgeron provider
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	batchSize  int
+	timeout    time.Duration
+	mu         sync.Mutex
+	cache      map[string][]*forgeron.Fingerprint
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used to reach the service
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBatchSize sets how many fingerprints are fetched per request, the extra ones are cached locally
+func WithBatchSize(size int) Option {
+	return func(c *Client) {
+		c.batchSize = size
+	}
+}
+
+// WithTimeout sets the timeout of requests made without a context
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// New creates a client for the forgeron service at baseURL
+func New(baseURL string, opts ...Option) (*Client, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("base URL is required")
+	}
+	c := &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				Proxy:               http.ProxyFromEnvironment,
+				MaxIdleConns:        100,
+				MaxIdleConnsPerHost: 100,
+				IdleConnTimeout:     90 * time.Second,
+			},
+		},
+		batchSize: 1,
+		timeout:   30 * time.Second,
+		cache:     make(map[string][]*forgeron.Fingerprint),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.batchSize <= 0 {
+		return nil, fmt.Errorf("batch size must be positive")
+	}
+	return c, nil
+}
+
+// Generate generates a fingerprint with the given options
+func (c *Client) Generate(opts ...forgeron.FingerprintOption) (*forgeron.Fingerprint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	return c.GenerateContext(ctx, opts...)
+}
+
+// GenerateContext generates a fingerprint with the given options, serving it from the local cache when possible
+func (c *Client) GenerateContext(ctx context.Context, opts ...forgeron.FingerprintOption) (*forgeron.Fingerprint, error) {
+	request := forgeron.NewGenerateRequest(opts...)
+	key, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+	if fp := c.cached(string(key)); fp != nil {
+		return fp, nil
+	}
+
+	var response FingerprintResponse
+	if err := c.post(ctx, "/fingerprint", FingerprintRequest{Request: request, Count: c.batchSize}, &response); err != nil {
+		return nil, err
+	}
+	if len(response.Fingerprints) == 0 {
+		return nil, fmt.Errorf("service returned no fingerprints")
+	}
+
+	if len(response.Fingerprints) > 1 {
+		c.mu.Lock()
+		c.cache[string(key)] = append(c.cache[string(key)], response.Fingerprints[1:]...)
+		c.mu.Unlock()
+	}
+	return response.Fingerprints[0], nil
+}
+
+// GenerateHeaders generates headers with the given constraints
+func (c *Client) GenerateHeaders(options forgeron.HeaderConstraints) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	return c.GenerateHeadersContext(ctx, options)
+}
+
+// GenerateHeadersContext generates headers with the given constraints
+func (c *Client) GenerateHeadersContext(ctx context.Context, options forgeron.HeaderConstraints) (map[string]string, error) {
+	var response HeadersResponse
+	if err := c.post(ctx, "/headers", HeadersRequest{Constraints: options}, &response); err != nil {
+		return nil, err
+	}
+	return response.Headers, nil
+}
+
+// cached pops a cached fingerprint for the request key. Drained keys are removed, so clients
+// generating with many different options do not keep an entry per request forever.
+func (c *Client) cached(key string) *forgeron.Fingerprint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fingerprints := c.cache[key]
+	if len(fingerprints) == 0 {
+		return nil
+	}
+	fp := fingerprints[0]
+	// Release the popped fingerprint, the backing array outlives it
+	fingerprints[0] = nil
+	if len(fingerprints) == 1 {
+		delete(c.cache, key)
+	} else {
+		c.cache[key] = fingerprints[1:]
+	}
+	return fp
+}
+
+// post sends a JSON request to the service and decodes the JSON response
+func (c *Client) post(ctx context.Context, path string, body any, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to %s failed: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var errResp ErrorResponse
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+		if json.Unmarshal(raw, &errResp) == nil && errResp.Error != "" {
+			return fmt.Errorf("service returned %d: %s", resp.StatusCode, errResp.Error)
+		}
+		return fmt.Errorf("service returned %d: %s", resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+var (
+	_ forgeron.HeaderProvider      = (*Client)(nil)
+	_ forgeron.FingerprintProvider = (*Client)(nil)
+)
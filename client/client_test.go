@@ -0,0 +1,128 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ta0uf19/forgeron"
+	"github.com/ta0uf19/forgeron/forgerontest"
+)
+
+// newTestServer serves the client protocol from the forgerontest fakes
+func newTestServer(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+	fingerprints := forgerontest.NewFingerprintGenerator()
+	headers := forgerontest.NewHeaderGenerator()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/fingerprint", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		var req FingerprintRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error()})
+			return
+		}
+		if len(req.Request.Constraints.Browsers) > 0 && req.Request.Constraints.Browsers[0] == "unknown" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: "unsupported browser"})
+			return
+		}
+		var resp FingerprintResponse
+		for i := 0; i < req.Count; i++ {
+			fp, _ := fingerprints.Generate(req.Request.Options()...)
+			resp.Fingerprints = append(resp.Fingerprints, fp)
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+	mux.HandleFunc("/headers", func(w http.ResponseWriter, r *http.Request) {
+		var req HeadersRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		h, _ := headers.GenerateHeaders(req.Constraints)
+		json.NewEncoder(w).Encode(HeadersResponse{Headers: h})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClientGenerateUsesLocalCache(t *testing.T) {
+	var requests atomic.Int32
+	server := newTestServer(t, &requests)
+	c, err := New(server.URL, WithBatchSize(3))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for i := 0; i < 4; i++ {
+		fp, err := c.Generate(forgeron.WithHeaderConstraints(forgeron.HeaderConstraints{Browsers: []forgeron.Browser{"chrome"}}))
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if fp.Navigator.UserAgent == "" {
+			t.Fatal("Generate() returned an empty user agent")
+		}
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("service received %d requests, want 2", got)
+	}
+
+	// Different options are cached separately
+	if _, err := c.Generate(forgeron.WithSlim(true)); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("service received %d requests, want 3", got)
+	}
+}
+
+func TestClientDropsDrainedCacheEntries(t *testing.T) {
+	var requests atomic.Int32
+	server := newTestServer(t, &requests)
+	c, err := New(server.URL, WithBatchSize(2))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for _, locale := range []string{"en-US", "de-DE", "fr-FR"} {
+		opt := forgeron.WithHeaderConstraints(forgeron.HeaderConstraints{Locales: []string{locale}})
+		for i := 0; i < 2; i++ {
+			if _, err := c.Generate(opt); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+		}
+	}
+	if len(c.cache) != 0 {
+		t.Errorf("cache holds %d drained entries, want 0", len(c.cache))
+	}
+}
+
+func TestClientGenerateHeaders(t *testing.T) {
+	server := newTestServer(t, new(atomic.Int32))
+	c, err := New(server.URL)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	headers, err := c.GenerateHeaders(forgeron.HeaderConstraints{Browsers: []forgeron.Browser{"chrome"}})
+	if err != nil {
+		t.Fatalf("GenerateHeaders() error = %v", err)
+	}
+	if headers["User-Agent"] == "" {
+		t.Error("missing User-Agent header")
+	}
+}
+
+func TestClientReportsServiceErrors(t *testing.T) {
+	server := newTestServer(t, new(atomic.Int32))
+	c, err := New(server.URL)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	_, err = c.Generate(forgeron.WithHeaderConstraints(forgeron.HeaderConstraints{Browsers: []forgeron.Browser{"unknown"}}))
+	if err == nil || !strings.Contains(err.Error(), "unsupported browser") {
+		t.Errorf("Generate() error = %v, want the service error", err)
+	}
+}
@@ -0,0 +1,173 @@
+package forgeron
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// ClientHint is a high-entropy client hint header, which Chromium browsers only send once a server asks for it
+// with Accept-CH
+type ClientHint string
+
+// High-entropy client hints
+const (
+	HintFullVersionList ClientHint = "sec-ch-ua-full-version-list"
+	HintFullVersion     ClientHint = "sec-ch-ua-full-version"
+	HintArch            ClientHint = "sec-ch-ua-arch"
+	HintBitness         ClientHint = "sec-ch-ua-bitness"
+	HintModel           ClientHint = "sec-ch-ua-model"
+	HintPlatformVersion ClientHint = "sec-ch-ua-platform-version"
+)
+
+// SupportedClientHints lists the high-entropy client hints headers can be generated for
+var SupportedClientHints = []ClientHint{HintFullVersionList, HintFullVersion, HintArch, HintBitness, HintModel, HintPlatformVersion}
+
+// greaseChars and greaseVersions are the characters and versions Chromium builds its GREASE brand from
+var (
+	greaseChars    = []string{" ", "(", ":", "-", ".", "/", ")", ";", "=", "?", "_"}
+	greaseVersions = []string{"8", "99", "24"}
+)
+
+// ParseAcceptCH returns the client hints requested by an Accept-CH header, ignoring the low-entropy hints sent
+// anyway and the hints forgeron does not generate
+func ParseAcceptCH(header string) []ClientHint {
+	var hints []ClientHint
+	for _, name := range strings.Split(header, ",") {
+		hint := ClientHint(strings.ToLower(strings.TrimSpace(name)))
+		if slices.Contains(SupportedClientHints, hint) && !slices.Contains(hints, hint) {
+			hints = append(hints, hint)
+		}
+	}
+	return hints
+}
+
+// ClientHintHeaders returns the lowercase client hint headers of the user agent data: Sec-CH-UA, Sec-CH-UA-Mobile
+// and Sec-CH-UA-Platform, sent with every request, and the requested high-entropy hints. Brands get the GREASE brand
+// Chromium adds when the data has none. High-entropy hints are left out when the data has no high-entropy values,
+// as with RealismLow. Nil data, from browsers without client hints, gives no headers.
+func ClientHintHeaders(data *UserAgentData, requested ...ClientHint) map[string]string {
+	if data == nil {
+		return nil
+	}
+	major := brandMajorVersion(data.Brands)
+	headers := map[string]string{
+		"sec-ch-ua":          formatBrands(greasedBrands(data.Brands, major, false)),
+		"sec-ch-ua-mobile":   structuredBool(data.Mobile),
+		"sec-ch-ua-platform": structuredString(data.Platform),
+	}
+	if data.UAFullVersion == "" {
+		return headers
+	}
+	for _, hint := range requested {
+		switch hint {
+		case HintFullVersionList:
+			headers[string(hint)] = formatBrands(greasedBrands(data.FullVersionList, major, true))
+		case HintFullVersion:
+			headers[string(hint)] = structuredString(data.UAFullVersion)
+		case HintArch:
+			headers[string(hint)] = structuredString(data.Architecture)
+		case HintBitness:
+			headers[string(hint)] = structuredString(data.Bitness)
+		case HintModel:
+			headers[string(hint)] = structuredString(data.Model)
+		case HintPlatformVersion:
+			headers[string(hint)] = structuredString(data.PlatformVersion)
+		}
+	}
+	return headers
+}
+
+// ClientHintHeaders returns the client hint headers of the fingerprint with the requested high-entropy hints, see
+// ClientHintHeaders
+func (f *Fingerprint) ClientHintHeaders(requested ...ClientHint) map[string]string {
+	return ClientHintHeaders(f.Navigator.UserAgentData, requested...)
+}
+
+// applyClientHintConsistency sets the low-entropy client hint headers from the user agent data, which the headers
+// are sampled independently of, e.g. the brands of Brave with the headers of Chrome. Fingerprints without user agent
+// data send no client hints.
+func applyClientHintConsistency(fp *Fingerprint) {
+	if fp.Navigator.UserAgentData == nil {
+		dropClientHints(fp.Headers)
+		return
+	}
+	for name, value := range fp.ClientHintHeaders() {
+		key := name
+		for existing := range fp.Headers {
+			if strings.EqualFold(existing, name) {
+				key = existing
+			}
+		}
+		fp.setHeader(key, value)
+	}
+}
+
+// greasedBrands returns the brands with the GREASE brand of the major version, placed where Chromium shuffles it,
+// unless they already have one
+func greasedBrands(brands []UserAgentBrand, major int, fullVersion bool) []UserAgentBrand {
+	if len(brands) == 0 || slices.ContainsFunc(brands, func(brand UserAgentBrand) bool { return isGreaseBrand(brand.Brand) }) {
+		return brands
+	}
+	grease := greaseBrand(major)
+	if fullVersion {
+		grease.Version += ".0.0.0"
+	}
+	// Chromium permutes the GREASE, Chromium and browser brands by the major version, GREASE going first for
+	// 0 and 1, second for 2 and 3, last for 4 and 5
+	at := min(major%6/2, len(brands))
+	return slices.Insert(slices.Clone(brands), at, grease)
+}
+
+// greaseBrand returns the GREASE brand Chromium sends for a major version, e.g. "Not(A:Brand" version 8 for 144
+func greaseBrand(major int) UserAgentBrand {
+	return UserAgentBrand{
+		Brand:   fmt.Sprintf("Not%sA%sBrand", greaseChars[major%len(greaseChars)], greaseChars[(major+1)%len(greaseChars)]),
+		Version: greaseVersions[major%len(greaseVersions)],
+	}
+}
+
+// isGreaseBrand reports whether a brand is a GREASE brand, e.g. "Not(A:Brand" or the older "Not_A Brand",
+// " Not A;Brand" and ";Not A Brand", whatever the punctuation and spaces around the words
+func isGreaseBrand(brand string) bool {
+	letters := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) {
+			return r
+		}
+		return -1
+	}, brand)
+	return letters == "NotABrand"
+}
+
+// brandMajorVersion returns the major version of the Chromium brand, or of the first brand that is not GREASE
+func brandMajorVersion(brands []UserAgentBrand) int {
+	major := 0
+	for _, brand := range brands {
+		if isGreaseBrand(brand.Brand) {
+			continue
+		}
+		var version int
+		fmt.Sscanf(brand.Version, "%d", &version)
+		if brand.Brand == "Chromium" {
+			return version
+		}
+		if major == 0 {
+			major = version
+		}
+	}
+	return major
+}
+
+// structuredBool formats a structured header boolean
+func structuredBool(b bool) string {
+	if b {
+		return "?1"
+	}
+	return "?0"
+}
+
+// structuredString formats a structured header string
+func structuredString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,171 @@
+package forgeron
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestGreaseBrand(t *testing.T) {
+	tests := []struct {
+		major int
+		want  UserAgentBrand
+	}{
+		{131, UserAgentBrand{Brand: "Not_A Brand", Version: "24"}},
+		{144, UserAgentBrand{Brand: "Not(A:Brand", Version: "8"}},
+		{145, UserAgentBrand{Brand: "Not:A-Brand", Version: "99"}},
+	}
+	for _, tt := range tests {
+		if got := greaseBrand(tt.major); got != tt.want {
+			t.Errorf("greaseBrand(%d) = %+v, want %+v", tt.major, got, tt.want)
+		}
+	}
+}
+
+func TestIsGreaseBrand(t *testing.T) {
+	for _, brand := range []string{"Not(A:Brand", "Not_A Brand", "Not A(Brand", " Not A;Brand", ";Not A Brand", "Not/A)Brand"} {
+		if !isGreaseBrand(brand) {
+			t.Errorf("isGreaseBrand(%q) = false", brand)
+		}
+	}
+	for _, brand := range []string{"Google Chrome", "Chromium", "Microsoft Edge", "Notable Brand"} {
+		if isGreaseBrand(brand) {
+			t.Errorf("isGreaseBrand(%q) = true", brand)
+		}
+	}
+}
+
+func TestParseAcceptCH(t *testing.T) {
+	got := ParseAcceptCH("Sec-CH-UA-Arch, sec-ch-ua-platform, Sec-CH-UA-Full-Version-List, Sec-CH-UA-Arch, Viewport-Width")
+	want := []ClientHint{HintArch, HintFullVersionList}
+	if !slices.Equal(got, want) {
+		t.Errorf("ParseAcceptCH() = %v, want %v", got, want)
+	}
+}
+
+func TestClientHintHeaders(t *testing.T) {
+	data := &UserAgentData{
+		Brands:          []UserAgentBrand{{Brand: "Google Chrome", Version: "131"}, {Brand: "Chromium", Version: "131"}},
+		Platform:        "Windows",
+		Architecture:    "x86",
+		Bitness:         "64",
+		FullVersionList: []UserAgentBrand{{Brand: "Google Chrome", Version: "131.0.6778.86"}, {Brand: "Chromium", Version: "131.0.6778.86"}},
+		PlatformVersion: "15.0.0",
+		UAFullVersion:   "131.0.6778.86",
+	}
+
+	tests := []struct {
+		name      string
+		data      *UserAgentData
+		requested []ClientHint
+		want      map[string]string
+	}{
+		{
+			name: "low entropy with grease",
+			data: data,
+			want: map[string]string{
+				"sec-ch-ua":          `"Google Chrome";v="131", "Chromium";v="131", "Not_A Brand";v="24"`,
+				"sec-ch-ua-mobile":   "?0",
+				"sec-ch-ua-platform": `"Windows"`,
+			},
+		},
+		{
+			name:      "requested high entropy",
+			data:      data,
+			requested: []ClientHint{HintFullVersionList, HintArch, HintBitness, HintModel, HintPlatformVersion},
+			want: map[string]string{
+				"sec-ch-ua":                   `"Google Chrome";v="131", "Chromium";v="131", "Not_A Brand";v="24"`,
+				"sec-ch-ua-mobile":            "?0",
+				"sec-ch-ua-platform":          `"Windows"`,
+				"sec-ch-ua-full-version-list": `"Google Chrome";v="131.0.6778.86", "Chromium";v="131.0.6778.86", "Not_A Brand";v="24.0.0.0"`,
+				"sec-ch-ua-arch":              `"x86"`,
+				"sec-ch-ua-bitness":           `"64"`,
+				"sec-ch-ua-model":             `""`,
+				"sec-ch-ua-platform-version":  `"15.0.0"`,
+			},
+		},
+		{
+			name:      "no high entropy values",
+			data:      &UserAgentData{Brands: data.Brands, Mobile: true, Platform: "Android"},
+			requested: []ClientHint{HintArch},
+			want: map[string]string{
+				"sec-ch-ua":          `"Google Chrome";v="131", "Chromium";v="131", "Not_A Brand";v="24"`,
+				"sec-ch-ua-mobile":   "?1",
+				"sec-ch-ua-platform": `"Android"`,
+			},
+		},
+		{
+			name: "legacy grease",
+			data: &UserAgentData{Brands: []UserAgentBrand{{Brand: " Not A;Brand", Version: "99"}, {Brand: "Chromium", Version: "90"}, {Brand: "Google Chrome", Version: "90"}}, Platform: "Windows"},
+			want: map[string]string{
+				"sec-ch-ua":          `" Not A;Brand";v="99", "Chromium";v="90", "Google Chrome";v="90"`,
+				"sec-ch-ua-mobile":   "?0",
+				"sec-ch-ua-platform": `"Windows"`,
+			},
+		},
+		{
+			name: "legacy grease last",
+			data: &UserAgentData{Brands: []UserAgentBrand{{Brand: "Google Chrome", Version: "143"}, {Brand: "Chromium", Version: "143"}, {Brand: ";Not A Brand", Version: "99"}}, Platform: "Windows"},
+			want: map[string]string{
+				"sec-ch-ua":          `"Google Chrome";v="143", "Chromium";v="143", ";Not A Brand";v="99"`,
+				"sec-ch-ua-mobile":   "?0",
+				"sec-ch-ua-platform": `"Windows"`,
+			},
+		},
+		{
+			name: "no user agent data",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClientHintHeaders(tt.data, tt.requested...)
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("ClientHintHeaders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateClientHintHeaders(t *testing.T) {
+	gen := newGeneratorOrFatal(t, WithRandomSource(NewSeededSource(3)))
+	for range 20 {
+		fp, err := gen.Generate(WithHeaderConstraints(HeaderConstraints{Browsers: []Browser{Chrome}, OS: []OS{Windows, Android}}))
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		hints := fp.ClientHintHeaders(SupportedClientHints...)
+		// The generated hints agree with the ones of the recorded headers
+		for _, name := range []string{"sec-ch-ua", "sec-ch-ua-mobile", "sec-ch-ua-platform"} {
+			if recorded := headerValue(fp.Headers, name); recorded != hints[name] {
+				t.Errorf("%s = %q, recorded %q", name, hints[name], recorded)
+			}
+		}
+		if hints["sec-ch-ua-full-version"] != `"`+fp.Navigator.UserAgentData.UAFullVersion+`"` {
+			t.Errorf("sec-ch-ua-full-version = %q, want %q", hints["sec-ch-ua-full-version"], fp.Navigator.UserAgentData.UAFullVersion)
+		}
+	}
+}
+
+func TestApplyClientHintConsistency(t *testing.T) {
+	data := &UserAgentData{Brands: []UserAgentBrand{{Brand: "Chromium", Version: "144"}, {Brand: "Google Chrome", Version: "144"}}, Mobile: true, Platform: "Android"}
+	fp := &Fingerprint{
+		Navigator: NavigatorFingerprint{UserAgentData: data},
+		Headers:   map[string]string{"sec-ch-ua-platform": `"Windows"`, "accept": "*/*"},
+	}
+	applyClientHintConsistency(fp)
+	want := map[string]string{
+		"sec-ch-ua":          formatBrands(greasedBrands(data.Brands, 144, false)),
+		"sec-ch-ua-mobile":   "?1",
+		"sec-ch-ua-platform": `"Android"`,
+		"accept":             "*/*",
+	}
+	if !maps.Equal(fp.Headers, want) {
+		t.Errorf("headers = %v, want %v", fp.Headers, want)
+	}
+
+	fp = &Fingerprint{Headers: map[string]string{"Sec-CH-UA": `"Chromium";v="144"`, "sec-ch-ua-mobile": "?0", "Accept": "*/*"}}
+	applyClientHintConsistency(fp)
+	if !maps.Equal(fp.Headers, map[string]string{"Accept": "*/*"}) {
+		t.Errorf("headers = %v, want the client hints dropped without user agent data", fp.Headers)
+	}
+}
@@ -0,0 +1,337 @@
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// networkNode is the part of a network node the data files are derived from
+type networkNode struct {
+	Name           string   `json:"name"`
+	PossibleValues []string `json:"possibleValues"`
+}
+
+// capture is a request recorded from a real browser, one JSON object per line of the captures file
+type capture struct {
+	Browser     string   `json:"browser"`
+	HTTPVersion string   `json:"httpVersion"`
+	RequestType string   `json:"requestType"`
+	Headers     []string `json:"headers"`
+}
+
+// dataFiles are the data files derived from the networks and captures
+type dataFiles struct {
+	browserHelper []string
+	headersOrder  map[string][]string
+	fetchOrder    map[string]map[string][]string
+}
+
+// readNetworkNodes reads the nodes of a zipped network definition
+func readNetworkNodes(path string) ([]networkNode, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	if len(zipReader.File) == 0 {
+		return nil, fmt.Errorf("no files found in %s", path)
+	}
+	file, err := zipReader.File[0].Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open network in %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var network struct {
+		Nodes []networkNode `json:"nodes"`
+	}
+	if err := json.NewDecoder(file).Decode(&network); err != nil {
+		return nil, fmt.Errorf("failed to parse network in %s: %w", path, err)
+	}
+	return network.Nodes, nil
+}
+
+// findNode returns the named node
+func findNode(nodes []networkNode, name string) (networkNode, error) {
+	for _, node := range nodes {
+		if node.Name == name {
+			return node, nil
+		}
+	}
+	return networkNode{}, fmt.Errorf("network has no %s node", name)
+}
+
+// networkBrowsers returns the browser names of the *BROWSER node, e.g. chrome for chrome/144.0.0.0
+func networkBrowsers(nodes []networkNode) ([]string, error) {
+	node, err := findNode(nodes, "*BROWSER")
+	if err != nil {
+		return nil, err
+	}
+	var browsers []string
+	for _, value := range node.PossibleValues {
+		name, _, _ := strings.Cut(value, "/")
+		if name != "" && !slices.Contains(browsers, name) {
+			browsers = append(browsers, name)
+		}
+	}
+	sort.Strings(browsers)
+	return browsers, nil
+}
+
+// readCaptures reads the captured requests, skipping blank lines
+func readCaptures(r io.Reader) ([]capture, error) {
+	var captures []capture
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var c capture
+		if err := json.Unmarshal([]byte(text), &c); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		c.Browser = strings.ToLower(c.Browser)
+		if c.RequestType == "" {
+			c.RequestType = "navigation"
+		}
+		if c.Browser == "" || len(c.Headers) == 0 {
+			return nil, fmt.Errorf("line %d: browser and headers are required", line)
+		}
+		if c.HTTPVersion != "1" && c.HTTPVersion != "2" {
+			return nil, fmt.Errorf("line %d: unsupported http version '%s'", line, c.HTTPVersion)
+		}
+		if c.RequestType != "navigation" && c.RequestType != "fetch" {
+			return nil, fmt.Errorf("line %d: unsupported request type '%s'", line, c.RequestType)
+		}
+		captures = append(captures, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return captures, nil
+}
+
+// mergeOrders merges the header sequences of several captures into a single order.
+// A header goes before another when most captures sending both send it first, and
+// headers without a majority keep the order they were first seen in.
+func mergeOrders(sequences [][]string) []string {
+	var headers []string
+	firstSeen := make(map[string]int)
+	before := make(map[[2]string]int)
+	for _, sequence := range sequences {
+		for i, a := range sequence {
+			if _, ok := firstSeen[a]; !ok {
+				firstSeen[a] = len(headers)
+				headers = append(headers, a)
+			}
+			for _, b := range sequence[i+1:] {
+				before[[2]string{a, b}]++
+			}
+		}
+	}
+
+	// Place the header that most remaining headers must follow, until none is left
+	var order []string
+	remaining := slices.Clone(headers)
+	for len(remaining) > 0 {
+		best, bestLosses := 0, -1
+		for i, candidate := range remaining {
+			losses := 0
+			for _, other := range remaining {
+				if before[[2]string{other, candidate}] > before[[2]string{candidate, other}] {
+					losses++
+				}
+			}
+			if bestLosses < 0 || losses < bestLosses {
+				best, bestLosses = i, losses
+			}
+		}
+		order = append(order, remaining[best])
+		remaining = slices.Delete(remaining, best, best+1)
+	}
+	return order
+}
+
+// splitOrder splits a headers-order.json order into its HTTP/1 and HTTP/2 parts, the HTTP/2 part starting with the pseudo-headers
+func splitOrder(order []string) (http1, http2 []string) {
+	i := slices.IndexFunc(order, func(header string) bool { return strings.HasPrefix(header, ":") })
+	if i < 0 {
+		return order, nil
+	}
+	return order[:i], order[i:]
+}
+
+// derive builds the data files of dir from its networks, with the header orders of the captured browsers
+// replaced by the captured ones. It returns warnings about networks and orders drifting apart.
+func derive(dir string, captures []capture) (*dataFiles, []string, error) {
+	inputNodes, err := readNetworkNodes(filepath.Join(dir, "input-network-definition.zip"))
+	if err != nil {
+		return nil, nil, err
+	}
+	headerNodes, err := readNetworkNodes(filepath.Join(dir, "header-network-definition.zip"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The helper file lists the *BROWSER_HTTP values the input network samples
+	browserHTTP, err := findNode(inputNodes, "*BROWSER_HTTP")
+	if err != nil {
+		return nil, nil, err
+	}
+	files := &dataFiles{browserHelper: slices.Clone(browserHTTP.PossibleValues)}
+
+	if err := readJSON(filepath.Join(dir, "headers-order.json"), &files.headersOrder); err != nil {
+		return nil, nil, err
+	}
+	if err := readJSON(filepath.Join(dir, "headers-order-fetch.json"), &files.fetchOrder); err != nil {
+		return nil, nil, err
+	}
+	if files.headersOrder == nil {
+		files.headersOrder = make(map[string][]string)
+	}
+	if files.fetchOrder == nil {
+		files.fetchOrder = make(map[string]map[string][]string)
+	}
+	applyCaptures(files, captures)
+
+	browsers, err := networkBrowsers(headerNodes)
+	if err != nil {
+		return nil, nil, err
+	}
+	var missing, warnings []string
+	for _, browser := range browsers {
+		if len(files.headersOrder[browser]) == 0 {
+			missing = append(missing, browser)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, nil, fmt.Errorf("no header order for %s, capture their requests", strings.Join(missing, ", "))
+	}
+	for browser := range files.headersOrder {
+		if !slices.Contains(browsers, browser) {
+			warnings = append(warnings, fmt.Sprintf("header order of %s is unused, the header network has no %s", browser, browser))
+		}
+	}
+	warnings = append(warnings, uncoveredHeaders(headerNodes, files.headersOrder)...)
+	sort.Strings(warnings)
+	return files, warnings, nil
+}
+
+// applyCaptures replaces the header orders of the captured browsers, HTTP versions and request types
+func applyCaptures(files *dataFiles, captures []capture) {
+	type key struct{ browser, httpVersion, requestType string }
+	sequences := make(map[key][][]string)
+	for _, c := range captures {
+		k := key{c.Browser, c.HTTPVersion, c.RequestType}
+		sequences[k] = append(sequences[k], c.Headers)
+	}
+
+	for k, captured := range sequences {
+		order := mergeOrders(captured)
+		if k.requestType == "fetch" {
+			if files.fetchOrder[k.browser] == nil {
+				files.fetchOrder[k.browser] = make(map[string][]string)
+			}
+			files.fetchOrder[k.browser][k.httpVersion] = order
+			continue
+		}
+		http1, http2 := splitOrder(files.headersOrder[k.browser])
+		if k.httpVersion == "1" {
+			http1 = order
+		} else {
+			http2 = order
+		}
+		files.headersOrder[k.browser] = append(slices.Clone(http1), http2...)
+	}
+}
+
+// uncoveredHeaders returns warnings for the headers the network generates but no order places.
+// Lowercase nodes are HTTP/2 headers and the others HTTP/1 ones.
+func uncoveredHeaders(nodes []networkNode, orders map[string][]string) []string {
+	ordered := make(map[string]bool)
+	for _, order := range orders {
+		for _, header := range order {
+			ordered[header] = true
+		}
+	}
+	var warnings []string
+	for _, node := range nodes {
+		if strings.HasPrefix(node.Name, "*") || ordered[node.Name] {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("header %s is generated but missing from every order", node.Name))
+	}
+	return warnings
+}
+
+// readJSON decodes a JSON file, leaving v untouched when the file does not exist
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// encode returns the content of each data file, formatted like the committed files
+func (f *dataFiles) encode() (map[string][]byte, error) {
+	helper, err := json.Marshal(f.browserHelper)
+	if err != nil {
+		return nil, err
+	}
+	order, err := json.MarshalIndent(f.headersOrder, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	fetch, err := json.MarshalIndent(f.fetchOrder, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{
+		"browser-helper-file.json": helper,
+		"headers-order.json":       order,
+		"headers-order-fetch.json": fetch,
+	}, nil
+}
+
+// drifted returns the files whose content on disk differs from the derived one, comparing decoded JSON
+// so formatting changes alone do not count
+func drifted(dir string, files map[string][]byte) ([]string, error) {
+	var names []string
+	for name, want := range files {
+		var got, derived any
+		if err := readJSON(filepath.Join(dir, name), &got); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(want, &derived); err != nil {
+			return nil, err
+		}
+		gotJSON, _ := json.Marshal(got)
+		derivedJSON, _ := json.Marshal(derived)
+		if !bytes.Equal(gotJSON, derivedJSON) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// copyNetworks copies the embedded network definitions to a temporary data directory
+func copyNetworks(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range []string{"input-network-definition.zip", "header-network-definition.zip", "fingerprint-network-definition.zip", "headers-order.json", "headers-order-fetch.json"} {
+		data, err := os.ReadFile(filepath.Join("..", "..", "data_points", name))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestDataFilesInSync(t *testing.T) {
+	dir := filepath.Join("..", "..", "data_points")
+	files, _, err := derive(dir, nil)
+	if err != nil {
+		t.Fatalf("Failed to derive data files: %v", err)
+	}
+	encoded, err := files.encode()
+	if err != nil {
+		t.Fatalf("Failed to encode data files: %v", err)
+	}
+	changed, err := drifted(dir, encoded)
+	if err != nil {
+		t.Fatalf("Failed to compare data files: %v", err)
+	}
+	if len(changed) > 0 {
+		t.Errorf("Expected the data files to match the networks, %v drifted: run go generate", changed)
+	}
+	unstamped, err := stampNetworks(dir, "", true)
+	if err != nil {
+		t.Fatalf("Failed to check the network stamps: %v", err)
+	}
+	if len(unstamped) > 0 {
+		t.Errorf("Expected the networks to be stamped with the data schema, %v are not: run go generate", unstamped)
+	}
+}
+
+func TestMergeOrders(t *testing.T) {
+	tests := []struct {
+		name      string
+		sequences [][]string
+		want      []string
+	}{
+		{
+			name:      "single capture",
+			sequences: [][]string{{"Host", "User-Agent", "Accept"}},
+			want:      []string{"Host", "User-Agent", "Accept"},
+		},
+		{
+			name:      "optional headers are placed between their neighbours",
+			sequences: [][]string{{"Host", "User-Agent", "Accept"}, {"Host", "Cookie", "Accept"}, {"User-Agent", "Cookie"}},
+			want:      []string{"Host", "User-Agent", "Cookie", "Accept"},
+		},
+		{
+			name:      "majority wins",
+			sequences: [][]string{{"Accept", "Referer"}, {"Referer", "Accept"}, {"Referer", "Accept"}},
+			want:      []string{"Referer", "Accept"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeOrders(tt.sequences); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestReadCaptures(t *testing.T) {
+	captures, err := readCaptures(strings.NewReader(`{"browser": "Chrome", "httpVersion": "1", "headers": ["Host"]}
+
+{"browser": "chrome", "httpVersion": "2", "requestType": "fetch", "headers": [":method"]}`))
+	if err != nil {
+		t.Fatalf("Failed to read captures: %v", err)
+	}
+	want := []capture{
+		{Browser: "chrome", HTTPVersion: "1", RequestType: "navigation", Headers: []string{"Host"}},
+		{Browser: "chrome", HTTPVersion: "2", RequestType: "fetch", Headers: []string{":method"}},
+	}
+	if !reflect.DeepEqual(captures, want) {
+		t.Errorf("Expected %+v, got %+v", want, captures)
+	}
+
+	for _, line := range []string{
+		`{"httpVersion": "1", "headers": ["Host"]}`,
+		`{"browser": "chrome", "httpVersion": "3", "headers": ["Host"]}`,
+		`{"browser": "chrome", "httpVersion": "1", "requestType": "preflight", "headers": ["Host"]}`,
+		`{"browser": "chrome", "httpVersion": "1"}`,
+		`not json`,
+	} {
+		if _, err := readCaptures(strings.NewReader(line)); err == nil {
+			t.Errorf("Expected an error for %s", line)
+		}
+	}
+}
+
+func TestDeriveWithCaptures(t *testing.T) {
+	dir := copyNetworks(t)
+	captures := []capture{
+		{Browser: "firefox", HTTPVersion: "1", RequestType: "navigation", Headers: []string{"Host", "User-Agent", "Accept"}},
+		{Browser: "firefox", HTTPVersion: "2", RequestType: "fetch", Headers: []string{":method", ":path", "accept"}},
+	}
+	files, _, err := derive(dir, captures)
+	if err != nil {
+		t.Fatalf("Failed to derive data files: %v", err)
+	}
+
+	http1, http2 := splitOrder(files.headersOrder["firefox"])
+	if !reflect.DeepEqual(http1, captures[0].Headers) {
+		t.Errorf("Expected the captured HTTP/1 order %v, got %v", captures[0].Headers, http1)
+	}
+	if len(http2) == 0 || http2[0] != ":method" {
+		t.Errorf("Expected the HTTP/2 order to be kept, got %v", http2)
+	}
+	if got := files.fetchOrder["firefox"]["2"]; !reflect.DeepEqual(got, captures[1].Headers) {
+		t.Errorf("Expected the captured fetch order %v, got %v", captures[1].Headers, got)
+	}
+	if len(files.browserHelper) == 0 {
+		t.Error("Expected the browser helper to list the input network browsers")
+	}
+
+	if err := run(dir, "", "", false); err != nil {
+		t.Fatalf("Failed to write data files: %v", err)
+	}
+	if err := run(dir, "", "", true); err != nil {
+		t.Errorf("Expected written data files to pass the check, got %v", err)
+	}
+}
+
+func TestDeriveRequiresOrderForEveryBrowser(t *testing.T) {
+	dir := copyNetworks(t)
+	if err := os.WriteFile(filepath.Join(dir, "headers-order.json"), []byte(`{"chrome": ["Host"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err := derive(dir, nil)
+	if err == nil || !strings.Contains(err.Error(), "firefox") {
+		t.Errorf("Expected an error naming the browsers without order, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+// Command forgeron-data regenerates browser-helper-file.json and the header order files from the network
+// definitions, so the data files cannot drift out of sync with the networks.
+//
+// The helper file lists the browsers the input network samples. Networks do not record header order, so
+// the orders of headers-order.json and headers-order-fetch.json are kept, and replaced by the ones merged
+// from captured requests when -captures is set. Every line of the captures file is a request:
+//
+//	{"browser": "chrome", "httpVersion": "2", "requestType": "navigation", "headers": [":method", ":authority", ...]}
+//
+// The network definitions are stamped with the data schema version read by this forgeron release, so older releases
+// reject data they cannot read. -requires records the first release reading the schema in the stamp, which the
+// error of older releases points to.
+//
+// With -check the files are left untouched and the command fails when they differ from the derived ones.
+//
+// With -minimal the networks are pruned to the browsers of -browsers and written with the other data files to
+// the given directory, the dataset embedded by builds with the forgeron_minimal tag.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ta0uf19/forgeron"
+)
+
+func main() {
+	dir := flag.String("data", "data_points", "data directory holding the network definitions")
+	capturesPath := flag.String("captures", "", "NDJSON file of captured requests to derive the header orders from")
+	check := flag.Bool("check", false, "fail when the data files are out of sync instead of writing them")
+	requires := flag.String("requires", "", "first forgeron release reading the data schema, recorded in the network definitions")
+	minimal := flag.String("minimal", "", "directory to write the minimal dataset to")
+	browsers := flag.String("browsers", strings.Join(minimalBrowsers, ","), "comma separated *BROWSER_HTTP values kept in the minimal dataset")
+	flag.Parse()
+
+	err := run(*dir, *capturesPath, *requires, *check)
+	if err == nil && *minimal != "" && !*check {
+		err = buildMinimal(*dir, *minimal, parseBrowserList(*browsers))
+		if err == nil {
+			err = run(*minimal, "", *requires, false)
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run stamps the networks of dir and derives its data files, writing the ones that changed, or reports them with check
+func run(dir, capturesPath, requires string, check bool) error {
+	unstamped, err := stampNetworks(dir, requires, check)
+	if err != nil {
+		return err
+	}
+
+	var captures []capture
+	if capturesPath != "" {
+		file, err := os.Open(capturesPath)
+		if err != nil {
+			return fmt.Errorf("failed to open captures: %w", err)
+		}
+		defer file.Close()
+		if captures, err = readCaptures(file); err != nil {
+			return fmt.Errorf("failed to read captures: %w", err)
+		}
+	}
+
+	files, warnings, err := derive(dir, captures)
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, "warning:", warning)
+	}
+	encoded, err := files.encode()
+	if err != nil {
+		return err
+	}
+	changed, err := drifted(dir, encoded)
+	if err != nil {
+		return err
+	}
+
+	if check {
+		if len(unstamped) > 0 {
+			return fmt.Errorf("networks not stamped with data schema %d: %v, run go generate", forgeron.DataSchemaVersion, unstamped)
+		}
+		if len(changed) > 0 {
+			return fmt.Errorf("data files out of sync with the networks: %v, run go generate", changed)
+		}
+		return nil
+	}
+	for _, name := range changed {
+		if err := os.WriteFile(filepath.Join(dir, name), append(encoded[name], '\n'), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		fmt.Println("updated", name)
+	}
+	return nil
+}
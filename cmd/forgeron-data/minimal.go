@@ -0,0 +1,324 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// minimalBrowsers are the *BROWSER_HTTP values kept in the minimal dataset: the current major of each browser
+var minimalBrowsers = []string{
+	"chrome/144.0.0.0|2", "chrome/144.0.0.0|1",
+	"edge/144.0.0.0|2", "edge/144.0.0.0|1",
+	"firefox/147.0|2",
+	"safari/26.2|2", "safari/26.2|1",
+}
+
+// minimalFloor is the probability under which values are dropped from the minimal dataset, trimming the long tail
+// of rare screens, plugins and fonts that makes up most of the fingerprint network
+const minimalFloor = 0.01
+
+// minimalCopied are the data files copied as is to the minimal dataset
+var minimalCopied = []string{"headers-order.json", "headers-order-fetch.json", "sec-fetch-support.json"}
+
+// network is a Bayesian network definition, decoded generically so pruning keeps every field
+type network struct {
+	Nodes []map[string]any `json:"nodes"`
+	// comment is the zip comment of the definition, stamping it with the data schema
+	comment string
+}
+
+// readNetwork reads a zipped network definition
+func readNetwork(path string) (*network, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	if len(zipReader.File) == 0 {
+		return nil, fmt.Errorf("no files found in %s", path)
+	}
+	file, err := zipReader.File[0].Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open network in %s: %w", path, err)
+	}
+	defer file.Close()
+	n := network{comment: zipReader.Comment}
+	if err := json.NewDecoder(file).Decode(&n); err != nil {
+		return nil, fmt.Errorf("failed to parse network in %s: %w", path, err)
+	}
+	return &n, nil
+}
+
+// writeNetwork writes a zipped network definition stamped with the data schema, with a fixed timestamp so
+// regenerating it is reproducible
+func writeNetwork(path string, n *network) error {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	zipWriter := zip.NewWriter(&buf)
+	file, err := zipWriter.CreateHeader(&zip.FileHeader{Name: "network.json", Method: zip.Deflate})
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		return err
+	}
+	if err := zipWriter.SetComment(schemaComment(commentRequires(n.comment))); err != nil {
+		return err
+	}
+	if err := zipWriter.Close(); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// possibleValues returns the possible values of a node
+func (n *network) possibleValues(name string) []string {
+	for _, node := range n.Nodes {
+		if node["name"] == name {
+			var values []string
+			for _, value := range node["possibleValues"].([]any) {
+				values = append(values, value.(string))
+			}
+			return values
+		}
+	}
+	return nil
+}
+
+// prune restricts the nodes of the network to the allowed values and drops the values less likely than floor,
+// then drops every value that can no longer be sampled given the values left to its parents. The floor does not
+// apply to the allowed nodes nor to the *-prefixed ones constraints are set on. Nodes are listed parents first,
+// so a single pass is enough. Probabilities left are normalized again. Branches left empty and skip branches,
+// used for parent values missing from a node, are replaced by the average of the branches left, so the sampler
+// neither fails on a missing parent value nor brings pruned values back.
+func (n *network) prune(allowed map[string][]string, floor float64) {
+	remaining := make(map[string][]string)
+	for _, node := range n.Nodes {
+		name := node["name"].(string)
+		var parents []string
+		for _, parent := range node["parentNames"].([]any) {
+			parents = append(parents, parent.(string))
+		}
+		var values []string
+		probabilities, _ := node["conditionalProbabilities"].(map[string]any)
+		nodeFloor := floor
+		if strings.HasPrefix(name, "*") || allowed[name] != nil {
+			nodeFloor = 0
+		}
+		pruneProbabilities(probabilities, parents, remaining, allowed[name], nodeFloor, &values)
+
+		var possible []any
+		for _, value := range node["possibleValues"].([]any) {
+			if slices.Contains(values, value.(string)) {
+				possible = append(possible, value)
+			}
+		}
+		node["possibleValues"] = possible
+		remaining[name] = values
+	}
+}
+
+// pruneProbabilities prunes the conditional probabilities below the parents, collecting the values left in values.
+// It reports whether any value is left.
+func pruneProbabilities(probabilities map[string]any, parents []string, remaining map[string][]string, allowed []string, floor float64, values *[]string) bool {
+	if len(parents) == 0 {
+		for value := range probabilities {
+			if allowed != nil && !slices.Contains(allowed, value) {
+				delete(probabilities, value)
+			}
+		}
+		if !normalize(probabilities) {
+			return false
+		}
+		// The most likely value is kept even under the floor, so no distribution is left empty
+		likeliest, max := "", 0.0
+		for value, probability := range probabilities {
+			if p := probability.(float64); p > max || p == max && value < likeliest {
+				likeliest, max = value, p
+			}
+		}
+		for value, probability := range probabilities {
+			if probability.(float64) < floor && value != likeliest {
+				delete(probabilities, value)
+			}
+		}
+		normalize(probabilities)
+		for value := range probabilities {
+			if !slices.Contains(*values, value) {
+				*values = append(*values, value)
+			}
+		}
+		return true
+	}
+
+	deeper, _ := probabilities["deeper"].(map[string]any)
+	// Branches are visited in order so the averages, and the generated files, are reproducible
+	var branches []map[string]any
+	for _, parentValue := range slices.Sorted(maps.Keys(deeper)) {
+		next := deeper[parentValue]
+		if !slices.Contains(remaining[parents[0]], parentValue) ||
+			!pruneProbabilities(next.(map[string]any), parents[1:], remaining, allowed, floor, values) {
+			delete(deeper, parentValue)
+			continue
+		}
+		branches = append(branches, next.(map[string]any))
+	}
+	if len(branches) == 0 {
+		return false
+	}
+	merged := mergeProbabilities(branches, len(parents)-1)
+	for _, parentValue := range remaining[parents[0]] {
+		if _, ok := deeper[parentValue]; !ok {
+			deeper[parentValue] = merged
+		}
+	}
+	if _, ok := probabilities["skip"]; ok {
+		probabilities["skip"] = merged
+	}
+	return true
+}
+
+// normalize scales the probabilities of a distribution to sum to one, reporting false when it is empty
+func normalize(probabilities map[string]any) bool {
+	total := 0.0
+	for _, value := range slices.Sorted(maps.Keys(probabilities)) {
+		total += probabilities[value].(float64)
+	}
+	if total == 0 {
+		return false
+	}
+	for value, probability := range probabilities {
+		probabilities[value] = probability.(float64) / total
+	}
+	return true
+}
+
+// mergeProbabilities averages conditional probability branches with depth parents left
+func mergeProbabilities(branches []map[string]any, depth int) map[string]any {
+	merged := make(map[string]any)
+	if depth == 0 {
+		for _, branch := range branches {
+			for value, probability := range branch {
+				sum, _ := merged[value].(float64)
+				merged[value] = sum + probability.(float64)/float64(len(branches))
+			}
+		}
+		return merged
+	}
+	byValue := make(map[string][]map[string]any)
+	var skips []map[string]any
+	for _, branch := range branches {
+		for parentValue, next := range branch["deeper"].(map[string]any) {
+			byValue[parentValue] = append(byValue[parentValue], next.(map[string]any))
+		}
+		if skip, ok := branch["skip"].(map[string]any); ok {
+			skips = append(skips, skip)
+		}
+	}
+	deeper := make(map[string]any, len(byValue))
+	for parentValue, next := range byValue {
+		deeper[parentValue] = mergeProbabilities(next, depth-1)
+	}
+	merged["deeper"] = deeper
+	if len(skips) > 0 {
+		merged["skip"] = mergeProbabilities(skips, depth-1)
+	}
+	return merged
+}
+
+// buildMinimal writes to out a minimal dataset pruned from the networks of dir, sampling only the browsers listed
+func buildMinimal(dir, out string, browsers []string) error {
+	if err := os.MkdirAll(out, 0o755); err != nil {
+		return err
+	}
+
+	input, err := readNetwork(filepath.Join(dir, "input-network-definition.zip"))
+	if err != nil {
+		return err
+	}
+	input.prune(map[string][]string{"*BROWSER_HTTP": browsers}, 0)
+	if len(input.possibleValues("*BROWSER_HTTP")) == 0 {
+		return fmt.Errorf("none of the browsers %v is in the input network", browsers)
+	}
+
+	// The header network samples browsers without the HTTP version
+	names := input.possibleValues("*BROWSER")
+	headers, err := readNetwork(filepath.Join(dir, "header-network-definition.zip"))
+	if err != nil {
+		return err
+	}
+	headers.prune(map[string][]string{"*BROWSER": names}, minimalFloor)
+
+	// The fingerprint network samples user agents generated by the header network
+	userAgents := append(headers.possibleValues("user-agent"), headers.possibleValues("User-Agent")...)
+	fingerprints, err := readNetwork(filepath.Join(dir, "fingerprint-network-definition.zip"))
+	if err != nil {
+		return err
+	}
+	fingerprints.prune(map[string][]string{"userAgent": userAgents}, minimalFloor)
+	if len(fingerprints.possibleValues("userAgent")) == 0 {
+		return fmt.Errorf("the fingerprint network has no user agent of the browsers %v", browsers)
+	}
+
+	for name, n := range map[string]*network{
+		"input-network-definition.zip":       input,
+		"header-network-definition.zip":      headers,
+		"fingerprint-network-definition.zip": fingerprints,
+	} {
+		if err := writeNetwork(filepath.Join(out, name), n); err != nil {
+			return err
+		}
+	}
+	for _, name := range minimalCopied {
+		if err := copyFile(filepath.Join(dir, name), filepath.Join(out, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyFile copies a data file
+func copyFile(from, to string) error {
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.Create(to)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+// parseBrowserList parses a comma separated list of *BROWSER_HTTP values
+func parseBrowserList(value string) []string {
+	var browsers []string
+	for _, browser := range strings.Split(value, ",") {
+		if browser = strings.TrimSpace(browser); browser != "" {
+			browsers = append(browsers, browser)
+		}
+	}
+	return browsers
+}
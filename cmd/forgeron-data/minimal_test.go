@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMinimalDataInSync(t *testing.T) {
+	out := t.TempDir()
+	if err := buildMinimal(filepath.Join("..", "..", "data_points"), out, minimalBrowsers); err != nil {
+		t.Fatalf("Failed to build the minimal dataset: %v", err)
+	}
+	entries, err := os.ReadDir(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		want, err := os.ReadFile(filepath.Join(out, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(filepath.Join("..", "..", "data_minimal", entry.Name()))
+		if err != nil {
+			t.Fatalf("Failed to read the committed minimal dataset: %v", err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("Expected data_minimal/%s to match the pruned networks: run go generate", entry.Name())
+		}
+	}
+
+	input, err := readNetwork(filepath.Join(out, "input-network-definition.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := input.possibleValues("*BROWSER_HTTP"); len(got) != len(minimalBrowsers) {
+		t.Errorf("Expected the input network to sample %v, got %v", minimalBrowsers, got)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	n := &network{Nodes: []map[string]any{
+		{
+			"name":                     "*BROWSER",
+			"parentNames":              []any{},
+			"possibleValues":           []any{"chrome", "firefox", "opera"},
+			"conditionalProbabilities": map[string]any{"chrome": 0.5, "firefox": 0.3, "opera": 0.2},
+		},
+		{
+			"name":                     "*OS",
+			"parentNames":              []any{},
+			"possibleValues":           []any{"windows", "linux"},
+			"conditionalProbabilities": map[string]any{"windows": 0.995, "linux": 0.005},
+		},
+		{
+			"name":           "user-agent",
+			"parentNames":    []any{"*BROWSER"},
+			"possibleValues": []any{"chrome-ua", "chrome-rare-ua", "firefox-ua", "opera-ua"},
+			"conditionalProbabilities": map[string]any{
+				"deeper": map[string]any{
+					"chrome":  map[string]any{"chrome-ua": 0.999, "chrome-rare-ua": 0.001},
+					"firefox": map[string]any{"firefox-ua": 1.0},
+					"opera":   map[string]any{"opera-ua": 1.0},
+				},
+				"skip": map[string]any{"chrome-ua": 0.5, "firefox-ua": 0.3, "opera-ua": 0.2},
+			},
+		},
+	}}
+	n.prune(map[string][]string{"*BROWSER": {"chrome", "firefox"}}, 0.01)
+
+	if got := n.possibleValues("*BROWSER"); !reflect.DeepEqual(got, []string{"chrome", "firefox"}) {
+		t.Errorf("Expected opera to be pruned, got %v", got)
+	}
+	if got := n.possibleValues("*OS"); !reflect.DeepEqual(got, []string{"windows", "linux"}) {
+		t.Errorf("Expected the floor to spare *-prefixed nodes, got %v", got)
+	}
+	if got := n.possibleValues("user-agent"); !reflect.DeepEqual(got, []string{"chrome-ua", "firefox-ua"}) {
+		t.Errorf("Expected the opera and rare user agents to be pruned, got %v", got)
+	}
+	probabilities := n.Nodes[0]["conditionalProbabilities"].(map[string]any)
+	if probabilities["chrome"].(float64) != 0.625 {
+		t.Errorf("Expected probabilities to be normalized, got %v", probabilities)
+	}
+	skip := n.Nodes[2]["conditionalProbabilities"].(map[string]any)["skip"].(map[string]any)
+	if !reflect.DeepEqual(skip, map[string]any{"chrome-ua": 0.5, "firefox-ua": 0.5}) {
+		t.Errorf("Expected the skip branch to average the branches left, got %v", skip)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ta0uf19/forgeron"
+)
+
+// networkFiles are the network definitions stamped with the data schema
+var networkFiles = []string{"input-network-definition.zip", "header-network-definition.zip", "fingerprint-network-definition.zip"}
+
+// schemaComment returns the zip comment stamping a network with the data schema, and the first release reading it
+func schemaComment(requires string) string {
+	comment := fmt.Sprintf("forgeron-data schema=%d", forgeron.DataSchemaVersion)
+	if requires != "" {
+		comment += " requires=" + requires
+	}
+	return comment
+}
+
+// commentRequires returns the release required by a schema stamp, empty when there is none
+func commentRequires(comment string) string {
+	for _, field := range strings.Fields(comment) {
+		if value, ok := strings.CutPrefix(field, "requires="); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// stampNetworks stamps the networks of dir with the data schema, keeping the release they require when requires is
+// empty. It returns the networks that were not stamped yet, and leaves them untouched with check.
+func stampNetworks(dir, requires string, check bool) ([]string, error) {
+	var changed []string
+	for _, name := range networkFiles {
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", path, err)
+		}
+		want := requires
+		if want == "" {
+			want = commentRequires(reader.Comment)
+		}
+		comment := schemaComment(want)
+		if reader.Comment == comment {
+			continue
+		}
+		changed = append(changed, name)
+		if check {
+			continue
+		}
+		stamped, err := restamp(reader, comment)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stamp %s: %w", path, err)
+		}
+		if err := os.WriteFile(path, stamped, 0o644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		fmt.Println("stamped", name)
+	}
+	return changed, nil
+}
+
+// restamp copies the zip with another comment, leaving its compressed files as they are
+func restamp(reader *zip.Reader, comment string) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for _, file := range reader.File {
+		if err := writer.Copy(file); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.SetComment(comment); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// readZip opens a zip file for the test
+func readZip(t *testing.T, path string) *zip.ReadCloser {
+	t.Helper()
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", path, err)
+	}
+	t.Cleanup(func() { reader.Close() })
+	return reader
+}
+
+func TestStampNetworks(t *testing.T) {
+	dir := copyNetworks(t)
+	path := filepath.Join(dir, "input-network-definition.zip")
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Drop the stamp to stamp the network again
+	stripped, err := restamp(&readZip(t, path).Reader, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, stripped, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	unstamped, err := stampNetworks(dir, "", true)
+	if err != nil {
+		t.Fatalf("Failed to check the stamps: %v", err)
+	}
+	if !reflect.DeepEqual(unstamped, []string{"input-network-definition.zip"}) {
+		t.Errorf("Expected the input network to be reported unstamped, got %v", unstamped)
+	}
+	if _, err := stampNetworks(dir, "", false); err != nil {
+		t.Fatalf("Failed to stamp the networks: %v", err)
+	}
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != string(before) {
+		t.Error("Expected stamping again to restore the network")
+	}
+
+	// The required release is recorded, and kept when stamping without one
+	if _, err := stampNetworks(dir, "v2.0.0", false); err != nil {
+		t.Fatalf("Failed to stamp the networks: %v", err)
+	}
+	if _, err := stampNetworks(dir, "", false); err != nil {
+		t.Fatalf("Failed to stamp the networks: %v", err)
+	}
+	for _, name := range networkFiles {
+		reader := readZip(t, filepath.Join(dir, name))
+		if want := schemaComment("v2.0.0"); reader.Comment != want {
+			t.Errorf("Expected %s to be stamped %q, got %q", name, want, reader.Comment)
+		}
+		if len(reader.File) != 1 {
+			t.Errorf("Expected %s to hold the network only, got %d files", name, len(reader.File))
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Command forgeron-wasm exposes forgeron to JavaScript when built to WebAssembly, so browser extensions and Node
+// services can reuse the Go implementation. forgeron.js loads the module and wraps its functions:
+//
+//	GOOS=js GOARCH=wasm go build -tags forgeron_minimal -o forgeron.wasm ./cmd/forgeron-wasm
+//	cp "$(go env GOROOT)/lib/wasm/wasm_exec.js" .   # misc/wasm before Go 1.24
+//
+// The functions take and return JSON, a fingerprint request is a forgeron.GenerateRequest and a headers request
+// forgeron.HeaderConstraints, both with an optional seed generating the same output for the same request.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ta0uf19/forgeron"
+)
+
+// fingerprintRequest is the JSON argument of generateFingerprint
+type fingerprintRequest struct {
+	forgeron.GenerateRequest
+	Seed int64 `json:"seed,omitempty"`
+}
+
+// headersRequest is the JSON argument of generateHeaders
+type headersRequest struct {
+	forgeron.HeaderConstraints
+	Seed int64 `json:"seed,omitempty"`
+}
+
+// bindings are the functions exposed to JavaScript
+type bindings struct {
+	gen *forgeron.FingerprintGenerator
+}
+
+// generateFingerprint generates the fingerprint of a JSON fingerprint request
+func (b bindings) generateFingerprint(input string) (string, error) {
+	var req fingerprintRequest
+	if err := decodeRequest(input, &req); err != nil {
+		return "", err
+	}
+	opts := req.Options()
+	if req.Seed != 0 {
+		opts = append(opts, forgeron.WithRandomSource(forgeron.NewSeededSource(req.Seed)))
+	}
+	fp, err := b.gen.Generate(opts...)
+	if err != nil {
+		return "", err
+	}
+	return encodeResponse(fp)
+}
+
+// generateHeaders generates the headers of a JSON headers request
+func (b bindings) generateHeaders(input string) (string, error) {
+	var req headersRequest
+	if err := decodeRequest(input, &req); err != nil {
+		return "", err
+	}
+	gen := b.gen.HeaderGenerator()
+	if req.Seed != 0 {
+		gen.SetRandomSource(forgeron.NewSeededSource(req.Seed))
+	}
+	headers, err := gen.GenerateHeaders(req.HeaderConstraints)
+	if err != nil {
+		return "", err
+	}
+	return encodeResponse(headers)
+}
+
+// decodeRequest decodes a JSON request, an empty input is an empty request
+func decodeRequest(input string, out any) error {
+	if input == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(input), out); err != nil {
+		return fmt.Errorf("invalid request: %w", err)
+	}
+	return nil
+}
+
+// encodeResponse encodes a generated value to JSON
+func encodeResponse(value any) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode response: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ta0uf19/forgeron"
+)
+
+// maxEmbeddedData bounds the minimal dataset embedded in WebAssembly builds, which are downloaded by every page
+// or extension using them
+const maxEmbeddedData = 128 << 10
+
+func newBindings(t *testing.T) bindings {
+	t.Helper()
+	gen, err := forgeron.NewFingerprintGenerator()
+	if err != nil {
+		t.Fatalf("NewFingerprintGenerator() error = %v", err)
+	}
+	return bindings{gen: gen}
+}
+
+func TestGenerateFingerprint(t *testing.T) {
+	b := newBindings(t)
+	input := `{"constraints": {"Browsers": ["firefox"]}, "seed": 42}`
+	first, err := b.generateFingerprint(input)
+	if err != nil {
+		t.Fatalf("generateFingerprint() error = %v", err)
+	}
+	var fp forgeron.Fingerprint
+	if err := json.Unmarshal([]byte(first), &fp); err != nil {
+		t.Fatalf("failed to decode fingerprint: %v", err)
+	}
+	if !strings.Contains(fp.Navigator.UserAgent, "Firefox/") {
+		t.Errorf("UserAgent = %q, want Firefox", fp.Navigator.UserAgent)
+	}
+	if second, _ := b.generateFingerprint(input); second != first {
+		t.Error("generateFingerprint() generated different fingerprints for the same seed")
+	}
+	if _, err := b.generateFingerprint(""); err != nil {
+		t.Errorf("generateFingerprint() error = %v for an empty request", err)
+	}
+}
+
+func TestGenerateHeaders(t *testing.T) {
+	b := newBindings(t)
+	output, err := b.generateHeaders(`{"Locales": ["de-DE"], "seed": 1}`)
+	if err != nil {
+		t.Fatalf("generateHeaders() error = %v", err)
+	}
+	var headers map[string]string
+	if err := json.Unmarshal([]byte(output), &headers); err != nil {
+		t.Fatalf("failed to decode headers: %v", err)
+	}
+	if !strings.HasPrefix(headers["Accept-Language"], "de-DE") {
+		t.Errorf("Accept-Language = %q, want de-DE", headers["Accept-Language"])
+	}
+
+	for _, input := range []string{`{"OS": ["beos"]}`, `{"Locales": "de-DE"}`} {
+		if _, err := b.generateHeaders(input); err == nil {
+			t.Errorf("generateHeaders(%s) error = nil, want an error", input)
+		}
+	}
+}
+
+func TestEmbeddedDataSize(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "..", "data_minimal", "*"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("failed to list the minimal dataset: %v", err)
+	}
+	var size int64
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		size += info.Size()
+	}
+	if size > maxEmbeddedData {
+		t.Errorf("minimal dataset is %d bytes, more than the %d embedded in WebAssembly builds", size, maxEmbeddedData)
+	}
+}
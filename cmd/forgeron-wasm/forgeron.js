@@ -0,0 +1,36 @@
+// forgeron.js loads forgeron.wasm, built from cmd/forgeron-wasm, in a browser or Node. Go's wasm_exec.js must be
+// loaded first, it defines the Go runtime glue:
+//
+//   import "./wasm_exec.js";
+//   import { load } from "./forgeron.js";
+//
+//   const forgeron = await load(fetch("forgeron.wasm"));
+//   const fingerprint = forgeron.generateFingerprint({ constraints: { Browsers: ["firefox"] }, seed: 42 });
+//   const headers = forgeron.generateHeaders({ Locales: ["de-DE"] });
+//
+// Failed generations throw an Error with the message of the Go error.
+
+// load instantiates the module from its bytes, a Response or a promise of a Response, and returns its functions
+export async function load(source) {
+  const go = new Go();
+  source = await source;
+  const { instance } = typeof Response !== "undefined" && source instanceof Response
+    ? await WebAssembly.instantiateStreaming(source, go.importObject)
+    : await WebAssembly.instantiate(source, go.importObject);
+  go.run(instance);
+
+  const bindings = globalThis.forgeron;
+  return {
+    generateFingerprint: (request = {}) => call(bindings.generateFingerprint, request),
+    generateHeaders: (request = {}) => call(bindings.generateHeaders, request),
+  };
+}
+
+// call passes the request to a Go function as JSON and decodes its output
+function call(fn, request) {
+  const [output, error] = fn(JSON.stringify(request));
+  if (error !== null) {
+    throw new Error(error);
+  }
+  return JSON.parse(output);
+}
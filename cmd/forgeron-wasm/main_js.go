@@ -0,0 +1,36 @@
+package main
+
+import (
+	"syscall/js"
+
+	"github.com/ta0uf19/forgeron"
+)
+
+func main() {
+	gen, err := forgeron.NewFingerprintGenerator()
+	if err != nil {
+		panic(err)
+	}
+	b := bindings{gen: gen}
+	js.Global().Set("forgeron", js.ValueOf(map[string]any{
+		"generateFingerprint": export(b.generateFingerprint),
+		"generateHeaders":     export(b.generateHeaders),
+	}))
+	// Keep the functions callable for the life of the page or process
+	select {}
+}
+
+// export wraps a binding as a JavaScript function returning [output, error], the error being null on success
+func export(binding func(string) (string, error)) js.Func {
+	return js.FuncOf(func(this js.Value, args []js.Value) any {
+		var input string
+		if len(args) > 0 && args[0].Type() == js.TypeString {
+			input = args[0].String()
+		}
+		output, err := binding(input)
+		if err != nil {
+			return js.ValueOf([]any{nil, err.Error()})
+		}
+		return js.ValueOf([]any{output, nil})
+	})
+}
@@ -0,0 +1,13 @@
+//go:build !js
+
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	fmt.Fprintln(os.Stderr, "forgeron-wasm only runs as WebAssembly, build it with GOOS=js GOARCH=wasm")
+	os.Exit(1)
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ta0uf19/forgeron"
+)
+
+// errDoctorFailed is returned when identities of some combinations are inconsistent
+var errDoctorFailed = errors.New("doctor: inconsistent identities found")
+
+// doctorOptions are the flags of the doctor command
+type doctorOptions struct {
+	dataDir string
+	samples int
+	seed    int64
+}
+
+// newDoctorFlagSet returns the flags of the doctor command, parsed into opts
+func newDoctorFlagSet(opts *doctorOptions) *flag.FlagSet {
+	flags := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	flags.StringVar(&opts.dataDir, "data", "", "directory of network data to check instead of the embedded data")
+	flags.IntVar(&opts.samples, "samples", 5, "identities generated per combination")
+	flags.Int64Var(&opts.seed, "seed", 1, "seed of the generated identities")
+	return flags
+}
+
+// doctorCombination is a browser, OS, device and HTTP version identities are generated for
+type doctorCombination struct {
+	browser     forgeron.Browser
+	os          forgeron.OS
+	device      forgeron.Device
+	httpVersion forgeron.HTTPVersion
+}
+
+// doctorResult is the outcome of the identities of a combination
+type doctorResult struct {
+	combination doctorCombination
+	passed      int
+	// skipped is the reason the data has no identity for the combination, empty when it has
+	skipped string
+	// problems are the inconsistencies found, with the generation errors
+	problems []string
+}
+
+// status returns PASS, SKIP or FAIL
+func (r doctorResult) status() string {
+	switch {
+	case len(r.problems) > 0:
+		return "FAIL"
+	case r.skipped != "":
+		return "SKIP"
+	}
+	return "PASS"
+}
+
+// runDoctor loads the networks, generates identities for every browser, OS, device and HTTP version in strict
+// mode, checks their consistency and writes a report to w. Combinations the data cannot satisfy are skipped.
+func runDoctor(args []string, w io.Writer) error {
+	var opts doctorOptions
+	flags := newDoctorFlagSet(&opts)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %v", flags.Args())
+	}
+	if opts.samples < 1 {
+		return fmt.Errorf("samples must be positive")
+	}
+
+	genOpts := []forgeron.FingerprintOption{forgeron.WithRandomSource(forgeron.NewSeededSource(opts.seed))}
+	if opts.dataDir != "" {
+		genOpts = append(genOpts, forgeron.WithDataDir(opts.dataDir))
+	}
+	gen, err := forgeron.NewFingerprintGenerator(genOpts...)
+	if err != nil {
+		return fmt.Errorf("failed to load the networks: %w", err)
+	}
+	info := gen.DataInfo()
+	fmt.Fprintf(w, "data schema %d, %d browser versions, os %s, devices %s\n\n",
+		info.Schema, len(info.Browsers), strings.Join(info.OS, ","), strings.Join(info.Devices, ","))
+
+	var results []doctorResult
+	for _, browser := range forgeron.SupportedBrowsers {
+		for _, os := range forgeron.SupportedOS {
+			for _, device := range forgeron.SupportedDevices {
+				for _, httpVersion := range forgeron.SupportedHTTP {
+					results = append(results, checkCombination(gen, doctorCombination{browser, os, device, httpVersion}, opts.samples))
+				}
+			}
+		}
+	}
+	return writeDoctorReport(w, results)
+}
+
+// checkCombination generates the identities of a combination and checks each of them
+func checkCombination(gen *forgeron.FingerprintGenerator, c doctorCombination, samples int) doctorResult {
+	result := doctorResult{combination: c}
+	constraints := forgeron.HeaderConstraints{
+		Browsers:    []forgeron.Browser{c.browser},
+		OS:          []forgeron.OS{c.os},
+		Devices:     []forgeron.Device{c.device},
+		HTTPVersion: c.httpVersion,
+		Strict:      true,
+	}
+	for range samples {
+		fp, err := gen.Generate(forgeron.WithHeaderConstraints(constraints), forgeron.WithStrict(true))
+		var constraintErr *forgeron.ConstraintError
+		if errors.As(err, &constraintErr) {
+			result.skipped = constraintErr.Reason
+			return result
+		}
+		if err != nil {
+			result.problems = append(result.problems, err.Error())
+			continue
+		}
+		if problems := checkFingerprint(fp); len(problems) > 0 {
+			result.problems = append(result.problems, problems...)
+			continue
+		}
+		result.passed++
+	}
+	return result
+}
+
+// checkFingerprint returns the inconsistencies of a fingerprint: the ones Validate reports, and headers
+// disagreeing with the navigator
+func checkFingerprint(fp *forgeron.Fingerprint) []string {
+	var problems []string
+	if err := fp.Validate(); err != nil {
+		problems = append(problems, err.Error())
+	}
+	headers := fp.OrderedHeaders()
+	if ua := headers.Get("User-Agent"); ua != fp.Navigator.UserAgent {
+		problems = append(problems, fmt.Sprintf("User-Agent header %q does not match navigator user agent %q", ua, fp.Navigator.UserAgent))
+	}
+	for name, want := range fp.ClientHintHeaders() {
+		if got := headers.Get(name); got != "" && got != want {
+			problems = append(problems, fmt.Sprintf("%s header %s does not match the user agent data %s", name, got, want))
+		}
+	}
+	if language := headers.Get("Accept-Language"); language != "" && len(fp.Navigator.Languages) > 0 &&
+		!strings.HasPrefix(language, fp.Navigator.Languages[0]) {
+		problems = append(problems, fmt.Sprintf("Accept-Language %q does not start with language %q", language, fp.Navigator.Languages[0]))
+	}
+	return problems
+}
+
+// writeDoctorReport writes a line per combination and a summary, failing when a combination failed
+func writeDoctorReport(w io.Writer, results []doctorResult) error {
+	counts := make(map[string]int)
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, r := range results {
+		status := r.status()
+		counts[status]++
+		c := r.combination
+		detail := fmt.Sprintf("%d passed", r.passed)
+		switch {
+		case len(r.problems) > 0:
+			detail = fmt.Sprintf("%d passed, %s", r.passed, r.problems[0])
+		case r.skipped != "":
+			detail = r.skipped
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\thttp/%s\t%s\n", status, c.browser, c.os, c.device, c.httpVersion, detail)
+	}
+	if err := table.Flush(); err != nil {
+		return fmt.Errorf("failed to write the report: %w", err)
+	}
+	fmt.Fprintf(w, "\n%d combinations: %d passed, %d skipped, %d failed\n", len(results), counts["PASS"], counts["SKIP"], counts["FAIL"])
+	if counts["FAIL"] > 0 {
+		return errDoctorFailed
+	}
+	return nil
+}
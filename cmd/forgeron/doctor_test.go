@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ta0uf19/forgeron/forgerontest"
+)
+
+func TestRunDoctor(t *testing.T) {
+	var out bytes.Buffer
+	if err := runDoctor([]string{"-samples", "2"}, &out); err != nil {
+		t.Fatalf("runDoctor() error = %v\n%s", err, out.String())
+	}
+	report := out.String()
+	for _, want := range []string{"PASS  chrome   windows   desktop  http/2  2 passed", "SKIP  safari   windows", "144 combinations:", " 0 failed"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report misses %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestCheckFingerprint(t *testing.T) {
+	if problems := checkFingerprint(forgerontest.Fingerprint()); len(problems) > 0 {
+		t.Errorf("checkFingerprint(fixture) = %v, want none", problems)
+	}
+
+	fp := forgerontest.Fingerprint()
+	fp.Navigator.Platform = "MacIntel"
+	fp.Headers["sec-ch-ua-platform"] = `"macOS"`
+	if problems := checkFingerprint(fp); len(problems) != 2 {
+		t.Errorf("checkFingerprint() = %v, want the platform and the client hint problems", problems)
+	}
+}
@@ -0,0 +1,189 @@
+// Command forgeron generates browser headers or fingerprints to stdout, so they can be used from Python or Node
+// pipelines, or inspected without writing Go:
+//
+//	forgeron generate -browser chrome,firefox -os windows -locale de-DE,de -count 10
+//	forgeron generate -headers -browser "firefox>=140" -http-version 1 -seed 42
+//
+// Every generated fingerprint, or header set with -headers, is written as a JSON object on its own line. The same
+// -seed generates the same output.
+//
+// forgeron doctor checks the data end to end, e.g. after a data refresh or for a bug report: it generates identities
+// for every browser, OS, device and HTTP version, validates their consistency and prints a pass/fail report.
+//
+//	forgeron doctor -samples 10 -data ./data_points
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ta0uf19/forgeron"
+)
+
+const usage = `usage: forgeron generate [flags]
+       forgeron doctor [flags]
+
+Generate writes browser fingerprints, or headers with -headers, as one JSON object per line.
+Doctor generates identities for every browser, OS, device and HTTP version and reports their consistency.
+
+Generate flags:
+`
+
+func main() {
+	if len(os.Args) < 2 || (os.Args[1] != "generate" && os.Args[1] != "doctor") {
+		fmt.Fprint(os.Stderr, usage)
+		newFlagSet(new(options)).PrintDefaults()
+		fmt.Fprint(os.Stderr, "\nDoctor flags:\n")
+		newDoctorFlagSet(new(doctorOptions)).PrintDefaults()
+		os.Exit(2)
+	}
+	var err error
+	if os.Args[1] == "doctor" {
+		err = runDoctor(os.Args[2:], os.Stdout)
+	} else {
+		err = run(os.Args[2:], os.Stdout)
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// options are the flags of the generate command
+type options struct {
+	headers     bool
+	browsers    string
+	os          string
+	devices     string
+	locales     string
+	httpVersion string
+	strict      bool
+	realism     string
+	seed        int64
+	count       int
+}
+
+// newFlagSet returns the flags of the generate command, parsed into opts
+func newFlagSet(opts *options) *flag.FlagSet {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	flags.BoolVar(&opts.headers, "headers", false, "generate headers only instead of full fingerprints")
+	flags.StringVar(&opts.browsers, "browser", "", `comma separated browsers, with optional versions, e.g. "chrome>=120,firefox"`)
+	flags.StringVar(&opts.os, "os", "", "comma separated operating systems, e.g. windows,macos")
+	flags.StringVar(&opts.devices, "device", "", "comma separated devices, e.g. desktop,mobile")
+	flags.StringVar(&opts.locales, "locale", "", "comma separated locales, e.g. de-DE,de")
+	flags.StringVar(&opts.httpVersion, "http-version", "", "HTTP version of the headers, 1 or 2")
+	flags.BoolVar(&opts.strict, "strict", false, "fail instead of relaxing constraints the data cannot satisfy")
+	flags.StringVar(&opts.realism, "realism", "", "realism level of fingerprints: low, medium or high")
+	flags.Int64Var(&opts.seed, "seed", 0, "seed for reproducible output, 0 is random")
+	flags.IntVar(&opts.count, "count", 1, "number of fingerprints or header sets to generate")
+	return flags
+}
+
+// run parses the flags of the generate command and writes the generated output to w
+func run(args []string, w io.Writer) error {
+	var opts options
+	flags := newFlagSet(&opts)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %v", flags.Args())
+	}
+	if opts.count < 1 {
+		return fmt.Errorf("count must be positive")
+	}
+	constraints, err := opts.constraints()
+	if err != nil {
+		return err
+	}
+
+	var random forgeron.RandomSource
+	if opts.seed != 0 {
+		random = forgeron.NewSeededSource(opts.seed)
+	}
+	generate, err := newGenerate(constraints, opts.headers, random, forgeron.Realism(opts.realism))
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	for range opts.count {
+		value, err := generate()
+		if err != nil {
+			return err
+		}
+		if err := encoder.Encode(value); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+	}
+	return nil
+}
+
+// newGenerate returns a function generating a header set or a fingerprint with the constraints
+func newGenerate(constraints forgeron.Constraints, headers bool, random forgeron.RandomSource, realism forgeron.Realism) (func() (any, error), error) {
+	if headers {
+		gen, err := forgeron.NewHeaderGenerator()
+		if err != nil {
+			return nil, err
+		}
+		gen.SetRandomSource(random)
+		return func() (any, error) {
+			return gen.GenerateHeaders(constraints.HeaderConstraints)
+		}, nil
+	}
+	gen, err := forgeron.NewFingerprintGenerator(forgeron.WithConstraints(constraints), forgeron.WithRandomSource(random), forgeron.WithRealism(realism))
+	if err != nil {
+		return nil, err
+	}
+	return func() (any, error) {
+		return gen.Generate()
+	}, nil
+}
+
+// constraints returns the constraints described by the flags, empty flags keep the generator defaults
+func (o options) constraints() (forgeron.Constraints, error) {
+	var c forgeron.Constraints
+	if o.browsers != "" {
+		specs, err := forgeron.ParseBrowserSpecs(splitList(o.browsers)...)
+		if err != nil {
+			return c, fmt.Errorf("invalid -browser: %w", err)
+		}
+		versioned := false
+		for _, spec := range specs {
+			c.Browsers = append(c.Browsers, spec.Name)
+			versioned = versioned || spec.MinVersion > 0 || spec.MaxVersion > 0
+		}
+		if versioned {
+			c.BrowserSpecs = specs
+		}
+	}
+	for _, value := range splitList(o.os) {
+		c.OS = append(c.OS, forgeron.OS(value))
+	}
+	for _, value := range splitList(o.devices) {
+		c.Devices = append(c.Devices, forgeron.Device(value))
+	}
+	c.Locales = splitList(o.locales)
+	c.HTTPVersion = forgeron.HTTPVersion(o.httpVersion)
+	c.Strict = o.strict
+	return c, nil
+}
+
+// splitList splits a comma separated flag value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
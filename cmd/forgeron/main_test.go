@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ta0uf19/forgeron"
+)
+
+func TestRunGeneratesFingerprints(t *testing.T) {
+	var out bytes.Buffer
+	if err := run([]string{"-browser", "firefox", "-os", "windows", "-count", "3", "-seed", "7"}, &out); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("run() wrote %d lines, want 3", len(lines))
+	}
+	for _, line := range lines {
+		var fp forgeron.Fingerprint
+		if err := json.Unmarshal([]byte(line), &fp); err != nil {
+			t.Fatalf("failed to decode fingerprint: %v", err)
+		}
+		if ua := fp.Navigator.UserAgent; !strings.Contains(ua, "Firefox/") || !strings.Contains(ua, "Windows") {
+			t.Errorf("UserAgent = %q, want Firefox on Windows", ua)
+		}
+	}
+}
+
+func TestRunGeneratesHeaders(t *testing.T) {
+	var out bytes.Buffer
+	if err := run([]string{"-headers", "-browser", "chrome>=120", "-locale", "de-DE,de", "-http-version", "1"}, &out); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	var headers map[string]string
+	if err := json.Unmarshal(out.Bytes(), &headers); err != nil {
+		t.Fatalf("failed to decode headers: %v", err)
+	}
+	if !strings.Contains(headers["User-Agent"], "Chrome/") || !strings.HasPrefix(headers["Accept-Language"], "de-DE") {
+		t.Errorf("headers = %v, want Chrome headers in German", headers)
+	}
+}
+
+func TestRunSeedIsReproducible(t *testing.T) {
+	for _, args := range [][]string{{"-count", "2"}, {"-headers", "-count", "2"}} {
+		var first, second bytes.Buffer
+		args = append(args, "-seed", "42")
+		if err := run(args, &first); err != nil {
+			t.Fatalf("run(%v) error = %v", args, err)
+		}
+		if err := run(args, &second); err != nil {
+			t.Fatalf("run(%v) error = %v", args, err)
+		}
+		if first.String() != second.String() {
+			t.Errorf("run(%v) output differs between runs with the same seed", args)
+		}
+	}
+}
+
+func TestRunRejectsInvalidFlags(t *testing.T) {
+	tests := [][]string{
+		{"-count", "0"},
+		{"-browser", "chrome>>1"},
+		{"-os", "beos"},
+		{"-http-version", "3"},
+		{"extra"},
+	}
+	for _, args := range tests {
+		if err := run(args, &bytes.Buffer{}); err == nil {
+			t.Errorf("run(%v) error = nil, want an error", args)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// Command forgerond serves fingerprints and headers over HTTP with the server package, for services written in
+// other languages:
+//
+//	forgerond -addr :8080 -rate 20 -burst 40
+//	curl -d '{"request": {"constraints": {"Browsers": ["firefox"]}}, "seed": 42}' localhost:8080/fingerprint
+//
+// With -workers, at most that many fingerprints are generated at once and requests arriving while -queue requests
+// are waiting are answered with 429 Too Many Requests. With -data the network data is read from a directory instead of the embedded data. GET /metrics serves the
+// generations and the constraints they failed on or relaxed in the Prometheus text format.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ta0uf19/forgeron"
+	"github.com/ta0uf19/forgeron/server"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	rate := flag.Float64("rate", 0, "requests per second allowed per client address, 0 disables rate limiting")
+	burst := flag.Int("burst", 10, "requests a client can make at once above the rate limit")
+	maxCount := flag.Int("max-count", 100, "maximum number of fingerprints per request")
+	dataDir := flag.String("data", "", "directory of network data overriding the embedded data")
+	workers := flag.Int("workers", 0, "concurrent fingerprint generations, 0 leaves them unbounded")
+	queue := flag.Int("queue", 0, "requests waiting for a worker before answering 429, 0 allows 4 per worker")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := run(ctx, *addr, *dataDir, forgeron.PoolConfig{Workers: *workers, QueueSize: *queue}, server.Config{RateLimit: *rate, Burst: *burst, MaxCount: *maxCount}); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run serves the config until ctx is done, generating with a pool when pool has workers
+func run(ctx context.Context, addr, dataDir string, pool forgeron.PoolConfig, config server.Config) error {
+	gen, err := forgeron.NewFingerprintGenerator(forgeron.WithDataDir(dataDir))
+	if err != nil {
+		return err
+	}
+	config.Generator = gen
+	if pool.Workers > 0 {
+		config.Pool, err = forgeron.NewPool(func() (forgeron.FingerprintProvider, error) { return gen, nil }, pool)
+		if err != nil {
+			return err
+		}
+		defer config.Pool.Close()
+	}
+	s, err := server.New(config)
+	if err != nil {
+		return err
+	}
+
+	httpServer := &http.Server{Addr: addr, Handler: s, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+	log.Printf("serving on %s", addr)
+	if err := httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
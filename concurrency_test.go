@@ -0,0 +1,59 @@
+package forgeron
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+)
+
+// TestGenerateConcurrently shares one generator between goroutines with different per-call options,
+// run it with -race to check Generate does not mutate the generator
+func TestGenerateConcurrently(t *testing.T) {
+	gen := newGeneratorOrFatal(t, WithHeaderConstraints(HeaderConstraints{Browsers: []Browser{Chrome}}))
+	browsers := []Browser{Chrome, Firefox, Safari, Edge}
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 64)
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			browser := browsers[worker%len(browsers)]
+			for i := 0; i < 10; i++ {
+				fp, err := gen.Generate(
+					WithHeaderConstraints(HeaderConstraints{Browsers: []Browser{browser}, Strict: true}),
+					WithRandomSource(NewSeededSource(int64(worker*100+i))),
+					WithLogger(logger),
+				)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if got := parseUserAgent(fp.Navigator.UserAgent).Browser; got != browser {
+					errs <- fmt.Errorf("worker %d generated %s, want %s", worker, got, browser)
+					return
+				}
+			}
+		}(worker)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	// Per-call options do not leak into the generator
+	if gen.random != nil || gen.logger != nil || gen.headerGenerator.random != nil || gen.network.random != nil {
+		t.Error("Generate() options changed the generator settings")
+	}
+	fp, err := gen.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if got := parseUserAgent(fp.Navigator.UserAgent).Browser; got != Chrome {
+		t.Errorf("Generate() after per-call options generated %s, want the generator constraint %s", got, Chrome)
+	}
+}
@@ -0,0 +1,317 @@
+package forgeron
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConstraintProfiles maps profile names to the constraints they describe
+type ConstraintProfiles map[string]Constraints
+
+// Get returns the constraints of a profile
+func (p ConstraintProfiles) Get(name string) (Constraints, error) {
+	constraints, ok := p[name]
+	if !ok {
+		return Constraints{}, fmt.Errorf("constraint profile '%s' not found", name)
+	}
+	return constraints, nil
+}
+
+// constraintsFile is the schema of a constraints config file
+type constraintsFile struct {
+	Include  []string                   `json:"include" yaml:"include"`
+	Presets  map[string]constraintsSpec `json:"presets" yaml:"presets"`
+	Profiles map[string]constraintsSpec `json:"profiles" yaml:"profiles"`
+}
+
+// constraintsSpec is the schema of a preset or profile in a constraints config file
+type constraintsSpec struct {
+	Preset       string            `json:"preset" yaml:"preset"`
+	BrowserSpecs []browserSpecSpec `json:"browserSpecs" yaml:"browserSpecs"`
+	Browsers     []Browser         `json:"browsers" yaml:"browsers"`
+	OS           []OS              `json:"os" yaml:"os"`
+	Devices      []Device          `json:"devices" yaml:"devices"`
+	Locales      []string          `json:"locales" yaml:"locales"`
+	Language     string            `json:"language" yaml:"language"`
+	Region       string            `json:"region" yaml:"region"`
+	HTTPVersion  HTTPVersion       `json:"httpVersion" yaml:"httpVersion"`
+	Strict       *bool             `json:"strict" yaml:"strict"`
+	Screen       *screenSpec       `json:"screen" yaml:"screen"`
+	HeaderPolicy *headerPolicySpec `json:"headerPolicy" yaml:"headerPolicy"`
+	Accept       string            `json:"accept" yaml:"accept"`
+	// RequestContext is one of navigation, iframe, first-party, third-party, image, script or stylesheet
+	RequestContext RequestContext `json:"requestContext" yaml:"requestContext"`
+	// SecFetchSite is one of none, same-origin, same-site or cross-site, SecFetchUser ?1 or absent
+	SecFetchSite FetchSite `json:"secFetchSite" yaml:"secFetchSite"`
+	SecFetchUser FetchUser `json:"secFetchUser" yaml:"secFetchUser"`
+	// ExpandLocales expands every locale into the chain a browser sends
+	ExpandLocales *bool `json:"expandLocales" yaml:"expandLocales"`
+}
+
+// headerPolicySpec is the schema of a header policy in a constraints config file
+type headerPolicySpec struct {
+	Deny          []string            `json:"deny" yaml:"deny"`
+	DenyByBrowser map[string][]string `json:"denyByBrowser" yaml:"denyByBrowser"`
+	Allow         []string            `json:"allow" yaml:"allow"`
+}
+
+// browserSpecSpec is the schema of a browser specification in a constraints config file,
+// written as an object or as a compact string such as "chrome>=115"
+type browserSpecSpec struct {
+	Name        Browser     `json:"name" yaml:"name"`
+	MinVersion  int         `json:"minVersion" yaml:"minVersion"`
+	MaxVersion  int         `json:"maxVersion" yaml:"maxVersion"`
+	HTTPVersion HTTPVersion `json:"httpVersion" yaml:"httpVersion"`
+}
+
+// browserSpecObject has the fields of browserSpecSpec without its unmarshalers
+type browserSpecObject browserSpecSpec
+
+// UnmarshalJSON reads a browser specification object or compact string
+func (s *browserSpecSpec) UnmarshalJSON(data []byte) error {
+	var compact string
+	if err := json.Unmarshal(data, &compact); err == nil {
+		return s.parse(compact)
+	}
+	return json.Unmarshal(data, (*browserSpecObject)(s))
+}
+
+// UnmarshalYAML reads a browser specification mapping or compact string
+func (s *browserSpecSpec) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return s.parse(node.Value)
+	}
+	return node.Decode((*browserSpecObject)(s))
+}
+
+// parse sets the spec from a compact string
+func (s *browserSpecSpec) parse(compact string) error {
+	spec, err := ParseBrowserSpec(compact)
+	if err != nil {
+		return err
+	}
+	*s = browserSpecSpec{Name: spec.Name, MinVersion: spec.MinVersion, MaxVersion: spec.MaxVersion}
+	return nil
+}
+
+// screenSpec is the schema of screen constraints in a constraints config file
+type screenSpec struct {
+	MinWidth            *int     `json:"minWidth" yaml:"minWidth"`
+	MaxWidth            *int     `json:"maxWidth" yaml:"maxWidth"`
+	MinHeight           *int     `json:"minHeight" yaml:"minHeight"`
+	MaxHeight           *int     `json:"maxHeight" yaml:"maxHeight"`
+	MinDevicePixelRatio *float64 `json:"minDevicePixelRatio" yaml:"minDevicePixelRatio"`
+	MaxDevicePixelRatio *float64 `json:"maxDevicePixelRatio" yaml:"maxDevicePixelRatio"`
+}
+
+// LoadConstraints loads constraint profiles from a YAML or JSON config file.
+// Included files are loaded first and can be overridden by the including file, and
+// profiles can start from a named preset.
+func LoadConstraints(path string) (ConstraintProfiles, error) {
+	profiles, _, err := loadConstraints(path)
+	return profiles, err
+}
+
+// loadConstraints loads constraint profiles like LoadConstraints, along with the absolute paths of the config file
+// and of every file it includes
+func loadConstraints(path string) (ConstraintProfiles, []string, error) {
+	files := make(map[string]bool)
+	file, err := readConstraintsFile(path, make(map[string]bool), files)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	profiles := make(ConstraintProfiles, len(file.Profiles))
+	for name, spec := range file.Profiles {
+		if spec.Preset != "" {
+			preset, ok := file.Presets[spec.Preset]
+			if !ok {
+				return nil, nil, fmt.Errorf("profile '%s' uses unknown preset '%s'", name, spec.Preset)
+			}
+			spec = preset.merge(spec)
+		}
+		constraints := spec.constraints()
+		if err := constraints.Validate(); err != nil {
+			return nil, nil, fmt.Errorf("invalid profile '%s': %v", name, err)
+		}
+		profiles[name] = constraints
+	}
+	return profiles, slices.Sorted(maps.Keys(files)), nil
+}
+
+// readConstraintsFile reads a config file and the files it includes, adding their absolute paths to files
+func readConstraintsFile(path string, visiting, files map[string]bool) (*constraintsFile, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %v", path, err)
+	}
+	if visiting[absPath] {
+		return nil, fmt.Errorf("include cycle detected at %s", path)
+	}
+	visiting[absPath] = true
+	defer delete(visiting, absPath)
+	files[absPath] = true
+
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	file, err := parseConstraintsFile(data, filepath.Ext(absPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+
+	merged := &constraintsFile{
+		Presets:  make(map[string]constraintsSpec),
+		Profiles: make(map[string]constraintsSpec),
+	}
+	for _, include := range file.Include {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(absPath), include)
+		}
+		included, err := readConstraintsFile(include, visiting, files)
+		if err != nil {
+			return nil, err
+		}
+		merged.add(included)
+	}
+	merged.add(file)
+	return merged, nil
+}
+
+// parseConstraintsFile decodes a config file according to its extension
+func parseConstraintsFile(data []byte, ext string) (*constraintsFile, error) {
+	var file constraintsFile
+	switch strings.ToLower(ext) {
+	case ".json":
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, err
+		}
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported config format '%s'", ext)
+	}
+	return &file, nil
+}
+
+// add merges the presets and profiles of another file, overriding existing ones
+func (f *constraintsFile) add(other *constraintsFile) {
+	for name, spec := range other.Presets {
+		f.Presets[name] = spec
+	}
+	for name, spec := range other.Profiles {
+		f.Profiles[name] = spec
+	}
+}
+
+// merge returns the spec overridden by the non-zero fields of other
+func (s constraintsSpec) merge(other constraintsSpec) constraintsSpec {
+	merged := s
+	if other.BrowserSpecs != nil {
+		merged.BrowserSpecs = other.BrowserSpecs
+	}
+	if other.Browsers != nil {
+		merged.Browsers = other.Browsers
+	}
+	if other.OS != nil {
+		merged.OS = other.OS
+	}
+	if other.Devices != nil {
+		merged.Devices = other.Devices
+	}
+	if other.Locales != nil {
+		merged.Locales = other.Locales
+	}
+	if other.ExpandLocales != nil {
+		merged.ExpandLocales = other.ExpandLocales
+	}
+	if other.Language != "" {
+		merged.Language = other.Language
+	}
+	if other.Region != "" {
+		merged.Region = other.Region
+	}
+	if other.HTTPVersion != "" {
+		merged.HTTPVersion = other.HTTPVersion
+	}
+	if other.Strict != nil {
+		merged.Strict = other.Strict
+	}
+	if other.Screen != nil {
+		merged.Screen = other.Screen
+	}
+	if other.HeaderPolicy != nil {
+		merged.HeaderPolicy = other.HeaderPolicy
+	}
+	if other.Accept != "" {
+		merged.Accept = other.Accept
+	}
+	if other.RequestContext != "" {
+		merged.RequestContext = other.RequestContext
+	}
+	if other.SecFetchSite != "" {
+		merged.SecFetchSite = other.SecFetchSite
+	}
+	if other.SecFetchUser != "" {
+		merged.SecFetchUser = other.SecFetchUser
+	}
+	merged.Preset = ""
+	return merged
+}
+
+// constraints converts the spec to Constraints
+func (s constraintsSpec) constraints() Constraints {
+	c := Constraints{
+		HeaderConstraints: HeaderConstraints{
+			Browsers:       s.Browsers,
+			OS:             s.OS,
+			Devices:        s.Devices,
+			Locales:        s.Locales,
+			Language:       s.Language,
+			Region:         s.Region,
+			HTTPVersion:    s.HTTPVersion,
+			Strict:         s.Strict != nil && *s.Strict,
+			Accept:         s.Accept,
+			RequestContext: s.RequestContext,
+			SecFetchSite:   s.SecFetchSite,
+			SecFetchUser:   s.SecFetchUser,
+			ExpandLocales:  s.ExpandLocales != nil && *s.ExpandLocales,
+		},
+	}
+	for _, spec := range s.BrowserSpecs {
+		c.BrowserSpecs = append(c.BrowserSpecs, &BrowserSpec{
+			Name:        spec.Name,
+			MinVersion:  spec.MinVersion,
+			MaxVersion:  spec.MaxVersion,
+			HTTPVersion: spec.HTTPVersion,
+		})
+	}
+	if s.HeaderPolicy != nil {
+		c.HeaderPolicy = &HeaderPolicy{
+			Deny:          s.HeaderPolicy.Deny,
+			DenyByBrowser: s.HeaderPolicy.DenyByBrowser,
+			Allow:         s.HeaderPolicy.Allow,
+		}
+	}
+	if s.Screen != nil {
+		c.Screen = &Screen{
+			MinWidth:            s.Screen.MinWidth,
+			MaxWidth:            s.Screen.MaxWidth,
+			MinHeight:           s.Screen.MinHeight,
+			MaxHeight:           s.Screen.MaxHeight,
+			MinDevicePixelRatio: s.Screen.MinDevicePixelRatio,
+			MaxDevicePixelRatio: s.Screen.MaxDevicePixelRatio,
+		}
+	}
+	return c
+}
@@ -0,0 +1,94 @@
+package forgeron
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadConstraintsYAML(t *testing.T) {
+	profiles, err := LoadConstraints(filepath.Join("testdata", "config", "profiles.yaml"))
+	if err != nil {
+		t.Fatalf("LoadConstraints() error = %v", err)
+	}
+	if len(profiles) != 4 {
+		t.Fatalf("got %d profiles, want 4", len(profiles))
+	}
+
+	def, err := profiles.Get("default")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !reflect.DeepEqual(def.Browsers, []Browser{Chrome}) || !reflect.DeepEqual(def.Locales, []string{"de-DE", "de"}) {
+		t.Errorf("preset not merged into profile: %+v", def.HeaderConstraints)
+	}
+	if !def.Strict {
+		t.Error("expected strict mode from the preset")
+	}
+	if def.Screen == nil || def.Screen.MinWidth == nil || *def.Screen.MinWidth != 1280 {
+		t.Errorf("unexpected screen constraints: %+v", def.Screen)
+	}
+
+	mobile, err := profiles.Get("mobile")
+	if err != nil {
+		t.Fatalf("Get() included profile error = %v", err)
+	}
+	if !reflect.DeepEqual(mobile.Devices, []Device{Mobile}) {
+		t.Errorf("Devices = %v, want [mobile]", mobile.Devices)
+	}
+
+	firefox, err := profiles.Get("modern-firefox")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if len(firefox.BrowserSpecs) != 2 || firefox.BrowserSpecs[0].MinVersion != 140 || firefox.BrowserSpecs[1].String() != "chrome=130-140" {
+		t.Errorf("unexpected browser specs: %+v", firefox.BrowserSpecs)
+	}
+
+	clean, err := profiles.Get("clean-headers")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if clean.HeaderPolicy == nil || !reflect.DeepEqual(clean.HeaderPolicy.DenyByBrowser["chrome"], []string{"DNT"}) {
+		t.Errorf("unexpected header policy: %+v", clean.HeaderPolicy)
+	}
+
+	if _, err := profiles.Get("missing"); err == nil {
+		t.Error("expected error for a missing profile")
+	}
+}
+
+func TestLoadConstraintsJSON(t *testing.T) {
+	profiles, err := LoadConstraints(filepath.Join("testdata", "config", "profiles.json"))
+	if err != nil {
+		t.Fatalf("LoadConstraints() error = %v", err)
+	}
+	french, err := profiles.Get("french")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if french.Language != "fr" || french.Region != "FR" {
+		t.Errorf("unexpected language/region: %q/%q", french.Language, french.Region)
+	}
+	swiss, err := profiles.Get("swiss")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !swiss.ExpandLocales {
+		t.Error("expected expandLocales from the profile")
+	}
+	recent, err := profiles.Get("recent")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	want := []*BrowserSpec{{Name: Chrome, MinVersion: 115}, {Name: Firefox, MinVersion: 118, MaxVersion: 121}, {Name: Safari, MinVersion: 17}}
+	if !reflect.DeepEqual(recent.BrowserSpecs, want) {
+		t.Errorf("BrowserSpecs = %v, want %v", recent.BrowserSpecs, want)
+	}
+}
+
+func TestLoadConstraintsIncludeCycle(t *testing.T) {
+	if _, err := LoadConstraints(filepath.Join("testdata", "config", "cycle.yaml")); err == nil {
+		t.Error("expected error for an include cycle")
+	}
+}
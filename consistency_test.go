@@ -0,0 +1,61 @@
+package forgeron
+
+import (
+	"testing"
+)
+
+// TestDataConsistency generates headers for every browser of the data and checks the user agent parses back to it,
+// catching drift between the data files and the generation logic across the whole dataset
+func TestDataConsistency(t *testing.T) {
+	gen, err := NewHeaderGenerator()
+	if err != nil {
+		t.Fatalf("NewHeaderGenerator() error = %v", err)
+	}
+	if len(gen.uniqueBrowsers) == 0 {
+		t.Fatal("no browsers found in the data")
+	}
+
+	for _, browser := range gen.uniqueBrowsers {
+		if browser.Name == nil || len(browser.Version) == 0 {
+			continue
+		}
+		name, major := Browser(*browser.Name), browser.Version[0]
+		httpVersion := HTTPVersion(browser.HTTPVersion)
+		t.Run(browser.CompleteString, func(t *testing.T) {
+			spec := &BrowserSpec{Name: name, MinVersion: major, MaxVersion: major, HTTPVersion: httpVersion}
+			headers, err := gen.GenerateHeaders(HeaderConstraints{
+				BrowserSpecs: []*BrowserSpec{spec},
+				HTTPVersion:  httpVersion,
+				Strict:       true,
+			})
+			if err != nil {
+				t.Fatalf("GenerateHeaders() error = %v", err)
+			}
+			userAgent := headerValue(headers, "user-agent")
+			info := parseUserAgent(userAgent)
+			if info.Browser != name || info.Version != major {
+				t.Errorf("user agent %q parses to %s %d, want %s %d", userAgent, info.Browser, info.Version, name, major)
+			}
+			if info.OS == "" {
+				t.Errorf("user agent %q has no known OS", userAgent)
+			}
+
+			// Every OS the browser is available on must generate user agents of that OS
+			for _, os := range SupportedOS {
+				headers, err := gen.GenerateHeaders(HeaderConstraints{
+					BrowserSpecs: []*BrowserSpec{spec},
+					OS:           []OS{os},
+					HTTPVersion:  httpVersion,
+					Strict:       true,
+				})
+				if err != nil {
+					continue
+				}
+				userAgent := headerValue(headers, "user-agent")
+				if info := parseUserAgent(userAgent); info.OS != os || info.Browser != name || info.Version != major {
+					t.Errorf("user agent %q for %s parses to %s %d on %s", userAgent, os, info.Browser, info.Version, info.OS)
+				}
+			}
+		})
+	}
+}
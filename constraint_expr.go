@@ -0,0 +1,399 @@
+package forgeron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// exprToken is a token of a constraint expression
+type exprToken struct {
+	value string
+	pos   int
+}
+
+// exprOperators are the operator tokens of constraint expressions, longest first
+var exprOperators = []string{"&&", "==", ">=", "<=", "=", ">", "<", "(", ")", ","}
+
+// ParseHeaderConstraints parses a compact constraint expression into header constraints, e.g.
+//
+//	browser in (chrome>=120, edge) && os=windows && device=desktop && locale=de-DE
+//
+// Clauses are joined with &&, each one sets a field with = or lists values with in (...).
+// The fields are browser, os, device, locale, language, region and http. Browsers can carry
+// version bounds with >=, <=, >, < or =, e.g. chrome>=120<=140 or firefox=118-121.
+func ParseHeaderConstraints(expr string) (HeaderConstraints, error) {
+	tokens, err := tokenizeExpr(expr)
+	if err != nil {
+		return HeaderConstraints{}, err
+	}
+	p := &exprParser{tokens: tokens, end: len(expr), seen: make(map[string]bool)}
+	if len(tokens) == 0 {
+		return HeaderConstraints{}, fmt.Errorf("empty constraint expression")
+	}
+	for {
+		if err := p.parseClause(); err != nil {
+			return HeaderConstraints{}, err
+		}
+		if p.done() {
+			break
+		}
+		if err := p.expect("&&"); err != nil {
+			return HeaderConstraints{}, err
+		}
+	}
+	return p.constraints, nil
+}
+
+// tokenizeExpr splits a constraint expression into tokens
+func tokenizeExpr(expr string) ([]exprToken, error) {
+	var tokens []exprToken
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		if c == ' ' || c == '\t' || c == '\n' {
+			i++
+			continue
+		}
+		if op := exprOperatorAt(expr[i:]); op != "" {
+			tokens = append(tokens, exprToken{value: op, pos: i})
+			i += len(op)
+			continue
+		}
+		if !isExprWordChar(c) {
+			return nil, fmt.Errorf("unexpected character '%c' at offset %d", c, i)
+		}
+		start := i
+		for i < len(expr) && isExprWordChar(expr[i]) {
+			i++
+		}
+		tokens = append(tokens, exprToken{value: expr[start:i], pos: start})
+	}
+	return tokens, nil
+}
+
+// exprOperatorAt returns the operator starting s, or an empty string
+func exprOperatorAt(s string) string {
+	for _, op := range exprOperators {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// isExprWordChar reports whether c can be part of a field name or value
+func isExprWordChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.'
+}
+
+// exprFieldAliases maps the plural field names to the singular ones
+var exprFieldAliases = map[string]string{"browsers": "browser", "devices": "device", "locales": "locale"}
+
+// exprParser parses the tokens of a constraint expression into header constraints
+type exprParser struct {
+	tokens      []exprToken
+	i           int
+	end         int
+	seen        map[string]bool
+	constraints HeaderConstraints
+}
+
+// done reports whether every token was consumed
+func (p *exprParser) done() bool {
+	return p.i >= len(p.tokens)
+}
+
+// pos returns the offset of the current token, for error messages
+func (p *exprParser) pos() int {
+	if p.done() {
+		return p.end
+	}
+	return p.tokens[p.i].pos
+}
+
+// peek returns the current token value, or an empty string at the end
+func (p *exprParser) peek() string {
+	if p.done() {
+		return ""
+	}
+	return p.tokens[p.i].value
+}
+
+// next consumes and returns the current token value
+func (p *exprParser) next() string {
+	value := p.peek()
+	p.i++
+	return value
+}
+
+// expect consumes the current token, which must be value
+func (p *exprParser) expect(value string) error {
+	if got := p.peek(); got != value {
+		return p.errorf("expected '%s', got %s", value, describeToken(got))
+	}
+	p.i++
+	return nil
+}
+
+// word consumes a field name or value
+func (p *exprParser) word() (string, error) {
+	value := p.peek()
+	if value == "" || exprOperatorAt(value) != "" {
+		return "", p.errorf("expected a value, got %s", describeToken(value))
+	}
+	p.i++
+	return value, nil
+}
+
+// errorf returns an error located at the current token
+func (p *exprParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("invalid constraint expression at offset %d: %s", p.pos(), fmt.Sprintf(format, args...))
+}
+
+// describeToken quotes a token for error messages
+func describeToken(value string) string {
+	if value == "" {
+		return "end of expression"
+	}
+	return "'" + value + "'"
+}
+
+// parseClause parses a single field clause: field = value or field in (value, ...)
+func (p *exprParser) parseClause() error {
+	fieldPos := p.pos()
+	field, err := p.word()
+	if err != nil {
+		return err
+	}
+	field = strings.ToLower(field)
+	// Plural fields are aliases, setting both would append their values
+	key := field
+	if singular, ok := exprFieldAliases[field]; ok {
+		key = singular
+	}
+	if p.seen[key] {
+		return fmt.Errorf("invalid constraint expression at offset %d: duplicate field '%s'", fieldPos, field)
+	}
+	p.seen[key] = true
+
+	var items []exprItem
+	switch op := p.next(); op {
+	case "=", "==":
+		item, err := p.parseItem(field)
+		if err != nil {
+			return err
+		}
+		items = append(items, item)
+	case "in":
+		if err := p.expect("("); err != nil {
+			return err
+		}
+		for {
+			item, err := p.parseItem(field)
+			if err != nil {
+				return err
+			}
+			items = append(items, item)
+			if p.peek() != "," {
+				break
+			}
+			p.i++
+		}
+		if err := p.expect(")"); err != nil {
+			return err
+		}
+	default:
+		p.i--
+		return p.errorf("expected '=' or 'in' after '%s', got %s", field, describeToken(op))
+	}
+	return p.apply(field, fieldPos, items)
+}
+
+// exprItem is a value of a clause, with the version bounds of browsers
+type exprItem struct {
+	value      string
+	minVersion int
+	maxVersion int
+}
+
+// parseItem parses a value and, for browsers, its version bounds
+func (p *exprParser) parseItem(field string) (exprItem, error) {
+	value, err := p.word()
+	if err != nil {
+		return exprItem{}, err
+	}
+	item := exprItem{value: value}
+	for {
+		op := p.peek()
+		if op != ">=" && op != "<=" && op != ">" && op != "<" && op != "=" && op != "==" {
+			if item.maxVersion > 0 && item.minVersion > item.maxVersion {
+				return item, p.errorf("min version %d above max version %d", item.minVersion, item.maxVersion)
+			}
+			return item, nil
+		}
+		if field != "browser" && field != "browsers" {
+			return item, p.errorf("version bounds are only supported on browsers")
+		}
+		p.i++
+		versionPos := p.pos()
+		raw, err := p.word()
+		if err != nil {
+			return item, err
+		}
+		if from, to, ok := strings.Cut(raw, "-"); ok && (op == "=" || op == "==") {
+			min, minErr := strconv.Atoi(from)
+			max, maxErr := strconv.Atoi(to)
+			if minErr != nil || maxErr != nil || min <= 0 || max < min {
+				return item, fmt.Errorf("invalid constraint expression at offset %d: invalid version range '%s'", versionPos, raw)
+			}
+			item.minVersion, item.maxVersion = min, max
+			continue
+		}
+		version, err := strconv.Atoi(raw)
+		if err != nil || version <= 0 {
+			return item, fmt.Errorf("invalid constraint expression at offset %d: invalid version '%s'", versionPos, raw)
+		}
+		// A zero max version means no upper bound
+		if op == "<" && version == 1 {
+			return item, fmt.Errorf("invalid constraint expression at offset %d: no version below 1", versionPos)
+		}
+		switch op {
+		case ">=":
+			item.minVersion = version
+		case ">":
+			item.minVersion = version + 1
+		case "<=":
+			item.maxVersion = version
+		case "<":
+			item.maxVersion = version - 1
+		default:
+			item.minVersion, item.maxVersion = version, version
+		}
+	}
+}
+
+// apply sets the clause values on the constraints
+func (p *exprParser) apply(field string, fieldPos int, items []exprItem) error {
+	errorf := func(format string, args ...any) error {
+		return fmt.Errorf("invalid constraint expression at offset %d: %s", fieldPos, fmt.Sprintf(format, args...))
+	}
+	single := func() (string, error) {
+		if len(items) != 1 {
+			return "", errorf("'%s' takes a single value", field)
+		}
+		return items[0].value, nil
+	}
+
+	c := &p.constraints
+	switch field {
+	case "browser", "browsers":
+		var specs []*BrowserSpec
+		versioned := false
+		for _, item := range items {
+			browser := Browser(strings.ToLower(item.value))
+			if err := validateAgainstSupported(browser, SupportedBrowsers); err != nil {
+				return errorf("browser %v", err)
+			}
+			c.Browsers = append(c.Browsers, browser)
+			specs = append(specs, &BrowserSpec{Name: browser, MinVersion: item.minVersion, MaxVersion: item.maxVersion})
+			versioned = versioned || item.minVersion > 0 || item.maxVersion > 0
+		}
+		if versioned {
+			c.BrowserSpecs = specs
+		}
+	case "os":
+		for _, item := range items {
+			os := OS(strings.ToLower(item.value))
+			if err := validateAgainstSupported(os, SupportedOS); err != nil {
+				return errorf("os %v", err)
+			}
+			c.OS = append(c.OS, os)
+		}
+	case "device", "devices":
+		for _, item := range items {
+			device := Device(strings.ToLower(item.value))
+			if err := validateAgainstSupported(device, SupportedDevices); err != nil {
+				return errorf("device %v", err)
+			}
+			c.Devices = append(c.Devices, device)
+		}
+	case "locale", "locales":
+		for _, item := range items {
+			c.Locales = append(c.Locales, item.value)
+		}
+	case "language":
+		value, err := single()
+		if err != nil {
+			return err
+		}
+		c.Language = value
+	case "region":
+		value, err := single()
+		if err != nil {
+			return err
+		}
+		c.Region = value
+	case "http":
+		value, err := single()
+		if err != nil {
+			return err
+		}
+		version := HTTPVersion(strings.TrimSuffix(strings.TrimSuffix(value, ".1"), ".0"))
+		if err := validateAgainstSupported(version, SupportedHTTP); err != nil {
+			return errorf("http %v", err)
+		}
+		c.HTTPVersion = version
+	default:
+		return errorf("unknown field '%s'", field)
+	}
+	return nil
+}
+
+// ParseBrowserSpec parses a compact browser specification such as "chrome>=115", "firefox=118-121",
+// "chrome>=120<=140" or a bare "safari", using the version bounds of constraint expressions
+func ParseBrowserSpec(spec string) (*BrowserSpec, error) {
+	tokens, err := tokenizeExpr(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid browser spec '%s': %w", spec, err)
+	}
+	p := &exprParser{tokens: tokens, end: len(spec)}
+	item, err := p.parseItem("browser")
+	if err == nil && !p.done() {
+		err = p.errorf("unexpected %s", describeToken(p.peek()))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid browser spec '%s': %w", spec, err)
+	}
+	browser := Browser(strings.ToLower(item.value))
+	if err := validateAgainstSupported(browser, SupportedBrowsers); err != nil {
+		return nil, fmt.Errorf("invalid browser spec '%s': %w", spec, err)
+	}
+	return &BrowserSpec{Name: browser, MinVersion: item.minVersion, MaxVersion: item.maxVersion}, nil
+}
+
+// ParseBrowserSpecs parses compact browser specifications, see ParseBrowserSpec
+func ParseBrowserSpecs(specs ...string) ([]*BrowserSpec, error) {
+	parsed := make([]*BrowserSpec, len(specs))
+	for i, spec := range specs {
+		var err error
+		if parsed[i], err = ParseBrowserSpec(spec); err != nil {
+			return nil, err
+		}
+	}
+	return parsed, nil
+}
+
+// String formats the spec in the compact form read by ParseBrowserSpec
+func (s BrowserSpec) String() string {
+	switch {
+	case s.MinVersion > 0 && s.MinVersion == s.MaxVersion:
+		return fmt.Sprintf("%s=%d", s.Name, s.MinVersion)
+	case s.MinVersion > 0 && s.MaxVersion > 0:
+		return fmt.Sprintf("%s=%d-%d", s.Name, s.MinVersion, s.MaxVersion)
+	case s.MinVersion > 0:
+		return fmt.Sprintf("%s>=%d", s.Name, s.MinVersion)
+	case s.MaxVersion > 0:
+		return fmt.Sprintf("%s<=%d", s.Name, s.MaxVersion)
+	}
+	return string(s.Name)
+}
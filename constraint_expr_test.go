@@ -0,0 +1,159 @@
+package forgeron
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseHeaderConstraints(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want HeaderConstraints
+	}{
+		{
+			name: "full expression",
+			expr: "browser in (chrome>=120, edge) && os=windows && device=desktop && locale=de-DE",
+			want: HeaderConstraints{
+				Browsers:     []Browser{Chrome, Edge},
+				BrowserSpecs: []*BrowserSpec{{Name: Chrome, MinVersion: 120}, {Name: Edge}},
+				OS:           []OS{Windows},
+				Devices:      []Device{Desktop},
+				Locales:      []string{"de-DE"},
+			},
+		},
+		{
+			name: "browsers without versions",
+			expr: "browser in (firefox, safari)",
+			want: HeaderConstraints{Browsers: []Browser{Firefox, Safari}},
+		},
+		{
+			name: "version range",
+			expr: "browser == chrome>120<=140 && http=1.1",
+			want: HeaderConstraints{
+				Browsers:     []Browser{Chrome},
+				BrowserSpecs: []*BrowserSpec{{Name: Chrome, MinVersion: 121, MaxVersion: 140}},
+				HTTPVersion:  HTTP1,
+			},
+		},
+		{
+			name: "language and region",
+			expr: "language=pt&&region=BR&&os in (android,ios)",
+			want: HeaderConstraints{Language: "pt", Region: "BR", OS: []OS{Android, IOS}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHeaderConstraints(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseHeaderConstraints() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseHeaderConstraints() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHeaderConstraintsErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{expr: "", want: "empty constraint expression"},
+		{expr: "browser=opera", want: "offset 0: browser value 'opera' is not supported"},
+		{expr: "os=windows && os=linux", want: "offset 14: duplicate field 'os'"},
+		{expr: "os=windows os=linux", want: "expected '&&', got 'os'"},
+		{expr: "browser in (chrome", want: "expected ')', got end of expression"},
+		{expr: "os>=10", want: "expected '=' or 'in' after 'os'"},
+		{expr: "os=windows>=10", want: "version bounds are only supported on browsers"},
+		{expr: "browser=chrome>=x", want: "invalid version 'x'"},
+		{expr: "language in (pt, fr)", want: "'language' takes a single value"},
+		{expr: "color=red", want: "unknown field 'color'"},
+		{expr: "locale=en_US!", want: "unexpected character '!' at offset 12"},
+		{expr: "browser=chrome<1", want: "offset 15: no version below 1"},
+		{expr: "browser=chrome>=140<=120", want: "min version 140 above max version 120"},
+		{expr: "browser in (chrome>130<131, edge)", want: "min version 131 above max version 130"},
+		{expr: "browser=chrome && browsers=firefox", want: "offset 18: duplicate field 'browsers'"},
+		{expr: "devices=mobile && device=tablet", want: "duplicate field 'device'"},
+		{expr: "locale=fr-FR && locales in (de-DE)", want: "duplicate field 'locales'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseHeaderConstraints(tt.expr)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseHeaderConstraints() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBrowserSpec(t *testing.T) {
+	tests := []struct {
+		spec string
+		want BrowserSpec
+	}{
+		{"safari", BrowserSpec{Name: Safari}},
+		{"chrome>=115", BrowserSpec{Name: Chrome, MinVersion: 115}},
+		{"Firefox=118-121", BrowserSpec{Name: Firefox, MinVersion: 118, MaxVersion: 121}},
+		{"edge = 120", BrowserSpec{Name: Edge, MinVersion: 120, MaxVersion: 120}},
+		{"chrome>120<=140", BrowserSpec{Name: Chrome, MinVersion: 121, MaxVersion: 140}},
+		{"chrome<130", BrowserSpec{Name: Chrome, MaxVersion: 129}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseBrowserSpec(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseBrowserSpec() error = %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("ParseBrowserSpec() = %+v, want %+v", *got, tt.want)
+			}
+			// The compact form round trips
+			again, err := ParseBrowserSpec(got.String())
+			if err != nil || *again != *got {
+				t.Errorf("ParseBrowserSpec(%q) = %+v, %v, want %+v", got.String(), again, err, *got)
+			}
+		})
+	}
+
+	for _, spec := range []string{"", "netscape>=4", "chrome>=", "chrome>=abc", "firefox=121-118", "chrome>=140<=120", "chrome<1", "chrome>=115 firefox", "chrome,firefox"} {
+		if _, err := ParseBrowserSpec(spec); err == nil {
+			t.Errorf("ParseBrowserSpec(%q) error = nil, want an error", spec)
+		}
+	}
+}
+
+func TestParseHeaderConstraintsVersionRange(t *testing.T) {
+	got, err := ParseHeaderConstraints("browser in (firefox=118-121, chrome)")
+	if err != nil {
+		t.Fatalf("ParseHeaderConstraints() error = %v", err)
+	}
+	want := []*BrowserSpec{{Name: Firefox, MinVersion: 118, MaxVersion: 121}, {Name: Chrome}}
+	if !reflect.DeepEqual(got.BrowserSpecs, want) {
+		t.Errorf("BrowserSpecs = %v, want %v", got.BrowserSpecs, want)
+	}
+}
+
+func TestParsedConstraintsGenerateHeaders(t *testing.T) {
+	constraints, err := ParseHeaderConstraints("browser in (chrome>=120, edge) && os=windows && device=desktop && locale=de-DE")
+	if err != nil {
+		t.Fatalf("ParseHeaderConstraints() error = %v", err)
+	}
+	gen, err := NewHeaderGenerator()
+	if err != nil {
+		t.Fatalf("NewHeaderGenerator() error = %v", err)
+	}
+	headers, err := gen.GenerateOrderedHeaders(constraints)
+	if err != nil {
+		t.Fatalf("GenerateOrderedHeaders() error = %v", err)
+	}
+	info := parseUserAgent(headers.Get("User-Agent"))
+	if info.Browser != Chrome && info.Browser != Edge || info.OS != Windows {
+		t.Errorf("User-Agent %q does not match the constraints", headers.Get("User-Agent"))
+	}
+	if !strings.HasPrefix(headers.Get("Accept-Language"), "de-DE") {
+		t.Errorf("Accept-Language = %q, want de-DE first", headers.Get("Accept-Language"))
+	}
+}
@@ -0,0 +1,41 @@
+package forgeron
+
+// Constraints describes a whole identity request, shared by header and fingerprint generation
+type Constraints struct {
+	HeaderConstraints
+	Screen *Screen
+}
+
+// NewConstraints builds Constraints from header constraints and optional screen constraints
+func NewConstraints(headers HeaderConstraints, screen *Screen) Constraints {
+	return Constraints{
+		HeaderConstraints: headers,
+		Screen:            screen,
+	}
+}
+
+// Validate validates the constraints that can be checked without the generator data
+func (c Constraints) Validate() error {
+	if c.Screen != nil {
+		return c.Screen.Validate()
+	}
+	return nil
+}
+
+// WithConstraints sets the header and screen constraints of the fingerprint generator from a single Constraints,
+// strict mode included
+func WithConstraints(constraints Constraints) FingerprintOption {
+	return func(g *FingerprintGenerator) {
+		g.headerConstraints = constraints.HeaderConstraints
+		g.screen = constraints.Screen
+		g.strict = constraints.Strict
+	}
+}
+
+// Constraints returns the constraints currently used by the fingerprint generator
+func (g *FingerprintGenerator) Constraints() Constraints {
+	return Constraints{
+		HeaderConstraints: g.headerConstraints,
+		Screen:            g.screen,
+	}
+}
@@ -0,0 +1,74 @@
+package forgeron
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptrace"
+)
+
+// fingerprintContextKey is the context key of the fingerprint attached to a context
+type fingerprintContextKey struct{}
+
+// NewContext returns a copy of ctx carrying the fingerprint
+func NewContext(ctx context.Context, fp *Fingerprint) context.Context {
+	return context.WithValue(ctx, fingerprintContextKey{}, fp)
+}
+
+// FromContext returns the fingerprint carried by ctx, if any
+func FromContext(ctx context.Context) (*Fingerprint, bool) {
+	fp, ok := ctx.Value(fingerprintContextKey{}).(*Fingerprint)
+	return fp, ok && fp != nil
+}
+
+// IdentityEvent reports which identity made a request and over which connection
+type IdentityEvent struct {
+	Fingerprint *Fingerprint
+	Method      string
+	URL         string
+	RemoteAddr  string
+	ConnReused  bool
+}
+
+// TraceIdentity returns a copy of the request reporting an IdentityEvent once its connection is obtained.
+// The identity is read from the request context; requests without one are returned unchanged.
+func TraceIdentity(req *http.Request, report func(IdentityEvent)) *http.Request {
+	fp, ok := FromContext(req.Context())
+	if !ok {
+		return req
+	}
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			event := IdentityEvent{
+				Fingerprint: fp,
+				Method:      req.Method,
+				URL:         req.URL.String(),
+				ConnReused:  info.Reused,
+			}
+			if info.Conn != nil {
+				event.RemoteAddr = info.Conn.RemoteAddr().String()
+			}
+			report(event)
+		},
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+}
+
+// identityTransport is an http.RoundTripper tracing the identity of every request
+type identityTransport struct {
+	next   http.RoundTripper
+	report func(IdentityEvent)
+}
+
+// RoundTrip traces the identity of the request and forwards it to the wrapped transport
+func (t *identityTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.next.RoundTrip(TraceIdentity(req, t.report))
+}
+
+// IdentityTransport wraps a transport so every request carrying a fingerprint in its context is reported.
+// A nil next uses http.DefaultTransport.
+func IdentityTransport(next http.RoundTripper, report func(IdentityEvent)) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &identityTransport{next: next, report: report}
+}
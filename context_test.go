@@ -0,0 +1,47 @@
+package forgeron
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestContextRoundTrip(t *testing.T) {
+	if _, ok := FromContext(context.Background()); ok {
+		t.Error("expected no fingerprint in an empty context")
+	}
+	fp := &Fingerprint{Navigator: NavigatorFingerprint{UserAgent: "Mozilla/5.0"}}
+	got, ok := FromContext(NewContext(context.Background(), fp))
+	if !ok || got != fp {
+		t.Errorf("FromContext() = %v, %v, want the stored fingerprint", got, ok)
+	}
+}
+
+func TestIdentityTransportReportsIdentity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	var events []IdentityEvent
+	client := &http.Client{Transport: IdentityTransport(nil, func(event IdentityEvent) {
+		events = append(events, event)
+	})}
+
+	fp := &Fingerprint{Navigator: NavigatorFingerprint{UserAgent: "Mozilla/5.0"}}
+	req, err := http.NewRequestWithContext(NewContext(context.Background(), fp), http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	if events[0].Fingerprint != fp || events[0].URL != server.URL || events[0].RemoteAddr == "" {
+		t.Errorf("unexpected event: %+v", events[0])
+	}
+}
@@ -0,0 +1,150 @@
+package forgeron
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// SameSite is the value of the SameSite cookie attribute
+type SameSite string
+
+const (
+	SameSiteLax  SameSite = "Lax"
+	SameSiteNone SameSite = "None"
+)
+
+// ThirdPartyCookies describes how third-party cookies are stored by default
+type ThirdPartyCookies string
+
+const (
+	ThirdPartyAllowed     ThirdPartyCookies = "allowed"
+	ThirdPartyPartitioned ThirdPartyCookies = "partitioned"
+	ThirdPartyBlocked     ThirdPartyCookies = "blocked"
+)
+
+// CookiePolicy describes the default cookie behavior of the browser of a fingerprint,
+// so session layers can mimic how it stores and sends cookies
+type CookiePolicy struct {
+	// SameSiteDefault is applied to cookies set without a SameSite attribute
+	SameSiteDefault SameSite
+	// LaxAllowsUnsafePost is Chromium's two minute window sending fresh default-Lax cookies on top-level POSTs
+	LaxAllowsUnsafePost bool
+	// NoneRequiresSecure rejects SameSite=None cookies without the Secure attribute
+	NoneRequiresSecure bool
+	// Partitioned reports support for the Partitioned attribute (CHIPS)
+	Partitioned bool
+	// ThirdParty is the default handling of third-party cookies
+	ThirdParty ThirdPartyCookies
+	// MaxCookiesPerDomain and MaxCookies are the eviction limits, 0 when the browser has no fixed limit
+	MaxCookiesPerDomain int
+	MaxCookies          int
+	// MaxCookieSize is the maximum size in bytes of a cookie name and value
+	MaxCookieSize int
+}
+
+// CookiePolicy returns the cookie behavior of the browser of the fingerprint
+func (f *Fingerprint) CookiePolicy() CookiePolicy {
+	info := parseUserAgent(f.Navigator.UserAgent)
+	switch engineOf(f.Navigator.UserAgent) {
+	case blinkEngine:
+		return CookiePolicy{
+			// Lax by default and Secure SameSite=None shipped in Chrome 80
+			SameSiteDefault:     sameSiteFrom(info.Version, 80),
+			LaxAllowsUnsafePost: info.Version >= 80,
+			NoneRequiresSecure:  info.Version >= 80,
+			Partitioned:         info.Version >= 114,
+			ThirdParty:          ThirdPartyAllowed,
+			MaxCookiesPerDomain: 180,
+			MaxCookies:          3300,
+			MaxCookieSize:       4096,
+		}
+	case geckoEngine:
+		return CookiePolicy{
+			SameSiteDefault:     SameSiteNone,
+			Partitioned:         info.Version >= 141,
+			ThirdParty:          ThirdPartyPartitioned,
+			MaxCookiesPerDomain: 180,
+			MaxCookies:          3000,
+			MaxCookieSize:       4096,
+		}
+	case webKitEngine:
+		// Intelligent Tracking Prevention blocks third-party cookies instead of partitioning them
+		return CookiePolicy{
+			SameSiteDefault: SameSiteNone,
+			ThirdParty:      ThirdPartyBlocked,
+			MaxCookieSize:   4096,
+		}
+	default:
+		return CookiePolicy{SameSiteDefault: SameSiteNone, ThirdParty: ThirdPartyAllowed, MaxCookieSize: 4096}
+	}
+}
+
+// sameSiteFrom returns Lax from the given browser version on, None before
+func sameSiteFrom(version, since int) SameSite {
+	if version >= since {
+		return SameSiteLax
+	}
+	return SameSiteNone
+}
+
+// CookieHeader returns the Cookie header the browser of the fingerprint sends to u with the cookies of the jar:
+// name=value pairs in the jar order, longest path first with net/http/cookiejar as in browsers, joined with "; ".
+// Values are sent as stored, quotes included, where http.Request.AddCookie rewrites them. Empty without cookies.
+func (f *Fingerprint) CookieHeader(jar http.CookieJar, u *url.URL) string {
+	return strings.Join(cookiePairs(jar.Cookies(u)), "; ")
+}
+
+// OrderedHeadersWithCookies returns the headers of OrderedHeaders with the Cookie header of CookieHeader where the
+// browser sends it, e.g. last for Chrome and after Referer for Firefox, which also sends a header per cookie over
+// HTTP/2 so that HPACK indexes them
+func (f *Fingerprint) OrderedHeadersWithCookies(jar http.CookieJar, u *url.URL) OrderedHeaders {
+	headers := make(map[string]string, len(f.Headers)+1)
+	for name, value := range f.Headers {
+		if !strings.EqualFold(name, "cookie") {
+			headers[name] = value
+		}
+	}
+	pairs := cookiePairs(jar.Cookies(u))
+	if len(pairs) == 0 {
+		return orderHeaders(headers, f.headerOrder())
+	}
+	name := "Cookie"
+	if f.httpVersion() == HTTP2 {
+		name = "cookie"
+	}
+	headers[name] = strings.Join(pairs, "; ")
+	ordered := orderHeaders(headers, f.headerOrder())
+	if !f.splitsCookies() {
+		return ordered
+	}
+	crumbs := make(OrderedHeaders, len(pairs))
+	for i, pair := range pairs {
+		crumbs[i] = HeaderKV{Name: name, Value: pair}
+	}
+	i := slices.IndexFunc(ordered, func(h HeaderKV) bool { return h.Name == name })
+	return slices.Replace(ordered, i, i+1, crumbs...)
+}
+
+// splitsCookies reports whether the browser sends a Cookie header per cookie, as Firefox does over HTTP/2
+func (f *Fingerprint) splitsCookies() bool {
+	return f.httpVersion() == HTTP2 && engineOf(f.Navigator.UserAgent) == geckoEngine
+}
+
+// cookiePairs formats cookies as browsers do, a cookie without name being sent as its bare value
+func cookiePairs(cookies []*http.Cookie) []string {
+	pairs := make([]string, 0, len(cookies))
+	for _, c := range cookies {
+		value := c.Value
+		if c.Quoted {
+			value = `"` + value + `"`
+		}
+		if c.Name == "" {
+			pairs = append(pairs, value)
+			continue
+		}
+		pairs = append(pairs, c.Name+"="+value)
+	}
+	return pairs
+}
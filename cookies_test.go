@@ -0,0 +1,137 @@
+package forgeron
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"slices"
+	"testing"
+)
+
+func TestCookiePolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		want      CookiePolicy
+	}{
+		{
+			name:      "chrome",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36",
+			want: CookiePolicy{SameSiteDefault: SameSiteLax, LaxAllowsUnsafePost: true, NoneRequiresSecure: true, Partitioned: true,
+				ThirdParty: ThirdPartyAllowed, MaxCookiesPerDomain: 180, MaxCookies: 3300, MaxCookieSize: 4096},
+		},
+		{
+			name:      "old chrome",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/79.0.3945.88 Safari/537.36",
+			want: CookiePolicy{SameSiteDefault: SameSiteNone, ThirdParty: ThirdPartyAllowed,
+				MaxCookiesPerDomain: 180, MaxCookies: 3300, MaxCookieSize: 4096},
+		},
+		{
+			name:      "firefox",
+			userAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:147.0) Gecko/20100101 Firefox/147.0",
+			want: CookiePolicy{SameSiteDefault: SameSiteNone, Partitioned: true, ThirdParty: ThirdPartyPartitioned,
+				MaxCookiesPerDomain: 180, MaxCookies: 3000, MaxCookieSize: 4096},
+		},
+		{
+			name:      "chrome on ios",
+			userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 18_7 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/144.0.7559.95 Mobile/15E148 Safari/604.1",
+			want:      CookiePolicy{SameSiteDefault: SameSiteNone, ThirdParty: ThirdPartyBlocked, MaxCookieSize: 4096},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fp := &Fingerprint{Navigator: NavigatorFingerprint{UserAgent: tt.userAgent}}
+			if got := fp.CookiePolicy(); got != tt.want {
+				t.Errorf("CookiePolicy() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// newCookieJarOrFatal returns a jar holding the cookies set by u
+func newCookieJarOrFatal(t *testing.T, u *url.URL, cookies ...*http.Cookie) http.CookieJar {
+	t.Helper()
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatalf("cookiejar.New() error = %v", err)
+	}
+	jar.SetCookies(u, cookies)
+	return jar
+}
+
+func TestCookieHeader(t *testing.T) {
+	u, _ := url.Parse("https://shop.example.com/cart/items")
+	jar := newCookieJarOrFatal(t, u,
+		&http.Cookie{Name: "session", Value: "abc", Path: "/"},
+		&http.Cookie{Name: "cart", Value: "1 2", Path: "/cart", Quoted: true},
+		&http.Cookie{Name: "theme", Value: "dark"},
+	)
+	fp := &Fingerprint{Navigator: NavigatorFingerprint{UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36"}}
+	want := `cart="1 2"; theme=dark; session=abc`
+	if got := fp.CookieHeader(jar, u); got != want {
+		t.Errorf("CookieHeader() = %q, want %q", got, want)
+	}
+	other, _ := url.Parse("https://example.org/")
+	if got := fp.CookieHeader(jar, other); got != "" {
+		t.Errorf("CookieHeader() = %q for another site, want none", got)
+	}
+}
+
+func TestOrderedHeadersWithCookies(t *testing.T) {
+	const (
+		chrome  = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36"
+		firefox = "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:147.0) Gecko/20100101 Firefox/147.0"
+	)
+	u, _ := url.Parse("https://example.com/")
+	jar := newCookieJarOrFatal(t, u, &http.Cookie{Name: "a", Value: "1"}, &http.Cookie{Name: "b", Value: "2"})
+	tests := []struct {
+		name      string
+		userAgent string
+		headers   map[string]string
+		want      []string
+	}{
+		{
+			name:      "chrome http1",
+			userAgent: chrome,
+			headers:   map[string]string{"Connection": "keep-alive", "User-Agent": chrome, "Accept-Language": "en-US", "Referer": "https://example.com/"},
+			want:      []string{"Connection", "User-Agent", "Referer", "Accept-Language", "Cookie: a=1; b=2"},
+		},
+		{
+			name:      "chrome http2",
+			userAgent: chrome,
+			headers:   map[string]string{"user-agent": chrome, "accept-language": "en-US", "priority": "u=0, i"},
+			want:      []string{"user-agent", "accept-language", "cookie: a=1; b=2", "priority"},
+		},
+		{
+			name:      "firefox http1",
+			userAgent: firefox,
+			headers:   map[string]string{"Connection": "keep-alive", "User-Agent": firefox, "Referer": "https://example.com/", "Upgrade-Insecure-Requests": "1"},
+			want:      []string{"User-Agent", "Connection", "Referer", "Cookie: a=1; b=2", "Upgrade-Insecure-Requests"},
+		},
+		{
+			name:      "firefox http2",
+			userAgent: firefox,
+			headers:   map[string]string{"user-agent": firefox, "referer": "https://example.com/", "upgrade-insecure-requests": "1"},
+			want:      []string{"user-agent", "referer", "cookie: a=1", "cookie: b=2", "upgrade-insecure-requests"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fp := &Fingerprint{Headers: tt.headers, Navigator: NavigatorFingerprint{UserAgent: tt.userAgent}}
+			var got []string
+			for _, h := range fp.OrderedHeadersWithCookies(jar, u) {
+				if h.Name == "Cookie" || h.Name == "cookie" {
+					got = append(got, h.Name+": "+h.Value)
+				} else {
+					got = append(got, h.Name)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("OrderedHeadersWithCookies() = %v, want %v", got, tt.want)
+			}
+			if _, ok := fp.Headers["Cookie"]; ok {
+				t.Errorf("OrderedHeadersWithCookies() changed the fingerprint headers")
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !forgeron_minimal
+
+package forgeron
+
+import "embed"
+
+// dataDir is the embedded data directory
+const dataDir = "data_points"
+
+//go:embed data_points/*.json data_points/*.zip
+var dataFiles embed.FS
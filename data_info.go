@@ -0,0 +1,67 @@
+package forgeron
+
+import (
+	"slices"
+	"strings"
+)
+
+// DataInfo describes the network data loaded by a generator, e.g. to check what a deployed service serves
+type DataInfo struct {
+	// Schema is the newest data schema of the loaded networks
+	Schema int `json:"schema"`
+	// Requires is the first release reading the data, empty when the data does not record it
+	Requires string `json:"requires,omitempty"`
+	// Browsers lists the browser versions of the data, e.g. "chrome/144.0.0.0"
+	Browsers []string `json:"browsers"`
+	OS       []string `json:"os"`
+	Devices  []string `json:"devices"`
+}
+
+// DataInfo describes the network data loaded by the generator
+func (g *HeaderGenerator) DataInfo() DataInfo {
+	var info DataInfo
+	info.addSchema(g.inputGeneratorNetwork, g.headerGeneratorNetwork)
+	for _, browser := range g.uniqueBrowsers {
+		name, _, _ := strings.Cut(browser.CompleteString, "|")
+		if !slices.Contains(info.Browsers, name) {
+			info.Browsers = append(info.Browsers, name)
+		}
+	}
+	slices.Sort(info.Browsers)
+	info.OS = networkValues(g.inputGeneratorNetwork, "*OPERATING_SYSTEM")
+	info.Devices = networkValues(g.inputGeneratorNetwork, "*DEVICE")
+	return info
+}
+
+// DataInfo describes the network data loaded by the generator
+func (g *FingerprintGenerator) DataInfo() DataInfo {
+	g = g.current()
+	info := g.headerGenerator.DataInfo()
+	info.addSchema(g.network)
+	return info
+}
+
+// addSchema keeps the newest schema of the networks
+func (info *DataInfo) addSchema(networks ...*bayesianNetwork) {
+	for _, network := range networks {
+		if network != nil && network.schema.version > info.Schema {
+			info.Schema = network.schema.version
+			info.Requires = network.schema.requires
+		}
+	}
+}
+
+// networkValues returns the sorted possible values of a node of the network, without missing values
+func networkValues(network *bayesianNetwork, nodeName string) []string {
+	if network == nil || network.NodesByName[nodeName] == nil {
+		return nil
+	}
+	var values []string
+	for _, value := range network.NodesByName[nodeName].PossibleValues {
+		if !isMissingValue(value) {
+			values = append(values, value)
+		}
+	}
+	slices.Sort(values)
+	return values
+}
@@ -0,0 +1,25 @@
+package forgeron
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDataInfo(t *testing.T) {
+	info := newGeneratorOrFatal(t).DataInfo()
+	if info.Schema != DataSchemaVersion {
+		t.Errorf("Schema = %d, want %d", info.Schema, DataSchemaVersion)
+	}
+	if !slices.ContainsFunc(info.Browsers, func(browser string) bool { return strings.HasPrefix(browser, "chrome/") }) {
+		t.Errorf("Browsers = %v, want chrome versions", info.Browsers)
+	}
+	for _, want := range []struct {
+		values []string
+		value  string
+	}{{info.OS, string(Windows)}, {info.OS, string(Android)}, {info.Devices, string(Desktop)}, {info.Devices, string(Mobile)}} {
+		if !slices.Contains(want.values, want.value) {
+			t.Errorf("%v has no %q", want.values, want.value)
+		}
+	}
+}
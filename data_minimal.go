@@ -0,0 +1,12 @@
+//go:build forgeron_minimal
+
+package forgeron
+
+import "embed"
+
+// dataDir is the embedded data directory. The minimal dataset only samples the current major of Chrome, Edge,
+// Firefox and Safari, for builds that cannot ship the full networks.
+const dataDir = "data_minimal"
+
+//go:embed data_minimal/*.json data_minimal/*.zip
+var dataFiles embed.FS
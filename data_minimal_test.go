@@ -0,0 +1,27 @@
+//go:build forgeron_minimal
+
+package forgeron
+
+import "testing"
+
+// TestMinimalData checks the minimal dataset generates consistent identities for each of its browsers.
+// Run it with: go test -tags forgeron_minimal -run TestMinimalData .
+func TestMinimalData(t *testing.T) {
+	for _, browser := range []Browser{Chrome, Edge, Firefox, Safari} {
+		t.Run(string(browser), func(t *testing.T) {
+			gen := newGeneratorOrFatal(t, WithHeaderConstraints(HeaderConstraints{Browsers: []Browser{browser}}), WithStrict(true))
+			for i := 0; i < 20; i++ {
+				fp, err := gen.Generate()
+				if err != nil {
+					t.Fatalf("Generate() error = %v", err)
+				}
+				if got := parseUserAgent(fp.Navigator.UserAgent).Browser; got != browser {
+					t.Errorf("user agent %q parses to %s, want %s", fp.Navigator.UserAgent, got, browser)
+				}
+				if err := fp.Validate(); err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package forgeron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DataSchemaVersion is the version of the network data schema read by this release. Data files of a newer
+// schema are rejected rather than misread.
+const DataSchemaVersion = 1
+
+// ErrIncompatibleData is returned when loading network data of a schema newer than DataSchemaVersion
+var ErrIncompatibleData = errors.New("incompatible data")
+
+// dataSchemaPrefix starts the zip comment stamping network definitions with their schema,
+// e.g. "forgeron-data schema=2 requires=v1.4.0"
+const dataSchemaPrefix = "forgeron-data"
+
+// dataSchema is the schema stamped on a network definition
+type dataSchema struct {
+	version int
+	// requires is the first forgeron release reading the schema, empty when unknown
+	requires string
+}
+
+// parseDataSchema parses the zip comment of a network definition. Networks without a stamp, such as the ones
+// of upstream header-generator releases, have the first schema.
+func parseDataSchema(comment string) (dataSchema, error) {
+	fields := strings.Fields(comment)
+	if len(fields) == 0 || fields[0] != dataSchemaPrefix {
+		return dataSchema{version: 1}, nil
+	}
+	var schema dataSchema
+	for _, field := range fields[1:] {
+		key, value, _ := strings.Cut(field, "=")
+		switch key {
+		case "schema":
+			version, err := strconv.Atoi(value)
+			if err != nil || version < 1 {
+				return dataSchema{}, fmt.Errorf("invalid data schema version %q", value)
+			}
+			schema.version = version
+		case "requires":
+			schema.requires = value
+		}
+	}
+	if schema.version == 0 {
+		return dataSchema{}, fmt.Errorf("missing data schema version in %q", comment)
+	}
+	return schema, nil
+}
+
+// check returns an error when this release cannot read the schema
+func (s dataSchema) check() error {
+	if s.version <= DataSchemaVersion {
+		return nil
+	}
+	if s.requires != "" {
+		return fmt.Errorf("%w: data requires forgeron >= %s (data schema %d, this release reads schema %d)", ErrIncompatibleData, s.requires, s.version, DataSchemaVersion)
+	}
+	return fmt.Errorf("%w: data requires a newer forgeron (data schema %d, this release reads schema %d)", ErrIncompatibleData, s.version, DataSchemaVersion)
+}
@@ -0,0 +1,99 @@
+package forgeron
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// zipNetwork returns a zipped network definition with the given comment
+func zipNetwork(t *testing.T, comment string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	file, err := writer.Create("network.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.Write([]byte(`{"nodes": []}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.SetComment(comment); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseDataSchema(t *testing.T) {
+	tests := []struct {
+		comment string
+		want    dataSchema
+		wantErr bool
+	}{
+		{comment: "", want: dataSchema{version: 1}},
+		{comment: "generated by header-generator", want: dataSchema{version: 1}},
+		{comment: "forgeron-data schema=1", want: dataSchema{version: 1}},
+		{comment: "forgeron-data schema=3 requires=v1.4.0", want: dataSchema{version: 3, requires: "v1.4.0"}},
+		{comment: "forgeron-data schema=x", wantErr: true},
+		{comment: "forgeron-data requires=v1.4.0", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseDataSchema(tt.comment)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseDataSchema(%q) error = %v, wantErr %v", tt.comment, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseDataSchema(%q) = %+v, want %+v", tt.comment, got, tt.want)
+		}
+	}
+}
+
+func TestLoadNetworkChecksDataSchema(t *testing.T) {
+	data := fstest.MapFS{
+		"current.zip":  {Data: zipNetwork(t, "forgeron-data schema=1")},
+		"upstream.zip": {Data: zipNetwork(t, "")},
+		"newer.zip":    {Data: zipNetwork(t, "forgeron-data schema=2 requires=v9.0.0")},
+		"unknown.zip":  {Data: zipNetwork(t, "forgeron-data schema=2")},
+	}
+	for _, name := range []string{"current.zip", "upstream.zip"} {
+		if _, err := loadNetworkFromZip(data, name); err != nil {
+			t.Errorf("loadNetworkFromZip(%s) error = %v", name, err)
+		}
+	}
+
+	_, err := loadNetworkFromZip(data, "newer.zip")
+	if !errors.Is(err, ErrIncompatibleData) {
+		t.Fatalf("loadNetworkFromZip(newer.zip) error = %v, want ErrIncompatibleData", err)
+	}
+	if !strings.Contains(err.Error(), "data requires forgeron >= v9.0.0") {
+		t.Errorf("error = %q, want the required release", err)
+	}
+	if _, err := loadNetworkFromZip(data, "unknown.zip"); !errors.Is(err, ErrIncompatibleData) {
+		t.Errorf("loadNetworkFromZip(unknown.zip) error = %v, want ErrIncompatibleData", err)
+	}
+}
+
+func TestEmbeddedDataSchema(t *testing.T) {
+	for _, name := range []string{"input-network-definition.zip", "header-network-definition.zip", "fingerprint-network-definition.zip"} {
+		data, err := fs.ReadFile(embeddedData(), name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		schema, err := parseDataSchema(reader.Comment)
+		if err != nil || schema.version != DataSchemaVersion {
+			t.Errorf("%s schema = %+v, %v, want version %d", name, schema, err, DataSchemaVersion)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package forgeron
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// diskCacheEntry is a cached fingerprint as stored on disk
+type diskCacheEntry struct {
+	CreatedAt   time.Time    `json:"createdAt"`
+	Fingerprint *Fingerprint `json:"fingerprint"`
+}
+
+// DiskCache is a FingerprintProvider caching generated fingerprints on disk, keyed by the generation request,
+// so short-lived processes reuse identities instead of loading the networks on every run
+type DiskCache struct {
+	dir         string
+	ttl         time.Duration
+	newProvider func() (FingerprintProvider, error)
+	now         func() time.Time
+	cipher      *Cipher
+	mu          sync.Mutex
+	provider    FingerprintProvider
+}
+
+// DiskCacheOption configures a DiskCache
+type DiskCacheOption func(*DiskCache)
+
+// WithCacheCipher encrypts the cache entries with the cipher, entries that cannot be decrypted are cache misses
+func WithCacheCipher(c *Cipher) DiskCacheOption {
+	return func(cache *DiskCache) {
+		cache.cipher = c
+	}
+}
+
+// NewDiskCache creates a disk cache in dir whose entries expire after ttl.
+// newProvider is only called on the first cache miss, so cache hits never load the generator.
+func NewDiskCache(dir string, ttl time.Duration, newProvider func() (FingerprintProvider, error), opts ...DiskCacheOption) (*DiskCache, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("cache TTL must be positive")
+	}
+	if newProvider == nil {
+		return nil, fmt.Errorf("provider constructor is required")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	cache := &DiskCache{dir: dir, ttl: ttl, newProvider: newProvider, now: time.Now}
+	for _, opt := range opts {
+		opt(cache)
+	}
+	return cache, nil
+}
+
+// Generate returns the cached fingerprint for the options, generating and caching one if missing or expired
+func (c *DiskCache) Generate(opts ...FingerprintOption) (*Fingerprint, error) {
+	key, err := cacheKey(NewGenerateRequest(opts...))
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(c.dir, key+".json")
+	if fp := c.read(path); fp != nil {
+		return fp, nil
+	}
+
+	provider, err := c.loadProvider()
+	if err != nil {
+		return nil, err
+	}
+	fp, err := provider.Generate(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.write(path, fp); err != nil {
+		return nil, err
+	}
+	return fp, nil
+}
+
+// Purge removes the expired entries from the cache directory
+func (c *DiskCache) Purge() error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read cache directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(c.dir, entry.Name())
+		if c.read(path) == nil {
+			if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("failed to remove %s: %w", path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// loadProvider creates the provider on first use
+func (c *DiskCache) loadProvider() (FingerprintProvider, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.provider == nil {
+		provider, err := c.newProvider()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create provider: %w", err)
+		}
+		c.provider = provider
+	}
+	return c.provider, nil
+}
+
+// read returns the fingerprint cached at path, or nil if it is missing, unreadable, inconsistent or expired
+func (c *DiskCache) read(path string) *Fingerprint {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	if c.cipher != nil {
+		// Plain entries left by an unencrypted cache are misses, and get replaced by encrypted ones
+		if data, err = c.cipher.Decrypt(data); err != nil {
+			return nil
+		}
+	}
+	var entry diskCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Fingerprint == nil || entry.Fingerprint.Validate() != nil {
+		return nil
+	}
+	if c.now().Sub(entry.CreatedAt) >= c.ttl {
+		return nil
+	}
+	return entry.Fingerprint
+}
+
+// write atomically stores a fingerprint at path
+func (c *DiskCache) write(path string, fp *Fingerprint) error {
+	data, err := json.Marshal(diskCacheEntry{CreatedAt: c.now(), Fingerprint: fp})
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+	if c.cipher != nil {
+		if data, err = c.cipher.Encrypt(data); err != nil {
+			return err
+		}
+	}
+	tmp, err := os.CreateTemp(c.dir, ".entry-*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache entry: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store cache entry: %w", err)
+	}
+	return nil
+}
+
+// cacheKey hashes a generation request into a file name
+func cacheKey(request GenerateRequest) (string, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,133 @@
+package forgeron
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// countingProvider returns a new fingerprint per call and counts calls
+type countingProvider struct {
+	calls int
+}
+
+func (p *countingProvider) Generate(opts ...FingerprintOption) (*Fingerprint, error) {
+	p.calls++
+	return &Fingerprint{Navigator: NavigatorFingerprint{UserAgent: "Mozilla/5.0", Language: "en-US", Languages: []string{"en-US"}, HardwareConcurrency: p.calls}}, nil
+}
+
+func TestDiskCache(t *testing.T) {
+	provider := &countingProvider{}
+	created := 0
+	dir := t.TempDir()
+	cache, err := NewDiskCache(dir, time.Hour, func() (FingerprintProvider, error) {
+		created++
+		return provider, nil
+	})
+	if err != nil {
+		t.Fatalf("NewDiskCache() error = %v", err)
+	}
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	chrome := WithHeaderConstraints(HeaderConstraints{Browsers: []Browser{"chrome"}})
+	first, err := cache.Generate(chrome)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	second, err := cache.Generate(chrome)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if provider.calls != 1 || second.Navigator.HardwareConcurrency != first.Navigator.HardwareConcurrency {
+		t.Errorf("expected a cache hit, provider called %d times", provider.calls)
+	}
+
+	// Different constraints use a different entry
+	if _, err := cache.Generate(WithSlim(true)); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if provider.calls != 2 {
+		t.Errorf("provider called %d times, want 2", provider.calls)
+	}
+
+	// A new process with a fresh cache reuses the entries without creating a provider
+	reopened, err := NewDiskCache(dir, time.Hour, func() (FingerprintProvider, error) {
+		t.Fatal("provider created on a cache hit")
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("NewDiskCache() error = %v", err)
+	}
+	reopened.now = cache.now
+	if _, err := reopened.Generate(chrome); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	// Expired entries are regenerated and purged
+	now = now.Add(2 * time.Hour)
+	if _, err := cache.Generate(chrome); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if provider.calls != 3 {
+		t.Errorf("provider called %d times after expiry, want 3", provider.calls)
+	}
+	if err := cache.Purge(); err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("%d entries left after purge, want 1", len(entries))
+	}
+	if created != 1 {
+		t.Errorf("provider created %d times, want 1", created)
+	}
+}
+
+func TestDiskCacheEncryption(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	c, err := NewCipher(key)
+	if err != nil {
+		t.Fatalf("NewCipher() error = %v", err)
+	}
+	provider := &countingProvider{}
+	dir := t.TempDir()
+	newProvider := func() (FingerprintProvider, error) { return provider, nil }
+	cache, err := NewDiskCache(dir, time.Hour, newProvider, WithCacheCipher(c))
+	if err != nil {
+		t.Fatalf("NewDiskCache() error = %v", err)
+	}
+	if _, err := cache.Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if _, err := cache.Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if provider.calls != 1 {
+		t.Errorf("provider called %d times, want a cache hit", provider.calls)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	data, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("hardwareConcurrency")) {
+		t.Error("cache entry is stored in plain text")
+	}
+
+	// Another key cannot read the entries
+	other, _ := NewCipher(bytes.Repeat([]byte{2}, 32))
+	reopened, err := NewDiskCache(dir, time.Hour, newProvider, WithCacheCipher(other))
+	if err != nil {
+		t.Fatalf("NewDiskCache() error = %v", err)
+	}
+	if _, err := reopened.Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if provider.calls != 2 {
+		t.Errorf("provider called %d times, want a miss with another key", provider.calls)
+	}
+}
@@ -0,0 +1,66 @@
+package forgeron
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// ErrDecrypt is returned when encrypted identity data cannot be decrypted, because of a wrong key or corrupted data
+var ErrDecrypt = errors.New("failed to decrypt identity data")
+
+// encryptedMagic prefixes encrypted identity data, it is authenticated with the ciphertext
+var encryptedMagic = []byte("fgc1")
+
+// Cipher encrypts persisted identities with AES-GCM, since fingerprints stored along
+// proxies and cookies are sensitive operational data
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher creates a cipher from a 16, 24 or 32 bytes key, selecting AES-128, AES-192 or AES-256
+func NewCipher(key []byte) (*Cipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AES-GCM: %w", err)
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// Encrypt encrypts data with a random nonce
+func (c *Cipher) Encrypt(data []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	out := make([]byte, 0, len(encryptedMagic)+len(nonce)+len(data)+c.aead.Overhead())
+	out = append(out, encryptedMagic...)
+	out = append(out, nonce...)
+	return c.aead.Seal(out, nonce, data, encryptedMagic), nil
+}
+
+// Decrypt decrypts data encrypted with Encrypt, it returns ErrDecrypt for a wrong key or tampered data
+func (c *Cipher) Decrypt(data []byte) ([]byte, error) {
+	if !isEncrypted(data) || len(data) < len(encryptedMagic)+c.aead.NonceSize() {
+		return nil, fmt.Errorf("%w: not encrypted data", ErrDecrypt)
+	}
+	data = data[len(encryptedMagic):]
+	nonce, sealed := data[:c.aead.NonceSize()], data[c.aead.NonceSize():]
+	plain, err := c.aead.Open(nil, nonce, sealed, encryptedMagic)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plain, nil
+}
+
+// isEncrypted reports whether data starts like encrypted identity data
+func isEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, encryptedMagic)
+}
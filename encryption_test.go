@@ -0,0 +1,51 @@
+package forgeron
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestCipher(t *testing.T) {
+	c, err := NewCipher(bytes.Repeat([]byte{7}, 16))
+	if err != nil {
+		t.Fatalf("NewCipher() error = %v", err)
+	}
+	plain := []byte(`{"navigator":{"userAgent":"Mozilla/5.0"}}`)
+	sealed, err := c.Encrypt(plain)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if bytes.Contains(sealed, plain) {
+		t.Fatal("Encrypt() leaked the plain text")
+	}
+	again, _ := c.Encrypt(plain)
+	if bytes.Equal(sealed, again) {
+		t.Error("Encrypt() reused a nonce")
+	}
+	got, err := c.Decrypt(sealed)
+	if err != nil || !bytes.Equal(got, plain) {
+		t.Fatalf("Decrypt() = %s, %v", got, err)
+	}
+
+	tampered := bytes.Clone(sealed)
+	tampered[len(tampered)-1] ^= 1
+	other, _ := NewCipher(bytes.Repeat([]byte{8}, 16))
+	for name, tt := range map[string]struct {
+		c    *Cipher
+		data []byte
+	}{
+		"wrong key":  {other, sealed},
+		"tampered":   {c, tampered},
+		"plain text": {c, plain},
+		"truncated":  {c, sealed[:6]},
+	} {
+		if _, err := tt.c.Decrypt(tt.data); !errors.Is(err, ErrDecrypt) {
+			t.Errorf("%s: Decrypt() error = %v, want ErrDecrypt", name, err)
+		}
+	}
+
+	if _, err := NewCipher([]byte("short")); err == nil {
+		t.Error("NewCipher() accepted an invalid key size")
+	}
+}
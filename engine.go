@@ -0,0 +1,83 @@
+package forgeron
+
+import (
+	"regexp"
+	"strings"
+)
+
+// browserEngine is the rendering engine family of a user agent
+type browserEngine int
+
+const (
+	unknownEngine browserEngine = iota
+	blinkEngine
+	webKitEngine
+	geckoEngine
+)
+
+// androidVersionPattern extracts the Android version from a user agent
+var androidVersionPattern = regexp.MustCompile(`Android ([0-9.]+)`)
+
+// engineOf returns the engine family of a user agent. Every iOS browser runs on WebKit.
+func engineOf(userAgent string) browserEngine {
+	switch {
+	case strings.Contains(userAgent, "iPhone"), strings.Contains(userAgent, "iPad"), strings.Contains(userAgent, "iPod"):
+		return webKitEngine
+	case strings.Contains(userAgent, "Firefox/"):
+		return geckoEngine
+	case strings.Contains(userAgent, "Chrome/"), strings.Contains(userAgent, "Chromium/"):
+		return blinkEngine
+	case strings.Contains(userAgent, "AppleWebKit/"):
+		return webKitEngine
+	default:
+		return unknownEngine
+	}
+}
+
+// geckoAppVersion returns navigator.appVersion as reported by Firefox, which only exposes the platform family
+func geckoAppVersion(userAgent string) string {
+	switch {
+	case strings.Contains(userAgent, "Android"):
+		if match := androidVersionPattern.FindStringSubmatch(userAgent); match != nil {
+			return "5.0 (Android " + match[1] + ")"
+		}
+		return "5.0 (Android)"
+	case strings.Contains(userAgent, "Windows"):
+		return "5.0 (Windows)"
+	case strings.Contains(userAgent, "Macintosh"):
+		return "5.0 (Macintosh)"
+	default:
+		return "5.0 (X11)"
+	}
+}
+
+// applyEngineConsistency aligns vendor, productSub and appVersion with the engine of the user agent, only Chromium
+// exposes userAgentData
+func applyEngineConsistency(fp *Fingerprint) {
+	userAgent := fp.Navigator.UserAgent
+	switch engineOf(userAgent) {
+	case blinkEngine:
+		fp.Navigator.Vendor = "Google Inc."
+		fp.Navigator.ProductSub = "20030107"
+		fp.Navigator.AppVersion = strings.TrimPrefix(userAgent, "Mozilla/")
+	case webKitEngine:
+		fp.Navigator.Vendor = "Apple Computer, Inc."
+		fp.Navigator.ProductSub = "20030107"
+		fp.Navigator.AppVersion = strings.TrimPrefix(userAgent, "Mozilla/")
+		fp.Navigator.UserAgentData = nil
+	case geckoEngine:
+		fp.Navigator.Vendor = ""
+		fp.Navigator.ProductSub = "20100101"
+		fp.Navigator.AppVersion = geckoAppVersion(userAgent)
+		fp.Navigator.UserAgentData = nil
+	}
+}
+
+// dropClientHints removes the user agent client hints, only sent by Chromium browsers
+func dropClientHints(headers map[string]string) {
+	for k := range headers {
+		if strings.HasPrefix(strings.ToLower(k), "sec-ch-") {
+			delete(headers, k)
+		}
+	}
+}
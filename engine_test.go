@@ -0,0 +1,87 @@
+package forgeron
+
+import "testing"
+
+func TestApplyEngineConsistency(t *testing.T) {
+	tests := []struct {
+		name       string
+		userAgent  string
+		vendor     string
+		productSub string
+		appVersion string
+	}{
+		{
+			name:       "chrome",
+			userAgent:  "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36",
+			vendor:     "Google Inc.",
+			productSub: "20030107",
+			appVersion: "5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36",
+		},
+		{
+			name:       "edge",
+			userAgent:  "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36 Edg/144.0.0.0",
+			vendor:     "Google Inc.",
+			productSub: "20030107",
+			appVersion: "5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36 Edg/144.0.0.0",
+		},
+		{
+			name:       "safari",
+			userAgent:  "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/26.2 Safari/605.1.15",
+			vendor:     "Apple Computer, Inc.",
+			productSub: "20030107",
+			appVersion: "5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/26.2 Safari/605.1.15",
+		},
+		{
+			name:       "chrome on ios",
+			userAgent:  "Mozilla/5.0 (iPhone; CPU iPhone OS 18_7 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/144.0.7559.95 Mobile/15E148 Safari/604.1",
+			vendor:     "Apple Computer, Inc.",
+			productSub: "20030107",
+			appVersion: "5.0 (iPhone; CPU iPhone OS 18_7 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/144.0.7559.95 Mobile/15E148 Safari/604.1",
+		},
+		{
+			name:       "firefox windows",
+			userAgent:  "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:147.0) Gecko/20100101 Firefox/147.0",
+			vendor:     "",
+			productSub: "20100101",
+			appVersion: "5.0 (Windows)",
+		},
+		{
+			name:       "firefox linux",
+			userAgent:  "Mozilla/5.0 (X11; Linux x86_64; rv:147.0) Gecko/20100101 Firefox/147.0",
+			vendor:     "",
+			productSub: "20100101",
+			appVersion: "5.0 (X11)",
+		},
+		{
+			name:       "firefox android",
+			userAgent:  "Mozilla/5.0 (Android 14; Mobile; rv:147.0) Gecko/147.0 Firefox/147.0",
+			vendor:     "",
+			productSub: "20100101",
+			appVersion: "5.0 (Android 14)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fp := &Fingerprint{Navigator: NavigatorFingerprint{
+				UserAgent:     tt.userAgent,
+				Vendor:        "wrong",
+				ProductSub:    "wrong",
+				AppVersion:    "wrong",
+				UserAgentData: &UserAgentData{Platform: "wrong"},
+			}}
+			applyEngineConsistency(fp)
+			if fp.Navigator.Vendor != tt.vendor {
+				t.Errorf("Vendor = %q, want %q", fp.Navigator.Vendor, tt.vendor)
+			}
+			if fp.Navigator.ProductSub != tt.productSub {
+				t.Errorf("ProductSub = %q, want %q", fp.Navigator.ProductSub, tt.productSub)
+			}
+			if fp.Navigator.AppVersion != tt.appVersion {
+				t.Errorf("AppVersion = %q, want %q", fp.Navigator.AppVersion, tt.appVersion)
+			}
+			if chromium := tt.vendor == "Google Inc."; (fp.Navigator.UserAgentData != nil) != chromium {
+				t.Errorf("UserAgentData = %+v, want it only for Chromium", fp.Navigator.UserAgentData)
+			}
+		})
+	}
+}
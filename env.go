@@ -0,0 +1,182 @@
+package forgeron
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// envPrefix prefixes the environment variables read by ConstraintsFromEnv
+const envPrefix = "FORGERON_"
+
+// ConstraintsFromEnv reads constraints from environment variables, so containerized deployments can be tuned
+// without config files or code changes. Unset variables leave their constraint empty.
+//
+//	FORGERON_CONSTRAINTS   a constraint expression, e.g. "browser in (chrome>=120, edge) && os=windows"
+//	FORGERON_BROWSERS      comma separated browsers with optional version bounds, e.g. "chrome>=115,firefox=118-121"
+//	FORGERON_OS            comma separated operating systems
+//	FORGERON_DEVICES       comma separated devices
+//	FORGERON_LOCALES       comma separated locales, e.g. "en-US,de-DE"
+//	FORGERON_LANGUAGE      language
+//	FORGERON_REGION        region
+//	FORGERON_HTTP_VERSION  "1" or "2"
+//	FORGERON_STRICT        "true" to fail instead of relaxing constraints
+//	FORGERON_ACCEPT        Accept header override
+//	FORGERON_REQUEST_CONTEXT  "navigation", "iframe", "first-party", "third-party", "image", "script" or "stylesheet"
+//	FORGERON_SEC_FETCH_SITE   "none", "same-origin", "same-site" or "cross-site"
+//	FORGERON_SEC_FETCH_USER   "?1" or "absent"
+//	FORGERON_SCREEN_MIN_WIDTH, FORGERON_SCREEN_MAX_WIDTH, FORGERON_SCREEN_MIN_HEIGHT, FORGERON_SCREEN_MAX_HEIGHT
+//	FORGERON_SCREEN_MIN_PIXEL_RATIO, FORGERON_SCREEN_MAX_PIXEL_RATIO
+//
+// The other variables override the fields set by FORGERON_CONSTRAINTS.
+func ConstraintsFromEnv() (Constraints, error) {
+	var c Constraints
+	if expr, ok := lookupEnv("CONSTRAINTS"); ok {
+		headers, err := ParseHeaderConstraints(expr)
+		if err != nil {
+			return Constraints{}, fmt.Errorf("invalid %sCONSTRAINTS: %w", envPrefix, err)
+		}
+		c.HeaderConstraints = headers
+	}
+
+	var err error
+	if value, ok := lookupEnv("BROWSERS"); ok {
+		specs, err := ParseBrowserSpecs(splitEnvList(value)...)
+		if err != nil {
+			return Constraints{}, fmt.Errorf("invalid %sBROWSERS: %w", envPrefix, err)
+		}
+		c.Browsers, c.BrowserSpecs = nil, nil
+		versioned := false
+		for _, spec := range specs {
+			c.Browsers = append(c.Browsers, spec.Name)
+			versioned = versioned || spec.MinVersion > 0 || spec.MaxVersion > 0
+		}
+		if versioned {
+			c.BrowserSpecs = specs
+		}
+	}
+	if c.OS, err = envList("OS", SupportedOS, c.OS); err != nil {
+		return Constraints{}, err
+	}
+	if c.Devices, err = envList("DEVICES", SupportedDevices, c.Devices); err != nil {
+		return Constraints{}, err
+	}
+	if value, ok := lookupEnv("LOCALES"); ok {
+		c.Locales = splitEnvList(value)
+	}
+	if value, ok := lookupEnv("LANGUAGE"); ok {
+		c.Language = value
+	}
+	if value, ok := lookupEnv("REGION"); ok {
+		c.Region = value
+	}
+	if value, ok := lookupEnv("HTTP_VERSION"); ok {
+		version := HTTPVersion(value)
+		if err := validateAgainstSupported(version, SupportedHTTP); err != nil {
+			return Constraints{}, fmt.Errorf("invalid %sHTTP_VERSION: %w", envPrefix, err)
+		}
+		c.HTTPVersion = version
+	}
+	if value, ok := lookupEnv("STRICT"); ok {
+		if c.Strict, err = strconv.ParseBool(value); err != nil {
+			return Constraints{}, fmt.Errorf("invalid %sSTRICT: %w", envPrefix, err)
+		}
+	}
+	if value, ok := lookupEnv("ACCEPT"); ok {
+		c.Accept = value
+	}
+	if value, ok := lookupEnv("REQUEST_CONTEXT"); ok {
+		context := RequestContext(value)
+		if err := validateAgainstSupported(context, SupportedRequestContexts); err != nil {
+			return Constraints{}, fmt.Errorf("invalid %sREQUEST_CONTEXT: %w", envPrefix, err)
+		}
+		c.RequestContext = context
+	}
+	if value, ok := lookupEnv("SEC_FETCH_SITE"); ok {
+		site := FetchSite(value)
+		if err := validateAgainstSupported(site, SupportedFetchSites); err != nil {
+			return Constraints{}, fmt.Errorf("invalid %sSEC_FETCH_SITE: %w", envPrefix, err)
+		}
+		c.SecFetchSite = site
+	}
+	if value, ok := lookupEnv("SEC_FETCH_USER"); ok {
+		user := FetchUser(value)
+		if err := validateAgainstSupported(user, SupportedFetchUsers); err != nil {
+			return Constraints{}, fmt.Errorf("invalid %sSEC_FETCH_USER: %w", envPrefix, err)
+		}
+		c.SecFetchUser = user
+	}
+
+	screen := &Screen{}
+	ints := map[string]**int{
+		"SCREEN_MIN_WIDTH":  &screen.MinWidth,
+		"SCREEN_MAX_WIDTH":  &screen.MaxWidth,
+		"SCREEN_MIN_HEIGHT": &screen.MinHeight,
+		"SCREEN_MAX_HEIGHT": &screen.MaxHeight,
+	}
+	for name, field := range ints {
+		if value, ok := lookupEnv(name); ok {
+			i, err := strconv.Atoi(value)
+			if err != nil {
+				return Constraints{}, fmt.Errorf("invalid %s%s: %w", envPrefix, name, err)
+			}
+			*field = &i
+		}
+	}
+	floats := map[string]**float64{
+		"SCREEN_MIN_PIXEL_RATIO": &screen.MinDevicePixelRatio,
+		"SCREEN_MAX_PIXEL_RATIO": &screen.MaxDevicePixelRatio,
+	}
+	for name, field := range floats {
+		if value, ok := lookupEnv(name); ok {
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return Constraints{}, fmt.Errorf("invalid %s%s: %w", envPrefix, name, err)
+			}
+			*field = &f
+		}
+	}
+	if screen.IsSet() {
+		c.Screen = screen
+	}
+
+	if err := c.Validate(); err != nil {
+		return Constraints{}, fmt.Errorf("invalid constraints from environment: %w", err)
+	}
+	return c, nil
+}
+
+// lookupEnv returns the trimmed value of a prefixed environment variable, empty values count as unset
+func lookupEnv(name string) (string, bool) {
+	value := strings.TrimSpace(os.Getenv(envPrefix + name))
+	return value, value != ""
+}
+
+// splitEnvList splits a comma separated list, dropping empty items
+func splitEnvList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// envList reads a comma separated list of supported values, or returns current when the variable is unset
+func envList[T ~string](name string, supported, current []T) ([]T, error) {
+	value, ok := lookupEnv(name)
+	if !ok {
+		return current, nil
+	}
+	var values []T
+	for _, item := range splitEnvList(value) {
+		v := T(strings.ToLower(item))
+		if err := validateAgainstSupported(v, supported); err != nil {
+			return nil, fmt.Errorf("invalid %s%s: %w", envPrefix, name, err)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
@@ -0,0 +1,114 @@
+package forgeron
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConstraintsFromEnv(t *testing.T) {
+	t.Setenv("FORGERON_BROWSERS", "Chrome>=115, edge")
+	t.Setenv("FORGERON_OS", "windows")
+	t.Setenv("FORGERON_DEVICES", "desktop")
+	t.Setenv("FORGERON_LOCALES", "de-DE,de,")
+	t.Setenv("FORGERON_HTTP_VERSION", "2")
+	t.Setenv("FORGERON_STRICT", "true")
+	t.Setenv("FORGERON_REQUEST_CONTEXT", "third-party")
+	t.Setenv("FORGERON_SEC_FETCH_SITE", "same-site")
+	t.Setenv("FORGERON_SCREEN_MIN_WIDTH", "1280")
+	t.Setenv("FORGERON_SCREEN_MAX_PIXEL_RATIO", "2")
+
+	c, err := ConstraintsFromEnv()
+	if err != nil {
+		t.Fatalf("ConstraintsFromEnv() error = %v", err)
+	}
+	if want := []Browser{Chrome, Edge}; !reflect.DeepEqual(c.Browsers, want) {
+		t.Errorf("Browsers = %v, want %v", c.Browsers, want)
+	}
+	if len(c.BrowserSpecs) != 2 || c.BrowserSpecs[0].MinVersion != 115 || c.BrowserSpecs[1].MinVersion != 0 {
+		t.Errorf("BrowserSpecs = %v, want chrome>=115 and edge", c.BrowserSpecs)
+	}
+	if want := []OS{Windows}; !reflect.DeepEqual(c.OS, want) {
+		t.Errorf("OS = %v, want %v", c.OS, want)
+	}
+	if want := []Device{Desktop}; !reflect.DeepEqual(c.Devices, want) {
+		t.Errorf("Devices = %v, want %v", c.Devices, want)
+	}
+	if want := []string{"de-DE", "de"}; !reflect.DeepEqual(c.Locales, want) {
+		t.Errorf("Locales = %v, want %v", c.Locales, want)
+	}
+	if c.HTTPVersion != HTTP2 || !c.Strict {
+		t.Errorf("HTTPVersion = %q, Strict = %v", c.HTTPVersion, c.Strict)
+	}
+	if c.RequestContext != ThirdPartySubresource {
+		t.Errorf("RequestContext = %q, want %q", c.RequestContext, ThirdPartySubresource)
+	}
+	if c.SecFetchSite != FetchSiteSameSite || c.SecFetchUser != "" {
+		t.Errorf("SecFetchSite = %q, SecFetchUser = %q, want same-site and none", c.SecFetchSite, c.SecFetchUser)
+	}
+	if c.Screen == nil || *c.Screen.MinWidth != 1280 || *c.Screen.MaxDevicePixelRatio != 2 || c.Screen.MaxWidth != nil {
+		t.Errorf("Screen = %+v, want min width 1280 and max pixel ratio 2", c.Screen)
+	}
+}
+
+func TestConstraintsFromEnvUnset(t *testing.T) {
+	c, err := ConstraintsFromEnv()
+	if err != nil {
+		t.Fatalf("ConstraintsFromEnv() error = %v", err)
+	}
+	if !reflect.DeepEqual(c, Constraints{}) {
+		t.Errorf("ConstraintsFromEnv() = %+v, want empty constraints", c)
+	}
+}
+
+func TestConstraintsFromEnvExpression(t *testing.T) {
+	t.Setenv("FORGERON_CONSTRAINTS", "browser=firefox && os=linux")
+	t.Setenv("FORGERON_OS", "macos")
+
+	c, err := ConstraintsFromEnv()
+	if err != nil {
+		t.Fatalf("ConstraintsFromEnv() error = %v", err)
+	}
+	if want := []OS{MacOS}; !reflect.DeepEqual(c.OS, want) {
+		t.Errorf("OS = %v, want %v overriding the expression", c.OS, want)
+	}
+	if len(c.Browsers)+len(c.BrowserSpecs) == 0 {
+		t.Error("browser constraint of the expression is lost")
+	}
+}
+
+func TestConstraintsFromEnvInvalid(t *testing.T) {
+	tests := []struct {
+		name, value string
+	}{
+		{"FORGERON_BROWSERS", "chrome,netscape"},
+		{"FORGERON_BROWSERS", "chrome>=abc"},
+		{"FORGERON_OS", "beos"},
+		{"FORGERON_DEVICES", "toaster"},
+		{"FORGERON_HTTP_VERSION", "3"},
+		{"FORGERON_STRICT", "maybe"},
+		{"FORGERON_REQUEST_CONTEXT", "popup"},
+		{"FORGERON_SEC_FETCH_SITE", "?1"},
+		{"FORGERON_SEC_FETCH_USER", "document"},
+		{"FORGERON_SCREEN_MIN_WIDTH", "wide"},
+		{"FORGERON_SCREEN_MIN_PIXEL_RATIO", "x"},
+		{"FORGERON_CONSTRAINTS", "browser in ("},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.name, tt.value)
+			_, err := ConstraintsFromEnv()
+			if err == nil || !strings.Contains(err.Error(), tt.name) {
+				t.Errorf("ConstraintsFromEnv() error = %v, want an error naming %s", err, tt.name)
+			}
+		})
+	}
+
+	t.Run("screen bounds", func(t *testing.T) {
+		t.Setenv("FORGERON_SCREEN_MIN_WIDTH", "1920")
+		t.Setenv("FORGERON_SCREEN_MAX_WIDTH", "1280")
+		if _, err := ConstraintsFromEnv(); err == nil {
+			t.Error("ConstraintsFromEnv() error = nil, want an error for min width above max width")
+		}
+	})
+}
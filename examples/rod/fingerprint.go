@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/go-rod/stealth"
+	"github.com/ta0uf19/forgeron"
+	"github.com/ta0uf19/forgeron/forgeronrod"
+)
+
+// identityHeaders are replaced with the values of the fingerprint when the browser sends them
+var identityHeaders = []string{"user-agent", "accept-language", "sec-ch-ua", "sec-ch-ua-mobile", "sec-ch-ua-platform"}
+
+// generateFingerprint generates a desktop Chrome fingerprint, matching the Chromium driven by rod
+func generateFingerprint() (*forgeron.Fingerprint, error) {
+	gen, err := forgeron.NewFingerprintGenerator(
+		forgeron.WithHeaderConstraints(forgeron.HeaderConstraints{
+			Browsers: []forgeron.Browser{forgeron.Chrome},
+			Devices:  []forgeron.Device{forgeron.Desktop},
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return gen.Generate()
+}
+
+// orderHeaders replaces the identity headers of a request with the fingerprint values and sorts them in the browser order.
+// Headers missing from the order are appended after the ordered ones, sorted by name.
+func orderHeaders(request proto.NetworkHeaders, fp *forgeron.Fingerprint, order []string) []*proto.FetchHeaderEntry {
+	values := make(map[string]string, len(request))
+	names := make(map[string]string, len(request))
+	var unordered []string
+	for name, value := range request {
+		key := strings.ToLower(name)
+		values[key], names[key] = value.String(), name
+		unordered = append(unordered, key)
+	}
+	for name, value := range fp.Headers {
+		key := strings.ToLower(name)
+		if _, sent := values[key]; sent && slices.Contains(identityHeaders, key) {
+			values[key] = value
+		}
+	}
+
+	entries := make([]*proto.FetchHeaderEntry, 0, len(values))
+	for _, name := range order {
+		key := strings.ToLower(name)
+		if value, ok := values[key]; ok {
+			entries = append(entries, &proto.FetchHeaderEntry{Name: names[key], Value: value})
+			delete(values, key)
+		}
+	}
+	// Maps have no order, sort the remaining headers so the result is stable
+	slices.Sort(unordered)
+	for _, key := range unordered {
+		if value, ok := values[key]; ok {
+			entries = append(entries, &proto.FetchHeaderEntry{Name: names[key], Value: value})
+		}
+	}
+	return entries
+}
+
+// requestType returns the forgeron request type of a hijacked request
+func requestType(request *rod.HijackRequest) forgeron.RequestType {
+	if request.Type() == proto.NetworkResourceTypeDocument {
+		return forgeron.Navigation
+	}
+	return forgeron.Fetch
+}
+
+// newPage opens a stealth page with the fingerprint applied: the fingerprint script runs before any page script,
+// the user agent and screen are emulated, and every request is sent with ordered headers
+func newPage(browser *rod.Browser, fp *forgeron.Fingerprint, headers *forgeron.HeaderGenerator) (*rod.Page, *rod.HijackRouter, error) {
+	page, err := stealth.Page(browser)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create page: %w", err)
+	}
+	if err := forgeronrod.ApplyFingerprint(page, fp); err != nil {
+		return nil, nil, err
+	}
+
+	// Chrome negotiates HTTP/2 by itself, the HTTP/1 order is the one it keeps for overridden headers
+	router := page.HijackRequests()
+	if err := router.Add("*", "", func(ctx *rod.Hijack) {
+		order := headers.OrderFor(forgeron.Chrome, forgeron.HTTP1, requestType(ctx.Request))
+		ctx.ContinueRequest(&proto.FetchContinueRequest{Headers: orderHeaders(ctx.Request.Headers(), fp, order)})
+	}); err != nil {
+		return nil, nil, fmt.Errorf("failed to hijack requests: %w", err)
+	}
+	go router.Run()
+	return page, router, nil
+}
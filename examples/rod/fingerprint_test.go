@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/ta0uf19/forgeron"
+	"github.com/ysmood/gson"
+)
+
+func TestOrderHeaders(t *testing.T) {
+	request := proto.NetworkHeaders{
+		"Accept":          gson.New("text/html"),
+		"User-Agent":      gson.New("HeadlessChrome"),
+		"X-Requested-By":  gson.New("app"),
+		"Accept-Language": gson.New("en-US"),
+		"Cookie":          gson.New("a=b"),
+	}
+	fp := &forgeron.Fingerprint{Headers: map[string]string{
+		"user-agent":      "Chrome",
+		"accept-language": "fr-FR,fr;q=0.9",
+		"sec-ch-ua":       `"Chromium";v="144"`,
+	}}
+	order := []string{"Host", "User-Agent", "Accept", "Accept-Language", "Cookie"}
+
+	got := orderHeaders(request, fp, order)
+	want := []proto.FetchHeaderEntry{
+		{Name: "User-Agent", Value: "Chrome"},
+		{Name: "Accept", Value: "text/html"},
+		{Name: "Accept-Language", Value: "fr-FR,fr;q=0.9"},
+		{Name: "Cookie", Value: "a=b"},
+		{Name: "X-Requested-By", Value: "app"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("orderHeaders() returned %d headers, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if *got[i] != want[i] {
+			t.Errorf("header %d = %+v, want %+v", i, *got[i], want[i])
+		}
+	}
+}
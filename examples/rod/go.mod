@@ -0,0 +1,27 @@
+module github.com/ta0uf19/forgeron/examples/rod
+
+go 1.23.4
+
+require (
+	github.com/go-rod/rod v0.116.2
+	github.com/go-rod/stealth v0.4.9
+	github.com/ta0uf19/forgeron v0.0.0
+	github.com/ta0uf19/forgeron/forgeronrod v0.0.0
+	github.com/ysmood/gson v0.7.3
+)
+
+require (
+	github.com/fsnotify/fsnotify v1.10.1 // indirect
+	github.com/ysmood/fetchup v0.2.3 // indirect
+	github.com/ysmood/goob v0.4.0 // indirect
+	github.com/ysmood/got v0.40.0 // indirect
+	github.com/ysmood/leakless v0.9.0 // indirect
+	golang.org/x/sys v0.13.0 // indirect
+	golang.org/x/text v0.24.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace (
+	github.com/ta0uf19/forgeron => ../..
+	github.com/ta0uf19/forgeron/forgeronrod => ../../forgeronrod
+)
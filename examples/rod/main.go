@@ -0,0 +1,93 @@
+// Command rod shows how to combine forgeron with go-rod and go-rod/stealth: it launches Chromium with
+// arguments matching a generated fingerprint, injects the fingerprint before any page script runs,
+// sends every request with the browser header order, and opens a public fingerprint checker.
+//
+//	go run . -url https://bot.sannysoft.com -screenshot report.png
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/go-rod/rod"
+	"github.com/ta0uf19/forgeron"
+	"github.com/ta0uf19/forgeron/forgeronrod"
+	"github.com/ta0uf19/forgeron/probes"
+)
+
+func main() {
+	target := flag.String("url", "https://bot.sannysoft.com", "page to open with the fingerprint")
+	screenshot := flag.String("screenshot", "", "save a full page screenshot to this file")
+	headless := flag.Bool("headless", true, "run the browser headless")
+	flag.Parse()
+
+	if err := run(*target, *screenshot, *headless); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run opens target in a browser driven with a generated fingerprint and reports the probes failing in the page
+func run(target, screenshot string, headless bool) error {
+	fp, err := generateFingerprint()
+	if err != nil {
+		return fmt.Errorf("failed to generate fingerprint: %w", err)
+	}
+	headers, err := forgeron.NewHeaderGenerator()
+	if err != nil {
+		return fmt.Errorf("failed to create header generator: %w", err)
+	}
+
+	controlURL, err := forgeronrod.Launcher(fp).Headless(headless).Launch()
+	if err != nil {
+		return fmt.Errorf("failed to launch browser: %w", err)
+	}
+	browser := rod.New().ControlURL(controlURL)
+	if err := browser.Connect(); err != nil {
+		return fmt.Errorf("failed to connect to browser: %w", err)
+	}
+	defer browser.Close()
+
+	page, router, err := newPage(browser, fp, headers)
+	if err != nil {
+		return err
+	}
+	defer router.Stop()
+
+	if err := page.Navigate(target); err != nil {
+		return fmt.Errorf("failed to open %s: %w", target, err)
+	}
+	if err := page.WaitLoad(); err != nil {
+		return fmt.Errorf("failed to load %s: %w", target, err)
+	}
+
+	fmt.Printf("User-Agent: %s\n", fp.Navigator.UserAgent)
+	if err := probes.Failures(probes.Run(fp, evaluator(page))); err != nil {
+		fmt.Println(err)
+	} else {
+		fmt.Println("All probes passed")
+	}
+
+	if screenshot != "" {
+		data, err := page.Screenshot(true, nil)
+		if err != nil {
+			return fmt.Errorf("failed to take screenshot: %w", err)
+		}
+		if err := os.WriteFile(screenshot, data, 0o644); err != nil {
+			return fmt.Errorf("failed to save screenshot: %w", err)
+		}
+	}
+	return nil
+}
+
+// evaluator evaluates probe expressions in the page
+func evaluator(page *rod.Page) probes.Evaluator {
+	return func(expression string) (any, error) {
+		result, err := page.Eval("() => (" + expression + ")")
+		if err != nil {
+			return nil, err
+		}
+		return result.Value.Val(), nil
+	}
+}
@@ -0,0 +1,140 @@
+//go:build integration
+
+// The integration tests drive a real Chromium, downloaded by rod when missing:
+//
+//	go test -tags integration ./...
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-rod/rod"
+	"github.com/ta0uf19/forgeron"
+	"github.com/ta0uf19/forgeron/forgeronrod"
+	"github.com/ta0uf19/forgeron/probes"
+)
+
+// openPage launches a browser with a generated fingerprint and returns a page with it applied
+func openPage(t *testing.T) (*rod.Page, *forgeron.Fingerprint, *forgeron.HeaderGenerator) {
+	t.Helper()
+	fp, err := generateFingerprint()
+	if err != nil {
+		t.Fatalf("generateFingerprint() error = %v", err)
+	}
+	headers, err := forgeron.NewHeaderGenerator()
+	if err != nil {
+		t.Fatalf("NewHeaderGenerator() error = %v", err)
+	}
+	controlURL, err := forgeronrod.Launcher(fp).Launch()
+	if err != nil {
+		t.Fatalf("Launch() error = %v", err)
+	}
+	browser := rod.New().ControlURL(controlURL)
+	if err := browser.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	t.Cleanup(func() { browser.Close() })
+
+	page, router, err := newPage(browser, fp, headers)
+	if err != nil {
+		t.Fatalf("newPage() error = %v", err)
+	}
+	t.Cleanup(func() { router.Stop() })
+	return page, fp, headers
+}
+
+func TestProbesPass(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "<html><body>forgeron</body></html>")
+	}))
+	defer server.Close()
+
+	page, fp, _ := openPage(t)
+	if err := page.Navigate(server.URL); err != nil {
+		t.Fatalf("Navigate() error = %v", err)
+	}
+	page.MustWaitLoad()
+	if err := probes.Failures(probes.Run(fp, evaluator(page))); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNavigationHeadersAreOrdered(t *testing.T) {
+	// net/http stores headers in a map, read the raw request to see the order they were sent in
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		reader.ReadString('\n')
+		var names []string
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil || strings.TrimSpace(line) == "" {
+				break
+			}
+			names = append(names, strings.ToLower(strings.SplitN(line, ":", 2)[0]))
+		}
+		received <- names
+		fmt.Fprint(conn, "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\nContent-Length: 0\r\nConnection: close\r\n\r\n")
+	}()
+
+	page, _, headers := openPage(t)
+	if err := page.Navigate("http://" + listener.Addr().String()); err != nil {
+		t.Fatalf("Navigate() error = %v", err)
+	}
+	names := <-received
+
+	position := make(map[string]int)
+	for i, name := range headers.OrderFor(forgeron.Chrome, forgeron.HTTP1, forgeron.Navigation) {
+		position[strings.ToLower(name)] = i
+	}
+	last := -1
+	for _, name := range names {
+		i, ok := position[name]
+		if !ok {
+			continue
+		}
+		if i < last {
+			t.Fatalf("headers sent out of order: %v", names)
+		}
+		last = i
+	}
+}
+
+func TestPublicChecker(t *testing.T) {
+	page, fp, _ := openPage(t)
+	if err := page.Navigate("https://bot.sannysoft.com"); err != nil {
+		t.Skipf("fingerprint checker unreachable: %v", err)
+	}
+	page.MustWaitLoad()
+	if err := probes.Failures(probes.Run(fp, evaluator(page))); err != nil {
+		t.Error(err)
+	}
+	// The checker marks failed tests with the "failed" class
+	failed, err := page.Elements("td.failed")
+	if err != nil {
+		t.Fatalf("Elements() error = %v", err)
+	}
+	for _, cell := range failed {
+		row, err := cell.Parent()
+		if err != nil {
+			continue
+		}
+		t.Errorf("checker flagged: %s", row.MustText())
+	}
+}
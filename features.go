@@ -0,0 +1,188 @@
+package forgeron
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Feature is a web API or image format whose support depends on the browser and its version
+type Feature string
+
+// Features reported in Fingerprint.Supports
+const (
+	FeatureWebGL2            Feature = "webgl2"
+	FeatureWebGPU            Feature = "webgpu"
+	FeatureSharedArrayBuffer Feature = "shared-array-buffer"
+	FeatureAVIF              Feature = "avif"
+	FeatureWebP              Feature = "webp"
+	FeatureJXL               Feature = "jxl"
+	FeatureHEIC              Feature = "heic"
+)
+
+// Features lists the features reported in Fingerprint.Supports
+var Features = []Feature{FeatureWebGL2, FeatureWebGPU, FeatureSharedArrayBuffer, FeatureAVIF, FeatureWebP, FeatureJXL, FeatureHEIC}
+
+// featureImageTypes are the media types of the image formats, claimed in Accept headers only when supported
+var featureImageTypes = map[Feature]string{
+	FeatureAVIF: "image/avif",
+	FeatureWebP: "image/webp",
+	FeatureJXL:  "image/jxl",
+	FeatureHEIC: "image/heic",
+}
+
+// featureSupport is the first major version of each engine supporting a feature, zero when it does not
+type featureSupport struct {
+	chromium int
+	// chromiumAndroid is the first Chromium version on Android, when it differs from desktop
+	chromiumAndroid int
+	firefox         int
+	webKit          int
+	// chromiumOS and firefoxOS restrict the support to some operating systems, nil supports every one
+	chromiumOS []OS
+	firefoxOS  []OS
+}
+
+// featureMatrix is the support of each feature by default, without flags. Safari and every iOS browser run on
+// WebKit, whose version is the Safari version.
+var featureMatrix = map[Feature]featureSupport{
+	FeatureWebGL2: {chromium: 56, firefox: 51, webKit: 15},
+	FeatureWebGPU: {
+		chromium: 113, chromiumAndroid: 121, chromiumOS: []OS{Windows, MacOS, ChromeOS, Android},
+		firefox: 141, firefoxOS: []OS{Windows},
+		webKit: 26,
+	},
+	FeatureSharedArrayBuffer: {chromium: 68, chromiumAndroid: 88, firefox: 79, webKit: 16},
+	FeatureAVIF:              {chromium: 85, chromiumAndroid: 89, firefox: 93, webKit: 16},
+	FeatureWebP:              {chromium: 32, firefox: 65, webKit: 14},
+	FeatureJXL:               {webKit: 17},
+	FeatureHEIC:              {webKit: 17},
+}
+
+// acceptRange is a media range of an Accept header, only sent when the browser supports its feature if it has one
+type acceptRange struct {
+	mediaRange string
+	feature    Feature
+}
+
+// imageAcceptRanges are the media ranges of the Accept header of image requests per engine, in the order browsers
+// send them
+var imageAcceptRanges = map[browserEngine][]acceptRange{
+	blinkEngine: {
+		{"image/avif", FeatureAVIF}, {"image/webp", FeatureWebP}, {"image/apng", ""}, {"image/svg+xml", ""},
+		{"image/*", ""}, {"*/*;q=0.8", ""},
+	},
+	geckoEngine: {
+		{"image/avif", FeatureAVIF}, {"image/webp", FeatureWebP}, {"image/png", ""}, {"image/svg+xml", ""},
+		{"image/*;q=0.8", ""}, {"*/*;q=0.5", ""},
+	},
+	webKitEngine: {
+		{"image/webp", FeatureWebP}, {"image/avif", FeatureAVIF}, {"image/jxl", FeatureJXL}, {"image/heic", FeatureHEIC},
+		{"image/heic-sequence", FeatureHEIC}, {"video/*;q=0.8", ""}, {"image/png", ""}, {"image/svg+xml", ""},
+		{"image/*;q=0.8", ""}, {"*/*;q=0.5", ""},
+	},
+}
+
+// imageAccept returns the Accept header the browser of the user agent sends for images, claiming the image formats
+// it decodes, or "" when the browser is unknown
+func imageAccept(userAgent string) string {
+	ranges := imageAcceptRanges[engineOf(userAgent)]
+	supports := supportedFeatures(userAgent)
+	if ranges == nil || supports == nil {
+		return ""
+	}
+	var accept []string
+	for _, r := range ranges {
+		if r.feature == "" || supports[r.feature] {
+			accept = append(accept, r.mediaRange)
+		}
+	}
+	return strings.Join(accept, ",")
+}
+
+var (
+	// webKitVersionPattern extracts the Safari version of a WebKit user agent
+	webKitVersionPattern = regexp.MustCompile(`Version/(\d+)`)
+	// iOSVersionPattern extracts the iOS version, used by iOS browsers without a Safari version
+	iOSVersionPattern = regexp.MustCompile(`OS (\d+)_`)
+)
+
+// supportedFeatures returns the support of each feature by the browser of the user agent, nil when the browser
+// is unknown
+func supportedFeatures(userAgent string) map[Feature]bool {
+	engine := engineOf(userAgent)
+	info := parseUserAgent(userAgent)
+	version := info.Version
+	if engine == webKitEngine {
+		version = webKitVersion(userAgent)
+	}
+	if engine == unknownEngine || version == 0 {
+		return nil
+	}
+
+	supports := make(map[Feature]bool, len(featureMatrix))
+	for feature, support := range featureMatrix {
+		first, osList := 0, []OS(nil)
+		switch engine {
+		case blinkEngine:
+			first, osList = support.chromium, support.chromiumOS
+			if info.OS == Android && support.chromiumAndroid != 0 {
+				first = support.chromiumAndroid
+			}
+		case geckoEngine:
+			first, osList = support.firefox, support.firefoxOS
+		case webKitEngine:
+			first = support.webKit
+		}
+		supports[feature] = first != 0 && version >= first && (osList == nil || slices.Contains(osList, info.OS))
+	}
+	return supports
+}
+
+// webKitVersion returns the Safari version of a WebKit user agent, or the iOS version when it has none
+func webKitVersion(userAgent string) int {
+	match := webKitVersionPattern.FindStringSubmatch(userAgent)
+	if match == nil {
+		match = iOSVersionPattern.FindStringSubmatch(userAgent)
+	}
+	if match == nil {
+		return 0
+	}
+	version, _ := strconv.Atoi(match[1])
+	return version
+}
+
+// applyFeatureConsistency computes the features supported by the browser and, when fixAccept is set, removes the
+// image formats it cannot decode from the Accept header
+func applyFeatureConsistency(fp *Fingerprint, fixAccept bool) {
+	fp.Supports = supportedFeatures(fp.Navigator.UserAgent)
+	if fp.Supports == nil || !fixAccept {
+		return
+	}
+	for key, value := range fp.Headers {
+		if strings.EqualFold(key, "accept") {
+			fp.Headers[key] = acceptWithoutUnsupportedImages(value, fp.Supports)
+		}
+	}
+}
+
+// acceptWithoutUnsupportedImages removes the media ranges of the image formats that are not supported
+func acceptWithoutUnsupportedImages(accept string, supports map[Feature]bool) string {
+	items := strings.Split(accept, ",")
+	kept := items[:0]
+	for _, item := range items {
+		mediaType, _, _ := strings.Cut(strings.TrimSpace(item), ";")
+		mediaType = strings.TrimSuffix(strings.TrimSpace(mediaType), "-sequence")
+		unsupported := false
+		for feature, imageType := range featureImageTypes {
+			if strings.EqualFold(mediaType, imageType) && !supports[feature] {
+				unsupported = true
+			}
+		}
+		if !unsupported {
+			kept = append(kept, item)
+		}
+	}
+	return strings.Join(kept, ",")
+}
@@ -0,0 +1,150 @@
+package forgeron
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSupportedFeatures(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		want      map[Feature]bool
+	}{
+		{
+			name:      "recent chrome on windows",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36",
+			want:      map[Feature]bool{FeatureWebGL2: true, FeatureWebGPU: true, FeatureSharedArrayBuffer: true, FeatureAVIF: true, FeatureWebP: true},
+		},
+		{
+			name:      "chrome on linux has no webgpu",
+			userAgent: "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36",
+			want:      map[Feature]bool{FeatureWebGL2: true, FeatureWebGPU: false, FeatureSharedArrayBuffer: true, FeatureAVIF: true, FeatureWebP: true},
+		},
+		{
+			name:      "old chrome on android",
+			userAgent: "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/86.0.0.0 Mobile Safari/537.36",
+			want:      map[Feature]bool{FeatureWebGL2: true, FeatureWebGPU: false, FeatureSharedArrayBuffer: false, FeatureAVIF: false, FeatureWebP: true},
+		},
+		{
+			name:      "firefox on mac",
+			userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10.15; rv:147.0) Gecko/20100101 Firefox/147.0",
+			want:      map[Feature]bool{FeatureWebGL2: true, FeatureWebGPU: false, FeatureSharedArrayBuffer: true, FeatureAVIF: true, FeatureWebP: true},
+		},
+		{
+			name:      "safari 15",
+			userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/15.6 Safari/605.1.15",
+			want:      map[Feature]bool{FeatureWebGL2: true, FeatureWebGPU: false, FeatureSharedArrayBuffer: false, FeatureAVIF: false, FeatureWebP: true, FeatureJXL: false},
+		},
+		{
+			name:      "safari 26",
+			userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/26.2 Safari/605.1.15",
+			want:      map[Feature]bool{FeatureWebGPU: true, FeatureAVIF: true, FeatureJXL: true, FeatureHEIC: true},
+		},
+		{
+			name:      "chrome on ios runs on webkit",
+			userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 18_7 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/144.0.7559.95 Mobile/15E148 Safari/604.1",
+			want:      map[Feature]bool{FeatureWebGL2: true, FeatureWebGPU: false, FeatureSharedArrayBuffer: true, FeatureAVIF: true, FeatureWebP: true},
+		},
+		{
+			name:      "unknown browser",
+			userAgent: "curl/8.0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := supportedFeatures(tt.userAgent)
+			if (got == nil) != (tt.want == nil) {
+				t.Fatalf("supportedFeatures() = %v, want %v", got, tt.want)
+			}
+			for feature, want := range tt.want {
+				if got[feature] != want {
+					t.Errorf("supports %s = %v, want %v", feature, got[feature], want)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyFeatureConsistencyAccept(t *testing.T) {
+	fp := &Fingerprint{
+		Navigator: NavigatorFingerprint{UserAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/15.6 Safari/605.1.15"},
+		Headers:   map[string]string{"Accept": "text/html,image/avif,image/webp,*/*;q=0.8"},
+	}
+	applyFeatureConsistency(fp, true)
+	if want := "text/html,image/webp,*/*;q=0.8"; fp.Headers["Accept"] != want {
+		t.Errorf("Accept = %q, want %q", fp.Headers["Accept"], want)
+	}
+}
+
+func TestGeneratedAcceptMatchesSupports(t *testing.T) {
+	gen := newGeneratorOrFatal(t)
+	for i := 0; i < 50; i++ {
+		fp, err := gen.Generate()
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if fp.Supports == nil {
+			t.Fatalf("Supports = nil for %s", fp.Navigator.UserAgent)
+		}
+		accept := strings.ToLower(headerValue(fp.Headers, "accept"))
+		for feature, imageType := range featureImageTypes {
+			if strings.Contains(accept, imageType) && !fp.Supports[feature] {
+				t.Errorf("Accept %q claims %s, unsupported by %s", accept, imageType, fp.Navigator.UserAgent)
+			}
+		}
+	}
+}
+
+func TestImageAccept(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		want      string
+	}{
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36",
+			"image/avif,image/webp,image/apng,image/svg+xml,image/*,*/*;q=0.8",
+		},
+		{
+			"Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/86.0.0.0 Mobile Safari/537.36",
+			"image/webp,image/apng,image/svg+xml,image/*,*/*;q=0.8",
+		},
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:147.0) Gecko/20100101 Firefox/147.0",
+			"image/avif,image/webp,image/png,image/svg+xml,image/*;q=0.8,*/*;q=0.5",
+		},
+		{
+			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/26.2 Safari/605.1.15",
+			"image/webp,image/avif,image/jxl,image/heic,image/heic-sequence,video/*;q=0.8,image/png,image/svg+xml,image/*;q=0.8,*/*;q=0.5",
+		},
+		{
+			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/15.6 Safari/605.1.15",
+			"image/webp,video/*;q=0.8,image/png,image/svg+xml,image/*;q=0.8,*/*;q=0.5",
+		},
+		{"curl/8.0", ""},
+	}
+	for _, tt := range tests {
+		if got := imageAccept(tt.userAgent); got != tt.want {
+			t.Errorf("imageAccept(%q) = %q, want %q", tt.userAgent, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateHeadersImageAccept(t *testing.T) {
+	gen, err := NewHeaderGenerator()
+	if err != nil {
+		t.Fatalf("NewHeaderGenerator() error = %v", err)
+	}
+	for _, browser := range []Browser{Chrome, Firefox, Safari} {
+		headers, err := gen.GenerateHeaders(HeaderConstraints{Browsers: []Browser{browser}, RequestContext: ImageSubresource})
+		if err != nil {
+			t.Fatalf("GenerateHeaders() error = %v", err)
+		}
+		if want := imageAccept(headerValue(headers, "user-agent")); headerValue(headers, "accept") != want {
+			t.Errorf("%s image Accept = %q, want %q", browser, headerValue(headers, "accept"), want)
+		}
+		if headerValue(headers, "sec-fetch-dest") != "" && headerValue(headers, "sec-fetch-dest") != "image" {
+			t.Errorf("%s image Sec-Fetch-Dest = %q", browser, headerValue(headers, "sec-fetch-dest"))
+		}
+	}
+}
@@ -3,7 +3,11 @@ package forgeron
 import (
 	"encoding/json"
 	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
 	"strings"
+	"sync/atomic"
 )
 
 // ScreenFingerprint represents screen-related fingerprint data
@@ -50,25 +54,26 @@ type UserAgentData struct {
 
 // NavigatorFingerprint represents navigator-related fingerprint data
 type NavigatorFingerprint struct {
-	UserAgent           string         `json:"userAgent"`
-	UserAgentData       *UserAgentData `json:"userAgentData"`
-	DoNotTrack          *string        `json:"doNotTrack"`
-	AppCodeName         string         `json:"appCodeName"`
-	AppName             string         `json:"appName"`
-	AppVersion          string         `json:"appVersion"`
-	OSCpu               string         `json:"oscpu"`
-	Webdriver           string         `json:"webdriver"`
-	Language            string         `json:"language"`
-	Languages           []string       `json:"languages"`
-	Platform            string         `json:"platform"`
-	DeviceMemory        *int           `json:"deviceMemory"`
-	HardwareConcurrency int            `json:"hardwareConcurrency"`
-	Product             string         `json:"product"`
-	ProductSub          string         `json:"productSub"`
-	Vendor              string         `json:"vendor"`
-	VendorSub           string         `json:"vendorSub"`
-	MaxTouchPoints      int            `json:"maxTouchPoints"`
-	ExtraProperties     map[string]any `json:"extraProperties"`
+	UserAgent            string         `json:"userAgent"`
+	UserAgentData        *UserAgentData `json:"userAgentData,omitempty"`
+	DoNotTrack           *string        `json:"doNotTrack"`
+	GlobalPrivacyControl *bool          `json:"globalPrivacyControl,omitempty"`
+	AppCodeName          string         `json:"appCodeName"`
+	AppName              string         `json:"appName"`
+	AppVersion           string         `json:"appVersion"`
+	OSCpu                *string        `json:"oscpu,omitempty"`
+	Webdriver            string         `json:"webdriver"`
+	Language             string         `json:"language"`
+	Languages            []string       `json:"languages"`
+	Platform             string         `json:"platform"`
+	DeviceMemory         *int           `json:"deviceMemory,omitempty"`
+	HardwareConcurrency  int            `json:"hardwareConcurrency"`
+	Product              string         `json:"product"`
+	ProductSub           string         `json:"productSub"`
+	Vendor               string         `json:"vendor"`
+	VendorSub            string         `json:"vendorSub"`
+	MaxTouchPoints       int            `json:"maxTouchPoints"`
+	ExtraProperties      map[string]any `json:"extraProperties"`
 }
 
 // VideoCard represents video card information
@@ -130,25 +135,85 @@ type Fingerprint struct {
 	VideoCodecs       map[string]string    `json:"videoCodecs"`
 	AudioCodecs       map[string]string    `json:"audioCodecs"`
 	PluginsData       PluginsData          `json:"pluginsData"`
-	Battery           *Battery             `json:"battery"`
+	Battery           *Battery             `json:"battery,omitempty"`
 	VideoCard         *VideoCard           `json:"videoCard"`
 	MultimediaDevices *MultimediaDevices   `json:"multimediaDevices"`
 	Fonts             []string             `json:"fonts"`
 	MockWebRTC        bool                 `json:"mockWebRTC"`
 	Slim              bool                 `json:"slim"`
+	// Supports tells which Features the browser supports, nil when the browser is not recognized
+	Supports map[Feature]bool `json:"supports,omitempty"`
+	// Locale is the locale Intl formats with when none is given, e.g. Intl.DateTimeFormat().resolvedOptions().locale
+	Locale string `json:"locale,omitempty"`
+	// Timezone is the time zone of the identity, nil when none is known for its region
+	Timezone *Timezone `json:"timezone,omitempty"`
+	// Geolocation is the position reported by navigator.geolocation, set with WithGeo
+	Geolocation *Geolocation `json:"geolocation,omitempty"`
+	// Transport is the TLS and HTTP/2 profile of the browser, set from the medium realism level on
+	Transport *TransportProfile `json:"transport,omitempty"`
+	// Noise seeds the canvas and audio noise of the injector script, set at the high realism level
+	Noise *Noise `json:"noise,omitempty"`
+	// TimingJitter is the timer precision emulated by the injector script, set at the high realism level
+	TimingJitter *TimingJitter `json:"timingJitter,omitempty"`
 }
 
-// Screen represents screen dimension constraints
+// Screen represents screen dimension and device pixel ratio constraints
 type Screen struct {
-	MinWidth  *int
-	MaxWidth  *int
-	MinHeight *int
-	MaxHeight *int
+	MinWidth            *int
+	MaxWidth            *int
+	MinHeight           *int
+	MaxHeight           *int
+	MinDevicePixelRatio *float64
+	MaxDevicePixelRatio *float64
 }
 
 // IsSet returns true if any screen constraints are set
 func (s *Screen) IsSet() bool {
-	return s.MinWidth != nil || s.MaxWidth != nil || s.MinHeight != nil || s.MaxHeight != nil
+	return s.MinWidth != nil || s.MaxWidth != nil || s.MinHeight != nil || s.MaxHeight != nil ||
+		s.MinDevicePixelRatio != nil || s.MaxDevicePixelRatio != nil
+}
+
+// Matches returns true if the screen fingerprint satisfies the constraints
+func (s *Screen) Matches(screen ScreenFingerprint) bool {
+	if s.MinWidth != nil && screen.Width < *s.MinWidth {
+		return false
+	}
+	if s.MaxWidth != nil && screen.Width > *s.MaxWidth {
+		return false
+	}
+	if s.MinHeight != nil && screen.Height < *s.MinHeight {
+		return false
+	}
+	if s.MaxHeight != nil && screen.Height > *s.MaxHeight {
+		return false
+	}
+	if s.MinDevicePixelRatio != nil && screen.DevicePixelRatio < *s.MinDevicePixelRatio {
+		return false
+	}
+	if s.MaxDevicePixelRatio != nil && screen.DevicePixelRatio > *s.MaxDevicePixelRatio {
+		return false
+	}
+	return true
+}
+
+// String renders the constraints set, e.g. "minWidth=1280,maxWidth=1920"
+func (s *Screen) String() string {
+	var parts []string
+	for _, bound := range []struct {
+		name  string
+		value *int
+	}{{"minWidth", s.MinWidth}, {"maxWidth", s.MaxWidth}, {"minHeight", s.MinHeight}, {"maxHeight", s.MaxHeight}} {
+		if bound.value != nil {
+			parts = append(parts, fmt.Sprintf("%s=%d", bound.name, *bound.value))
+		}
+	}
+	if s.MinDevicePixelRatio != nil {
+		parts = append(parts, fmt.Sprintf("minDevicePixelRatio=%g", *s.MinDevicePixelRatio))
+	}
+	if s.MaxDevicePixelRatio != nil {
+		parts = append(parts, fmt.Sprintf("maxDevicePixelRatio=%g", *s.MaxDevicePixelRatio))
+	}
+	return strings.Join(parts, ",")
 }
 
 // Validate validates the screen constraints
@@ -159,10 +224,14 @@ func (s *Screen) Validate() error {
 	if s.MinHeight != nil && s.MaxHeight != nil && *s.MinHeight > *s.MaxHeight {
 		return fmt.Errorf("minHeight cannot be greater than maxHeight")
 	}
+	if s.MinDevicePixelRatio != nil && s.MaxDevicePixelRatio != nil && *s.MinDevicePixelRatio > *s.MaxDevicePixelRatio {
+		return fmt.Errorf("minDevicePixelRatio cannot be greater than maxDevicePixelRatio")
+	}
 	return nil
 }
 
-// FingerprintGenerator generates browser fingerprints using a Bayesian network
+// FingerprintGenerator generates browser fingerprints using a Bayesian network.
+// Generate is safe for concurrent use: options passed to it only apply to that call.
 type FingerprintGenerator struct {
 	network           *bayesianNetwork
 	headerGenerator   *HeaderGenerator
@@ -171,6 +240,24 @@ type FingerprintGenerator struct {
 	strict            bool
 	mockWebRTC        bool
 	slim              bool
+	linuxFlavor       *LinuxFlavor
+	embedded          *EmbeddedOptions
+	inApp             InAppBrowser
+	dataSource        fs.FS
+	sharedNetworks    bool
+	networks          map[string]*bayesianNetwork
+	logger            *slog.Logger
+	random            RandomSource
+	samplingBudget    samplingBudget
+	screenCandidates  []screenCandidate
+	loaded            *generatorData
+	latest            *atomic.Pointer[generatorData]
+	relaxationReport  *RelaxationReport
+	samplingTrace     *SamplingTrace
+	batchUniqueness   BatchUniqueness
+	realism           Realism
+	geoCountry        string
+	privacySignals    *PrivacySignals
 }
 
 // FingerprintOption represents an option for configuring the fingerprint generator
@@ -178,28 +265,61 @@ type FingerprintOption func(*FingerprintGenerator)
 
 // NewFingerprintGenerator creates a new fingerprint generator with the given options
 func NewFingerprintGenerator(opts ...FingerprintOption) (*FingerprintGenerator, error) {
-	hgen, err := NewHeaderGenerator()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create header generator: %w", err)
-	}
 	generator := &FingerprintGenerator{
-		network:         newBayesianNetwork(),
-		headerGenerator: hgen,
+		network: newBayesianNetwork(),
 	}
 
 	// Apply options
 	for _, opt := range opts {
 		opt(generator)
 	}
+	// Generate is safe for concurrent use, generations would share a report or trace given here
+	if generator.relaxationReport != nil {
+		return nil, fmt.Errorf("WithRelaxationReport is only accepted by Generate, not by NewFingerprintGenerator")
+	}
+	if generator.samplingTrace != nil {
+		return nil, fmt.Errorf("WithSamplingTrace is only accepted by Generate, not by NewFingerprintGenerator")
+	}
 
-	// Load the fingerprint network definition
-	if err := generator.loadNetwork(); err != nil {
-		return nil, fmt.Errorf("failed to load fingerprint network: %w", err)
+	data, err := generator.loadData(generator.dataSource)
+	if err != nil {
+		return nil, err
 	}
+	generator.latest = new(atomic.Pointer[generatorData])
+	generator.latest.Store(data)
+	generator.useData(data)
 
 	return generator, nil
 }
 
+// WithDataDir loads the network data from dir instead of the embedded data.
+// Files missing from dir fall back to the embedded ones.
+func WithDataDir(dir string) FingerprintOption {
+	return func(g *FingerprintGenerator) {
+		g.dataSource = nil
+		if dir != "" {
+			g.dataSource = os.DirFS(dir)
+		}
+	}
+}
+
+// WithDataSource loads the network data from data instead of the embedded data, e.g. files shipped out-of-band
+// or an fs.FS fetching them from a CDN. Files missing from data fall back to the embedded ones.
+func WithDataSource(data fs.FS) FingerprintOption {
+	return func(g *FingerprintGenerator) {
+		g.dataSource = data
+	}
+}
+
+// WithSharedNetworks makes the generator use the embedded networks parsed once per process instead of parsing its
+// own copy, so creating a generator per worker does not duplicate the parsed data. Shared networks stay in memory
+// for the life of the process, and are not used with WithDataDir or WithDataSource.
+func WithSharedNetworks(shared bool) FingerprintOption {
+	return func(g *FingerprintGenerator) {
+		g.sharedNetworks = shared
+	}
+}
+
 // WithScreen sets the screen constraints for the fingerprint generator
 func WithScreen(screen *Screen) FingerprintOption {
 	return func(g *FingerprintGenerator) {
@@ -235,18 +355,39 @@ func WithHeaderConstraints(constraints HeaderConstraints) FingerprintOption {
 	}
 }
 
-// Generate generates a new fingerprint with the given options
+// Generate generates a new fingerprint with the given options, which override the generator options for this call only
 func (g *FingerprintGenerator) Generate(opts ...FingerprintOption) (*Fingerprint, error) {
-	// Apply additional options
-	for _, opt := range opts {
-		opt(g)
+	g = g.current().withOptions(opts)
+	g.relaxationReport.reset()
+	g.samplingTrace.reset()
+
+	features, err := g.realism.Features()
+	if err != nil {
+		return nil, err
+	}
+	geoRegion, err := g.geoRegion()
+	if err != nil {
+		return nil, err
+	}
+	headerConstraints, err := embeddedHeaderConstraints(g.headerConstraints, g.embedded)
+	if err != nil {
+		return nil, err
+	}
+	headerConstraints, err = inAppHeaderConstraints(headerConstraints, g.inApp)
+	if err != nil {
+		return nil, err
+	}
+	// The locales follow the country of the geolocation unless requested
+	if geoRegion != "" && len(headerConstraints.Locales) == 0 && headerConstraints.Language == "" && headerConstraints.Region == "" {
+		headerConstraints.Region = geoRegion
 	}
 
 	// Generate headers first to get user agent
-	headers, err := g.headerGenerator.GenerateHeaders(g.headerConstraints)
+	result, err := g.headerGenerator.generateHeaders(headerConstraints, g.relaxationReport)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate headers: %w", err)
 	}
+	headers := result.headers
 
 	// Get user agent from headers
 	userAgent := headers["User-Agent"]
@@ -260,41 +401,144 @@ func (g *FingerprintGenerator) Generate(opts ...FingerprintOption) (*Fingerprint
 	}
 
 	// Add screen constraints if specified
-	if g.screen != nil && g.screen.IsSet() {
-		// TODO: Implement screen constraint filtering
+	screenSet := g.screen != nil && g.screen.IsSet()
+	if screenSet {
+		if err := g.screen.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid screen constraints: %w", err)
+		}
+		screens := g.filterScreenValues(g.screen.Matches)
+		if len(screens) == 0 {
+			if g.strict {
+				return nil, newConstraintError("screen", "no screen matches the constraints", fmt.Errorf("no screen in the data satisfies the screen constraints"))
+			}
+			logDebug(g.logger, "relaxing fingerprint constraints", "reason", "no screen matches the constraints", "dropped", []string{"screen"})
+			g.relaxationReport.add(Relaxation{Constraint: "screen", Requested: g.screen.String(), Reason: "no screen matches the constraints"})
+		} else {
+			constraints["screen"] = screens
+		}
+	} else if isTabletUserAgent(userAgent) {
+		constraints["screen"] = g.filterScreenValues(tabletScreen)
 	}
 
 	// Generate fingerprint
-	fingerprint, ok := g.network.generateConsistentSampleWhenPossible(constraints)
+	if err := g.samplingBudget.validate(); err != nil {
+		return nil, fmt.Errorf("invalid sampling budget: %w", err)
+	}
+	limit := g.samplingBudget.start()
+	fingerprint, ok := g.network.generateConsistentSampleWithinLimit(constraints, limit)
+	if !ok && g.strict && limit.exhausted() {
+		return nil, newConstraintError("userAgent", "sampling budget exhausted", fmt.Errorf("could not generate fingerprint with given constraints: %w", ErrSamplingBudgetExhausted))
+	}
+	if !ok && g.strict && screenSet {
+		return nil, newConstraintError("screen", "no sample matches the screen", fmt.Errorf("no screen satisfying the screen constraints is consistent with user agent %s", userAgent))
+	}
+	if !ok && !g.strict && constraints["screen"] != nil {
+		// Keep the user agent and drop the screen constraints
+		reason := samplingFailure(limit, "no sample matches the screen")
+		logDebug(g.logger, "relaxing fingerprint constraints", "reason", reason, "dropped", []string{"screen"})
+		requested := "tablet"
+		if screenSet {
+			requested = g.screen.String()
+		}
+		g.relaxationReport.add(Relaxation{Constraint: "screen", Requested: requested, Reason: reason})
+		delete(constraints, "screen")
+		fingerprint, ok = g.network.generateConsistentSampleWithinLimit(constraints, limit)
+	}
 	if !ok {
 		if g.strict {
-			return nil, fmt.Errorf("could not generate fingerprint with given constraints")
+			return nil, newConstraintError("userAgent", "no sample matches the user agent", fmt.Errorf("could not generate fingerprint with given constraints"))
 		}
 		// Try again without constraints
+		reason := samplingFailure(limit, "no sample matches the user agent")
+		logDebug(g.logger, "relaxing fingerprint constraints", "reason", reason, "userAgent", userAgent)
+		g.relaxationReport.add(Relaxation{Constraint: "userAgent", Requested: userAgent, Reason: reason})
 		fingerprint = g.network.generateSample(nil)
 	}
 
 	// Transform raw fingerprint into structured format
-	return g.transformFingerprint(fingerprint, headers, g.mockWebRTC, g.slim)
+	fp, err := g.transformFingerprint(fingerprint, headers, g.mockWebRTC, g.slim)
+	if err != nil {
+		return nil, err
+	}
+	// The languages follow the locales of the headers rather than the sampled ones
+	newLocaleModel(userAgent, result.locales).apply(fp)
+	// The time zone is sampled where the identity is located
+	region := headerConstraints.Region
+	if geoRegion != "" {
+		region = geoRegion
+	}
+	zone := sampleTimezoneName(timezoneRegion(region, result.locales), g.random)
+	if err := g.setTimezone(fp, zone); err != nil {
+		return nil, err
+	}
+	if geoRegion != "" {
+		if err := g.setGeolocation(fp, geoRegion, zone); err != nil {
+			return nil, err
+		}
+	}
+	g.applyRealism(fp, features)
+	g.postProcess(fp)
+	return fp, nil
 }
 
-// transformFingerprint converts a raw fingerprint map into a structured Fingerprint
-func (g *FingerprintGenerator) transformFingerprint(raw map[string]string, headers map[string]string, mockWebRTC bool, slim bool) (*Fingerprint, error) {
-	// Preprocess the fingerprint data
-	for key, value := range raw {
-		// Handle missing values, set to empty string
-		if value == "*MISSING_VALUE*" {
-			raw[key] = ""
+// withOptions returns a copy of the generator with the options applied, leaving the generator untouched
+// so concurrent calls do not race. The loaded data is shared, only the settings are copied.
+func (g *FingerprintGenerator) withOptions(opts []FingerprintOption) *FingerprintGenerator {
+	if len(opts) == 0 {
+		return g
+	}
+	c := *g
+	c.headerGenerator = g.headerGenerator.clone()
+	network := *g.network
+	c.network = &network
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return &c
+}
+
+// screenCandidate is a value of the screen node with its parsed screen
+type screenCandidate struct {
+	value  string
+	screen ScreenFingerprint
+}
+
+// parseScreenCandidates parses the stringified screen node values once, so screen constraints are not
+// parsed again on every generation
+func parseScreenCandidates(network *bayesianNetwork) []screenCandidate {
+	screenNode, ok := network.NodesByName["screen"]
+	if !ok {
+		return nil
+	}
+	candidates := make([]screenCandidate, 0, len(screenNode.PossibleValues))
+	for _, value := range screenNode.PossibleValues {
+		if !strings.HasPrefix(value, "*STRINGIFIED*") {
 			continue
 		}
+		var screen ScreenFingerprint
+		if err := json.Unmarshal([]byte(value[len("*STRINGIFIED*"):]), &screen); err != nil {
+			continue
+		}
+		candidates = append(candidates, screenCandidate{value: value, screen: screen})
+	}
+	return candidates
+}
 
-		// Handle stringified objects/arrays
-		if strings.HasPrefix(value, "*STRINGIFIED*") {
-			// Remove the prefix and parse the JSON
-			jsonStr := value[len("*STRINGIFIED*"):]
-			raw[key] = jsonStr
+// filterScreenValues returns the screen node values accepted by the given predicate
+func (g *FingerprintGenerator) filterScreenValues(accept func(ScreenFingerprint) bool) []string {
+	values := make([]string, 0)
+	for _, candidate := range g.screenCandidates {
+		if accept(candidate.screen) {
+			values = append(values, candidate.value)
 		}
 	}
+	return values
+}
+
+// transformFingerprint converts a raw fingerprint map into a structured Fingerprint
+func (g *FingerprintGenerator) transformFingerprint(raw map[string]string, headers map[string]string, mockWebRTC bool, slim bool) (*Fingerprint, error) {
+	// Decode stringified values and drop the ones the browser did not expose
+	normalizeRawValues(raw)
 
 	// Process Accept-Language header
 	if acceptLanguage := headers["Accept-Language"]; acceptLanguage != "" {
@@ -338,14 +582,14 @@ func (g *FingerprintGenerator) transformFingerprint(raw map[string]string, heade
 	navigator := NavigatorFingerprint{
 		UserAgent:           raw["userAgent"],
 		UserAgentData:       userAgentData,
-		DoNotTrack:          parseStringPtr(raw["doNotTrack"]),
+		DoNotTrack:          optionalString(raw, "doNotTrack"),
 		AppCodeName:         raw["appCodeName"],
 		AppName:             raw["appName"],
 		AppVersion:          raw["appVersion"],
-		OSCpu:               raw["oscpu"],
+		OSCpu:               optionalString(raw, "oscpu"),
 		Webdriver:           raw["webdriver"],
 		Platform:            raw["platform"],
-		DeviceMemory:        parseIntPtr(raw["deviceMemory"]),
+		DeviceMemory:        optionalInt(raw, "deviceMemory"),
 		Product:             raw["product"],
 		ProductSub:          raw["productSub"],
 		Vendor:              raw["vendor"],
@@ -424,12 +668,97 @@ func (g *FingerprintGenerator) transformFingerprint(raw map[string]string, heade
 	}, nil
 }
 
-// loadNetwork loads the fingerprint network definition from the embedded zip file
-func (g *FingerprintGenerator) loadNetwork() error {
-	network, err := loadNetworkFromZip("fingerprint-network-definition.zip")
+// generatorData is the network data used by a generator, swapped as a whole by Reload
+type generatorData struct {
+	network          *bayesianNetwork
+	headerGenerator  *HeaderGenerator
+	screenCandidates []screenCandidate
+}
+
+// loadData loads the header and fingerprint networks from the data source, nil loads the embedded data
+func (g *FingerprintGenerator) loadData(source fs.FS) (*generatorData, error) {
+	// Networks of a data source may differ between generators, only the embedded ones are shared
+	hgen, err := newHeaderGenerator(networkLoader{
+		data:     dataSourceFS(source),
+		shared:   g.sharedNetworks && source == nil,
+		networks: g.networks,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create header generator: %w", err)
+	}
+	hgen.SetLogger(g.logger)
+	hgen.SetRandomSource(g.random)
+	hgen.setSamplingTrace(g.samplingTrace)
+
+	network, err := hgen.loader.load(fingerprintNetworkFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fingerprint network: %w", err)
+	}
+	network.logger = networkLogger(g.logger, "fingerprint")
+	network.random = g.random
+	network.tracer = networkTracer{trace: g.samplingTrace, network: "fingerprint"}
+	return &generatorData{
+		network:          network,
+		headerGenerator:  hgen,
+		screenCandidates: parseScreenCandidates(network),
+	}, nil
+}
+
+// useData makes the generator generate from data
+func (g *FingerprintGenerator) useData(data *generatorData) {
+	g.loaded = data
+	g.network = data.network
+	g.headerGenerator = data.headerGenerator
+	g.screenCandidates = data.screenCandidates
+}
+
+// current returns the generator using the latest data swapped in by Reload, keeping its logger, random source and
+// sampling trace
+func (g *FingerprintGenerator) current() *FingerprintGenerator {
+	data := g.latest.Load()
+	if data == g.loaded {
+		return g
+	}
+	c := *g
+	c.useData(data)
+	c.headerGenerator = data.headerGenerator.clone()
+	c.headerGenerator.SetLogger(g.logger)
+	c.headerGenerator.SetRandomSource(g.random)
+	c.headerGenerator.setSamplingTrace(g.samplingTrace)
+	network := *data.network
+	network.logger = networkLogger(g.logger, "fingerprint")
+	network.random = g.random
+	network.tracer = networkTracer{trace: g.samplingTrace, network: "fingerprint"}
+	c.network = &network
+	return &c
+}
+
+// HeaderGenerator returns a header generator using the current data of the generator, with its logger and random
+// source. Setting them on the header generator does not affect the generator.
+func (g *FingerprintGenerator) HeaderGenerator() *HeaderGenerator {
+	hgen := g.current().headerGenerator.clone()
+	// The trace of the generator is only filled by its own generations
+	hgen.setSamplingTrace(nil)
+	return hgen
+}
+
+// Reload swaps in the network data of source, nil reloads the embedded data. Generations in flight finish on the
+// previous data and later ones use the new data, including on copies of the generator. The previous data is kept
+// when the new data fails to load or to generate a fingerprint.
+func (g *FingerprintGenerator) Reload(source fs.FS) error {
+	data, err := g.loadData(source)
 	if err != nil {
 		return err
 	}
-	g.network = network
+	// Check the data on its own, without drawing from the random source of the generator
+	candidate := *g
+	candidate.useData(data)
+	candidate.latest = new(atomic.Pointer[generatorData])
+	candidate.latest.Store(data)
+	candidate.relaxationReport = nil
+	if _, err := candidate.Generate(WithRandomSource(nil), WithSamplingTrace(nil)); err != nil {
+		return fmt.Errorf("reloaded data cannot generate fingerprints: %w", err)
+	}
+	g.latest.Store(data)
 	return nil
 }
@@ -0,0 +1,103 @@
+package forgeron
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidFingerprint is returned when a fingerprint is not internally consistent
+var ErrInvalidFingerprint = errors.New("invalid fingerprint")
+
+// fingerprintJSON has the fields of Fingerprint without its methods, so it is encoded without recursing into MarshalJSON
+type fingerprintJSON Fingerprint
+
+// MarshalJSON encodes the fingerprint, refusing inconsistent fingerprints so they are never persisted
+func (f *Fingerprint) MarshalJSON() ([]byte, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	return json.Marshal((*fingerprintJSON)(f))
+}
+
+// UnmarshalFingerprint decodes a fingerprint encoded with MarshalJSON and checks it is still consistent,
+// so fingerprints reloaded from disk or a shared store can be used safely
+func UnmarshalFingerprint(data []byte) (*Fingerprint, error) {
+	var fp Fingerprint
+	if err := json.Unmarshal(data, &fp); err != nil {
+		return nil, fmt.Errorf("failed to decode fingerprint: %w", err)
+	}
+	if err := fp.Validate(); err != nil {
+		return nil, err
+	}
+	return &fp, nil
+}
+
+// Validate checks the fingerprint is internally consistent: the platform matches the user agent, the language is
+// the first of the language list, the User-Agent header matches the navigator and the available screen fits the screen
+func (f *Fingerprint) Validate() error {
+	var problems []string
+	userAgent := f.Navigator.UserAgent
+	if userAgent == "" {
+		problems = append(problems, "user agent is empty")
+	} else if os := parseUserAgent(userAgent).OS; !platformMatchesOS(f.Navigator.Platform, os) {
+		problems = append(problems, fmt.Sprintf("platform %q does not match the %s user agent", f.Navigator.Platform, os))
+	}
+	if header, ok := f.header("User-Agent"); ok && header != userAgent {
+		problems = append(problems, fmt.Sprintf("User-Agent header %q does not match navigator user agent %q", header, userAgent))
+	}
+	if f.Screen.AvailWidth > f.Screen.Width || f.Screen.AvailHeight > f.Screen.Height {
+		problems = append(problems, fmt.Sprintf("available screen %dx%d exceeds screen %dx%d",
+			f.Screen.AvailWidth, f.Screen.AvailHeight, f.Screen.Width, f.Screen.Height))
+	}
+	if len(f.Navigator.Languages) == 0 {
+		problems = append(problems, "languages are empty")
+	} else if f.Navigator.Language != f.Navigator.Languages[0] {
+		problems = append(problems, fmt.Sprintf("language %q does not match first language %q", f.Navigator.Language, f.Navigator.Languages[0]))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidFingerprint, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// platformMatchesOS reports whether navigator.platform is plausible for the OS of the user agent, unknown OSes match any platform.
+// iPads requesting the desktop site report MacIntel.
+func platformMatchesOS(platform string, os OS) bool {
+	switch os {
+	case Windows:
+		return strings.HasPrefix(platform, "Win")
+	case MacOS:
+		return strings.HasPrefix(platform, "Mac")
+	case Linux, Android, ChromeOS:
+		return strings.HasPrefix(platform, "Linux") || (os == Android && platform == "Android")
+	case IOS:
+		return strings.HasPrefix(platform, "iP") || platform == "MacIntel"
+	}
+	return true
+}
+
+// defaultPlatforms is the navigator.platform reported on each OS
+var defaultPlatforms = map[OS]string{
+	Windows:  "Win32",
+	MacOS:    "MacIntel",
+	Linux:    "Linux x86_64",
+	Android:  "Linux armv8l",
+	ChromeOS: "Linux x86_64",
+	IOS:      "iPhone",
+}
+
+// applyPlatformConsistency replaces a platform that does not match the user agent, which happens
+// when the fingerprint was sampled without the user agent constraint
+func applyPlatformConsistency(fp *Fingerprint) {
+	os := parseUserAgent(fp.Navigator.UserAgent).OS
+	if platformMatchesOS(fp.Navigator.Platform, os) {
+		return
+	}
+	platform := defaultPlatforms[os]
+	if os == IOS && strings.Contains(fp.Navigator.UserAgent, "iPad") {
+		platform = "iPad"
+	}
+	fp.Navigator.Platform = platform
+}
@@ -0,0 +1,123 @@
+package forgeron
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// consistentFingerprint returns a minimal fingerprint passing Validate
+func consistentFingerprint() *Fingerprint {
+	return &Fingerprint{
+		Screen: ScreenFingerprint{Width: 1920, Height: 1080, AvailWidth: 1920, AvailHeight: 1040},
+		Navigator: NavigatorFingerprint{
+			UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36",
+			Platform:  "Win32",
+			Language:  "en-US",
+			Languages: []string{"en-US", "en"},
+		},
+		Headers: map[string]string{"Accept-Language": "en-US,en;q=0.9"},
+	}
+}
+
+func TestFingerprintJSONRoundTrip(t *testing.T) {
+	gen := newGeneratorOrFatal(t)
+	for i := 0; i < 200; i++ {
+		fp, err := gen.Generate()
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		data, err := json.Marshal(fp)
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		got, err := UnmarshalFingerprint(data)
+		if err != nil {
+			t.Fatalf("UnmarshalFingerprint() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, fp) {
+			t.Fatalf("round trip changed the fingerprint of %s", fp.Navigator.UserAgent)
+		}
+	}
+}
+
+func TestFingerprintValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(fp *Fingerprint)
+		want   string
+	}{
+		{"consistent", func(fp *Fingerprint) {}, ""},
+		{"empty user agent", func(fp *Fingerprint) { fp.Navigator.UserAgent = "" }, "user agent is empty"},
+		{"platform of another OS", func(fp *Fingerprint) { fp.Navigator.Platform = "MacIntel" }, `platform "MacIntel"`},
+		{"avail width above width", func(fp *Fingerprint) { fp.Screen.AvailWidth = 2560 }, "exceeds screen"},
+		{"avail height above height", func(fp *Fingerprint) { fp.Screen.AvailHeight = 1200 }, "exceeds screen"},
+		{"no languages", func(fp *Fingerprint) { fp.Navigator.Languages = nil }, "languages are empty"},
+		{"language not first", func(fp *Fingerprint) { fp.Navigator.Language = "fr-FR" }, `language "fr-FR"`},
+		{"user agent header of another browser", func(fp *Fingerprint) { fp.Headers["User-Agent"] = "curl/8.0" }, "User-Agent header"},
+		{"lowercase user agent header", func(fp *Fingerprint) { fp.Headers["user-agent"] = "curl/8.0" }, "User-Agent header"},
+		{"iPad in desktop mode", func(fp *Fingerprint) {
+			fp.Navigator.UserAgent = "Mozilla/5.0 (iPad; CPU OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1"
+			fp.Navigator.Platform = "MacIntel"
+		}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fp := consistentFingerprint()
+			tt.modify(fp)
+			err := fp.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidFingerprint) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() error = %v, want ErrInvalidFingerprint mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshalRejectsInconsistentFingerprint(t *testing.T) {
+	fp := consistentFingerprint()
+	fp.Navigator.Languages = nil
+	if _, err := json.Marshal(fp); !errors.Is(err, ErrInvalidFingerprint) {
+		t.Errorf("Marshal() error = %v, want ErrInvalidFingerprint", err)
+	}
+}
+
+func TestUnmarshalFingerprintRejectsInconsistentData(t *testing.T) {
+	data, err := json.Marshal(consistentFingerprint())
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	tampered := strings.Replace(string(data), `"platform":"Win32"`, `"platform":"iPhone"`, 1)
+	if _, err := UnmarshalFingerprint([]byte(tampered)); !errors.Is(err, ErrInvalidFingerprint) {
+		t.Errorf("UnmarshalFingerprint() error = %v, want ErrInvalidFingerprint", err)
+	}
+	if _, err := UnmarshalFingerprint([]byte("{")); err == nil || errors.Is(err, ErrInvalidFingerprint) {
+		t.Errorf("UnmarshalFingerprint() error = %v, want a decoding error", err)
+	}
+}
+
+func TestApplyPlatformConsistency(t *testing.T) {
+	tests := []struct {
+		userAgent, platform, want string
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36", "Linux x86_64", "Win32"},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36", "Linux x86_64", "MacIntel"},
+		{"Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Mobile Safari/537.36", "Win32", "Linux armv8l"},
+		{"Mozilla/5.0 (iPad; CPU OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1", "Win32", "iPad"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36", "Win32", "Win32"},
+	}
+	for _, tt := range tests {
+		fp := &Fingerprint{Navigator: NavigatorFingerprint{UserAgent: tt.userAgent, Platform: tt.platform}}
+		applyPlatformConsistency(fp)
+		if fp.Navigator.Platform != tt.want {
+			t.Errorf("platform of %q = %q, want %q", tt.userAgent, fp.Navigator.Platform, tt.want)
+		}
+	}
+}
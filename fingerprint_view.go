@@ -0,0 +1,259 @@
+package forgeron
+
+import (
+	"maps"
+	"slices"
+)
+
+// FingerprintView is a read-only view of a Fingerprint; accessors return copies so the
+// underlying identity cannot be corrupted by callers sharing it
+type FingerprintView struct {
+	fp *Fingerprint
+}
+
+// View returns an immutable view of a copy of the fingerprint
+func (f *Fingerprint) View() FingerprintView {
+	return FingerprintView{fp: f.Clone()}
+}
+
+// Clone returns a deep copy of the fingerprint
+func (f *Fingerprint) Clone() *Fingerprint {
+	if f == nil {
+		return nil
+	}
+	c := *f
+	c.Navigator = f.Navigator.clone()
+	c.Headers = maps.Clone(f.Headers)
+	c.VideoCodecs = maps.Clone(f.VideoCodecs)
+	c.AudioCodecs = maps.Clone(f.AudioCodecs)
+	c.PluginsData = f.PluginsData.clone()
+	c.Fonts = slices.Clone(f.Fonts)
+	c.Supports = maps.Clone(f.Supports)
+	if f.Battery != nil {
+		battery := *f.Battery
+		battery.ChargingTime = cloneIntPtr(f.Battery.ChargingTime)
+		battery.DischargingTime = cloneIntPtr(f.Battery.DischargingTime)
+		c.Battery = &battery
+	}
+	if f.VideoCard != nil {
+		videoCard := *f.VideoCard
+		c.VideoCard = &videoCard
+	}
+	if f.MultimediaDevices != nil {
+		c.MultimediaDevices = &MultimediaDevices{
+			Speakers: slices.Clone(f.MultimediaDevices.Speakers),
+			Micros:   slices.Clone(f.MultimediaDevices.Micros),
+			Webcams:  slices.Clone(f.MultimediaDevices.Webcams),
+		}
+	}
+	if f.Timezone != nil {
+		timezone := *f.Timezone
+		timezone.Transitions = slices.Clone(f.Timezone.Transitions)
+		c.Timezone = &timezone
+	}
+	if f.Geolocation != nil {
+		geolocation := *f.Geolocation
+		c.Geolocation = &geolocation
+	}
+	if f.Transport != nil {
+		transport := f.Transport.clone()
+		c.Transport = &transport
+	}
+	if f.Noise != nil {
+		noise := *f.Noise
+		c.Noise = &noise
+	}
+	if f.TimingJitter != nil {
+		jitter := *f.TimingJitter
+		c.TimingJitter = &jitter
+	}
+	return &c
+}
+
+// clone returns a deep copy of the transport profile
+func (p TransportProfile) clone() TransportProfile {
+	c := p
+	c.TLS.Versions = slices.Clone(p.TLS.Versions)
+	c.TLS.CipherSuites = slices.Clone(p.TLS.CipherSuites)
+	c.TLS.Extensions = slices.Clone(p.TLS.Extensions)
+	c.TLS.SupportedGroups = slices.Clone(p.TLS.SupportedGroups)
+	c.TLS.PointFormats = slices.Clone(p.TLS.PointFormats)
+	c.TLS.SignatureAlgorithms = slices.Clone(p.TLS.SignatureAlgorithms)
+	c.TLS.CertCompression = slices.Clone(p.TLS.CertCompression)
+	c.TLS.ALPN = slices.Clone(p.TLS.ALPN)
+	c.HTTP2.Settings = slices.Clone(p.HTTP2.Settings)
+	c.HTTP2.Priorities = slices.Clone(p.HTTP2.Priorities)
+	c.HTTP2.PseudoHeaderOrder = slices.Clone(p.HTTP2.PseudoHeaderOrder)
+	return c
+}
+
+// clone returns a deep copy of the navigator fingerprint
+func (n NavigatorFingerprint) clone() NavigatorFingerprint {
+	c := n
+	if n.UserAgentData != nil {
+		uaData := *n.UserAgentData
+		uaData.Brands = slices.Clone(n.UserAgentData.Brands)
+		uaData.FullVersionList = slices.Clone(n.UserAgentData.FullVersionList)
+		c.UserAgentData = &uaData
+	}
+	c.DoNotTrack = cloneStringPtr(n.DoNotTrack)
+	if n.GlobalPrivacyControl != nil {
+		gpc := *n.GlobalPrivacyControl
+		c.GlobalPrivacyControl = &gpc
+	}
+	c.OSCpu = cloneStringPtr(n.OSCpu)
+	c.DeviceMemory = cloneIntPtr(n.DeviceMemory)
+	c.Languages = slices.Clone(n.Languages)
+	c.ExtraProperties = cloneAnyMap(n.ExtraProperties)
+	return c
+}
+
+// clone returns a deep copy of the plugins data
+func (p PluginsData) clone() PluginsData {
+	c := PluginsData{MimeTypes: slices.Clone(p.MimeTypes)}
+	if p.Plugins != nil {
+		c.Plugins = make([]Plugin, len(p.Plugins))
+		for i, plugin := range p.Plugins {
+			plugin.MimeTypes = slices.Clone(plugin.MimeTypes)
+			c.Plugins[i] = plugin
+		}
+	}
+	return c
+}
+
+// cloneStringPtr returns a copy of an optional string
+func cloneStringPtr(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	return &c
+}
+
+// cloneIntPtr returns a copy of an optional integer
+func cloneIntPtr(i *int) *int {
+	if i == nil {
+		return nil
+	}
+	c := *i
+	return &c
+}
+
+// cloneAnyMap returns a deep copy of a decoded JSON object
+func cloneAnyMap(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]any, len(m))
+	for k, v := range m {
+		c[k] = cloneAny(v)
+	}
+	return c
+}
+
+// cloneAny returns a deep copy of a decoded JSON value
+func cloneAny(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		return cloneAnyMap(v)
+	case []any:
+		c := make([]any, len(v))
+		for i, item := range v {
+			c[i] = cloneAny(item)
+		}
+		return c
+	default:
+		return v
+	}
+}
+
+// Fingerprint returns a mutable deep copy of the viewed fingerprint
+func (v FingerprintView) Fingerprint() *Fingerprint {
+	return v.fp.Clone()
+}
+
+// UserAgent returns the navigator user agent
+func (v FingerprintView) UserAgent() string {
+	return v.fp.Navigator.UserAgent
+}
+
+// Screen returns the screen fingerprint
+func (v FingerprintView) Screen() ScreenFingerprint {
+	return v.fp.Screen
+}
+
+// Navigator returns a copy of the navigator fingerprint
+func (v FingerprintView) Navigator() NavigatorFingerprint {
+	return v.fp.Navigator.clone()
+}
+
+// Headers returns a copy of the generated headers
+func (v FingerprintView) Headers() map[string]string {
+	return maps.Clone(v.fp.Headers)
+}
+
+// Header returns a single generated header value
+func (v FingerprintView) Header(name string) (string, bool) {
+	value, ok := v.fp.Headers[name]
+	return value, ok
+}
+
+// Languages returns a copy of the navigator languages
+func (v FingerprintView) Languages() []string {
+	return slices.Clone(v.fp.Navigator.Languages)
+}
+
+// Fonts returns a copy of the fonts list
+func (v FingerprintView) Fonts() []string {
+	return slices.Clone(v.fp.Fonts)
+}
+
+// Supports reports whether the browser supports the feature
+func (v FingerprintView) Supports(feature Feature) bool {
+	return v.fp.Supports[feature]
+}
+
+// VideoCodecs returns a copy of the video codecs support
+func (v FingerprintView) VideoCodecs() map[string]string {
+	return maps.Clone(v.fp.VideoCodecs)
+}
+
+// AudioCodecs returns a copy of the audio codecs support
+func (v FingerprintView) AudioCodecs() map[string]string {
+	return maps.Clone(v.fp.AudioCodecs)
+}
+
+// with applies a mutation to a copy of the viewed fingerprint and re-validates it
+func (v FingerprintView) with(mutate func(*Fingerprint)) (FingerprintView, error) {
+	fp := v.fp.Clone()
+	mutate(fp)
+	if err := fp.Validate(); err != nil {
+		return v, err
+	}
+	return FingerprintView{fp: fp}, nil
+}
+
+// WithHeaders returns a new view with the given headers
+func (v FingerprintView) WithHeaders(headers map[string]string) (FingerprintView, error) {
+	return v.with(func(fp *Fingerprint) {
+		fp.Headers = maps.Clone(headers)
+	})
+}
+
+// WithScreen returns a new view with the given screen fingerprint
+func (v FingerprintView) WithScreen(screen ScreenFingerprint) (FingerprintView, error) {
+	return v.with(func(fp *Fingerprint) {
+		fp.Screen = screen
+	})
+}
+
+// WithLanguages returns a new view with the given navigator languages, the first one becoming navigator.language
+func (v FingerprintView) WithLanguages(languages []string) (FingerprintView, error) {
+	return v.with(func(fp *Fingerprint) {
+		fp.Navigator.Languages = slices.Clone(languages)
+		fp.Navigator.Language = ""
+		if len(languages) > 0 {
+			fp.Navigator.Language = languages[0]
+		}
+	})
+}
@@ -0,0 +1,165 @@
+package forgeron
+
+import (
+	"reflect"
+	"testing"
+)
+
+// testViewFingerprint returns a minimal consistent fingerprint
+func testViewFingerprint() *Fingerprint {
+	return &Fingerprint{
+		Screen: ScreenFingerprint{Width: 1920, Height: 1080, AvailWidth: 1920, AvailHeight: 1040},
+		Navigator: NavigatorFingerprint{
+			UserAgent:       "Mozilla/5.0",
+			Language:        "en-US",
+			Languages:       []string{"en-US"},
+			ExtraProperties: map[string]any{"vendorFlavors": []any{"chrome"}},
+		},
+		Headers: map[string]string{"User-Agent": "Mozilla/5.0"},
+		Fonts:   []string{"Arial"},
+	}
+}
+
+func TestFingerprintViewAccessorsReturnCopies(t *testing.T) {
+	view := testViewFingerprint().View()
+
+	headers := view.Headers()
+	headers["User-Agent"] = "changed"
+	view.Languages()[0] = "changed"
+	view.Fonts()[0] = "changed"
+	view.Navigator().ExtraProperties["vendorFlavors"].([]any)[0] = "changed"
+
+	if ua, _ := view.Header("User-Agent"); ua != "Mozilla/5.0" {
+		t.Errorf("header mutated through accessor: %q", ua)
+	}
+	if view.Languages()[0] != "en-US" {
+		t.Errorf("languages mutated through accessor: %v", view.Languages())
+	}
+	if view.Fonts()[0] != "Arial" {
+		t.Errorf("fonts mutated through accessor: %v", view.Fonts())
+	}
+	if got := view.Navigator().ExtraProperties["vendorFlavors"].([]any)[0]; got != "chrome" {
+		t.Errorf("extra properties mutated through accessor: %v", got)
+	}
+}
+
+func TestFingerprintViewMutatorsRevalidate(t *testing.T) {
+	view := testViewFingerprint().View()
+
+	updated, err := view.WithLanguages([]string{"fr-FR", "fr"})
+	if err != nil {
+		t.Fatalf("WithLanguages() error = %v", err)
+	}
+	if updated.Navigator().Language != "fr-FR" {
+		t.Errorf("Language = %q, want fr-FR", updated.Navigator().Language)
+	}
+	if view.Navigator().Language != "en-US" {
+		t.Error("WithLanguages() mutated the original view")
+	}
+
+	if _, err := view.WithLanguages(nil); err == nil {
+		t.Error("expected error for empty languages")
+	}
+	if _, err := view.WithScreen(ScreenFingerprint{Width: 800, Height: 600, AvailWidth: 1024, AvailHeight: 600}); err == nil {
+		t.Error("expected error for avail width greater than width")
+	}
+	if _, err := view.WithHeaders(map[string]string{"User-Agent": "other"}); err == nil {
+		t.Error("expected error for mismatching User-Agent header")
+	}
+	mismatched := consistentFingerprint()
+	mismatched.Navigator.Platform = "iPhone"
+	if _, err := mismatched.View().WithLanguages([]string{"en-US"}); err == nil {
+		t.Error("expected error for platform of another OS")
+	}
+}
+
+func TestFingerprintClone(t *testing.T) {
+	want := filledFingerprint()
+	fp := filledFingerprint()
+	mutateValue(reflect.ValueOf(fp.Clone()).Elem())
+	if !reflect.DeepEqual(fp, want) {
+		t.Errorf("fingerprint mutated through clone:\n got %+v\nwant %+v", fp, want)
+	}
+}
+
+// filledFingerprint returns a fingerprint with every pointer, slice and map field set
+func filledFingerprint() *Fingerprint {
+	fp := &Fingerprint{}
+	fillValue(reflect.ValueOf(fp).Elem())
+	return fp
+}
+
+// fillValue sets every exported field reachable from v to a non-zero value
+func fillValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		fillValue(v.Elem())
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if v.Field(i).CanSet() {
+				fillValue(v.Field(i))
+			}
+		}
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fillValue(v.Index(0))
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+		key, value := reflect.New(v.Type().Key()).Elem(), reflect.New(v.Type().Elem()).Elem()
+		fillValue(key)
+		fillValue(value)
+		v.SetMapIndex(key, value)
+	case reflect.Interface:
+		v.Set(reflect.ValueOf("value"))
+	case reflect.String:
+		v.SetString("value")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1)
+	}
+}
+
+// mutateValue changes every exported value reachable from v in place
+func mutateValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			mutateValue(v.Elem())
+		}
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if v.Field(i).CanSet() {
+				mutateValue(v.Field(i))
+			}
+		}
+	case reflect.Slice:
+		for i := range v.Len() {
+			mutateValue(v.Index(i))
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			value := reflect.New(v.Type().Elem()).Elem()
+			value.Set(v.MapIndex(key))
+			mutateValue(value)
+			v.SetMapIndex(key, value)
+		}
+	case reflect.Interface:
+		v.Set(reflect.ValueOf("mutated"))
+	case reflect.String:
+		v.SetString("mutated")
+	case reflect.Bool:
+		v.SetBool(!v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(v.Int() + 1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(v.Uint() + 1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(v.Float() + 1)
+	}
+}
@@ -0,0 +1,68 @@
+package forgeron
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// FixedGenerator picks one identity when created and returns it on every call until rotated, the "one browser per
+// crawler instance" pattern without a pool or sessions. It is safe for concurrent use.
+type FixedGenerator struct {
+	generator FingerprintProvider
+	opts      []FingerprintOption
+	seed      int64
+	mu        sync.RWMutex
+	fp        *Fingerprint
+}
+
+// NewFixedGenerator generates the identity of the run with the given generator and options. Identities are sampled
+// from a source seeded with seed, or with the current time when seed is zero, so logging Seed lets a run be
+// replayed with the same identities.
+func NewFixedGenerator(generator FingerprintProvider, seed int64, opts ...FingerprintOption) (*FixedGenerator, error) {
+	if generator == nil {
+		return nil, fmt.Errorf("generator is required")
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	f := &FixedGenerator{
+		generator: generator,
+		opts:      append([]FingerprintOption{WithRandomSource(NewSeededSource(seed))}, opts...),
+		seed:      seed,
+	}
+	if _, err := f.Rotate(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Generate returns a copy of the fixed identity. Options are ignored, the identity only changes with Rotate.
+func (f *FixedGenerator) Generate(...FingerprintOption) (*Fingerprint, error) {
+	return f.Fingerprint(), nil
+}
+
+// Fingerprint returns a copy of the fixed identity
+func (f *FixedGenerator) Fingerprint() *Fingerprint {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.fp.Clone()
+}
+
+// Rotate replaces the fixed identity with a newly generated one, e.g. once it got blocked. The previous
+// identity is kept when generation fails.
+func (f *FixedGenerator) Rotate() (*Fingerprint, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fp, err := f.generator.Generate(f.opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate identity: %w", err)
+	}
+	f.fp = fp
+	return fp.Clone(), nil
+}
+
+// Seed returns the seed the identities are sampled with
+func (f *FixedGenerator) Seed() int64 {
+	return f.seed
+}
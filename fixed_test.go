@@ -0,0 +1,71 @@
+package forgeron
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// failingProvider fails every generation
+type failingProvider struct{}
+
+func (failingProvider) Generate(...FingerprintOption) (*Fingerprint, error) {
+	return nil, errors.New("no identity")
+}
+
+func TestFixedGenerator(t *testing.T) {
+	provider := &countingProvider{}
+	fixed, err := NewFixedGenerator(provider, 0)
+	if err != nil {
+		t.Fatalf("NewFixedGenerator() error = %v", err)
+	}
+	if fixed.Seed() == 0 {
+		t.Error("Seed() = 0, want a seed from the current time")
+	}
+
+	first, _ := fixed.Generate()
+	second, _ := fixed.Generate(WithSlim(true))
+	if provider.calls != 1 || !reflect.DeepEqual(first, second) {
+		t.Errorf("Generate() called the provider %d times, want the identity generated once", provider.calls)
+	}
+	first.Navigator.HardwareConcurrency = 99
+	if fixed.Fingerprint().Navigator.HardwareConcurrency == 99 {
+		t.Error("Generate() returned the fixed identity instead of a copy")
+	}
+
+	rotated, err := fixed.Rotate()
+	if err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	if rotated.Navigator.HardwareConcurrency != 2 || fixed.Fingerprint().Navigator.HardwareConcurrency != 2 {
+		t.Errorf("Rotate() = %d, want the second identity", rotated.Navigator.HardwareConcurrency)
+	}
+}
+
+func TestFixedGeneratorSeed(t *testing.T) {
+	gen := newGeneratorOrFatal(t)
+	userAgents := func() []string {
+		fixed, err := NewFixedGenerator(gen, 42)
+		if err != nil {
+			t.Fatalf("NewFixedGenerator() error = %v", err)
+		}
+		first, _ := fixed.Generate()
+		rotated, err := fixed.Rotate()
+		if err != nil {
+			t.Fatalf("Rotate() error = %v", err)
+		}
+		return []string{first.Navigator.UserAgent, rotated.Navigator.UserAgent}
+	}
+	if first, again := userAgents(), userAgents(); !reflect.DeepEqual(first, again) {
+		t.Errorf("identities = %v and %v, want the same identities for a seed", first, again)
+	}
+}
+
+func TestFixedGeneratorErrors(t *testing.T) {
+	if _, err := NewFixedGenerator(nil, 1); err == nil {
+		t.Error("NewFixedGenerator(nil) error = nil")
+	}
+	if _, err := NewFixedGenerator(failingProvider{}, 1); err == nil {
+		t.Error("NewFixedGenerator() error = nil when generation fails")
+	}
+}
@@ -0,0 +1,161 @@
+// Package forgeronchromedp applies forgeron fingerprints to chromedp contexts.
+//
+// ApplyFingerprint overrides the user agent and client hints, emulates the screen, sends the
+// fingerprint headers and injects the spoofing script before any page script runs:
+//
+//	ctx, cancel := chromedp.NewExecAllocator(ctx, forgeronchromedp.AllocatorOptions(fingerprint)...)
+//	defer cancel()
+//	ctx, cancel = chromedp.NewContext(ctx)
+//	defer cancel()
+//	err := chromedp.Run(ctx,
+//		forgeronchromedp.ApplyFingerprint(fingerprint),
+//		chromedp.Navigate("https://example.com"),
+//	)
+package forgeronchromedp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+	"github.com/mailru/easyjson/jwriter"
+	"github.com/ta0uf19/forgeron"
+	"github.com/ta0uf19/forgeron/injector"
+)
+
+// ApplyFingerprint returns an action applying the fingerprint to the current target, it must run before navigating
+func ApplyFingerprint(fp *forgeron.Fingerprint) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		if err := InjectScript(fp).Do(ctx); err != nil {
+			return err
+		}
+		if err := UserAgentOverride(fp).Do(ctx); err != nil {
+			return fmt.Errorf("failed to override user agent: %w", err)
+		}
+		if err := DeviceMetrics(fp).Do(ctx); err != nil {
+			return fmt.Errorf("failed to emulate screen: %w", err)
+		}
+		return ExtraHeaders(fp).Do(ctx)
+	})
+}
+
+// InjectScript returns an action running the fingerprint script in every new document, before any page script
+func InjectScript(fp *forgeron.Fingerprint) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		script, err := injector.Script(fp)
+		if err != nil {
+			return err
+		}
+		if _, err := page.AddScriptToEvaluateOnNewDocument(script).Do(ctx); err != nil {
+			return fmt.Errorf("failed to inject fingerprint script: %w", err)
+		}
+		return nil
+	})
+}
+
+// ExtraHeaders returns an action sending the fingerprint headers the browser does not manage itself with every request
+func ExtraHeaders(fp *forgeron.Fingerprint) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		headers := network.Headers{}
+		for name, value := range injector.ExtraHeaders(fp) {
+			headers[name] = value
+		}
+		if len(headers) == 0 {
+			return nil
+		}
+		if err := network.Enable().Do(ctx); err != nil {
+			return fmt.Errorf("failed to enable network: %w", err)
+		}
+		if err := network.SetExtraHTTPHeaders(headers).Do(ctx); err != nil {
+			return fmt.Errorf("failed to set extra headers: %w", err)
+		}
+		return nil
+	})
+}
+
+// UserAgentOverrideParams are the parameters of Emulation.setUserAgentOverride. cdproto no longer has the deprecated
+// fullVersion of the client hints metadata, which Chromium still reports as the uaFullVersion high entropy value.
+type UserAgentOverrideParams struct {
+	UserAgent         string             `json:"userAgent"`
+	AcceptLanguage    string             `json:"acceptLanguage,omitempty"`
+	Platform          string             `json:"platform,omitempty"`
+	UserAgentMetadata *UserAgentMetadata `json:"userAgentMetadata,omitempty"`
+}
+
+// UserAgentMetadata is emulation.UserAgentMetadata with its fullVersion
+type UserAgentMetadata struct {
+	Brands          []*emulation.UserAgentBrandVersion `json:"brands,omitempty"`
+	FullVersionList []*emulation.UserAgentBrandVersion `json:"fullVersionList,omitempty"`
+	FullVersion     string                             `json:"fullVersion,omitempty"`
+	Platform        string                             `json:"platform"`
+	PlatformVersion string                             `json:"platformVersion"`
+	Architecture    string                             `json:"architecture"`
+	Model           string                             `json:"model"`
+	Mobile          bool                               `json:"mobile"`
+	Bitness         string                             `json:"bitness,omitempty"`
+}
+
+// Do sends the override to the current target
+func (p *UserAgentOverrideParams) Do(ctx context.Context) error {
+	return cdp.Execute(ctx, emulation.CommandSetUserAgentOverride, p, nil)
+}
+
+// MarshalEasyJSON encodes the parameters for cdp.Execute
+func (p *UserAgentOverrideParams) MarshalEasyJSON(w *jwriter.Writer) {
+	w.Raw(json.Marshal(p))
+}
+
+// UserAgentOverride returns the Emulation.setUserAgentOverride call matching the fingerprint,
+// including the client hints metadata of Chromium fingerprints
+func UserAgentOverride(fp *forgeron.Fingerprint) *UserAgentOverrideParams {
+	override := &UserAgentOverrideParams{
+		UserAgent:      fp.Navigator.UserAgent,
+		AcceptLanguage: strings.Join(fp.Navigator.Languages, ","),
+		Platform:       fp.Navigator.Platform,
+	}
+	data := fp.Navigator.UserAgentData
+	if data == nil {
+		return override
+	}
+	metadata := &UserAgentMetadata{
+		FullVersion:     data.UAFullVersion,
+		Platform:        data.Platform,
+		PlatformVersion: data.PlatformVersion,
+		Architecture:    data.Architecture,
+		Model:           data.Model,
+		Mobile:          data.Mobile,
+		Bitness:         data.Bitness,
+	}
+	for _, brand := range data.Brands {
+		metadata.Brands = append(metadata.Brands, &emulation.UserAgentBrandVersion{Brand: brand.Brand, Version: brand.Version})
+	}
+	for _, brand := range data.FullVersionList {
+		metadata.FullVersionList = append(metadata.FullVersionList, &emulation.UserAgentBrandVersion{Brand: brand.Brand, Version: brand.Version})
+	}
+	override.UserAgentMetadata = metadata
+	return override
+}
+
+// DeviceMetrics returns the Emulation.setDeviceMetricsOverride call matching the fingerprint screen
+func DeviceMetrics(fp *forgeron.Fingerprint) *emulation.SetDeviceMetricsOverrideParams {
+	mobile := fp.Navigator.UserAgentData != nil && fp.Navigator.UserAgentData.Mobile
+	return emulation.SetDeviceMetricsOverride(int64(fp.Screen.InnerWidth), int64(fp.Screen.InnerHeight), fp.Screen.DevicePixelRatio, mobile).
+		WithScreenWidth(int64(fp.Screen.Width)).
+		WithScreenHeight(int64(fp.Screen.Height))
+}
+
+// AllocatorOptions returns the default allocator options with the window, language and user agent of the fingerprint
+func AllocatorOptions(fp *forgeron.Fingerprint) []chromedp.ExecAllocatorOption {
+	return append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.WindowSize(fp.Screen.OuterWidth, fp.Screen.OuterHeight),
+		chromedp.Flag("lang", fp.Navigator.Language),
+		chromedp.UserAgent(fp.Navigator.UserAgent),
+		chromedp.Flag("disable-blink-features", "AutomationControlled"),
+	)
+}
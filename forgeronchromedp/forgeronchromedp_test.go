@@ -0,0 +1,48 @@
+package forgeronchromedp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mailru/easyjson"
+	"github.com/ta0uf19/forgeron"
+	"github.com/ta0uf19/forgeron/forgerontest"
+)
+
+func TestUserAgentOverride(t *testing.T) {
+	fp := forgerontest.Fingerprint()
+	override := UserAgentOverride(fp)
+	if override.UserAgent != fp.Navigator.UserAgent || override.Platform != fp.Navigator.Platform {
+		t.Errorf("UserAgentOverride() = %+v", override)
+	}
+	metadata := override.UserAgentMetadata
+	if metadata == nil || metadata.Platform != fp.Navigator.UserAgentData.Platform || len(metadata.Brands) != len(fp.Navigator.UserAgentData.Brands) {
+		t.Fatalf("UserAgentMetadata = %+v", metadata)
+	}
+	if metadata.FullVersion != fp.Navigator.UserAgentData.UAFullVersion {
+		t.Errorf("FullVersion = %q, want %q", metadata.FullVersion, fp.Navigator.UserAgentData.UAFullVersion)
+	}
+	params, err := easyjson.Marshal(override)
+	if err != nil {
+		t.Fatalf("MarshalEasyJSON() error = %v", err)
+	}
+	if want := `"fullVersion":"` + fp.Navigator.UserAgentData.UAFullVersion + `"`; !strings.Contains(string(params), want) {
+		t.Errorf("params = %s, want %s", params, want)
+	}
+
+	firefox := &forgeron.Fingerprint{Navigator: forgeron.NavigatorFingerprint{UserAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:147.0) Gecko/20100101 Firefox/147.0"}}
+	if UserAgentOverride(firefox).UserAgentMetadata != nil {
+		t.Error("UserAgentOverride() sent client hints for a fingerprint without them")
+	}
+}
+
+func TestDeviceMetrics(t *testing.T) {
+	fp := forgerontest.Fingerprint()
+	metrics := DeviceMetrics(fp)
+	if metrics.ScreenWidth != int64(fp.Screen.Width) || metrics.ScreenHeight != int64(fp.Screen.Height) || metrics.DeviceScaleFactor != fp.Screen.DevicePixelRatio {
+		t.Errorf("DeviceMetrics() = %+v", metrics)
+	}
+	if metrics.Mobile {
+		t.Error("DeviceMetrics() emulates a mobile device for a desktop fingerprint")
+	}
+}
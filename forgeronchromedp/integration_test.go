@@ -0,0 +1,55 @@
+//go:build integration
+
+package forgeronchromedp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chromedp/chromedp"
+	"github.com/ta0uf19/forgeron/forgerontest"
+	"github.com/ta0uf19/forgeron/probes"
+)
+
+func TestApplyFingerprint(t *testing.T) {
+	received := make(chan http.Header, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case received <- r.Header.Clone():
+		default:
+		}
+		fmt.Fprint(w, "<html><body>forgeron</body></html>")
+	}))
+	defer server.Close()
+
+	fp := forgerontest.Fingerprint()
+	ctx, cancel := chromedp.NewExecAllocator(context.Background(), AllocatorOptions(fp)...)
+	defer cancel()
+	ctx, cancel = chromedp.NewContext(ctx)
+	defer cancel()
+	if err := chromedp.Run(ctx, ApplyFingerprint(fp), chromedp.Navigate(server.URL)); err != nil {
+		t.Fatalf("ApplyFingerprint() error = %v", err)
+	}
+
+	headers := <-received
+	if got := headers.Get("User-Agent"); got != fp.Navigator.UserAgent {
+		t.Errorf("User-Agent = %q, want %q", got, fp.Navigator.UserAgent)
+	}
+	if got := headers.Get("Accept-Language"); got != fp.Headers["Accept-Language"] {
+		t.Errorf("Accept-Language = %q, want %q", got, fp.Headers["Accept-Language"])
+	}
+
+	results := probes.Run(fp, func(expression string) (any, error) {
+		var result any
+		if err := chromedp.Run(ctx, chromedp.Evaluate(expression, &result)); err != nil {
+			return nil, err
+		}
+		return result, nil
+	})
+	if err := probes.Failures(results); err != nil {
+		t.Error(err)
+	}
+}
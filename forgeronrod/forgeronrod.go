@@ -0,0 +1,105 @@
+// Package forgeronrod applies forgeron fingerprints to go-rod pages.
+//
+// ApplyFingerprint overrides the user agent and client hints, emulates the screen, sends the
+// fingerprint headers and injects the spoofing script before any page script runs:
+//
+//	page := browser.MustPage()
+//	if err := forgeronrod.ApplyFingerprint(page, fingerprint); err != nil {
+//		return err
+//	}
+//	page.MustNavigate("https://example.com")
+package forgeronrod
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/ta0uf19/forgeron"
+	"github.com/ta0uf19/forgeron/injector"
+	"github.com/ysmood/gson"
+)
+
+// ApplyFingerprint applies the fingerprint to the page, it must be called before navigating
+func ApplyFingerprint(page *rod.Page, fp *forgeron.Fingerprint) error {
+	script, err := injector.Script(fp)
+	if err != nil {
+		return err
+	}
+	if _, err := page.EvalOnNewDocument(script); err != nil {
+		return fmt.Errorf("failed to inject fingerprint script: %w", err)
+	}
+	if err := UserAgentOverride(fp).Call(page); err != nil {
+		return fmt.Errorf("failed to override user agent: %w", err)
+	}
+	if err := DeviceMetrics(fp).Call(page); err != nil {
+		return fmt.Errorf("failed to emulate screen: %w", err)
+	}
+
+	headers := proto.NetworkHeaders{}
+	for name, value := range injector.ExtraHeaders(fp) {
+		headers[name] = gson.New(value)
+	}
+	if len(headers) > 0 {
+		page.EnableDomain(&proto.NetworkEnable{})
+		if err := (proto.NetworkSetExtraHTTPHeaders{Headers: headers}).Call(page); err != nil {
+			return fmt.Errorf("failed to set extra headers: %w", err)
+		}
+	}
+	return nil
+}
+
+// UserAgentOverride returns the Emulation.setUserAgentOverride call matching the fingerprint,
+// including the client hints metadata of Chromium fingerprints
+func UserAgentOverride(fp *forgeron.Fingerprint) *proto.EmulationSetUserAgentOverride {
+	override := &proto.EmulationSetUserAgentOverride{
+		UserAgent:      fp.Navigator.UserAgent,
+		AcceptLanguage: strings.Join(fp.Navigator.Languages, ","),
+		Platform:       fp.Navigator.Platform,
+	}
+	data := fp.Navigator.UserAgentData
+	if data == nil {
+		return override
+	}
+	metadata := &proto.EmulationUserAgentMetadata{
+		FullVersion:     data.UAFullVersion,
+		Platform:        data.Platform,
+		PlatformVersion: data.PlatformVersion,
+		Architecture:    data.Architecture,
+		Model:           data.Model,
+		Mobile:          data.Mobile,
+		Bitness:         data.Bitness,
+	}
+	for _, brand := range data.Brands {
+		metadata.Brands = append(metadata.Brands, &proto.EmulationUserAgentBrandVersion{Brand: brand.Brand, Version: brand.Version})
+	}
+	for _, brand := range data.FullVersionList {
+		metadata.FullVersionList = append(metadata.FullVersionList, &proto.EmulationUserAgentBrandVersion{Brand: brand.Brand, Version: brand.Version})
+	}
+	override.UserAgentMetadata = metadata
+	return override
+}
+
+// DeviceMetrics returns the Emulation.setDeviceMetricsOverride call matching the fingerprint screen
+func DeviceMetrics(fp *forgeron.Fingerprint) *proto.EmulationSetDeviceMetricsOverride {
+	screenWidth, screenHeight := fp.Screen.Width, fp.Screen.Height
+	return &proto.EmulationSetDeviceMetricsOverride{
+		Width:             fp.Screen.InnerWidth,
+		Height:            fp.Screen.InnerHeight,
+		DeviceScaleFactor: fp.Screen.DevicePixelRatio,
+		Mobile:            fp.Navigator.UserAgentData != nil && fp.Navigator.UserAgentData.Mobile,
+		ScreenWidth:       &screenWidth,
+		ScreenHeight:      &screenHeight,
+	}
+}
+
+// Launcher returns a launcher whose window, language and user agent match the fingerprint
+func Launcher(fp *forgeron.Fingerprint) *launcher.Launcher {
+	return launcher.New().
+		Set("window-size", fmt.Sprintf("%d,%d", fp.Screen.OuterWidth, fp.Screen.OuterHeight)).
+		Set("lang", fp.Navigator.Language).
+		Set("user-agent", fp.Navigator.UserAgent).
+		Set("disable-blink-features", "AutomationControlled")
+}
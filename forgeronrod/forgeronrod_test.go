@@ -0,0 +1,36 @@
+package forgeronrod
+
+import (
+	"testing"
+
+	"github.com/ta0uf19/forgeron"
+	"github.com/ta0uf19/forgeron/forgerontest"
+)
+
+func TestUserAgentOverride(t *testing.T) {
+	fp := forgerontest.Fingerprint()
+	override := UserAgentOverride(fp)
+	if override.UserAgent != fp.Navigator.UserAgent || override.Platform != fp.Navigator.Platform {
+		t.Errorf("UserAgentOverride() = %+v", override)
+	}
+	metadata := override.UserAgentMetadata
+	if metadata == nil || metadata.Platform != fp.Navigator.UserAgentData.Platform || len(metadata.Brands) != len(fp.Navigator.UserAgentData.Brands) {
+		t.Fatalf("UserAgentMetadata = %+v", metadata)
+	}
+
+	firefox := &forgeron.Fingerprint{Navigator: forgeron.NavigatorFingerprint{UserAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:147.0) Gecko/20100101 Firefox/147.0"}}
+	if UserAgentOverride(firefox).UserAgentMetadata != nil {
+		t.Error("UserAgentOverride() sent client hints for a fingerprint without them")
+	}
+}
+
+func TestDeviceMetrics(t *testing.T) {
+	fp := forgerontest.Fingerprint()
+	metrics := DeviceMetrics(fp)
+	if *metrics.ScreenWidth != fp.Screen.Width || *metrics.ScreenHeight != fp.Screen.Height || metrics.DeviceScaleFactor != fp.Screen.DevicePixelRatio {
+		t.Errorf("DeviceMetrics() = %+v", metrics)
+	}
+	if metrics.Mobile {
+		t.Error("DeviceMetrics() emulates a mobile device for a desktop fingerprint")
+	}
+}
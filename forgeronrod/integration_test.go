@@ -0,0 +1,54 @@
+//go:build integration
+
+package forgeronrod
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-rod/rod"
+	"github.com/ta0uf19/forgeron/forgerontest"
+	"github.com/ta0uf19/forgeron/probes"
+)
+
+func TestApplyFingerprint(t *testing.T) {
+	received := make(chan http.Header, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case received <- r.Header.Clone():
+		default:
+		}
+		fmt.Fprint(w, "<html><body>forgeron</body></html>")
+	}))
+	defer server.Close()
+
+	fp := forgerontest.Fingerprint()
+	browser := rod.New().ControlURL(Launcher(fp).MustLaunch()).MustConnect()
+	defer browser.MustClose()
+	page := browser.MustPage()
+	if err := ApplyFingerprint(page, fp); err != nil {
+		t.Fatalf("ApplyFingerprint() error = %v", err)
+	}
+	page.MustNavigate(server.URL).MustWaitLoad()
+
+	headers := <-received
+	if got := headers.Get("User-Agent"); got != fp.Navigator.UserAgent {
+		t.Errorf("User-Agent = %q, want %q", got, fp.Navigator.UserAgent)
+	}
+	if got := headers.Get("Accept-Language"); got != fp.Headers["Accept-Language"] {
+		t.Errorf("Accept-Language = %q, want %q", got, fp.Headers["Accept-Language"])
+	}
+
+	results := probes.Run(fp, func(expression string) (any, error) {
+		result, err := page.Eval("() => (" + expression + ")")
+		if err != nil {
+			return nil, err
+		}
+		return result.Value.Val(), nil
+	})
+	if err := probes.Failures(results); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,92 @@
+package forgerontest
+
+import "github.com/ta0uf19/forgeron"
+
+// userAgent is the user agent of the default fixtures
+const userAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36"
+
+// Headers returns the headers of a Chrome 144 on Windows fixture
+func Headers() map[string]string {
+	return map[string]string{
+		"sec-ch-ua":                 `"Not(A:Brand";v="8", "Chromium";v="144", "Google Chrome";v="144"`,
+		"sec-ch-ua-mobile":          "?0",
+		"sec-ch-ua-platform":        `"Windows"`,
+		"Upgrade-Insecure-Requests": "1",
+		"User-Agent":                userAgent,
+		"Accept":                    "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.7",
+		"Accept-Encoding":           "gzip, deflate, br, zstd",
+		"Accept-Language":           "en-US,en;q=0.9",
+	}
+}
+
+// Fingerprint returns a consistent Chrome 144 on Windows fingerprint fixture
+func Fingerprint() *forgeron.Fingerprint {
+	deviceMemory := 8
+	return &forgeron.Fingerprint{
+		Screen: forgeron.ScreenFingerprint{
+			AvailHeight:      816,
+			AvailWidth:       1536,
+			ColorDepth:       24,
+			Height:           864,
+			PixelDepth:       24,
+			Width:            1536,
+			DevicePixelRatio: 1.25,
+			OuterHeight:      816,
+			OuterWidth:       1536,
+		},
+		Navigator: forgeron.NavigatorFingerprint{
+			UserAgent: userAgent,
+			UserAgentData: &forgeron.UserAgentData{
+				Brands: []forgeron.UserAgentBrand{
+					{Brand: "Not(A:Brand", Version: "8"},
+					{Brand: "Chromium", Version: "144"},
+					{Brand: "Google Chrome", Version: "144"},
+				},
+				Platform:     "Windows",
+				Architecture: "x86",
+				Bitness:      "64",
+				FullVersionList: []forgeron.UserAgentBrand{
+					{Brand: "Not(A:Brand", Version: "8.0.0.0"},
+					{Brand: "Chromium", Version: "144.0.7559.110"},
+					{Brand: "Google Chrome", Version: "144.0.7559.110"},
+				},
+				PlatformVersion: "19.0.0",
+				UAFullVersion:   "144.0.7559.110",
+			},
+			AppCodeName:         "Mozilla",
+			AppName:             "Netscape",
+			AppVersion:          userAgent[len("Mozilla/"):],
+			Webdriver:           "false",
+			Language:            "en-US",
+			Languages:           []string{"en-US", "en"},
+			Platform:            "Win32",
+			DeviceMemory:        &deviceMemory,
+			HardwareConcurrency: 8,
+			Product:             "Gecko",
+			ProductSub:          "20030107",
+			Vendor:              "Google Inc.",
+			ExtraProperties: map[string]any{
+				"pdfViewerEnabled": true,
+				"vendorFlavors":    []any{"chrome"},
+			},
+		},
+		Headers: Headers(),
+		VideoCard: &forgeron.VideoCard{
+			Renderer: "ANGLE (Intel, Intel(R) Iris(R) Xe Graphics (0x00009A49) Direct3D11 vs_5_0 ps_5_0, D3D11)",
+			Vendor:   "Google Inc. (Intel)",
+		},
+		Fonts:  []string{"Calibri", "Cambria", "Consolas", "Segoe UI"},
+		Locale: "en-US",
+		Timezone: &forgeron.Timezone{
+			Name:           "America/New_York",
+			Offset:         240,
+			StandardOffset: 300,
+			DST:            true,
+			Transitions: []forgeron.TimezoneTransition{
+				{At: 1762063200000, Offset: 300},
+				{At: 1772953200000, Offset: 240, DST: true},
+				{At: 1793512800000, Offset: 300},
+			},
+		},
+	}
+}
@@ -0,0 +1,146 @@
+// Package forgerontest provides deterministic fake generators so code built on forgeron can be unit-tested
+// without loading the real Bayesian networks.
+//
+// The fakes return fixed or scripted outputs in order and record the constraints they were called with.
+package forgerontest
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ta0uf19/forgeron"
+)
+
+// ErrScriptExhausted is returned once a fake without looping has returned all its scripted outputs
+var ErrScriptExhausted = errors.New("forgerontest: script exhausted")
+
+// FingerprintStep is a single scripted output of a FingerprintGenerator
+type FingerprintStep struct {
+	Fingerprint *forgeron.Fingerprint
+	Err         error
+}
+
+// FingerprintGenerator is a fake fingerprint generator returning scripted outputs
+type FingerprintGenerator struct {
+	mu    sync.Mutex
+	steps []FingerprintStep
+	loop  bool
+	calls int
+}
+
+// NewFingerprintGenerator returns a fake cycling through the given fingerprints, or Fingerprint() if none are given
+func NewFingerprintGenerator(fingerprints ...*forgeron.Fingerprint) *FingerprintGenerator {
+	if len(fingerprints) == 0 {
+		fingerprints = []*forgeron.Fingerprint{Fingerprint()}
+	}
+	steps := make([]FingerprintStep, len(fingerprints))
+	for i, fp := range fingerprints {
+		steps[i] = FingerprintStep{Fingerprint: fp}
+	}
+	return &FingerprintGenerator{steps: steps, loop: true}
+}
+
+// ScriptFingerprints returns a fake returning the given steps once each, then ErrScriptExhausted
+func ScriptFingerprints(steps ...FingerprintStep) *FingerprintGenerator {
+	return &FingerprintGenerator{steps: steps}
+}
+
+// Generate returns the next scripted fingerprint, the options are ignored.
+// Each call returns a copy so callers can mutate the result freely.
+func (g *FingerprintGenerator) Generate(opts ...forgeron.FingerprintOption) (*forgeron.Fingerprint, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	step, err := nextStep(g.steps, g.calls, g.loop)
+	g.calls++
+	if err != nil {
+		return nil, err
+	}
+	if step.Err != nil {
+		return nil, step.Err
+	}
+	return step.Fingerprint.Clone(), nil
+}
+
+// Calls returns the number of times Generate was called
+func (g *FingerprintGenerator) Calls() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.calls
+}
+
+// HeaderStep is a single scripted output of a HeaderGenerator
+type HeaderStep struct {
+	Headers map[string]string
+	Err     error
+}
+
+// HeaderGenerator is a fake header generator returning scripted outputs
+type HeaderGenerator struct {
+	mu          sync.Mutex
+	steps       []HeaderStep
+	loop        bool
+	constraints []forgeron.HeaderConstraints
+}
+
+// NewHeaderGenerator returns a fake cycling through the given headers, or Headers() if none are given
+func NewHeaderGenerator(headers ...map[string]string) *HeaderGenerator {
+	if len(headers) == 0 {
+		headers = []map[string]string{Headers()}
+	}
+	steps := make([]HeaderStep, len(headers))
+	for i, h := range headers {
+		steps[i] = HeaderStep{Headers: h}
+	}
+	return &HeaderGenerator{steps: steps, loop: true}
+}
+
+// ScriptHeaders returns a fake returning the given steps once each, then ErrScriptExhausted
+func ScriptHeaders(steps ...HeaderStep) *HeaderGenerator {
+	return &HeaderGenerator{steps: steps}
+}
+
+// GenerateHeaders records the constraints and returns a copy of the next scripted headers
+func (g *HeaderGenerator) GenerateHeaders(options forgeron.HeaderConstraints) (map[string]string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	step, err := nextStep(g.steps, len(g.constraints), g.loop)
+	g.constraints = append(g.constraints, options)
+	if err != nil {
+		return nil, err
+	}
+	if step.Err != nil {
+		return nil, step.Err
+	}
+	headers := make(map[string]string, len(step.Headers))
+	for k, v := range step.Headers {
+		headers[k] = v
+	}
+	return headers, nil
+}
+
+// Constraints returns the constraints of every GenerateHeaders call, in order
+func (g *HeaderGenerator) Constraints() []forgeron.HeaderConstraints {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]forgeron.HeaderConstraints(nil), g.constraints...)
+}
+
+// nextStep returns the step of the given call
+func nextStep[T any](steps []T, call int, loop bool) (T, error) {
+	var zero T
+	if len(steps) == 0 {
+		return zero, ErrScriptExhausted
+	}
+	if loop {
+		return steps[call%len(steps)], nil
+	}
+	if call >= len(steps) {
+		return zero, ErrScriptExhausted
+	}
+	return steps[call], nil
+}
+
+var (
+	_ forgeron.HeaderProvider      = (*HeaderGenerator)(nil)
+	_ forgeron.FingerprintProvider = (*FingerprintGenerator)(nil)
+)
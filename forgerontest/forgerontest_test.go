@@ -0,0 +1,84 @@
+package forgerontest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ta0uf19/forgeron"
+)
+
+func TestFingerprintFixtureIsConsistent(t *testing.T) {
+	if _, err := Fingerprint().View().WithHeaders(Headers()); err != nil {
+		t.Fatalf("fixture is not consistent: %v", err)
+	}
+}
+
+func TestFingerprintGeneratorCycles(t *testing.T) {
+	a, b := Fingerprint(), Fingerprint()
+	b.Navigator.Language = "fr-FR"
+	g := NewFingerprintGenerator(a, b)
+
+	for i, want := range []string{"en-US", "fr-FR", "en-US"} {
+		fp, err := g.Generate()
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if fp.Navigator.Language != want {
+			t.Errorf("call %d: Language = %q, want %q", i, fp.Navigator.Language, want)
+		}
+	}
+	if g.Calls() != 3 {
+		t.Errorf("Calls() = %d, want 3", g.Calls())
+	}
+}
+
+func TestScriptFingerprints(t *testing.T) {
+	errBlocked := errors.New("blocked")
+	g := ScriptFingerprints(FingerprintStep{Fingerprint: Fingerprint()}, FingerprintStep{Err: errBlocked})
+
+	if _, err := g.Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if _, err := g.Generate(); !errors.Is(err, errBlocked) {
+		t.Errorf("Generate() error = %v, want %v", err, errBlocked)
+	}
+	if _, err := g.Generate(); !errors.Is(err, ErrScriptExhausted) {
+		t.Errorf("Generate() error = %v, want %v", err, ErrScriptExhausted)
+	}
+}
+
+func TestHeaderGeneratorRecordsConstraints(t *testing.T) {
+	g := NewHeaderGenerator()
+	headers, err := g.GenerateHeaders(forgeron.HeaderConstraints{Browsers: []forgeron.Browser{"chrome"}})
+	if err != nil {
+		t.Fatalf("GenerateHeaders() error = %v", err)
+	}
+	headers["User-Agent"] = "mutated"
+	if again, _ := g.GenerateHeaders(forgeron.HeaderConstraints{}); again["User-Agent"] == "mutated" {
+		t.Error("GenerateHeaders() returned a shared map")
+	}
+	constraints := g.Constraints()
+	if len(constraints) != 2 || constraints[0].Browsers[0] != "chrome" {
+		t.Errorf("unexpected recorded constraints: %+v", constraints)
+	}
+}
+
+func TestFakeDrivesScheduler(t *testing.T) {
+	s, err := forgeron.NewScheduler(NewFingerprintGenerator(), forgeron.SchedulerConfig{
+		DomainConcurrency: 1,
+		IdentityBudget:    1,
+		MaxIdentities:     1,
+	})
+	if err != nil {
+		t.Fatalf("NewScheduler() error = %v", err)
+	}
+	lease, err := s.Acquire(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	defer lease.Release()
+	if lease.Fingerprint.Navigator.UserAgent != Fingerprint().Navigator.UserAgent {
+		t.Errorf("UserAgent = %q, want the fixture user agent", lease.Fingerprint.Navigator.UserAgent)
+	}
+}
@@ -0,0 +1,192 @@
+// Package forgeronutls builds uTLS ClientHellos matching forgeron fingerprints, so the TLS handshake
+// agrees with the browser claimed by the generated User-Agent.
+//
+//	fp, _ := gen.Generate()
+//	conn, _ := net.Dial("tcp", "example.com:443")
+//	tlsConn, err := forgeronutls.Client(conn, fp, &utls.Config{ServerName: "example.com"})
+package forgeronutls
+
+import (
+	"fmt"
+	"net"
+	"slices"
+
+	utls "github.com/refraction-networking/utls"
+	"github.com/ta0uf19/forgeron"
+)
+
+// Code points that have no uTLS constant
+const (
+	x25519MLKEM768                  = 4588
+	extensionApplicationSettingsNew = 17613
+	recordSizeLimit                 = 0x4001
+)
+
+// keyShareGroups are the groups uTLS can generate key shares for
+var keyShareGroups = []utls.CurveID{utls.X25519Kyber768Draft00, utls.X25519, utls.CurveP256, utls.CurveP384, utls.CurveP521}
+
+// ClientHelloID returns the uTLS preset closest to the browser of the fingerprint, for callers not needing an exact spec
+func ClientHelloID(fp *forgeron.Fingerprint) utls.ClientHelloID {
+	switch fp.TLSFingerprint().ClientHelloID {
+	case "HelloFirefox_Auto":
+		return utls.HelloFirefox_Auto
+	case "HelloSafari_Auto":
+		return utls.HelloSafari_Auto
+	case "HelloIOS_Auto":
+		return utls.HelloIOS_Auto
+	default:
+		return utls.HelloChrome_Auto
+	}
+}
+
+// ClientHelloSpec returns the uTLS ClientHelloSpec of the browser of the fingerprint.
+// uTLS cannot generate X25519MLKEM768 key shares yet, so that group is left out rather than failing handshakes
+// with servers asking for it.
+func ClientHelloSpec(fp *forgeron.Fingerprint) (*utls.ClientHelloSpec, error) {
+	if fp == nil {
+		return nil, fmt.Errorf("fingerprint is required")
+	}
+	tls := fp.TLSFingerprint()
+
+	var ciphers []uint16
+	if tls.GREASE {
+		ciphers = append(ciphers, utls.GREASE_PLACEHOLDER)
+	}
+	ciphers = append(ciphers, tls.CipherSuites...)
+
+	var groups []utls.CurveID
+	if tls.GREASE {
+		groups = append(groups, utls.CurveID(utls.GREASE_PLACEHOLDER))
+	}
+	for _, group := range tls.SupportedGroups {
+		if group != x25519MLKEM768 {
+			groups = append(groups, utls.CurveID(group))
+		}
+	}
+
+	var extensions []utls.TLSExtension
+	for _, id := range tls.Extensions {
+		extension, err := newExtension(id, tls, groups)
+		if err != nil {
+			return nil, err
+		}
+		extensions = append(extensions, extension)
+	}
+	if tls.PermuteExtensions {
+		extensions = utls.ShuffleChromeTLSExtensions(extensions)
+	}
+	if tls.GREASE {
+		// Boring based browsers send a GREASE extension first and another one last, before the padding
+		extensions = append([]utls.TLSExtension{&utls.UtlsGREASEExtension{}}, extensions...)
+		last := len(extensions)
+		if _, ok := extensions[last-1].(*utls.UtlsPaddingExtension); ok {
+			last--
+		}
+		extensions = slices.Insert(extensions, last, utls.TLSExtension(&utls.UtlsGREASEExtension{}))
+	}
+
+	return &utls.ClientHelloSpec{
+		CipherSuites:       ciphers,
+		CompressionMethods: []uint8{0},
+		Extensions:         extensions,
+		TLSVersMin:         slices.Min(tls.Versions),
+		TLSVersMax:         slices.Max(tls.Versions),
+	}, nil
+}
+
+// newExtension returns the uTLS extension of the code point
+func newExtension(id uint16, tls forgeron.TLSFingerprint, groups []utls.CurveID) (utls.TLSExtension, error) {
+	switch id {
+	case 0:
+		return &utls.SNIExtension{}, nil
+	case 5:
+		return &utls.StatusRequestExtension{}, nil
+	case 10:
+		return &utls.SupportedCurvesExtension{Curves: groups}, nil
+	case 11:
+		return &utls.SupportedPointsExtension{SupportedPoints: tls.PointFormats}, nil
+	case 13:
+		return &utls.SignatureAlgorithmsExtension{SupportedSignatureAlgorithms: signatureSchemes(tls.SignatureAlgorithms)}, nil
+	case 16:
+		return &utls.ALPNExtension{AlpnProtocols: tls.ALPN}, nil
+	case 18:
+		return &utls.SCTExtension{}, nil
+	case 21:
+		return &utls.UtlsPaddingExtension{GetPaddingLen: utls.BoringPaddingStyle}, nil
+	case 23:
+		return &utls.ExtendedMasterSecretExtension{}, nil
+	case 27:
+		algorithms := make([]utls.CertCompressionAlgo, len(tls.CertCompression))
+		for i, algorithm := range tls.CertCompression {
+			algorithms[i] = utls.CertCompressionAlgo(algorithm)
+		}
+		return &utls.UtlsCompressCertExtension{Algorithms: algorithms}, nil
+	case 28:
+		return &utls.FakeRecordSizeLimitExtension{Limit: recordSizeLimit}, nil
+	case 34:
+		return &utls.FakeDelegatedCredentialsExtension{SupportedSignatureAlgorithms: signatureSchemes(tls.SignatureAlgorithms[:4])}, nil
+	case 35:
+		return &utls.SessionTicketExtension{}, nil
+	case 43:
+		var versions []uint16
+		if tls.GREASE {
+			versions = append(versions, utls.GREASE_PLACEHOLDER)
+		}
+		return &utls.SupportedVersionsExtension{Versions: append(versions, tls.Versions...)}, nil
+	case 45:
+		return &utls.PSKKeyExchangeModesExtension{Modes: []uint8{utls.PskModeDHE}}, nil
+	case 51:
+		return &utls.KeyShareExtension{KeyShares: keyShares(groups, tls.GREASE)}, nil
+	case 17513:
+		return &utls.ApplicationSettingsExtension{SupportedProtocols: []string{"h2"}}, nil
+	case extensionApplicationSettingsNew:
+		// ALPS with the new code point has the same body: a list of ALPN protocols
+		return &utls.GenericExtension{Id: id, Data: []byte{0x00, 0x03, 0x02, 'h', '2'}}, nil
+	case 65037:
+		return utls.BoringGREASEECH(), nil
+	case 65281:
+		return &utls.RenegotiationInfoExtension{Renegotiation: utls.RenegotiateOnceAsClient}, nil
+	}
+	return nil, fmt.Errorf("unsupported TLS extension %d", id)
+}
+
+// keyShares returns the key shares sent by the browser: a GREASE share and one for the first real groups
+func keyShares(groups []utls.CurveID, grease bool) []utls.KeyShare {
+	var shares []utls.KeyShare
+	if grease {
+		shares = append(shares, utls.KeyShare{Group: utls.CurveID(utls.GREASE_PLACEHOLDER), Data: []byte{0}})
+	}
+	for _, group := range groups {
+		if !slices.Contains(keyShareGroups, group) {
+			continue
+		}
+		shares = append(shares, utls.KeyShare{Group: group})
+		// Hybrid groups come with a classic X25519 share for servers not supporting them
+		if group != utls.X25519Kyber768Draft00 {
+			break
+		}
+	}
+	return shares
+}
+
+// signatureSchemes converts signature algorithm code points
+func signatureSchemes(algorithms []uint16) []utls.SignatureScheme {
+	schemes := make([]utls.SignatureScheme, len(algorithms))
+	for i, algorithm := range algorithms {
+		schemes[i] = utls.SignatureScheme(algorithm)
+	}
+	return schemes
+}
+
+// Client returns a uTLS client connection presenting the ClientHello of the browser of the fingerprint
+func Client(conn net.Conn, fp *forgeron.Fingerprint, config *utls.Config) (*utls.UConn, error) {
+	spec, err := ClientHelloSpec(fp)
+	if err != nil {
+		return nil, err
+	}
+	uconn := utls.UClient(conn, config, utls.HelloCustom)
+	if err := uconn.ApplyPreset(spec); err != nil {
+		return nil, fmt.Errorf("failed to apply ClientHello: %w", err)
+	}
+	return uconn, nil
+}
@@ -0,0 +1,130 @@
+package forgeronutls
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	utls "github.com/refraction-networking/utls"
+	"github.com/ta0uf19/forgeron"
+)
+
+// isGREASE reports whether the value is a GREASE value (RFC 8701)
+func isGREASE(value uint16) bool {
+	return value&0x0f0f == 0x0a0a && value>>8 == value&0xff
+}
+
+// parseClientHello returns the cipher suites and extension types of a ClientHello handshake message, GREASE values excluded
+func parseClientHello(t *testing.T, hello []byte) (ciphers, extensions []uint16) {
+	t.Helper()
+	u16 := func(b []byte) int { return int(b[0])<<8 | int(b[1]) }
+	// type, length, version and random
+	b := hello[4+2+32:]
+	b = b[1+int(b[0]):]
+	cipherBytes := b[2 : 2+u16(b)]
+	for i := 0; i < len(cipherBytes); i += 2 {
+		if cipher := uint16(u16(cipherBytes[i:])); !isGREASE(cipher) {
+			ciphers = append(ciphers, cipher)
+		}
+	}
+	b = b[2+len(cipherBytes):]
+	b = b[1+int(b[0]):]
+	b = b[2:]
+	for len(b) >= 4 {
+		if extension := uint16(u16(b)); !isGREASE(extension) {
+			extensions = append(extensions, extension)
+		}
+		b = b[4+u16(b[2:]):]
+	}
+	if len(b) != 0 {
+		t.Fatalf("trailing %d bytes in ClientHello", len(b))
+	}
+	return ciphers, extensions
+}
+
+func TestClientHelloSpecMatchesTLSFingerprint(t *testing.T) {
+	userAgents := map[string]string{
+		"chrome 125": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/125.0.0.0 Safari/537.36",
+		"chrome 144": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36",
+		"firefox":    "Mozilla/5.0 (X11; Linux x86_64; rv:147.0) Gecko/20100101 Firefox/147.0",
+		"safari":     "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/26.2 Safari/605.1.15",
+		"ios":        "Mozilla/5.0 (iPhone; CPU iPhone OS 18_7 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/26.2 Mobile/15E148 Safari/604.1",
+	}
+	for name, userAgent := range userAgents {
+		t.Run(name, func(t *testing.T) {
+			fp := &forgeron.Fingerprint{Navigator: forgeron.NavigatorFingerprint{UserAgent: userAgent}}
+			want := fp.TLSFingerprint()
+
+			client, server := net.Pipe()
+			defer client.Close()
+			defer server.Close()
+			uconn, err := Client(client, fp, &utls.Config{ServerName: "example.com"})
+			if err != nil {
+				t.Fatalf("Client() error = %v", err)
+			}
+			if err := uconn.BuildHandshakeState(); err != nil {
+				t.Fatalf("BuildHandshakeState() error = %v", err)
+			}
+
+			// Parse the ClientHello back and compare it with the fingerprint
+			ciphers, extensions := parseClientHello(t, uconn.HandshakeState.Hello.Raw)
+			if !slices.Equal(ciphers, want.CipherSuites) {
+				t.Errorf("cipher suites = %v, want %v", ciphers, want.CipherSuites)
+			}
+			slices.Sort(extensions)
+			wantExtensions := slices.Sorted(slices.Values(want.Extensions))
+			if !slices.Equal(extensions, wantExtensions) {
+				t.Errorf("extensions = %v, want %v", extensions, wantExtensions)
+			}
+			if uconn.HandshakeState.Hello.ServerName != "example.com" {
+				t.Errorf("ServerName = %q", uconn.HandshakeState.Hello.ServerName)
+			}
+		})
+	}
+}
+
+func TestClientHelloID(t *testing.T) {
+	fp := &forgeron.Fingerprint{Navigator: forgeron.NavigatorFingerprint{
+		UserAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:147.0) Gecko/20100101 Firefox/147.0",
+	}}
+	if id := ClientHelloID(fp); id != utls.HelloFirefox_Auto {
+		t.Errorf("ClientHelloID() = %v, want HelloFirefox_Auto", id)
+	}
+}
+
+func TestClientHelloSpecRequiresFingerprint(t *testing.T) {
+	if _, err := ClientHelloSpec(nil); err == nil {
+		t.Error("ClientHelloSpec(nil) error = nil, want an error")
+	}
+}
+
+func TestClientHandshake(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	for _, userAgent := range []string{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36",
+		"Mozilla/5.0 (X11; Linux x86_64; rv:147.0) Gecko/20100101 Firefox/147.0",
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/26.2 Safari/605.1.15",
+	} {
+		fp := &forgeron.Fingerprint{Navigator: forgeron.NavigatorFingerprint{UserAgent: userAgent}}
+		conn, err := net.Dial("tcp", server.Listener.Addr().String())
+		if err != nil {
+			t.Fatalf("Dial() error = %v", err)
+		}
+		uconn, err := Client(conn, fp, &utls.Config{ServerName: "example.com", InsecureSkipVerify: true})
+		if err != nil {
+			t.Fatalf("Client() error = %v", err)
+		}
+		if err := uconn.Handshake(); err != nil {
+			t.Errorf("Handshake() for %s error = %v", userAgent, err)
+		} else if protocol := uconn.ConnectionState().NegotiatedProtocol; protocol != "h2" {
+			t.Errorf("negotiated protocol = %q, want h2", protocol)
+		}
+		uconn.Close()
+	}
+}
@@ -0,0 +1,21 @@
+module github.com/ta0uf19/forgeron/forgeronutls
+
+go 1.23.4
+
+require (
+	github.com/refraction-networking/utls v1.6.7
+	github.com/ta0uf19/forgeron v0.0.0
+)
+
+require (
+	github.com/andybalholm/brotli v1.0.6 // indirect
+	github.com/cloudflare/circl v1.3.7 // indirect
+	github.com/fsnotify/fsnotify v1.10.1 // indirect
+	github.com/klauspost/compress v1.17.4 // indirect
+	golang.org/x/crypto v0.21.0 // indirect
+	golang.org/x/sys v0.18.0 // indirect
+	golang.org/x/text v0.24.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/ta0uf19/forgeron => ../
@@ -0,0 +1,283 @@
+// Package forgeronv2 is the next layout of the generation API: requests group the constraints by concern instead of
+// one flat HeaderConstraints, and fingerprints separate the browser and the device from the environment and the
+// stealth settings added with realism levels, time zones and geolocation. Every type converts to and from the
+// current ones, so programs can migrate one call site at a time:
+//
+//	req := forgeronv2.FromGenerateRequest(forgeron.NewGenerateRequest(opts...))
+//	req.Identity.Realism = forgeron.RealismHigh
+//	fp, err := forgeronv2.Generate(gen, req)
+//	legacy := fp.V1()
+//
+// Conversions copy the fields, not the values behind them: converted fingerprints share their maps, slices and
+// pointers with the original.
+package forgeronv2
+
+import (
+	"github.com/ta0uf19/forgeron"
+)
+
+// Request is a fingerprint generation request, the grouped form of forgeron.GenerateRequest
+type Request struct {
+	Browser  BrowserConstraints `json:"browser"`
+	Locale   LocaleConstraints  `json:"locale"`
+	HTTP     HTTPConstraints    `json:"http"`
+	Screen   *forgeron.Screen   `json:"screen,omitempty"`
+	Identity IdentityOptions    `json:"identity"`
+	// Strict fails the generation instead of relaxing constraints the data cannot satisfy
+	Strict bool `json:"strict,omitempty"`
+}
+
+// BrowserConstraints select the browser, its OS and its device
+type BrowserConstraints struct {
+	Browsers []forgeron.Browser      `json:"browsers,omitempty"`
+	Specs    []*forgeron.BrowserSpec `json:"specs,omitempty"`
+	OS       []forgeron.OS           `json:"os,omitempty"`
+	Devices  []forgeron.Device       `json:"devices,omitempty"`
+}
+
+// LocaleConstraints select the locales, from Locales or else from Language and Region
+type LocaleConstraints struct {
+	Locales  []string `json:"locales,omitempty"`
+	Language string   `json:"language,omitempty"`
+	Region   string   `json:"region,omitempty"`
+	// Expand expands every locale into the chain a browser sends, e.g. [fr] into [fr-FR fr en-US en]
+	Expand bool `json:"expand,omitempty"`
+}
+
+// HTTPConstraints shape the headers of the requests
+type HTTPConstraints struct {
+	Version        forgeron.HTTPVersion    `json:"version,omitempty"`
+	Accept         string                  `json:"accept,omitempty"`
+	RequestContext forgeron.RequestContext `json:"requestContext,omitempty"`
+	HeaderPolicy   *forgeron.HeaderPolicy  `json:"headerPolicy,omitempty"`
+	URL            string                  `json:"url,omitempty"`
+	Referrer       string                  `json:"referrer,omitempty"`
+	ReferrerPolicy forgeron.ReferrerPolicy `json:"referrerPolicy,omitempty"`
+	SecFetchSite   forgeron.FetchSite      `json:"secFetchSite,omitempty"`
+	SecFetchUser   forgeron.FetchUser      `json:"secFetchUser,omitempty"`
+}
+
+// IdentityOptions decorate the generated identity
+type IdentityOptions struct {
+	Realism     forgeron.Realism          `json:"realism,omitempty"`
+	Geo         string                    `json:"geo,omitempty"`
+	InApp       forgeron.InAppBrowser     `json:"inApp,omitempty"`
+	LinuxFlavor *forgeron.LinuxFlavor     `json:"linuxFlavor,omitempty"`
+	Embedded    *forgeron.EmbeddedOptions `json:"embedded,omitempty"`
+	MockWebRTC  bool                      `json:"mockWebRTC,omitempty"`
+	Slim        bool                      `json:"slim,omitempty"`
+	Privacy     *forgeron.PrivacySignals  `json:"privacy,omitempty"`
+}
+
+// FromHeaderConstraints converts header constraints to a request
+func FromHeaderConstraints(constraints forgeron.HeaderConstraints) Request {
+	return Request{
+		Browser: BrowserConstraints{
+			Browsers: constraints.Browsers,
+			Specs:    constraints.BrowserSpecs,
+			OS:       constraints.OS,
+			Devices:  constraints.Devices,
+		},
+		Locale: LocaleConstraints{
+			Locales:  constraints.Locales,
+			Language: constraints.Language,
+			Region:   constraints.Region,
+			Expand:   constraints.ExpandLocales,
+		},
+		HTTP: HTTPConstraints{
+			Version:        constraints.HTTPVersion,
+			Accept:         constraints.Accept,
+			RequestContext: constraints.RequestContext,
+			HeaderPolicy:   constraints.HeaderPolicy,
+			URL:            constraints.URL,
+			Referrer:       constraints.Referrer,
+			ReferrerPolicy: constraints.ReferrerPolicy,
+			SecFetchSite:   constraints.SecFetchSite,
+			SecFetchUser:   constraints.SecFetchUser,
+		},
+		Strict: constraints.Strict,
+	}
+}
+
+// HeaderConstraints returns the header constraints of the request
+func (r Request) HeaderConstraints() forgeron.HeaderConstraints {
+	return forgeron.HeaderConstraints{
+		BrowserSpecs:   r.Browser.Specs,
+		Browsers:       r.Browser.Browsers,
+		OS:             r.Browser.OS,
+		Devices:        r.Browser.Devices,
+		Locales:        r.Locale.Locales,
+		Language:       r.Locale.Language,
+		Region:         r.Locale.Region,
+		HTTPVersion:    r.HTTP.Version,
+		Strict:         r.Strict,
+		HeaderPolicy:   r.HTTP.HeaderPolicy,
+		Accept:         r.HTTP.Accept,
+		RequestContext: r.HTTP.RequestContext,
+		ExpandLocales:  r.Locale.Expand,
+		URL:            r.HTTP.URL,
+		Referrer:       r.HTTP.Referrer,
+		ReferrerPolicy: r.HTTP.ReferrerPolicy,
+		SecFetchSite:   r.HTTP.SecFetchSite,
+		SecFetchUser:   r.HTTP.SecFetchUser,
+	}
+}
+
+// FromGenerateRequest converts a generation request to a request
+func FromGenerateRequest(request forgeron.GenerateRequest) Request {
+	r := FromHeaderConstraints(request.Constraints.HeaderConstraints)
+	r.Screen = request.Constraints.Screen
+	r.Strict = r.Strict || request.Strict
+	r.Identity = IdentityOptions{
+		Realism:     request.Realism,
+		Geo:         request.Geo,
+		InApp:       request.InApp,
+		LinuxFlavor: request.LinuxFlavor,
+		Embedded:    request.Embedded,
+		MockWebRTC:  request.MockWebRTC,
+		Slim:        request.Slim,
+		Privacy:     request.Privacy,
+	}
+	return r
+}
+
+// GenerateRequest returns the generation request of the request
+func (r Request) GenerateRequest() forgeron.GenerateRequest {
+	return forgeron.GenerateRequest{
+		Constraints: forgeron.NewConstraints(r.HeaderConstraints(), r.Screen),
+		Strict:      r.Strict,
+		MockWebRTC:  r.Identity.MockWebRTC,
+		Slim:        r.Identity.Slim,
+		LinuxFlavor: r.Identity.LinuxFlavor,
+		Embedded:    r.Identity.Embedded,
+		InApp:       r.Identity.InApp,
+		Realism:     r.Identity.Realism,
+		Geo:         r.Identity.Geo,
+		Privacy:     r.Identity.Privacy,
+	}
+}
+
+// Options returns the fingerprint options described by the request
+func (r Request) Options() []forgeron.FingerprintOption {
+	return r.GenerateRequest().Options()
+}
+
+// Fingerprint is a generated identity, the grouped form of forgeron.Fingerprint
+type Fingerprint struct {
+	Browser     Browser     `json:"browser"`
+	Device      Device      `json:"device"`
+	Environment Environment `json:"environment"`
+	Stealth     Stealth     `json:"stealth"`
+	// Slim reports whether the fingerprint was generated in slim mode
+	Slim bool `json:"slim"`
+}
+
+// Browser is what the browser reports about itself
+type Browser struct {
+	Navigator forgeron.NavigatorFingerprint `json:"navigator"`
+	Headers   map[string]string             `json:"headers"`
+	// Locale is the locale Intl formats with when none is given
+	Locale      string                    `json:"locale,omitempty"`
+	Supports    map[forgeron.Feature]bool `json:"supports,omitempty"`
+	VideoCodecs map[string]string         `json:"videoCodecs"`
+	AudioCodecs map[string]string         `json:"audioCodecs"`
+	PluginsData forgeron.PluginsData      `json:"pluginsData"`
+}
+
+// Device is the hardware the browser runs on
+type Device struct {
+	Screen            forgeron.ScreenFingerprint  `json:"screen"`
+	VideoCard         *forgeron.VideoCard         `json:"videoCard"`
+	Battery           *forgeron.Battery           `json:"battery,omitempty"`
+	MultimediaDevices *forgeron.MultimediaDevices `json:"multimediaDevices"`
+	Fonts             []string                    `json:"fonts"`
+}
+
+// Environment is where the identity is located
+type Environment struct {
+	Timezone    *forgeron.Timezone    `json:"timezone,omitempty"`
+	Geolocation *forgeron.Geolocation `json:"geolocation,omitempty"`
+}
+
+// Stealth holds the settings hiding the automation, set by the realism level and WithMockWebRTC
+type Stealth struct {
+	Transport    *forgeron.TransportProfile `json:"transport,omitempty"`
+	Noise        *forgeron.Noise            `json:"noise,omitempty"`
+	TimingJitter *forgeron.TimingJitter     `json:"timingJitter,omitempty"`
+	MockWebRTC   bool                       `json:"mockWebRTC"`
+}
+
+// FromFingerprint converts a fingerprint, nil giving nil
+func FromFingerprint(fp *forgeron.Fingerprint) *Fingerprint {
+	if fp == nil {
+		return nil
+	}
+	return &Fingerprint{
+		Browser: Browser{
+			Navigator:   fp.Navigator,
+			Headers:     fp.Headers,
+			Locale:      fp.Locale,
+			Supports:    fp.Supports,
+			VideoCodecs: fp.VideoCodecs,
+			AudioCodecs: fp.AudioCodecs,
+			PluginsData: fp.PluginsData,
+		},
+		Device: Device{
+			Screen:            fp.Screen,
+			VideoCard:         fp.VideoCard,
+			Battery:           fp.Battery,
+			MultimediaDevices: fp.MultimediaDevices,
+			Fonts:             fp.Fonts,
+		},
+		Environment: Environment{
+			Timezone:    fp.Timezone,
+			Geolocation: fp.Geolocation,
+		},
+		Stealth: Stealth{
+			Transport:    fp.Transport,
+			Noise:        fp.Noise,
+			TimingJitter: fp.TimingJitter,
+			MockWebRTC:   fp.MockWebRTC,
+		},
+		Slim: fp.Slim,
+	}
+}
+
+// V1 converts the fingerprint back, for the functions taking a forgeron.Fingerprint such as the injector, nil
+// giving nil
+func (f *Fingerprint) V1() *forgeron.Fingerprint {
+	if f == nil {
+		return nil
+	}
+	return &forgeron.Fingerprint{
+		Screen:            f.Device.Screen,
+		Navigator:         f.Browser.Navigator,
+		Headers:           f.Browser.Headers,
+		VideoCodecs:       f.Browser.VideoCodecs,
+		AudioCodecs:       f.Browser.AudioCodecs,
+		PluginsData:       f.Browser.PluginsData,
+		Battery:           f.Device.Battery,
+		VideoCard:         f.Device.VideoCard,
+		MultimediaDevices: f.Device.MultimediaDevices,
+		Fonts:             f.Device.Fonts,
+		MockWebRTC:        f.Stealth.MockWebRTC,
+		Slim:              f.Slim,
+		Supports:          f.Browser.Supports,
+		Locale:            f.Browser.Locale,
+		Timezone:          f.Environment.Timezone,
+		Geolocation:       f.Environment.Geolocation,
+		Transport:         f.Stealth.Transport,
+		Noise:             f.Stealth.Noise,
+		TimingJitter:      f.Stealth.TimingJitter,
+	}
+}
+
+// Generate generates a fingerprint for the request with a current provider, such as a forgeron.FingerprintGenerator
+// or a forgeron.Pool. The options are applied after the ones of the request.
+func Generate(provider forgeron.FingerprintProvider, r Request, opts ...forgeron.FingerprintOption) (*Fingerprint, error) {
+	fp, err := provider.Generate(append(r.Options(), opts...)...)
+	if err != nil {
+		return nil, err
+	}
+	return FromFingerprint(fp), nil
+}
@@ -0,0 +1,100 @@
+package forgeronv2
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ta0uf19/forgeron"
+	"github.com/ta0uf19/forgeron/forgerontest"
+)
+
+func TestFieldsCovered(t *testing.T) {
+	// A field added to a current type must be added to its grouped form and to the conversions
+	tests := []struct {
+		value any
+		want  int
+	}{
+		{forgeron.HeaderConstraints{}, 18},
+		{forgeron.GenerateRequest{}, 10},
+		{forgeron.Fingerprint{}, 19},
+	}
+	for _, tt := range tests {
+		typ := reflect.TypeOf(tt.value)
+		if got := typ.NumField(); got != tt.want {
+			t.Errorf("%s has %d fields, the conversions cover %d", typ, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateRequestRoundTrip(t *testing.T) {
+	minWidth := 1280
+	request := forgeron.NewGenerateRequest(
+		forgeron.WithHeaderConstraints(forgeron.HeaderConstraints{
+			BrowserSpecs:   []*forgeron.BrowserSpec{{Name: forgeron.Chrome, MinVersion: 120}},
+			Browsers:       []forgeron.Browser{forgeron.Chrome},
+			OS:             []forgeron.OS{forgeron.Windows},
+			Devices:        []forgeron.Device{forgeron.Desktop},
+			Locales:        []string{"de-DE"},
+			Language:       "de",
+			Region:         "DE",
+			HTTPVersion:    forgeron.HTTP2,
+			HeaderPolicy:   &forgeron.HeaderPolicy{Deny: []string{"dnt"}},
+			Accept:         "text/html",
+			RequestContext: forgeron.ThirdPartySubresource,
+			ExpandLocales:  true,
+			URL:            "https://api.example.com/v1",
+			Referrer:       "https://example.com/",
+			ReferrerPolicy: forgeron.OriginReferrer,
+			SecFetchSite:   forgeron.FetchSiteSameSite,
+			SecFetchUser:   forgeron.FetchUserAbsent,
+		}),
+		forgeron.WithScreen(&forgeron.Screen{MinWidth: &minWidth}),
+		forgeron.WithStrict(true),
+		forgeron.WithMockWebRTC(true),
+		forgeron.WithSlim(true),
+		forgeron.WithLinuxFlavor(forgeron.LinuxFlavor{Distro: "ubuntu"}),
+		forgeron.WithEmbedded(forgeron.EmbeddedOptions{AppName: "Slack"}),
+		forgeron.WithInAppBrowser(forgeron.Instagram),
+		forgeron.WithRealism(forgeron.RealismHigh),
+		forgeron.WithGeo("DE"),
+		forgeron.WithPrivacySignals(forgeron.PrivacySignals{DoNotTrack: true, GlobalPrivacyControl: true}),
+	)
+	// The grouped request has a single strict flag, set on both fields on the way back
+	request.Constraints.Strict = true
+
+	got := FromGenerateRequest(request).GenerateRequest()
+	if !reflect.DeepEqual(got, request) {
+		t.Errorf("round trip = %+v, want %+v", got, request)
+	}
+}
+
+func TestFingerprintRoundTrip(t *testing.T) {
+	fp := forgerontest.Fingerprint()
+	fp.Geolocation = &forgeron.Geolocation{Latitude: 48.85, Longitude: 2.35, Accuracy: 50}
+	fp.Noise = &forgeron.Noise{Canvas: 1, Audio: 2}
+	fp.TimingJitter = &forgeron.TimingJitter{Resolution: 0.1}
+	fp.Transport = &forgeron.TransportProfile{}
+	fp.MockWebRTC = true
+
+	v2 := FromFingerprint(fp)
+	if v2.Browser.Navigator.UserAgent != fp.Navigator.UserAgent || v2.Environment.Geolocation != fp.Geolocation || !v2.Stealth.MockWebRTC {
+		t.Errorf("FromFingerprint() = %+v, fields not carried over", v2)
+	}
+	if got := v2.V1(); !reflect.DeepEqual(got, fp) {
+		t.Errorf("round trip = %+v, want %+v", got, fp)
+	}
+	if FromFingerprint(nil) != nil || (*Fingerprint)(nil).V1() != nil {
+		t.Error("nil fingerprints should convert to nil")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	fake := forgerontest.NewFingerprintGenerator()
+	fp, err := Generate(fake, Request{Browser: BrowserConstraints{Browsers: []forgeron.Browser{forgeron.Chrome}}})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if want := forgerontest.Fingerprint(); fp.Browser.Navigator.UserAgent != want.Navigator.UserAgent {
+		t.Errorf("UserAgent = %q, want %q", fp.Browser.Navigator.UserAgent, want.Navigator.UserAgent)
+	}
+}
@@ -0,0 +1,105 @@
+package forgeron
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+//go:embed timezone_cities.json
+var timezoneCitiesJSON []byte
+
+// timezoneCity is a city of a time zone with the share of the population of the zone living in it
+type timezoneCity struct {
+	City      string  `json:"city"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Weight    float64 `json:"weight"`
+}
+
+// timezoneCities maps the time zones of regionTimezones to their main cities
+var timezoneCities = sync.OnceValue(func() map[string][]timezoneCity {
+	var cities map[string][]timezoneCity
+	if err := json.Unmarshal(timezoneCitiesJSON, &cities); err != nil {
+		panic("forgeron: invalid timezone_cities.json: " + err.Error())
+	}
+	return cities
+})
+
+// Positions are spread over a disc around the center of a city
+const (
+	geoRadiusKm = 15
+	kmPerDegree = 111.32
+)
+
+// Geolocation is the position navigator.geolocation reports, named like the geolocation option of Playwright
+type Geolocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	// Accuracy is the radius of the position in meters: a few meters from the GPS of mobile devices, tens of
+	// meters from the Wi-Fi positioning of desktops
+	Accuracy float64 `json:"accuracy"`
+}
+
+// WithGeo generates a geolocation in country, e.g. the country of the proxy exit, so the position agrees with the
+// time zone and the locales. The time zone is sampled in the country and the position in one of the cities of the
+// time zone, and the locales follow the country when no Locales, Language or Region are requested.
+func WithGeo(country string) FingerprintOption {
+	return func(g *FingerprintGenerator) {
+		g.geoCountry = country
+	}
+}
+
+// geoRegion returns the canonical region of the country of WithGeo, "" when none is set
+func (g *FingerprintGenerator) geoRegion() (string, error) {
+	if g.geoCountry == "" {
+		return "", nil
+	}
+	region, err := language.ParseRegion(g.geoCountry)
+	if err != nil {
+		return "", fmt.Errorf("invalid geo country '%s': %v", g.geoCountry, err)
+	}
+	return region.String(), nil
+}
+
+// setGeolocation sets a position of a fingerprint in the time zone zone of region, dropped outside strict mode
+// when the dataset has no city there
+func (g *FingerprintGenerator) setGeolocation(fp *Fingerprint, region, zone string) error {
+	cities := timezoneCities()[zone]
+	if len(cities) == 0 {
+		if g.strict {
+			return newConstraintError("geo", "country not in the geolocation data", fmt.Errorf("no geolocation data for country %s", region))
+		}
+		logDebug(g.logger, "relaxing fingerprint constraints", "reason", "country not in the geolocation data", "dropped", []string{"geo"})
+		g.relaxationReport.add(Relaxation{Constraint: "geo", Requested: region, Reason: "country not in the geolocation data"})
+		return nil
+	}
+	city := pickWeighted(cities, func(city timezoneCity) float64 { return city.Weight }, g.random)
+
+	// Uniform over the disc around the city
+	distance := geoRadiusKm * math.Sqrt(randomFloat(g.random))
+	bearing := 2 * math.Pi * randomFloat(g.random)
+	latitude := city.Latitude + distance*math.Cos(bearing)/kmPerDegree
+	longitude := city.Longitude + distance*math.Sin(bearing)/(kmPerDegree*math.Cos(city.Latitude*math.Pi/180))
+
+	minAccuracy, maxAccuracy := 5.0, 30.0
+	if parseUserAgent(fp.Navigator.UserAgent).Device == Desktop {
+		minAccuracy, maxAccuracy = 20, 150
+	}
+	fp.Geolocation = &Geolocation{
+		Latitude:  roundTo(latitude, 6),
+		Longitude: roundTo(longitude, 6),
+		Accuracy:  roundTo(minAccuracy+(maxAccuracy-minAccuracy)*randomFloat(g.random), 1),
+	}
+	return nil
+}
+
+// roundTo rounds x to the given number of decimals
+func roundTo(x float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(x*scale) / scale
+}
@@ -0,0 +1,98 @@
+package forgeron
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTimezoneCities(t *testing.T) {
+	for region, zones := range regionTimezones() {
+		for _, zone := range zones {
+			cities := timezoneCities()[zone.Zone]
+			if len(cities) == 0 {
+				t.Errorf("%s: time zone %s has no cities", region, zone.Zone)
+				continue
+			}
+			total := 0.0
+			for _, city := range cities {
+				if math.Abs(city.Latitude) > 90 || math.Abs(city.Longitude) > 180 {
+					t.Errorf("%s: invalid coordinates of %s", zone.Zone, city.City)
+				}
+				total += city.Weight
+			}
+			if total < 0.999 || total > 1.001 {
+				t.Errorf("%s: weights sum to %v, want 1", zone.Zone, total)
+			}
+		}
+	}
+}
+
+// nearCity reports whether the position is within the spread of a city of the time zone
+func nearCity(geo *Geolocation, zone string) bool {
+	for _, city := range timezoneCities()[zone] {
+		latitude := (geo.Latitude - city.Latitude) * kmPerDegree
+		longitude := (geo.Longitude - city.Longitude) * kmPerDegree * math.Cos(city.Latitude*math.Pi/180)
+		if math.Hypot(latitude, longitude) <= geoRadiusKm+0.1 {
+			return true
+		}
+	}
+	return false
+}
+
+func TestGenerateGeo(t *testing.T) {
+	gen := newGeneratorOrFatal(t, WithHeaderConstraints(HeaderConstraints{Devices: []Device{Desktop}}))
+	for range 10 {
+		fp, err := gen.Generate(WithGeo("fr"))
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if fp.Timezone == nil || fp.Timezone.Name != "Europe/Paris" {
+			t.Fatalf("Timezone = %+v, want Europe/Paris", fp.Timezone)
+		}
+		if fp.Geolocation == nil || !nearCity(fp.Geolocation, fp.Timezone.Name) {
+			t.Fatalf("Geolocation = %+v, want a position near a city of Europe/Paris", fp.Geolocation)
+		}
+		if fp.Geolocation.Accuracy < 20 || fp.Geolocation.Accuracy > 150 {
+			t.Errorf("Accuracy = %v, want a desktop accuracy", fp.Geolocation.Accuracy)
+		}
+		// The locales follow the country when none are requested
+		if fp.Navigator.Language != "fr-FR" {
+			t.Errorf("Language = %q, want fr-FR", fp.Navigator.Language)
+		}
+	}
+
+	// Requested locales are kept, the time zone and the position follow the country
+	fp, err := gen.Generate(WithGeo("US"), WithHeaderConstraints(HeaderConstraints{Devices: []Device{Desktop}, Locales: []string{"de-DE"}}))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if fp.Navigator.Language != "de-DE" {
+		t.Errorf("Language = %q, want de-DE", fp.Navigator.Language)
+	}
+	if fp.Timezone == nil || fp.Geolocation == nil || !nearCity(fp.Geolocation, fp.Timezone.Name) {
+		t.Errorf("Timezone, Geolocation = %+v, %+v, want a position in the time zone", fp.Timezone, fp.Geolocation)
+	}
+
+	if fp, err := gen.Generate(); err != nil || fp.Geolocation != nil {
+		t.Errorf("Generate() = %+v, %v, want no geolocation without WithGeo", fp.Geolocation, err)
+	}
+}
+
+func TestGenerateGeoUnknownCountry(t *testing.T) {
+	gen := newGeneratorOrFatal(t)
+
+	var report RelaxationReport
+	fp, err := gen.Generate(WithGeo("KE"), WithRelaxationReport(&report))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if fp.Geolocation != nil || !report.Dropped("geo") {
+		t.Errorf("Geolocation = %+v, report = %v, want the geolocation dropped", fp.Geolocation, report.String())
+	}
+	if _, err := gen.Generate(WithGeo("KE"), WithStrict(true)); err == nil {
+		t.Error("Generate() error = nil in strict mode")
+	}
+	if _, err := gen.Generate(WithGeo("not-a-country")); err == nil {
+		t.Error("Generate() error = nil for an invalid country")
+	}
+}
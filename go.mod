@@ -3,3 +3,10 @@ module github.com/ta0uf19/forgeron
 go 1.23.4
 
 require golang.org/x/text v0.24.0
+
+require (
+	github.com/fsnotify/fsnotify v1.10.1
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require golang.org/x/sys v0.13.0 // indirect
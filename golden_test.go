@@ -0,0 +1,113 @@
+package forgeron
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// updateGolden rewrites the golden files from the current transform output
+var updateGolden = flag.Bool("update", false, "update golden files in testdata/golden")
+
+// goldenInput is a raw network sample as recorded by fingerprint-suite, with the headers it was paired with
+type goldenInput struct {
+	Raw     map[string]string `json:"raw"`
+	Headers map[string]string `json:"headers"`
+}
+
+// goldenCases returns the names of all golden cases found in testdata/golden
+func goldenCases(t *testing.T) []string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join("testdata", "golden", "*.input.json"))
+	if err != nil {
+		t.Fatalf("failed to list golden inputs: %v", err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no golden inputs found in testdata/golden")
+	}
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		names = append(names, strings.TrimSuffix(filepath.Base(f), ".input.json"))
+	}
+	return names
+}
+
+// readGoldenInput reads the raw input of a golden case
+func readGoldenInput(t *testing.T, name string) goldenInput {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "golden", name+".input.json"))
+	if err != nil {
+		t.Fatalf("failed to read golden input: %v", err)
+	}
+	var in goldenInput
+	if err := json.Unmarshal(data, &in); err != nil {
+		t.Fatalf("failed to parse golden input: %v", err)
+	}
+	return in
+}
+
+// assertGolden compares got against the named golden file, rewriting it when -update is set
+func assertGolden(t *testing.T, file string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", file)
+	if *updateGolden {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(bytes.TrimSpace(got), bytes.TrimSpace(want)) {
+		t.Errorf("%s does not match golden output\n got: %s\nwant: %s", file, got, want)
+	}
+}
+
+// TestGoldenTransform guards the transform of recorded samples against regressions. The golden files are our own
+// output, the interop with fingerprint-suite is checked against its corpus by TestFingerprintSuiteCorpus in injector.
+func TestGoldenTransform(t *testing.T) {
+	for _, name := range goldenCases(t) {
+		t.Run(name, func(t *testing.T) {
+			in := readGoldenInput(t, name)
+			g := &FingerprintGenerator{}
+			fp, err := g.transformFingerprint(in.Raw, in.Headers, false, false)
+			if err != nil {
+				t.Fatalf("transformFingerprint() error = %v", err)
+			}
+			got, err := json.MarshalIndent(fp, "", "  ")
+			if err != nil {
+				t.Fatalf("failed to marshal fingerprint: %v", err)
+			}
+			assertGolden(t, name+".golden.json", got)
+		})
+	}
+}
+
+// TestGoldenRoundTrip verifies golden fingerprints parse back into the same structure
+func TestGoldenRoundTrip(t *testing.T) {
+	for _, name := range goldenCases(t) {
+		t.Run(name, func(t *testing.T) {
+			want, err := os.ReadFile(filepath.Join("testdata", "golden", name+".golden.json"))
+			if err != nil {
+				t.Fatalf("failed to read golden file: %v", err)
+			}
+			var fp Fingerprint
+			if err := json.Unmarshal(want, &fp); err != nil {
+				t.Fatalf("failed to parse golden fingerprint: %v", err)
+			}
+			got, err := json.MarshalIndent(&fp, "", "  ")
+			if err != nil {
+				t.Fatalf("failed to marshal fingerprint: %v", err)
+			}
+			if !bytes.Equal(bytes.TrimSpace(got), bytes.TrimSpace(want)) {
+				t.Errorf("round trip changed the fingerprint\n got: %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
@@ -2,16 +2,90 @@ package forgeron
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"log/slog"
+	"slices"
 	"strings"
 )
 
+// Browser is a browser name accepted in header constraints
+type Browser string
+
+// OS is an operating system name accepted in header constraints
+type OS string
+
+// Device is a device class accepted in header constraints
+type Device string
+
+// HTTPVersion is an HTTP version accepted in header constraints
+type HTTPVersion string
+
+// Browsers
+const (
+	Chrome  Browser = "chrome"
+	Firefox Browser = "firefox"
+	Safari  Browser = "safari"
+	Edge    Browser = "edge"
+)
+
+// Operating systems
+const (
+	Windows  OS = "windows"
+	MacOS    OS = "macos"
+	Linux    OS = "linux"
+	Android  OS = "android"
+	IOS      OS = "ios"
+	ChromeOS OS = "chromeos"
+)
+
+// Devices
+const (
+	Desktop Device = "desktop"
+	Mobile  Device = "mobile"
+	Tablet  Device = "tablet"
+)
+
+// HTTP versions
+const (
+	HTTP1 HTTPVersion = "1"
+	HTTP2 HTTPVersion = "2"
+)
+
+// BrowsersOf converts browser names to Browsers, for constraints built from plain strings as before the typed names
+func BrowsersOf(names ...string) []Browser {
+	return typedNames[Browser](names)
+}
+
+// OSesOf converts operating system names to OSes, for constraints built from plain strings as before the typed names
+func OSesOf(names ...string) []OS {
+	return typedNames[OS](names)
+}
+
+// DevicesOf converts device names to Devices, for constraints built from plain strings as before the typed names
+func DevicesOf(names ...string) []Device {
+	return typedNames[Device](names)
+}
+
+// typedNames converts names to a typed string, keeping nil as nil so unset constraints stay unset
+func typedNames[T ~string](names []string) []T {
+	if names == nil {
+		return nil
+	}
+	typed := make([]T, len(names))
+	for i, name := range names {
+		typed[i] = T(name)
+	}
+	return typed
+}
+
 // BrowserSpec represents a browser specification with name, min/max version, and HTTP version
 type BrowserSpec struct {
-	Name        string
+	Name        Browser
 	MinVersion  int
 	MaxVersion  int
-	HTTPVersion string
+	HTTPVersion HTTPVersion
 }
 
 // httpBrowser represents an HTTP browser object with name, version, complete string, and HTTP version
@@ -29,49 +103,77 @@ func (h *httpBrowser) IsHTTP2() bool {
 
 // Supported Browsers, OS, Devices, and HTTP versions
 var (
-	SupportedBrowsers = []string{"chrome", "firefox", "safari", "edge"}
-	SupportedOS       = []string{"windows", "macos", "linux", "android", "ios"}
-	SupportedDevices  = []string{"desktop", "mobile"}
-	SupportedHTTP     = []string{"1", "2"}
+	SupportedBrowsers = []Browser{Chrome, Firefox, Safari, Edge}
+	SupportedOS       = []OS{Windows, MacOS, Linux, Android, IOS, ChromeOS}
+	SupportedDevices  = []Device{Desktop, Mobile, Tablet}
+	SupportedHTTP     = []HTTPVersion{HTTP1, HTTP2}
 )
 
-// missingValueToken is used to indicate a missing value in the headers
-const missingValueToken = "*MISSING_VALUE*"
-
-// http1SecFetchAttributes defines the default Sec-Fetch headers for HTTP/1.1
-var http1SecFetchAttributes = map[string]string{
-	"Sec-Fetch-Mode": "same-site",
-	"Sec-Fetch-Dest": "navigate",
-	"Sec-Fetch-Site": "?1",
-	"Sec-Fetch-User": "document",
-}
-
-// http2SecFetchAttributes defines the default Sec-Fetch headers for HTTP/2
-var http2SecFetchAttributes = map[string]string{
-	"sec-fetch-mode": "same-site",
-	"sec-fetch-dest": "navigate",
-	"sec-fetch-site": "?1",
-	"sec-fetch-user": "document",
-}
-
 // HeaderConstraints represents the configuration constraints for header generation
 type HeaderConstraints struct {
 	BrowserSpecs []*BrowserSpec
-	Browsers     []string
-	OS           []string
-	Devices      []string
+	Browsers     []Browser
+	OS           []OS
+	Devices      []Device
 	Locales      []string
-	HTTPVersion  string
+	Language     string
+	Region       string
+	HTTPVersion  HTTPVersion
 	Strict       bool
+	// HeaderPolicy filters the recorded headers, nil keeps all of them
+	HeaderPolicy *HeaderPolicy
+	// Accept overrides the sampled Accept header, only browsers plausibly sending it are sampled
+	Accept string
+	// RequestContext is the context the request is made in, it decides the Sec-Fetch headers. Empty is a top-level navigation.
+	RequestContext RequestContext
+	// ExpandLocales expands every locale into the chain a browser sends, e.g. [fr] into [fr-FR fr en-US en]
+	ExpandLocales bool
+	// URL is the URL requested. Along with Referrer it decides the Referer, Origin and Sec-Fetch-Site headers.
+	URL string
+	// Referrer is the URL of the page making the request, e.g. the page of a clicked link. Empty is a navigation typed
+	// by the user.
+	Referrer string
+	// ReferrerPolicy is the Referrer-Policy of the referring page, empty is strict-origin-when-cross-origin, the
+	// default of every current browser
+	ReferrerPolicy ReferrerPolicy
+	// SecFetchSite overrides the Sec-Fetch-Site header, empty derives it from the request context and the referrer.
+	// FetchSiteNone is only possible on top-level navigations.
+	SecFetchSite FetchSite
+	// SecFetchUser overrides the Sec-Fetch-User header of navigations, empty sends ?1 on top-level navigations only
+	SecFetchUser FetchUser
 }
 
-// HeaderGenerator generates HTTP headers based on browser fingerprint
+// HeaderGenerator generates HTTP headers based on browser fingerprint.
+// Generating is safe for concurrent use, SetLogger and SetRandomSource are not.
 type HeaderGenerator struct {
 	headerGeneratorNetwork *bayesianNetwork
 	inputGeneratorNetwork  *bayesianNetwork
 	headersOrder           map[string][]string
+	headerOrders           map[headerOrderKey][]string
+	secFetchSupport        map[string][]int
 	uniqueBrowsers         []*httpBrowser
+	browsersByName         map[Browser][]*httpBrowser
+	classBrowsers          map[userAgentClass]map[string]bool
 	options                HeaderConstraints
+	data                   fs.FS
+	loader                 networkLoader
+	logger                 *slog.Logger
+	random                 RandomSource
+}
+
+// clone returns a copy of the generator sharing its loaded data, whose logger and random source can be changed
+// without affecting the generator
+func (g *HeaderGenerator) clone() *HeaderGenerator {
+	c := *g
+	if g.inputGeneratorNetwork != nil {
+		network := *g.inputGeneratorNetwork
+		c.inputGeneratorNetwork = &network
+	}
+	if g.headerGeneratorNetwork != nil {
+		network := *g.headerGeneratorNetwork
+		c.headerGeneratorNetwork = &network
+	}
+	return &c
 }
 
 // defaultHeaderOptions returns the default header constraints
@@ -81,7 +183,7 @@ func defaultHeaderOptions() HeaderConstraints {
 		OS:          SupportedOS,
 		Devices:     SupportedDevices,
 		Locales:     []string{"en-US"},
-		HTTPVersion: "2",
+		HTTPVersion: HTTP2,
 		Strict:      false,
 	}
 }
@@ -91,28 +193,31 @@ func (g *HeaderGenerator) mergeOptions(userOptions HeaderConstraints) (HeaderCon
 	merged := g.options // Start with defaults
 	var validationErrors []error
 
-	// Helper function to validate and merge a field
-	validateAndMerge := func(userValues, supported []string, setter func([]string)) {
-		if len(userValues) == 0 {
-			return
-		}
-		valid, err := filterValidValues(userValues, supported)
+	// Validate and merge each field
+	merged.Browsers = validateAndMerge("Browsers", userOptions.Browsers, SupportedBrowsers, merged.Browsers, &validationErrors)
+	merged.OS = validateAndMerge("OS", userOptions.OS, SupportedOS, merged.OS, &validationErrors)
+	merged.Devices = validateAndMerge("Devices", userOptions.Devices, SupportedDevices, merged.Devices, &validationErrors)
+
+	// Build locales from language and region when no explicit locales are given
+	if len(userOptions.Locales) == 0 && (userOptions.Language != "" || userOptions.Region != "") {
+		chain, err := localeChain(userOptions.Language, userOptions.Region)
 		if err != nil {
 			validationErrors = append(validationErrors, err)
-		}
-		if len(valid) > 0 {
-			setter(valid)
+		} else {
+			userOptions.Locales = chain
 		}
 	}
 
-	// Validate and merge each field
-	validateAndMerge(userOptions.Browsers, SupportedBrowsers, func(v []string) { merged.Browsers = v })
-	validateAndMerge(userOptions.OS, SupportedOS, func(v []string) { merged.OS = v })
-	validateAndMerge(userOptions.Devices, SupportedDevices, func(v []string) { merged.Devices = v })
-
 	// Handle locales
 	if len(userOptions.Locales) > 0 {
-		merged.Locales = userOptions.Locales
+		locales, err := canonicalLocales(userOptions.Locales)
+		if err != nil {
+			validationErrors = append(validationErrors, err)
+		} else if userOptions.ExpandLocales {
+			merged.Locales = expandLocales(locales)
+		} else {
+			merged.Locales = locales
+		}
 		if len(merged.Locales) > 10 {
 			merged.Locales = merged.Locales[:10]
 		}
@@ -121,7 +226,7 @@ func (g *HeaderGenerator) mergeOptions(userOptions HeaderConstraints) (HeaderCon
 	// Handle HTTP version
 	if userOptions.HTTPVersion != "" {
 		if err := validateAgainstSupported(userOptions.HTTPVersion, SupportedHTTP); err != nil {
-			validationErrors = append(validationErrors, err)
+			validationErrors = append(validationErrors, newFieldError("HTTPVersion", err, userOptions.HTTPVersion))
 		} else {
 			merged.HTTPVersion = userOptions.HTTPVersion
 		}
@@ -129,22 +234,89 @@ func (g *HeaderGenerator) mergeOptions(userOptions HeaderConstraints) (HeaderCon
 
 	merged.Strict = userOptions.Strict
 	merged.BrowserSpecs = userOptions.BrowserSpecs
+	merged.HeaderPolicy = userOptions.HeaderPolicy
 
-	if len(validationErrors) > 0 {
-		return merged, fmt.Errorf("validation errors: %v", validationErrors)
+	// Handle request context
+	if userOptions.RequestContext != "" {
+		if err := validateAgainstSupported(userOptions.RequestContext, SupportedRequestContexts); err != nil {
+			validationErrors = append(validationErrors, newFieldError("RequestContext", err, userOptions.RequestContext))
+		} else {
+			merged.RequestContext = userOptions.RequestContext
+		}
 	}
 
-	return merged, nil
+	// Handle the target and referring URLs
+	if userOptions.URL != "" {
+		if _, err := parseRequestURL("URL", userOptions.URL); err != nil {
+			validationErrors = append(validationErrors, err)
+		} else {
+			merged.URL = userOptions.URL
+		}
+	}
+	if userOptions.Referrer != "" {
+		if _, err := parseRequestURL("Referrer", userOptions.Referrer); err != nil {
+			validationErrors = append(validationErrors, err)
+		} else if userOptions.URL == "" {
+			err := fmt.Errorf("a referrer requires the URL requested")
+			validationErrors = append(validationErrors, newFieldError("Referrer", err, userOptions.Referrer))
+		} else {
+			merged.Referrer = userOptions.Referrer
+		}
+	}
+	if userOptions.ReferrerPolicy != "" {
+		if err := validateAgainstSupported(userOptions.ReferrerPolicy, SupportedReferrerPolicies); err != nil {
+			validationErrors = append(validationErrors, newFieldError("ReferrerPolicy", err, userOptions.ReferrerPolicy))
+		} else {
+			merged.ReferrerPolicy = userOptions.ReferrerPolicy
+		}
+	}
+
+	// Handle the Sec-Fetch overrides, checked against the validated request context
+	if errs := validateSecFetch(merged.RequestContext, userOptions.SecFetchSite, userOptions.SecFetchUser); len(errs) > 0 {
+		validationErrors = append(validationErrors, errs...)
+	} else {
+		merged.SecFetchSite = userOptions.SecFetchSite
+		merged.SecFetchUser = userOptions.SecFetchUser
+	}
+
+	// Limit browsers to the ones sending the Accept override
+	if userOptions.Accept != "" {
+		if err := validateAccept(userOptions.Accept); err != nil {
+			validationErrors = append(validationErrors, newFieldError("Accept", err, userOptions.Accept))
+		} else if browsers := acceptBrowsers(userOptions.Accept, merged.Browsers); len(browsers) == 0 {
+			err := fmt.Errorf("accept header '%s' is not sent by any of the browsers %v", userOptions.Accept, merged.Browsers)
+			validationErrors = append(validationErrors, newFieldError("Accept", err, userOptions.Accept))
+		} else {
+			merged.Browsers = browsers
+			merged.Accept = userOptions.Accept
+		}
+	}
+
+	// Every failed field is reported, callers find them with errors.As
+	return merged, errors.Join(validationErrors...)
 }
 
 // NewHeaderGenerator creates a new header generator
 func NewHeaderGenerator() (*HeaderGenerator, error) {
+	return newHeaderGenerator(networkLoader{data: embeddedData()})
+}
+
+// newHeaderGenerator creates a new header generator reading its data and networks with the loader
+func newHeaderGenerator(loader networkLoader) (*HeaderGenerator, error) {
 	generator := &HeaderGenerator{
 		options: defaultHeaderOptions(),
+		data:    loader.data,
+		loader:  loader,
 	}
 
 	// Load headers order and unique browsers
 	generator.loadHeadersOrder()
+	if err := generator.loadHeaderOrders(); err != nil {
+		return nil, err
+	}
+	if err := generator.loadSecFetchSupport(); err != nil {
+		return nil, err
+	}
 	generator.loadUniqueBrowsers()
 	// Load networks
 	err := generator.loadInputGeneratorNetwork()
@@ -155,64 +327,179 @@ func NewHeaderGenerator() (*HeaderGenerator, error) {
 	if err != nil {
 		return nil, err
 	}
+	generator.loadClassBrowsers()
 
 	return generator, nil
 }
 
 // GenerateHeaders generates HTTP headers based on the given options
 func (g *HeaderGenerator) GenerateHeaders(options HeaderConstraints) (map[string]string, error) {
+	result, err := g.generateHeaders(options, nil)
+	if err != nil {
+		return nil, err
+	}
+	return result.headers, nil
+}
+
+// GenerateHeadersWithReport generates HTTP headers like GenerateHeaders, also returning the constraints
+// relaxed in non-strict mode to generate them
+func (g *HeaderGenerator) GenerateHeadersWithReport(options HeaderConstraints) (map[string]string, *RelaxationReport, error) {
+	report := &RelaxationReport{}
+	result, err := g.generateHeaders(options, report)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result.headers, report, nil
+}
+
+// headerResult holds generated headers with the HTTP version they were generated for, which differs from the
+// requested one when HTTP/1 was relaxed to HTTP/2
+type headerResult struct {
+	headers     map[string]string
+	httpVersion HTTPVersion
+	// locales are the locales Accept-Language was built from, once relaxed
+	locales []string
+}
+
+// generateHeaders generates HTTP headers, recording the relaxed constraints in report when not nil.
+// The casing of the headers is normalized for the HTTP version used.
+func (g *HeaderGenerator) generateHeaders(options HeaderConstraints, report *RelaxationReport) (headerResult, error) {
+	result, err := g.sampleHeaders(options, report)
+	if err != nil {
+		return headerResult{}, err
+	}
+	if result.httpVersion == HTTP2 {
+		dropConnectionHeaders(result.headers)
+		result.headers = pascalizeHeaders(result.headers)
+	} else {
+		// HTTP/1.1 headers are sampled with the casing browsers send them
+		setKeepAlive(result.headers)
+	}
+	return result, nil
+}
+
+// sampleHeaders samples HTTP headers with the casing of the header network, relaxing the constraints in non-strict
+// mode when no headers satisfy them
+func (g *HeaderGenerator) sampleHeaders(options HeaderConstraints, report *RelaxationReport) (headerResult, error) {
 	// Merge user constraints with defaults
 	constraints, err := g.mergeOptions(options)
 	if err != nil {
-		return nil, err
+		return headerResult{}, err
 	}
 
 	// Prepare input constraints
 	inputConstraints, err := g.prepareConstraints(constraints)
 	if err != nil {
-		return nil, err
+		return headerResult{}, err
 	}
+	g.restrictBrowsersToClasses(inputConstraints, requestedClasses(constraints))
 
 	// Generate input values using the input generator network (randomized)
 	inputSample, ok := g.inputGeneratorNetwork.generateConsistentSampleWhenPossible(inputConstraints)
+	// Browsers are left unconstrained when none matches, which would mix HTTP/2 headers into HTTP/1 ones
+	// when none of the requested browsers sends HTTP/1 requests
+	if ok && constraints.HTTPVersion == HTTP1 && inputConstraints["*BROWSER_HTTP"] == nil {
+		ok = false
+	}
 	if !ok {
 		// fallback to default values
-		if constraints.HTTPVersion == "1" {
+		if constraints.HTTPVersion == HTTP1 && !constraints.Strict {
 			// Try with HTTP/2
-			constraints.HTTPVersion = "2"
-			headers, err := g.GenerateHeaders(constraints)
-			if err != nil {
-				return nil, err
-			}
-			return pascalizeHeaders(headers), nil
+			logDebug(g.logger, "relaxing header constraints", "reason", "no HTTP/1 input sample", "httpVersion", "2")
+			report.add(Relaxation{Constraint: "httpVersion", Requested: string(HTTP1), Used: string(HTTP2), Reason: "no HTTP/1 input sample"})
+			constraints.HTTPVersion = HTTP2
+			return g.sampleHeaders(constraints, report)
 		}
 		// If the input generation failed and strict mode is enabled, return an error
 		if constraints.Strict {
-			return nil, fmt.Errorf("no headers based on this input can be generated. Please relax or change some of the requirements you specified")
+			return headerResult{}, newConstraintError("headers", "no input sample", fmt.Errorf("no headers based on this input can be generated. Please relax or change some of the requirements you specified"))
 		}
 
-		// TODO: we can remove one by one
-		// Relax constraints
+		// Relax constraints one step at a time, dropped constraints falling back to the defaults
 		relaxedConstraints := constraints
-		relaxedConstraints.Locales = nil
-		relaxedConstraints.Devices = nil
-		headers, err := g.GenerateHeaders(relaxedConstraints)
-		if err != nil {
-			return nil, err
+		switch {
+		case !slices.Equal(constraints.Locales, g.options.Locales) || !slices.Equal(constraints.Devices, g.options.Devices):
+			logDebug(g.logger, "relaxing header constraints", "reason", "no input sample", "dropped", []string{"locales", "devices"})
+			if !slices.Equal(constraints.Locales, g.options.Locales) {
+				report.add(Relaxation{Constraint: "locales", Requested: joinValues(constraints.Locales), Used: joinValues(g.options.Locales), Reason: "no input sample"})
+			}
+			if !slices.Equal(constraints.Devices, g.options.Devices) {
+				report.add(Relaxation{Constraint: "devices", Requested: joinValues(constraints.Devices), Used: joinValues(g.options.Devices), Reason: "no input sample"})
+			}
+			relaxedConstraints.Locales = nil
+			relaxedConstraints.Devices = nil
+		case !slices.Equal(constraints.OS, g.options.OS):
+			logDebug(g.logger, "relaxing header constraints", "reason", "no input sample", "dropped", []string{"os"})
+			report.add(Relaxation{Constraint: "os", Requested: joinValues(constraints.OS), Reason: "no input sample"})
+			relaxedConstraints.OS = nil
+		case !slices.Equal(constraints.Browsers, g.options.Browsers) || constraints.BrowserSpecs != nil:
+			// The Accept override limits the browsers, it is dropped with them
+			logDebug(g.logger, "relaxing header constraints", "reason", "no input sample", "dropped", []string{"browsers"})
+			report.add(Relaxation{Constraint: "browsers", Requested: joinValues(constraints.Browsers), Reason: "no input sample"})
+			if constraints.Accept != "" {
+				report.add(Relaxation{Constraint: "accept", Requested: constraints.Accept, Reason: "no input sample"})
+			}
+			relaxedConstraints.Browsers = nil
+			relaxedConstraints.BrowserSpecs = nil
+			relaxedConstraints.Accept = ""
+		default:
+			return headerResult{}, newConstraintError("headers", "no input sample", fmt.Errorf("no headers can be generated even with the default constraints"))
 		}
-		return pascalizeHeaders(headers), nil
+		return g.sampleHeaders(relaxedConstraints, report)
 	}
 
-	// Generate headers using the header network
-	sample := g.headerGeneratorNetwork.generateSample(inputSample)
+	// Generate headers using the header network, sampling other inputs when the sampled browser has no user agent
+	// of the requested classes
+	sample, ok := g.generateHeaderSample(inputSample, constraints)
+	for attempt := 1; !ok && attempt < classSampleAttempts; attempt++ {
+		if retry, retried := g.inputGeneratorNetwork.generateConsistentSampleWhenPossible(inputConstraints); retried {
+			inputSample = retry
+			sample, ok = g.generateHeaderSample(inputSample, constraints)
+		}
+	}
+	if !ok {
+		classes := sampledClasses(inputSample, constraints)
+		if constraints.Strict {
+			return headerResult{}, newConstraintError(classConstraint(classes[0]), "no matching user agent", fmt.Errorf("no user agent matches the requested devices and operating systems. Please relax or change some of the requirements you specified"))
+		}
+		// Drop the constraints of the classes, falling back to the defaults, and sample again
+		relaxedConstraints, relaxed := constraints, false
+		for _, class := range classes {
+			switch constraint := classConstraint(class); {
+			case constraint == "devices" && relaxedConstraints.Devices != nil && !slices.Equal(constraints.Devices, g.options.Devices):
+				logDebug(g.logger, "relaxing header constraints", "reason", "no matching user agent", "dropped", []string{constraint})
+				report.add(Relaxation{Constraint: constraint, Requested: joinValues(constraints.Devices), Used: joinValues(g.options.Devices), Reason: "no matching user agent"})
+				relaxedConstraints.Devices, relaxed = nil, true
+			case constraint == "os" && relaxedConstraints.OS != nil && !slices.Equal(constraints.OS, g.options.OS):
+				logDebug(g.logger, "relaxing header constraints", "reason", "no matching user agent", "dropped", []string{constraint})
+				report.add(Relaxation{Constraint: constraint, Requested: joinValues(constraints.OS), Reason: "no matching user agent"})
+				relaxedConstraints.OS, relaxed = nil, true
+			}
+		}
+		if relaxed {
+			return g.sampleHeaders(relaxedConstraints, report)
+		}
+		sample = g.headerGeneratorNetwork.generateSample(inputSample)
+	}
 
 	// Generate headers from sample
 	headers := g.generateHeadersFromSample(sample)
+	// The header network can pair client hints with other user agents for parent values missing from the data
+	if engineOf(headerValue(headers, "user-agent")) != blinkEngine {
+		dropClientHints(headers)
+	}
+
+	// The headers follow the HTTP version of the sampled browser
+	httpVersion := constraints.HTTPVersion
+	browser := g.prepareHttpBrowserObject(sample["*BROWSER_HTTP"])
+	if browser != nil && browser.HTTPVersion != "" {
+		httpVersion = HTTPVersion(browser.HTTPVersion)
+	}
 
 	// Add Accept-Language header
 	if len(constraints.Locales) > 0 {
-		acceptLanguage := g.generateAcceptLanguageHeader(constraints.Locales)
-		if sample["*HTTP_VERSION"] == "2" {
+		acceptLanguage := newLocaleModel(headerValue(headers, "user-agent"), constraints.Locales).acceptLanguage()
+		if httpVersion == HTTP2 {
 			headers["accept-language"] = acceptLanguage
 		} else {
 			headers["Accept-Language"] = acceptLanguage
@@ -220,88 +507,104 @@ func (g *HeaderGenerator) GenerateHeaders(options HeaderConstraints) (map[string
 	}
 
 	// Add Sec-Fetch headers if needed
-	browser := g.prepareHttpBrowserObject(sample["*BROWSER_HTTP"])
+	var referrerValues map[string]string
+	if constraints.Referrer != "" {
+		referrerValues = constraints.referrerValues()
+	}
 	if browser != nil && g.shouldAddSecFetch(browser) {
-		if browser.IsHTTP2() {
-			for k, v := range http2SecFetchAttributes {
-				headers[k] = v
+		site := constraints.SecFetchSite
+		if site == "" {
+			site = FetchSite(referrerValues["sec-fetch-site"])
+		}
+		for k, v := range secFetchHeaders(constraints.RequestContext, site, constraints.SecFetchUser, Browser(*browser.Name)) {
+			if httpVersion == HTTP1 {
+				k = pascalizeKey(k)
 			}
-		} else {
-			for k, v := range http1SecFetchAttributes {
-				headers[k] = v
+			headers[k] = v
+		}
+	}
+	for _, k := range []string{"referer", "origin"} {
+		if v := referrerValues[k]; v != "" {
+			if httpVersion == HTTP1 {
+				k = pascalizeKey(k)
 			}
+			headers[k] = v
 		}
 	}
+	if constraints.RequestContext.requestType() == Fetch {
+		dropNavigationHeaders(headers)
+	}
+	if accept := constraints.RequestContext.accept(headerValue(headers, "user-agent")); accept != "" {
+		setAccept(headers, accept)
+	}
 
-	// TODO: implement header reordering
-	// Pascalize headers for HTTP/2
-	if constraints.HTTPVersion == "2" {
-		return pascalizeHeaders(headers), nil
+	// Override the sampled Accept header
+	if constraints.Accept != "" {
+		setAccept(headers, constraints.Accept)
 	}
 
-	return nil, nil
+	// Drop the headers denied by the policy
+	browserName := ""
+	if browser != nil && browser.Name != nil {
+		browserName = *browser.Name
+	}
+	constraints.HeaderPolicy.apply(headers, browserName)
+	return headerResult{headers: headers, httpVersion: httpVersion, locales: constraints.Locales}, nil
 }
 
-// getPossibleAttributeValues returns the possible values for each attribute
-func (g *HeaderGenerator) getPossibleAttributeValues(options HeaderConstraints) map[string][]string {
-	values := make(map[string][]string)
-
-	// Get browser HTTP options
-	values["*BROWSER_HTTP"] = g.getBrowserHTTPOptions(options)
+// classSampleAttempts is the number of input samples tried before relaxing the user agent classes
+const classSampleAttempts = 10
 
-	// Get OS options
-	if len(options.OS) > 0 {
-		values["*OPERATING_SYSTEM"] = options.OS
+// generateHeaderSample samples the header network given the input sample. It returns false when the user agent
+// classes implied by the input sample and the constraints cannot be satisfied.
+func (g *HeaderGenerator) generateHeaderSample(inputSample map[string]string, constraints HeaderConstraints) (map[string]string, bool) {
+	if len(sampledClasses(inputSample, constraints)) == 0 {
+		return g.headerGeneratorNetwork.generateSample(inputSample), true
 	}
-
-	// Get device options
-	if len(options.Devices) > 0 {
-		values["*DEVICE"] = options.Devices
+	restrictions := g.headerRestrictions(inputSample, constraints)
+	if restrictions == nil {
+		return nil, false
 	}
-
-	return values
+	sample, ok := g.headerGeneratorNetwork.generateConsistentSampleWhenPossible(restrictions)
+	if !ok {
+		return nil, false
+	}
+	for k, v := range inputSample {
+		sample[k] = v
+	}
+	return sample, true
 }
 
-// getBrowserHTTPOptions returns the browser HTTP options based on the given constraints
-func (g *HeaderGenerator) getBrowserHTTPOptions(options HeaderConstraints) []string {
-	var result []string
-
-	// BrowserSpecs are specified
-	if len(options.BrowserSpecs) > 0 {
-		for _, browser := range options.BrowserSpecs {
-			for _, uniqueBrowser := range g.uniqueBrowsers {
-				if uniqueBrowser.Name != nil && *uniqueBrowser.Name == browser.Name {
-					if browser.HTTPVersion == uniqueBrowser.HTTPVersion || browser.HTTPVersion == "" {
-						// Check version constraints if specified
-						if browser.MinVersion > 0 && uniqueBrowser.Version[0] < browser.MinVersion {
-							continue
-						}
-						if browser.MaxVersion > 0 && uniqueBrowser.Version[0] > browser.MaxVersion {
-							continue
-						}
-						result = append(result, uniqueBrowser.CompleteString)
-					}
-				}
-			}
+// browserHTTPValues returns the *BROWSER_HTTP values allowed by the browser specs, or by the browser names
+// and HTTP version when no specs are given
+func (g *HeaderGenerator) browserHTTPValues(options HeaderConstraints) []string {
+	specs := options.BrowserSpecs
+	if len(specs) == 0 {
+		specs = make([]*BrowserSpec, len(options.Browsers))
+		for i, name := range options.Browsers {
+			specs[i] = &BrowserSpec{Name: name, HTTPVersion: options.HTTPVersion}
 		}
-		return result
 	}
 
-	// Otherwise, use browser strings
-	for _, browserName := range options.Browsers {
-		browser := &BrowserSpec{
-			Name:        browserName,
-			HTTPVersion: options.HTTPVersion,
+	var result []string
+	for _, spec := range specs {
+		// Browser specs are still limited to the requested browser names
+		if !slices.Contains(options.Browsers, spec.Name) {
+			continue
 		}
-		for _, uniqueBrowser := range g.uniqueBrowsers {
-			if uniqueBrowser.Name != nil && *uniqueBrowser.Name == browser.Name {
-				if browser.HTTPVersion == uniqueBrowser.HTTPVersion || browser.HTTPVersion == "" {
-					result = append(result, uniqueBrowser.CompleteString)
-				}
+		for _, browser := range g.browsersByName[spec.Name] {
+			if spec.HTTPVersion != "" && spec.HTTPVersion != HTTPVersion(browser.HTTPVersion) {
+				continue
+			}
+			if spec.MinVersion > 0 && browser.Version[0] < spec.MinVersion {
+				continue
 			}
+			if spec.MaxVersion > 0 && browser.Version[0] > spec.MaxVersion {
+				continue
+			}
+			result = append(result, browser.CompleteString)
 		}
 	}
-
 	return result
 }
 
@@ -309,47 +612,40 @@ func (g *HeaderGenerator) getBrowserHTTPOptions(options HeaderConstraints) []str
 func (g *HeaderGenerator) generateHeadersFromSample(sample map[string]string) map[string]string {
 	headers := make(map[string]string)
 	for k, v := range sample {
-		if !strings.HasPrefix(k, "*") && v != missingValueToken {
+		if !strings.HasPrefix(k, "*") && !isMissingValue(v) {
 			headers[k] = v
 		}
 	}
 	return headers
 }
 
-// generateAcceptLanguageHeader generates the Accept-Language header
-func (g *HeaderGenerator) generateAcceptLanguageHeader(locales []string) string {
-	var parts []string
-	for i, locale := range locales {
-		if i >= 10 { // Limit to 10 languages
-			break
+// connectionHeaders are connection-specific headers, forbidden in HTTP/2 (RFC 9113 section 8.2.2)
+var connectionHeaders = []string{"connection", "keep-alive", "proxy-connection", "transfer-encoding", "upgrade"}
+
+// dropConnectionHeaders removes the connection-specific headers from HTTP/2 headers
+func dropConnectionHeaders(headers map[string]string) {
+	for k := range headers {
+		if slices.Contains(connectionHeaders, strings.ToLower(k)) {
+			delete(headers, k)
 		}
-		q := 1.0 - float64(i)*0.1
-		parts = append(parts, fmt.Sprintf("%s;q=%.1f", locale, q))
 	}
-	return strings.Join(parts, ", ")
 }
 
-// shouldAddSecFetch determines if Sec-Fetch headers should be added based on the user agent
-func (g *HeaderGenerator) shouldAddSecFetch(browser *httpBrowser) bool {
-	if browser == nil || browser.Name == nil || len(browser.Version) == 0 {
-		return false
-	}
-
-	switch *browser.Name {
-	case "chrome":
-		return browser.Version[0] >= 76
-	case "firefox":
-		return browser.Version[0] >= 90
-	case "edge":
-		return browser.Version[0] >= 79
-	default:
-		return false
+// setKeepAlive sets the persistent connection headers of HTTP/1.1 browsers: they always send Connection: keep-alive
+// and never a Keep-Alive header, which only HTTP/1.0 clients use
+func setKeepAlive(headers map[string]string) {
+	for k := range headers {
+		switch strings.ToLower(k) {
+		case "connection", "keep-alive", "proxy-connection":
+			delete(headers, k)
+		}
 	}
+	headers["Connection"] = "keep-alive"
 }
 
 // loadHeadersOrder loads the headers order from the headers-order.json file
 func (g *HeaderGenerator) loadHeadersOrder() {
-	data, err := dataFiles.ReadFile("data_points/headers-order.json")
+	data, err := fs.ReadFile(g.data, "headers-order.json")
 	if err != nil {
 		fmt.Printf("Warning: failed to read headers-order.json: %v\n", err)
 		return
@@ -364,7 +660,7 @@ func (g *HeaderGenerator) loadHeadersOrder() {
 
 // loadHeaderNetwork loads the header generator network
 func (g *HeaderGenerator) loadHeaderNetwork() error {
-	network, err := loadNetworkFromZip("header-network-definition.zip")
+	network, err := g.loader.load(headerNetworkFile)
 	if err != nil {
 		return err
 	}
@@ -374,7 +670,7 @@ func (g *HeaderGenerator) loadHeaderNetwork() error {
 
 // loadInputGeneratorNetwork loads the input generator input-network-definition
 func (g *HeaderGenerator) loadInputGeneratorNetwork() error {
-	network, err := loadNetworkFromZip("input-network-definition.zip")
+	network, err := g.loader.load(inputNetworkFile)
 	if err != nil {
 		return err
 	}
@@ -386,7 +682,7 @@ func (g *HeaderGenerator) loadInputGeneratorNetwork() error {
 func (g *HeaderGenerator) loadUniqueBrowsers() {
 	g.uniqueBrowsers = make([]*httpBrowser, 0)
 
-	data, err := dataFiles.ReadFile("data_points/browser-helper-file.json")
+	data, err := fs.ReadFile(g.data, "browser-helper-file.json")
 	if err != nil {
 		fmt.Printf("Warning: failed to read browser-helper-file.json: %v\n", err)
 		return
@@ -401,7 +697,7 @@ func (g *HeaderGenerator) loadUniqueBrowsers() {
 	// Convert browser strings to httpBrowser
 	g.uniqueBrowsers = make([]*httpBrowser, 0, len(browserStrings))
 	for _, browserStr := range browserStrings {
-		if browserStr == missingValueToken {
+		if isMissingValue(browserStr) {
 			continue
 		}
 		browser := g.prepareHttpBrowserObject(browserStr)
@@ -409,11 +705,19 @@ func (g *HeaderGenerator) loadUniqueBrowsers() {
 			g.uniqueBrowsers = append(g.uniqueBrowsers, browser)
 		}
 	}
+
+	// Index browsers by name for constraint preparation
+	g.browsersByName = make(map[Browser][]*httpBrowser)
+	for _, browser := range g.uniqueBrowsers {
+		if browser.Name != nil && len(browser.Version) > 0 {
+			g.browsersByName[Browser(*browser.Name)] = append(g.browsersByName[Browser(*browser.Name)], browser)
+		}
+	}
 }
 
 // prepareHttpBrowserObject extracts structured information about a browser and HTTP version from a string
 func (g *HeaderGenerator) prepareHttpBrowserObject(httpBrowserString string) *httpBrowser {
-	if httpBrowserString == missingValueToken {
+	if isMissingValue(httpBrowserString) {
 		return &httpBrowser{
 			Name:           nil,
 			Version:        []int{},
@@ -434,22 +738,61 @@ func (g *HeaderGenerator) prepareHttpBrowserObject(httpBrowserString string) *ht
 	}
 
 	browserName, versionString := browserParts[0], browserParts[1]
-	versionParts := strings.Split(versionString, ".")
-	version := make([]int, len(versionParts))
-	for i, part := range versionParts {
-		version[i] = atoi(part)
-	}
-
 	return &httpBrowser{
 		Name:           &browserName,
-		Version:        version,
+		Version:        parseVersion(versionString),
 		CompleteString: httpBrowserString,
 		HTTPVersion:    httpVersion,
 	}
 }
 
+// FieldError is a HeaderConstraints field that failed validation. Validation reports every failed field at once,
+// errors.As finds each of them.
+type FieldError struct {
+	// Field is the name of the HeaderConstraints field, e.g. "Browsers"
+	Field string
+	// Values are the rejected values of the field
+	Values []string
+	Err    error
+}
+
+// newFieldError returns the validation error of a field rejecting the values
+func newFieldError[T ~string](field string, err error, values ...T) *FieldError {
+	rejected := make([]string, len(values))
+	for i, value := range values {
+		rejected[i] = string(value)
+	}
+	return &FieldError{Field: field, Values: rejected, Err: err}
+}
+
+// Error implements error
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Field, e.Err)
+}
+
+// Unwrap returns the validation error
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// validateAndMerge returns the supported user values, or the current values when none are valid
+func validateAndMerge[T ~string](field string, userValues, supported, current []T, validationErrors *[]error) []T {
+	if len(userValues) == 0 {
+		return current
+	}
+	valid, invalid := filterValidValues(userValues, supported)
+	if len(invalid) > 0 {
+		err := fmt.Errorf("the following values are not supported: %v", invalid)
+		*validationErrors = append(*validationErrors, newFieldError(field, err, invalid...))
+	}
+	if len(valid) > 0 {
+		return valid
+	}
+	return current
+}
+
 // validateAgainstSupported checks if a value exists in the supported values slice
-func validateAgainstSupported(value string, supported []string) error {
+func validateAgainstSupported[T ~string](value T, supported []T) error {
 	for _, s := range supported {
 		if value == s {
 			return nil
@@ -458,131 +801,37 @@ func validateAgainstSupported(value string, supported []string) error {
 	return fmt.Errorf("value '%s' is not supported", value)
 }
 
-// filterValidValues filters a slice of values to only include those that are in the supported values
-func filterValidValues(values []string, supported []string) ([]string, error) {
-	if len(values) == 0 {
-		return nil, nil
-	}
-
-	var invalidValues []string
-	valid := make([]string, 0, len(values))
+// filterValidValues splits values into the supported and the unsupported ones
+func filterValidValues[T ~string](values []T, supported []T) (valid, invalid []T) {
+	valid = make([]T, 0, len(values))
 	for _, v := range values {
 		if err := validateAgainstSupported(v, supported); err != nil {
-			invalidValues = append(invalidValues, v)
+			invalid = append(invalid, v)
 			continue
 		}
 		valid = append(valid, v)
 	}
-
-	if len(invalidValues) > 0 {
-		return valid, fmt.Errorf("the following values are not supported: %v", invalidValues)
-	}
-	return valid, nil
+	return valid, invalid
 }
 
-// prepareConstraints prepares and validates the input constraints for the input generator network
+// prepareConstraints builds the input network constraints from the merged header constraints
 func (g *HeaderGenerator) prepareConstraints(options HeaderConstraints) (map[string][]string, error) {
-	// Get possible attribute values
-	possibleAttributeValues := g.getPossibleAttributeValues(options)
-
-	// Prepare HTTP version specific values
-	http1Values := make(map[string][]string)
-	http2Values := make(map[string][]string)
-
-	// Always include values for both HTTP versions
-	http1Values["*BROWSER"] = options.Browsers
-	http1Values["*OPERATING_SYSTEM"] = options.OS
-	http1Values["*DEVICE"] = options.Devices
-	http2Values["*BROWSER"] = options.Browsers
-	http2Values["*OPERATING_SYSTEM"] = options.OS
-	http2Values["*DEVICE"] = options.Devices
-
-	// Filter values based on HTTP version
-	constraints := make(map[string][]string)
-	for key, values := range possibleAttributeValues {
-		var filteredValues []string
-		for _, value := range values {
-			var shouldInclude bool
-			if key == "*BROWSER_HTTP" {
-				shouldInclude = g.filterBrowserHTTP(value, http1Values, http2Values)
-				if shouldInclude {
-					filteredValues = append(filteredValues, value)
-				}
-			} else {
-				shouldInclude = g.filterOtherValues(value, http1Values, http2Values, key)
-				if shouldInclude {
-					filteredValues = append(filteredValues, value)
-				}
-			}
-		}
-		if len(filteredValues) > 0 {
-			constraints[key] = filteredValues
-		}
-	}
-
-	return constraints, nil
-}
-
-// filterBrowserHTTP filters the browser HTTP value based on the HTTP/1 and HTTP/2 values
-func (g *HeaderGenerator) filterBrowserHTTP(value string, http1Values, http2Values map[string][]string) bool {
-	parts := strings.Split(value, "|")
-	if len(parts) != 2 {
-		return false
-	}
-	browserString, httpVersion := parts[0], parts[1]
-	browserParts := strings.Split(browserString, "/")
-	if len(browserParts) != 2 {
-		return false
+	constraints := make(map[string][]string, 3)
+	if values := g.browserHTTPValues(options); len(values) > 0 {
+		constraints["*BROWSER_HTTP"] = values
 	}
-	browserName := browserParts[0]
 
-	if httpVersion == "1" {
-		if len(http1Values) == 0 {
-			return true
-		}
-		browsers := http1Values["*BROWSER"]
-		for _, browser := range browsers {
-			if browser == browserName {
-				return true
-			}
-		}
-		return false
-	} else {
-		if len(http2Values) == 0 {
-			return true
-		}
-		browsers := http2Values["*BROWSER"]
-		for _, browser := range browsers {
-			if browser == browserName {
-				return true
-			}
+	// Map public OS and device names to the values recorded in the network
+	if len(options.OS) > 0 {
+		mapped := make([]string, len(options.OS))
+		for i, os := range options.OS {
+			mapped[i] = networkOSValue(os)
 		}
-		return false
-	}
-}
-
-// filterOtherValues filters the other attribute values based on the HTTP/1 and HTTP/2 values
-func (g *HeaderGenerator) filterOtherValues(value string, http1Values, http2Values map[string][]string, key string) bool {
-	// If no values are specified for either HTTP version, include all values
-	if len(http1Values) == 0 && len(http2Values) == 0 {
-		return true
+		constraints["*OPERATING_SYSTEM"] = mapped
 	}
-
-	// Check if the value exists in either HTTP version's allowed values
-	if values, ok := http1Values[key]; ok {
-		for _, v := range values {
-			if v == value {
-				return true
-			}
-		}
+	if len(options.Devices) > 0 {
+		constraints["*DEVICE"] = networkDeviceValues(options.Devices)
 	}
 
-	if values, ok := http2Values[key]; ok {
-		for _, v := range values {
-			if v == value {
-				return true
-			}
-		}
-	}
-	return false
+	return constraints, nil
 }
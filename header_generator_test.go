@@ -0,0 +1,349 @@
+package forgeron
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestBrowserHTTPValues(t *testing.T) {
+	gen, err := NewHeaderGenerator()
+	if err != nil {
+		t.Fatalf("NewHeaderGenerator() error = %v", err)
+	}
+	tests := []struct {
+		name    string
+		options HeaderConstraints
+		accept  func(browser *httpBrowser) bool
+	}{
+		{
+			name:    "browser names",
+			options: HeaderConstraints{Browsers: []Browser{"firefox"}},
+			accept:  func(b *httpBrowser) bool { return *b.Name == "firefox" },
+		},
+		{
+			name:    "browser names and HTTP version",
+			options: HeaderConstraints{Browsers: []Browser{"chrome"}, HTTPVersion: "2"},
+			accept:  func(b *httpBrowser) bool { return *b.Name == "chrome" && b.HTTPVersion == "2" },
+		},
+		{
+			name: "specs limited to browser names",
+			options: HeaderConstraints{
+				Browsers:     []Browser{"chrome"},
+				BrowserSpecs: []*BrowserSpec{{Name: "chrome", MinVersion: 130, MaxVersion: 140}, {Name: "firefox"}},
+			},
+			accept: func(b *httpBrowser) bool { return *b.Name == "chrome" && b.Version[0] >= 130 && b.Version[0] <= 140 },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string]bool)
+			for _, value := range gen.browserHTTPValues(tt.options) {
+				got[value] = true
+			}
+			for _, browser := range gen.uniqueBrowsers {
+				if browser.Name == nil || strings.HasPrefix(browser.CompleteString, "*") {
+					continue
+				}
+				if want := tt.accept(browser); got[browser.CompleteString] != want {
+					t.Errorf("%s included = %v, want %v", browser.CompleteString, got[browser.CompleteString], want)
+				}
+			}
+		})
+	}
+}
+
+func TestOrderFor(t *testing.T) {
+	gen, err := NewHeaderGenerator()
+	if err != nil {
+		t.Fatalf("NewHeaderGenerator() error = %v", err)
+	}
+	tests := []struct {
+		name        string
+		browser     Browser
+		httpVersion HTTPVersion
+		requestType RequestType
+		first       string
+		absent      string
+	}{
+		{name: "chrome h2 navigation", browser: Chrome, httpVersion: HTTP2, requestType: Navigation, first: ":method"},
+		{name: "chrome h1 navigation", browser: Chrome, httpVersion: HTTP1, requestType: Navigation, first: "Host", absent: ":method"},
+		{name: "chrome h2 fetch", browser: Chrome, httpVersion: HTTP2, requestType: Fetch, first: ":method", absent: "upgrade-insecure-requests"},
+		{name: "firefox h2 fetch", browser: Firefox, httpVersion: HTTP2, requestType: Fetch, first: ":method", absent: "sec-fetch-user"},
+		{name: "safari h1 fetch", browser: Safari, httpVersion: HTTP1, requestType: Fetch, first: "Referer", absent: "Upgrade-Insecure-Requests"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := gen.OrderFor(tt.browser, tt.httpVersion, tt.requestType)
+			if len(order) == 0 {
+				t.Fatal("OrderFor() returned no order")
+			}
+			if order[0] != tt.first {
+				t.Errorf("first header = %q, want %q", order[0], tt.first)
+			}
+			if tt.absent != "" && slices.Contains(order, tt.absent) {
+				t.Errorf("order contains %q: %v", tt.absent, order)
+			}
+		})
+	}
+
+	if order := gen.OrderFor("opera", HTTP2, Navigation); order != nil {
+		t.Errorf("OrderFor(opera) = %v, want nil", order)
+	}
+	nav := gen.OrderFor(Chrome, HTTP2, Navigation)
+	fetch := gen.OrderFor(Chrome, HTTP2, Fetch)
+	if slices.Index(fetch, "user-agent") > slices.Index(fetch, "accept") || slices.Index(nav, "user-agent") < slices.Index(nav, "sec-ch-ua-platform") {
+		t.Errorf("chrome fetch order was not taken from headers-order-fetch.json: %v", fetch)
+	}
+}
+
+func TestLoadHeaderOrdersFetchFile(t *testing.T) {
+	g := &HeaderGenerator{
+		data:         fstest.MapFS{},
+		headersOrder: map[string][]string{"chrome": {"Host", "Upgrade-Insecure-Requests", "Accept"}},
+	}
+	if err := g.loadHeaderOrders(); err != nil {
+		t.Fatalf("loadHeaderOrders() error = %v without headers-order-fetch.json", err)
+	}
+	if got, want := g.OrderFor(Chrome, HTTP1, Fetch), []string{"Host", "Accept"}; !slices.Equal(got, want) {
+		t.Errorf("derived fetch order = %v, want %v", got, want)
+	}
+
+	g.data = fstest.MapFS{"headers-order-fetch.json": {Data: []byte("{")}}
+	if err := g.loadHeaderOrders(); err == nil || !strings.Contains(err.Error(), "headers-order-fetch.json") {
+		t.Errorf("loadHeaderOrders() error = %v, want a parse error", err)
+	}
+}
+
+func TestPseudoHeaderOrderMatchesHeaderOrder(t *testing.T) {
+	gen, err := NewHeaderGenerator()
+	if err != nil {
+		t.Fatalf("NewHeaderGenerator() error = %v", err)
+	}
+	tests := []struct {
+		browser   Browser
+		userAgent string
+	}{
+		{Chrome, "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36"},
+		{Edge, "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36 Edg/144.0.0.0"},
+		{Firefox, "Mozilla/5.0 (X11; Linux x86_64; rv:147.0) Gecko/20100101 Firefox/147.0"},
+		{Safari, "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/18.6 Safari/605.1.15"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.browser), func(t *testing.T) {
+			var want []string
+			for _, header := range gen.OrderFor(tt.browser, HTTP2, Navigation) {
+				if strings.HasPrefix(header, ":") {
+					want = append(want, header)
+				}
+			}
+			fp := &Fingerprint{Navigator: NavigatorFingerprint{UserAgent: tt.userAgent}}
+			if got := fp.PseudoHeaderOrder(); !slices.Equal(got, want) {
+				t.Errorf("PseudoHeaderOrder() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestOrderHeaders(t *testing.T) {
+	headers := map[string]string{"Accept": "*/*", "User-Agent": "ua", "X-Custom": "1", "Host": "example.com", "A-Custom": "2"}
+	got := orderHeaders(headers, []string{"host", "user-agent", "accept"})
+	want := []string{"Host", "User-Agent", "Accept", "A-Custom", "X-Custom"}
+	if len(got) != len(want) {
+		t.Fatalf("orderHeaders() = %v, want names %v", got, want)
+	}
+	for i, name := range want {
+		if got[i].Name != name || got[i].Value != headers[name] {
+			t.Errorf("header %d = %+v, want %s: %s", i, got[i], name, headers[name])
+		}
+	}
+	if got.Get("user-agent") != "ua" {
+		t.Errorf("Get(user-agent) = %q, want ua", got.Get("user-agent"))
+	}
+}
+
+func TestGenerateOrderedHeaders(t *testing.T) {
+	gen, err := NewHeaderGenerator()
+	if err != nil {
+		t.Fatalf("NewHeaderGenerator() error = %v", err)
+	}
+	for _, browser := range []Browser{Chrome, Firefox, Safari, Edge} {
+		t.Run(string(browser), func(t *testing.T) {
+			headers, err := gen.GenerateOrderedHeaders(HeaderConstraints{Browsers: []Browser{browser}})
+			if err != nil {
+				t.Fatalf("GenerateOrderedHeaders() error = %v", err)
+			}
+			if len(headers) == 0 {
+				t.Fatal("GenerateOrderedHeaders() returned no headers")
+			}
+			position := make(map[string]int)
+			for i, name := range gen.OrderFor(browser, HTTP2, Navigation) {
+				position[name] = i
+			}
+			last := -1
+			for _, header := range headers {
+				i, ok := position[strings.ToLower(header.Name)]
+				if !ok {
+					continue
+				}
+				if i < last {
+					t.Fatalf("headers out of order at %s: %v", header.Name, headers)
+				}
+				last = i
+			}
+		})
+	}
+	if order := gen.GetOrder("chrome"); len(order) == 0 || order[0] != "Host" {
+		t.Errorf("GetOrder(chrome) = %v", order)
+	}
+}
+
+func TestGenerateHeadersHTTP1(t *testing.T) {
+	gen, err := NewHeaderGenerator()
+	if err != nil {
+		t.Fatalf("NewHeaderGenerator() error = %v", err)
+	}
+	for _, browser := range []Browser{Chrome, Edge, Safari} {
+		t.Run(string(browser), func(t *testing.T) {
+			headers, err := gen.GenerateOrderedHeaders(HeaderConstraints{Browsers: []Browser{browser}, HTTPVersion: HTTP1})
+			if err != nil {
+				t.Fatalf("GenerateOrderedHeaders() error = %v", err)
+			}
+			if headers.Get("User-Agent") == "" {
+				t.Fatalf("no User-Agent in %v", headers)
+			}
+			if got := headers.Get("Connection"); got != "keep-alive" {
+				t.Errorf("Connection = %q, want keep-alive", got)
+			}
+			for _, header := range headers {
+				if strings.EqualFold(header.Name, "keep-alive") {
+					t.Errorf("unexpected Keep-Alive header")
+				}
+				if strings.EqualFold(header.Name, "user-agent") && header.Name != "User-Agent" {
+					t.Errorf("User-Agent sent as %q", header.Name)
+				}
+			}
+			position := make(map[string]int)
+			for i, name := range gen.OrderFor(browser, HTTP1, Navigation) {
+				position[name] = i
+			}
+			last := -1
+			for _, header := range headers {
+				i, ok := position[header.Name]
+				if !ok {
+					continue
+				}
+				if i < last {
+					t.Fatalf("headers out of the HTTP/1 order at %s: %v", header.Name, headers)
+				}
+				last = i
+			}
+		})
+	}
+}
+
+func TestGenerateHeadersHTTP2DropsConnectionHeaders(t *testing.T) {
+	gen, err := NewHeaderGenerator()
+	if err != nil {
+		t.Fatalf("NewHeaderGenerator() error = %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		headers, err := gen.GenerateHeaders(HeaderConstraints{HTTPVersion: HTTP2})
+		if err != nil {
+			t.Fatalf("GenerateHeaders() error = %v", err)
+		}
+		for name := range headers {
+			if slices.Contains(connectionHeaders, strings.ToLower(name)) {
+				t.Fatalf("HTTP/2 headers contain %s", name)
+			}
+		}
+	}
+}
+
+// TestGenerateHeadersHTTP1Fallback checks headers relaxed from HTTP/1 to HTTP/2, Firefox sending no HTTP/1
+// requests in the data, are generated and ordered as HTTP/2 headers
+func TestGenerateHeadersHTTP1Fallback(t *testing.T) {
+	gen, err := NewHeaderGenerator()
+	if err != nil {
+		t.Fatalf("NewHeaderGenerator() error = %v", err)
+	}
+	constraints := HeaderConstraints{Browsers: []Browser{Firefox}, HTTPVersion: HTTP1}
+	for i := 0; i < 10; i++ {
+		result, err := gen.generateHeaders(constraints, nil)
+		if err != nil {
+			t.Fatalf("generateHeaders() error = %v", err)
+		}
+		if result.httpVersion != HTTP2 {
+			t.Fatalf("httpVersion = %q, want 2", result.httpVersion)
+		}
+		if _, ok := result.headers["User-Agent"]; !ok || !strings.Contains(result.headers["User-Agent"], "Firefox") {
+			t.Fatalf("no Firefox User-Agent in %v", result.headers)
+		}
+		for name := range result.headers {
+			if slices.Contains(connectionHeaders, strings.ToLower(name)) {
+				t.Errorf("relaxed headers contain %s", name)
+			}
+			if name != strings.ToLower(name) && name != pascalizeKey(name) {
+				t.Errorf("header %s is neither lowercase nor pascalized", name)
+			}
+		}
+	}
+
+	headers, err := gen.GenerateOrderedHeaders(constraints)
+	if err != nil {
+		t.Fatalf("GenerateOrderedHeaders() error = %v", err)
+	}
+	position := make(map[string]int)
+	for i, name := range gen.OrderFor(Firefox, HTTP2, Navigation) {
+		position[strings.ToLower(name)] = i
+	}
+	last := -1
+	for _, header := range headers {
+		i, ok := position[strings.ToLower(header.Name)]
+		if !ok {
+			continue
+		}
+		if i < last {
+			t.Fatalf("headers out of the HTTP/2 order at %s: %v", header.Name, headers)
+		}
+		last = i
+	}
+}
+
+func TestMergeOptionsFieldErrors(t *testing.T) {
+	gen, err := NewHeaderGenerator()
+	if err != nil {
+		t.Fatalf("NewHeaderGenerator() error = %v", err)
+	}
+	_, err = gen.mergeOptions(HeaderConstraints{
+		Browsers:       []Browser{Chrome, "netscape", "mosaic"},
+		HTTPVersion:    "3",
+		Region:         "not-a-region",
+		RequestContext: "popup",
+	})
+	if err == nil {
+		t.Fatal("mergeOptions() error = nil")
+	}
+
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "Browsers" || !reflect.DeepEqual(fieldErr.Values, []string{"netscape", "mosaic"}) {
+		t.Errorf("first field error = %+v, want the unsupported browsers", fieldErr)
+	}
+	var fields []string
+	for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+		if errors.As(err, &fieldErr) {
+			fields = append(fields, fieldErr.Field)
+		}
+	}
+	if want := []string{"Browsers", "Region", "HTTPVersion", "RequestContext"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("failed fields = %v, want %v", fields, want)
+	}
+
+	// Errors of the headers generation keep the field errors
+	if _, err := gen.GenerateHeaders(HeaderConstraints{OS: []OS{"beos"}}); !errors.As(err, &fieldErr) || fieldErr.Field != "OS" {
+		t.Errorf("GenerateHeaders() error = %v, want an OS field error", err)
+	}
+}
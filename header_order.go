@@ -0,0 +1,230 @@
+package forgeron
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// RequestType is the kind of request headers are generated for
+type RequestType string
+
+const (
+	// Navigation is a top-level document request
+	Navigation RequestType = "navigation"
+	// Fetch is a fetch or XMLHttpRequest call made by a page
+	Fetch RequestType = "fetch"
+)
+
+// HeaderKV is a single header name and value
+type HeaderKV struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// OrderedHeaders are headers in the order a browser sends them
+type OrderedHeaders []HeaderKV
+
+// Get returns the value of the named header ignoring case, or an empty string if missing
+func (h OrderedHeaders) Get(name string) string {
+	for _, header := range h {
+		if strings.EqualFold(header.Name, name) {
+			return header.Value
+		}
+	}
+	return ""
+}
+
+// Map returns the headers as a map, losing their order
+func (h OrderedHeaders) Map() map[string]string {
+	headers := make(map[string]string, len(h))
+	for _, header := range h {
+		headers[header.Name] = header.Value
+	}
+	return headers
+}
+
+// navigationOnlyHeaders are only sent on navigation requests
+var navigationOnlyHeaders = []string{"upgrade-insecure-requests", "sec-fetch-user"}
+
+// headerOrderKey identifies a header order
+type headerOrderKey struct {
+	browser     Browser
+	httpVersion HTTPVersion
+	requestType RequestType
+}
+
+// splitHeadersOrder splits a recorded browser order into its HTTP/1 and HTTP/2 orders.
+// The HTTP/2 order starts with the pseudo-headers.
+func splitHeadersOrder(order []string) (http1, http2 []string) {
+	i := slices.IndexFunc(order, func(header string) bool { return strings.HasPrefix(header, ":") })
+	if i < 0 {
+		return order, nil
+	}
+	return order[:i], order[i:]
+}
+
+// withoutNavigationHeaders returns the order without the navigation only headers
+func withoutNavigationHeaders(order []string) []string {
+	return slices.DeleteFunc(slices.Clone(order), func(header string) bool {
+		return slices.Contains(navigationOnlyHeaders, strings.ToLower(header))
+	})
+}
+
+// dropNavigationHeaders deletes the navigation only headers, which subresource requests do not carry
+func dropNavigationHeaders(headers map[string]string) {
+	for k := range headers {
+		if slices.Contains(navigationOnlyHeaders, strings.ToLower(k)) {
+			delete(headers, k)
+		}
+	}
+}
+
+// loadHeaderOrders builds the header orders per browser, HTTP version and request type.
+// Fetch orders come from headers-order-fetch.json when recorded, and are otherwise derived from the navigation order.
+// A headers-order-fetch.json that cannot be read or parsed is an error.
+func (g *HeaderGenerator) loadHeaderOrders() error {
+	var fetchOrders map[Browser]map[HTTPVersion][]string
+	data, err := fs.ReadFile(g.data, "headers-order-fetch.json")
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		// Without recorded fetch orders, they are derived from the navigation orders
+	case err != nil:
+		return fmt.Errorf("failed to read headers-order-fetch.json: %w", err)
+	default:
+		if err := json.Unmarshal(data, &fetchOrders); err != nil {
+			return fmt.Errorf("failed to parse headers-order-fetch.json: %w", err)
+		}
+	}
+
+	g.headerOrders = make(map[headerOrderKey][]string)
+	for name, order := range g.headersOrder {
+		browser := Browser(name)
+		http1, http2 := splitHeadersOrder(order)
+		for version, navigation := range map[HTTPVersion][]string{HTTP1: http1, HTTP2: http2} {
+			if len(navigation) == 0 {
+				continue
+			}
+			g.headerOrders[headerOrderKey{browser, version, Navigation}] = navigation
+			fetch := fetchOrders[browser][version]
+			if fetch == nil {
+				fetch = withoutNavigationHeaders(navigation)
+			}
+			g.headerOrders[headerOrderKey{browser, version, Fetch}] = fetch
+		}
+	}
+	return nil
+}
+
+// OrderFor returns the header order of a browser for the HTTP version and request type, or nil if unknown.
+// HTTP/2 orders start with the pseudo-headers.
+func (g *HeaderGenerator) OrderFor(browser Browser, httpVersion HTTPVersion, requestType RequestType) []string {
+	return slices.Clone(g.headerOrders[headerOrderKey{browser, httpVersion, requestType}])
+}
+
+// GetOrder returns the recorded header order of a browser, the HTTP/1 headers followed by the HTTP/2 ones,
+// or nil if the browser is unknown
+func (g *HeaderGenerator) GetOrder(browser string) []string {
+	return slices.Clone(g.headersOrder[browser])
+}
+
+// GenerateOrderedHeaders generates headers like GenerateHeaders, sorted in the order the sampled browser sends them
+// for the request type of the request context
+func (g *HeaderGenerator) GenerateOrderedHeaders(options HeaderConstraints) (OrderedHeaders, error) {
+	result, err := g.generateHeaders(options, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Headers relaxed from HTTP/1 to HTTP/2 follow the HTTP/2 order
+	browser := parseUserAgent(headerValue(result.headers, "user-agent")).Browser
+	return orderHeaders(result.headers, g.OrderFor(browser, result.httpVersion, options.RequestContext.requestType())), nil
+}
+
+// embeddedOrders holds the header orders of the embedded data, loaded on first use
+var embeddedOrders struct {
+	once sync.Once
+	gen  *HeaderGenerator
+}
+
+// OrderedHeaders returns the fingerprint headers in the order its browser sends them on navigations, using the
+// header orders of the embedded data. Headers generated for HTTP/1 carry a Connection header, others follow the HTTP/2 order.
+func (f *Fingerprint) OrderedHeaders() OrderedHeaders {
+	return orderHeaders(f.Headers, f.headerOrder())
+}
+
+// headerOrder returns the navigation header order of the browser of the fingerprint, for the HTTP version of its
+// headers
+func (f *Fingerprint) headerOrder() []string {
+	embeddedOrders.once.Do(func() {
+		gen := &HeaderGenerator{data: embeddedData()}
+		gen.loadHeadersOrder()
+		// The embedded data is checked when generators are created, a failure only loses the fetch orders
+		_ = gen.loadHeaderOrders()
+		embeddedOrders.gen = gen
+	})
+	browser := parseUserAgent(f.Navigator.UserAgent).Browser
+	return embeddedOrders.gen.OrderFor(browser, f.httpVersion(), Navigation)
+}
+
+// httpVersion returns the HTTP version of the headers of the fingerprint, only HTTP/1 sends Connection
+func (f *Fingerprint) httpVersion() HTTPVersion {
+	if headerValue(f.Headers, "connection") != "" {
+		return HTTP1
+	}
+	return HTTP2
+}
+
+// orderHeaders sorts headers following order, ignoring case.
+// Headers missing from the order are appended after the ordered ones, sorted by name.
+func orderHeaders(headers map[string]string, order []string) OrderedHeaders {
+	ordered := make(OrderedHeaders, 0, len(headers))
+	seen := make(map[string]bool, len(headers))
+	for _, name := range order {
+		for key, value := range headers {
+			if !seen[key] && strings.EqualFold(key, name) {
+				ordered = append(ordered, HeaderKV{Name: key, Value: value})
+				seen[key] = true
+			}
+		}
+	}
+	rest := len(ordered)
+	for key, value := range headers {
+		if !seen[key] {
+			ordered = append(ordered, HeaderKV{Name: key, Value: value})
+		}
+	}
+	slices.SortFunc(ordered[rest:], func(a, b HeaderKV) int { return strings.Compare(a.Name, b.Name) })
+	return ordered
+}
+
+// headerValue returns the value of the named header ignoring case
+func headerValue(headers map[string]string, name string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
+
+// PseudoHeaderOrder returns the HTTP/2 pseudo-header order sent by the browser of the fingerprint,
+// which is part of the Akamai HTTP/2 fingerprint. Set it on fhttp or h2 clients to match the headers.
+func (f *Fingerprint) PseudoHeaderOrder() []string {
+	return pseudoHeaderOrderFor(f.Navigator.UserAgent)
+}
+
+// pseudoHeaderOrderFor returns the HTTP/2 pseudo-header order sent by the browser of the user agent
+func pseudoHeaderOrderFor(userAgent string) []string {
+	switch engineOf(userAgent) {
+	case geckoEngine:
+		return []string{":method", ":path", ":authority", ":scheme"}
+	case webKitEngine:
+		return []string{":method", ":scheme", ":authority", ":path"}
+	default:
+		return []string{":method", ":authority", ":scheme", ":path"}
+	}
+}
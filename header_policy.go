@@ -0,0 +1,54 @@
+package forgeron
+
+import "strings"
+
+// HeaderPolicy filters the headers recorded in the header network.
+// Patterns are case-insensitive header names, a trailing "*" matches a prefix, e.g. "X-*".
+type HeaderPolicy struct {
+	// Deny lists header patterns removed for every browser
+	Deny []string
+	// DenyByBrowser lists header patterns removed for specific browsers, keyed by browser name
+	DenyByBrowser map[string][]string
+	// Allow lists header patterns kept even when they are denied
+	Allow []string
+}
+
+// DefaultHeaderPolicy drops X- headers, and DNT for Chromium browsers where it is off by default
+var DefaultHeaderPolicy = HeaderPolicy{
+	Deny: []string{"X-*"},
+	DenyByBrowser: map[string][]string{
+		"chrome": {"DNT"},
+		"edge":   {"DNT"},
+	},
+}
+
+// matchesHeaderPattern reports whether a header name matches one of the patterns
+func matchesHeaderPattern(name string, patterns []string) bool {
+	name = strings.ToLower(name)
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		} else if name == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// apply removes the denied headers generated for the given browser
+func (p *HeaderPolicy) apply(headers map[string]string, browser string) {
+	if p == nil {
+		return
+	}
+	for name := range headers {
+		if matchesHeaderPattern(name, p.Allow) {
+			continue
+		}
+		if matchesHeaderPattern(name, p.Deny) || matchesHeaderPattern(name, p.DenyByBrowser[browser]) {
+			delete(headers, name)
+		}
+	}
+}
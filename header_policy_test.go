@@ -0,0 +1,52 @@
+package forgeron
+
+import "testing"
+
+func TestHeaderPolicyApply(t *testing.T) {
+	policy := &HeaderPolicy{
+		Deny:          []string{"X-*"},
+		DenyByBrowser: map[string][]string{"chrome": {"dnt"}},
+		Allow:         []string{"X-Requested-With"},
+	}
+	tests := []struct {
+		browser string
+		header  string
+		kept    bool
+	}{
+		{"chrome", "X-Forwarded-For", false},
+		{"chrome", "x-client-data", false},
+		{"chrome", "X-Requested-With", true},
+		{"chrome", "DNT", false},
+		{"firefox", "DNT", true},
+		{"chrome", "User-Agent", true},
+	}
+	for _, tt := range tests {
+		headers := map[string]string{tt.header: "1"}
+		policy.apply(headers, tt.browser)
+		if _, kept := headers[tt.header]; kept != tt.kept {
+			t.Errorf("%s for %s: kept = %v, want %v", tt.header, tt.browser, kept, tt.kept)
+		}
+	}
+}
+
+func TestGenerateHeadersAppliesPolicy(t *testing.T) {
+	gen, err := NewHeaderGenerator()
+	if err != nil {
+		t.Fatalf("NewHeaderGenerator() error = %v", err)
+	}
+	policy := &HeaderPolicy{Deny: []string{"sec-ch-*", "Upgrade-Insecure-Requests"}}
+	for i := 0; i < 20; i++ {
+		headers, err := gen.GenerateHeaders(HeaderConstraints{Browsers: []Browser{"chrome"}, HeaderPolicy: policy})
+		if err != nil {
+			t.Fatalf("GenerateHeaders() error = %v", err)
+		}
+		for name := range headers {
+			if matchesHeaderPattern(name, policy.Deny) {
+				t.Fatalf("denied header %s was generated", name)
+			}
+		}
+		if headers["User-Agent"] == "" {
+			t.Fatal("missing User-Agent header")
+		}
+	}
+}
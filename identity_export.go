@@ -0,0 +1,68 @@
+package forgeron
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// maxExportLine is the maximum size of an exported fingerprint line
+const maxExportLine = 16 << 20
+
+// ExportNDJSON writes fingerprints as newline delimited JSON, one fingerprint per line.
+// When c is not nil, every line is encrypted and base64 encoded.
+func ExportNDJSON(w io.Writer, fingerprints []*Fingerprint, c *Cipher) error {
+	bw := bufio.NewWriter(w)
+	for i, fp := range fingerprints {
+		line, err := json.Marshal(fp)
+		if err != nil {
+			return fmt.Errorf("failed to encode fingerprint %d: %w", i, err)
+		}
+		if c != nil {
+			sealed, err := c.Encrypt(line)
+			if err != nil {
+				return err
+			}
+			line = []byte(base64.StdEncoding.EncodeToString(sealed))
+		}
+		if _, err := bw.Write(append(line, '\n')); err != nil {
+			return fmt.Errorf("failed to write fingerprint %d: %w", i, err)
+		}
+	}
+	return bw.Flush()
+}
+
+// ImportNDJSON reads fingerprints written by ExportNDJSON, c must be the cipher they were exported with, or nil.
+// Blank lines are skipped, inconsistent fingerprints fail with ErrInvalidFingerprint.
+func ImportNDJSON(r io.Reader, c *Cipher) ([]*Fingerprint, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxExportLine)
+	var fingerprints []*Fingerprint
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if c != nil {
+			sealed, err := base64.StdEncoding.DecodeString(string(line))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w: invalid base64", lineNumber, ErrDecrypt)
+			}
+			if line, err = c.Decrypt(sealed); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+		}
+		fp, err := UnmarshalFingerprint(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		fingerprints = append(fingerprints, fp)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read fingerprints: %w", err)
+	}
+	return fingerprints, nil
+}
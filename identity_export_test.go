@@ -0,0 +1,49 @@
+package forgeron
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestExportImportNDJSON(t *testing.T) {
+	fps := []*Fingerprint{
+		{Navigator: NavigatorFingerprint{UserAgent: "first", HardwareConcurrency: 4, Language: "en-US", Languages: []string{"en-US"}}},
+		{Navigator: NavigatorFingerprint{UserAgent: "second", HardwareConcurrency: 8, Language: "de-DE", Languages: []string{"de-DE"}}},
+	}
+	c, err := NewCipher(bytes.Repeat([]byte{3}, 32))
+	if err != nil {
+		t.Fatalf("NewCipher() error = %v", err)
+	}
+	for name, cipher := range map[string]*Cipher{"plain": nil, "encrypted": c} {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := ExportNDJSON(&buf, fps, cipher); err != nil {
+				t.Fatalf("ExportNDJSON() error = %v", err)
+			}
+			if lines := strings.Count(buf.String(), "\n"); lines != len(fps) {
+				t.Errorf("exported %d lines, want %d", lines, len(fps))
+			}
+			if encrypted := !strings.Contains(buf.String(), "first"); encrypted != (cipher != nil) {
+				t.Errorf("export encrypted = %v, want %v", encrypted, cipher != nil)
+			}
+			got, err := ImportNDJSON(&buf, cipher)
+			if err != nil {
+				t.Fatalf("ImportNDJSON() error = %v", err)
+			}
+			if len(got) != len(fps) || got[1].Navigator.UserAgent != "second" || got[1].Navigator.HardwareConcurrency != 8 {
+				t.Errorf("ImportNDJSON() = %+v", got)
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := ExportNDJSON(&buf, fps, c); err != nil {
+		t.Fatalf("ExportNDJSON() error = %v", err)
+	}
+	other, _ := NewCipher(bytes.Repeat([]byte{4}, 32))
+	if _, err := ImportNDJSON(&buf, other); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("ImportNDJSON() with another key error = %v, want ErrDecrypt", err)
+	}
+}
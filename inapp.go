@@ -0,0 +1,194 @@
+package forgeron
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// InAppBrowser identifies a social app whose in-app browser decorates the user agent
+type InAppBrowser string
+
+const (
+	Instagram InAppBrowser = "instagram"
+	Facebook  InAppBrowser = "facebook"
+	TikTok    InAppBrowser = "tiktok"
+)
+
+// inAppApp describes the tokens an app adds to the user agent of its in-app browser
+type inAppApp struct {
+	androidPackage string
+	version        string
+	buildNumber    string
+}
+
+// inAppApps lists the supported in-app browsers
+var inAppApps = map[InAppBrowser]inAppApp{
+	Instagram: {androidPackage: "com.instagram.android", version: "360.0.0.30.109", buildNumber: "672535977"},
+	Facebook:  {androidPackage: "com.facebook.katana", version: "495.0.0.45.201", buildNumber: "680240377"},
+	TikTok:    {androidPackage: "com.zhiliaoapp.musically", version: "38.4.0", buildNumber: "380400"},
+}
+
+// iPhoneModels maps CSS screen sizes to the iPhone model identifiers reported by in-app browsers
+var iPhoneModels = map[[2]int]string{
+	{375, 667}: "iPhone12,8",
+	{375, 812}: "iPhone12,1",
+	{414, 896}: "iPhone11,8",
+	{390, 844}: "iPhone14,5",
+	{428, 926}: "iPhone14,3",
+	{393, 852}: "iPhone15,4",
+	{430, 932}: "iPhone15,5",
+	{402, 874}: "iPhone17,3",
+	{440, 956}: "iPhone17,2",
+}
+
+// WithInAppBrowser decorates mobile fingerprints as the in-app browser of a social app
+func WithInAppBrowser(app InAppBrowser) FingerprintOption {
+	return func(g *FingerprintGenerator) {
+		g.inApp = app
+	}
+}
+
+// inAppHosts are the browsers whose WebView hosts in-app browsers on each OS
+var inAppHosts = map[OS]Browser{Android: Chrome, IOS: Safari}
+
+// inAppHeaderConstraints narrows the header constraints to mobile identities hosting in-app browsers: Chrome on
+// Android and Safari on iOS. Requesting another OS or browser is an error.
+func inAppHeaderConstraints(constraints HeaderConstraints, app InAppBrowser) (HeaderConstraints, error) {
+	if app == "" {
+		return constraints, nil
+	}
+	if _, ok := inAppApps[app]; !ok {
+		return constraints, fmt.Errorf("in-app browser '%s' is not supported", app)
+	}
+	if len(constraints.OS) == 0 {
+		constraints.OS = []OS{Android, IOS}
+	}
+	var hosts []Browser
+	for _, os := range constraints.OS {
+		host, ok := inAppHosts[os]
+		if !ok {
+			return constraints, fmt.Errorf("in-app browser '%s' does not run on %s", app, os)
+		}
+		hosts = append(hosts, host)
+	}
+	requested := slices.Clone(constraints.Browsers)
+	for _, spec := range constraints.BrowserSpecs {
+		requested = append(requested, spec.Name)
+	}
+	for _, browser := range requested {
+		if !slices.Contains(hosts, browser) {
+			return constraints, fmt.Errorf("in-app browser '%s' is hosted by %v, not %s", app, hosts, browser)
+		}
+	}
+	if len(constraints.Browsers) == 0 {
+		constraints.Browsers = hosts
+	}
+	constraints.Devices = []Device{Mobile}
+	return constraints, nil
+}
+
+// applyInAppBrowser rewrites a mobile fingerprint into the in-app browser of a social app
+func applyInAppBrowser(fp *Fingerprint, app InAppBrowser) {
+	details, ok := inAppApps[app]
+	if !ok {
+		return
+	}
+	locale := strings.ReplaceAll(fp.Navigator.Language, "-", "_")
+	language, _, _ := strings.Cut(fp.Navigator.Language, "-")
+	width, height := fp.Screen.Width, fp.Screen.Height
+
+	switch {
+	case strings.Contains(fp.Navigator.UserAgent, "Android"):
+		applyEmbeddedProfile(fp, &EmbeddedOptions{Profile: AndroidWebView})
+		// Android WebViews identify the hosting app through X-Requested-With
+		fp.setHeader("X-Requested-With", details.androidPackage)
+		dpi := int(fp.Screen.DevicePixelRatio * 160)
+		pixels := fmt.Sprintf("%dx%d", int(float64(width)*fp.Screen.DevicePixelRatio), int(float64(height)*fp.Screen.DevicePixelRatio))
+		var suffix string
+		switch app {
+		case Instagram:
+			release := androidRelease(fp.Navigator.UserAgent)
+			suffix = fmt.Sprintf(" Instagram %s Android (%d/%s; %ddpi; %s; %s; %s)", details.version, androidAPILevel(release), release, dpi, pixels, locale, details.buildNumber)
+		case Facebook:
+			suffix = fmt.Sprintf(" [FB_IAB/FB4A;FBAV/%s;]", details.version)
+		case TikTok:
+			suffix = fmt.Sprintf(" trill_%s JsSdk/1.0 NetType/WIFI Channel/googleplay AppName/musical_ly app_version/%s ByteLocale/%s Region/%s",
+				details.buildNumber, details.version, language, regionOf(fp.Navigator.Language))
+		}
+		rewriteUserAgent(fp, fp.Navigator.UserAgent+suffix)
+	case strings.Contains(fp.Navigator.UserAgent, "iPhone") || strings.Contains(fp.Navigator.UserAgent, "iPad"):
+		applyEmbeddedProfile(fp, &EmbeddedOptions{Profile: IOSWebView})
+		model := iPhoneModels[[2]int{width, height}]
+		if model == "" {
+			model = "iPhone14,5"
+		}
+		osVersion := iOSVersion(fp.Navigator.UserAgent)
+		var suffix string
+		switch app {
+		case Instagram:
+			suffix = fmt.Sprintf(" Instagram %s (%s; iOS %s; %s; %s; scale=%.2f; %dx%d; %s)", details.version, model, osVersion, locale, language,
+				fp.Screen.DevicePixelRatio, int(float64(width)*fp.Screen.DevicePixelRatio), int(float64(height)*fp.Screen.DevicePixelRatio), details.buildNumber)
+		case Facebook:
+			suffix = fmt.Sprintf(" [FBAN/FBIOS;FBAV/%s;FBBV/%s;FBDV/%s;FBMD/iPhone;FBSN/iOS;FBSV/%s;FBSS/%.0f;FBID/phone;FBLC/%s;FBOP/5]",
+				details.version, details.buildNumber, model, strings.ReplaceAll(osVersion, "_", "."), fp.Screen.DevicePixelRatio, locale)
+		case TikTok:
+			suffix = fmt.Sprintf(" musical_ly_%s JsSdk/2.0 NetType/WIFI Channel/App Store ByteLocale/%s Region/%s",
+				details.version, language, regionOf(fp.Navigator.Language))
+		}
+		rewriteUserAgent(fp, fp.Navigator.UserAgent+suffix)
+	}
+}
+
+// androidAPILevels maps Android releases to their API levels
+var androidAPILevels = map[string]int{
+	"5": 21, "5.1": 22, "6": 23, "7": 24, "7.1": 25, "8": 26, "8.1": 27,
+	"9": 28, "10": 29, "11": 30, "12": 31, "13": 33, "14": 34, "15": 35, "16": 36,
+}
+
+// androidRelease extracts the Android release of a user agent, e.g. "10" or "8.1.0", defaulting to 10 which
+// reduced user agents report
+func androidRelease(userAgent string) string {
+	_, rest, ok := strings.Cut(userAgent, "Android ")
+	if !ok {
+		return "10"
+	}
+	release, _, _ := strings.Cut(rest, ";")
+	release, _, _ = strings.Cut(release, ")")
+	if release = strings.TrimSpace(release); release == "" {
+		return "10"
+	}
+	return release
+}
+
+// androidAPILevel returns the API level of an Android release, matching the major and minor versions first
+func androidAPILevel(release string) int {
+	parts := strings.SplitN(release, ".", 3)
+	if len(parts) > 1 && parts[1] != "0" {
+		if level, ok := androidAPILevels[parts[0]+"."+parts[1]]; ok {
+			return level
+		}
+	}
+	if level, ok := androidAPILevels[parts[0]]; ok {
+		return level
+	}
+	return androidAPILevels["10"]
+}
+
+// iOSVersion extracts the underscore separated iOS version from a user agent
+func iOSVersion(userAgent string) string {
+	_, rest, ok := strings.Cut(userAgent, " OS ")
+	if !ok {
+		return ""
+	}
+	version, _, _ := strings.Cut(rest, " ")
+	return version
+}
+
+// regionOf returns the region of a locale, defaulting to US
+func regionOf(locale string) string {
+	if _, region, ok := strings.Cut(locale, "-"); ok && region != "" {
+		return strings.ToUpper(region)
+	}
+	return "US"
+}
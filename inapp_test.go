@@ -0,0 +1,124 @@
+package forgeron
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplyInAppBrowser(t *testing.T) {
+	tests := []struct {
+		name        string
+		app         InAppBrowser
+		userAgent   string
+		wantSuffix  string
+		wantPackage string
+	}{
+		{
+			name:       "instagram ios",
+			app:        Instagram,
+			userAgent:  "Mozilla/5.0 (iPhone; CPU iPhone OS 18_7 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/26.2 Mobile/15E148 Safari/604.1",
+			wantSuffix: "Mobile/15E148 Instagram 360.0.0.30.109 (iPhone14,5; iOS 18_7; en_US; en; scale=3.00; 1170x2532; 672535977)",
+		},
+		{
+			name:        "instagram android",
+			app:         Instagram,
+			userAgent:   "Mozilla/5.0 (Linux; Android 8.1.0; SM-J530F) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Mobile Safari/537.36",
+			wantSuffix:  "Mobile Safari/537.36 Instagram 360.0.0.30.109 Android (27/8.1.0; 480dpi; 1170x2532; en_US; 672535977)",
+			wantPackage: "com.instagram.android",
+		},
+		{
+			name:        "instagram android reduced",
+			app:         Instagram,
+			userAgent:   "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Mobile Safari/537.36",
+			wantSuffix:  "Instagram 360.0.0.30.109 Android (29/10; 480dpi; 1170x2532; en_US; 672535977)",
+			wantPackage: "com.instagram.android",
+		},
+		{
+			name:        "facebook android",
+			app:         Facebook,
+			userAgent:   "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Mobile Safari/537.36",
+			wantSuffix:  "Mobile Safari/537.36 [FB_IAB/FB4A;FBAV/495.0.0.45.201;]",
+			wantPackage: "com.facebook.katana",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fp := &Fingerprint{
+				Screen:    ScreenFingerprint{Width: 390, Height: 844, DevicePixelRatio: 3},
+				Navigator: NavigatorFingerprint{UserAgent: tt.userAgent, Language: "en-US"},
+				Headers:   map[string]string{"User-Agent": tt.userAgent},
+			}
+			applyInAppBrowser(fp, tt.app)
+			if !strings.HasSuffix(fp.Navigator.UserAgent, tt.wantSuffix) {
+				t.Errorf("UserAgent = %q, want suffix %q", fp.Navigator.UserAgent, tt.wantSuffix)
+			}
+			if fp.Headers["User-Agent"] != fp.Navigator.UserAgent {
+				t.Errorf("User-Agent header %q does not match navigator", fp.Headers["User-Agent"])
+			}
+			if got := headerValue(fp.Headers, "x-requested-with"); got != tt.wantPackage {
+				t.Errorf("X-Requested-With = %q, want %q", got, tt.wantPackage)
+			}
+		})
+	}
+}
+
+func TestApplyInAppBrowserHeaderCase(t *testing.T) {
+	userAgent := "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Mobile Safari/537.36"
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    string
+	}{
+		{"http2", map[string]string{"user-agent": userAgent}, "x-requested-with"},
+		{"http1", map[string]string{"User-Agent": userAgent, "Connection": "keep-alive"}, "X-Requested-With"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fp := &Fingerprint{
+				Screen:    ScreenFingerprint{Width: 390, Height: 844, DevicePixelRatio: 3},
+				Navigator: NavigatorFingerprint{UserAgent: userAgent, Language: "en-US"},
+				Headers:   tt.headers,
+			}
+			applyInAppBrowser(fp, Instagram)
+			if _, ok := fp.Headers[tt.want]; !ok {
+				t.Errorf("headers = %v, want %s", fp.Headers, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateInAppBrowserHosts(t *testing.T) {
+	gen := newGeneratorOrFatal(t, WithInAppBrowser(Instagram))
+	for range 50 {
+		fp, err := gen.Generate()
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		userAgent := fp.Navigator.UserAgent
+		hosted := strings.Contains(userAgent, "Android") && parseUserAgent(userAgent).Browser == Chrome || strings.Contains(userAgent, "iPhone")
+		for _, token := range []string{"Firefox", "FxiOS", "EdgA", "EdgiOS", "CriOS", "OPR", "SamsungBrowser"} {
+			hosted = hosted && !strings.Contains(userAgent, token)
+		}
+		if !hosted {
+			t.Fatalf("in-app browser not hosted by Chrome on Android or Safari on iOS: %s", userAgent)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		constraints HeaderConstraints
+	}{
+		{"firefox", HeaderConstraints{Browsers: []Browser{Firefox}}},
+		{"edge", HeaderConstraints{Browsers: []Browser{Chrome, Edge}}},
+		{"firefox spec", HeaderConstraints{BrowserSpecs: []*BrowserSpec{{Name: Firefox, MinVersion: 140}}}},
+		{"safari on android", HeaderConstraints{Browsers: []Browser{Safari}, OS: []OS{Android}}},
+		{"windows", HeaderConstraints{OS: []OS{Windows}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := gen.Generate(WithConstraints(Constraints{HeaderConstraints: tt.constraints})); err == nil {
+				t.Error("Generate() error = nil, want the browser refused")
+			}
+		})
+	}
+}
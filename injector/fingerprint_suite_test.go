@@ -0,0 +1,122 @@
+package injector
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ta0uf19/forgeron"
+)
+
+// suiteSample is a fingerprint and its headers as returned by getFingerprint of fingerprint-suite
+type suiteSample struct {
+	Fingerprint map[string]any    `json:"fingerprint"`
+	Headers     map[string]string `json:"headers"`
+}
+
+// TestFingerprintSuiteCorpus verifies fingerprints generated by fingerprint-suite parse and validate, and reach the
+// injected script without losing or changing any upstream value. The corpus is written by
+// testdata/fingerprint-suite/generate.mjs.
+func TestFingerprintSuiteCorpus(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "fingerprint-suite", "*.json"))
+	if err != nil {
+		t.Fatalf("failed to list corpus: %v", err)
+	}
+	if len(files) == 0 {
+		// CI must check the interop, local runs without the corpus only skip it
+		if os.Getenv("CI") != "" {
+			t.Fatal("no fingerprint-suite corpus, run testdata/fingerprint-suite/generate.mjs and commit its output")
+		}
+		t.Skip("no fingerprint-suite corpus, run testdata/fingerprint-suite/generate.mjs")
+	}
+	prefix, suffix, _ := strings.Cut(template, fingerprintPlaceholder)
+	for _, file := range files {
+		t.Run(strings.TrimSuffix(filepath.Base(file), ".json"), func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatalf("failed to read corpus: %v", err)
+			}
+			var sample suiteSample
+			if err := json.Unmarshal(data, &sample); err != nil {
+				t.Fatalf("failed to decode corpus: %v", err)
+			}
+			upstream := make(map[string]any, len(sample.Fingerprint)+1)
+			for key, value := range sample.Fingerprint {
+				upstream[key] = value
+			}
+			upstream["headers"] = sample.Headers
+			raw, err := json.Marshal(upstream)
+			if err != nil {
+				t.Fatalf("failed to encode corpus: %v", err)
+			}
+
+			fp, err := forgeron.UnmarshalFingerprint(raw)
+			if err != nil {
+				t.Fatalf("UnmarshalFingerprint() error = %v", err)
+			}
+			script, err := Script(fp)
+			if err != nil {
+				t.Fatalf("Script() error = %v", err)
+			}
+			payload := strings.TrimSuffix(strings.TrimPrefix(script, prefix), suffix)
+			var injected map[string]any
+			if err := json.Unmarshal([]byte(payload), &injected); err != nil {
+				t.Fatalf("failed to decode injected fingerprint: %v", err)
+			}
+			for _, diff := range jsonDiff("", toJSONValue(t, upstream), injected) {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+// toJSONValue returns v as decoded by encoding/json, so it compares with decoded values
+func toJSONValue(t *testing.T, v any) any {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("failed to encode value: %v", err)
+	}
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to decode value: %v", err)
+	}
+	return decoded
+}
+
+// jsonDiff returns the paths where got loses or changes a value of want. Fields only in got are ignored, and null
+// upstream values may be omitted.
+func jsonDiff(path string, want, got any) []string {
+	if wantObject, ok := want.(map[string]any); ok {
+		gotObject, ok := got.(map[string]any)
+		if !ok {
+			return []string{path + ": got " + describe(got) + ", want an object"}
+		}
+		var diffs []string
+		for key, value := range wantObject {
+			gotValue, present := gotObject[key]
+			if !present && value == nil {
+				continue
+			}
+			if !present {
+				diffs = append(diffs, path+"."+key+": missing")
+				continue
+			}
+			diffs = append(diffs, jsonDiff(path+"."+key, value, gotValue)...)
+		}
+		return diffs
+	}
+	if !reflect.DeepEqual(want, got) {
+		return []string{path + ": got " + describe(got) + ", want " + describe(want)}
+	}
+	return nil
+}
+
+// describe returns v as compact JSON for diff messages
+func describe(v any) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -0,0 +1,30 @@
+package injector
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/ta0uf19/forgeron"
+)
+
+// browserManagedHeaders are set by the browser for each request or derived from the user agent override,
+// sending the fingerprint values with every request would make them inconsistent
+var browserManagedHeaders = []string{
+	"accept", "accept-encoding", "connection", "content-length", "content-type", "cookie", "host", "origin",
+	"priority", "referer", "te", "upgrade-insecure-requests", "user-agent",
+}
+
+// ExtraHeaders returns the fingerprint headers to send with every request of a page, e.g. with
+// Network.setExtraHTTPHeaders. Headers the browser sets per request, the Sec-Fetch metadata and the
+// client hints derived from the user agent override are left out, Sec-GPC is kept.
+func ExtraHeaders(fp *forgeron.Fingerprint) map[string]string {
+	headers := make(map[string]string)
+	for name, value := range fp.Headers {
+		key := strings.ToLower(name)
+		if slices.Contains(browserManagedHeaders, key) || (strings.HasPrefix(key, "sec-") && key != "sec-gpc") {
+			continue
+		}
+		headers[name] = value
+	}
+	return headers
+}
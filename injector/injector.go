@@ -0,0 +1,36 @@
+// Package injector turns a forgeron fingerprint into the JavaScript applying it to a page.
+//
+// The script overrides the navigator, client hints, screen, default Intl locale and time zone,
+// Date.prototype.getTimezoneOffset, geolocation, WebGL vendor and renderer, battery, plugins, media codecs and media
+// devices with the fingerprint values. Fingerprints of the high realism level also get seeded canvas and audio noise
+// and a coarsened performance.now(). It must run before any page script, e.g. with
+// Page.addScriptToEvaluateOnNewDocument in Chromium based automation.
+package injector
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ta0uf19/forgeron"
+)
+
+//go:embed injector.js
+var template string
+
+// fingerprintPlaceholder is replaced with the fingerprint JSON in the template
+const fingerprintPlaceholder = "__FINGERPRINT__"
+
+// Script returns the script overriding the page properties with the fingerprint
+func Script(fp *forgeron.Fingerprint) (string, error) {
+	if fp == nil {
+		return "", fmt.Errorf("fingerprint is required")
+	}
+	data, err := json.Marshal(fp)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode fingerprint: %w", err)
+	}
+	// JSON is a valid JavaScript expression, U+2028 and U+2029 are escaped by encoding/json
+	return strings.Replace(template, fingerprintPlaceholder, string(data), 1), nil
+}
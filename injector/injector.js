@@ -0,0 +1,368 @@
+(() => {
+	const fp = __FINGERPRINT__;
+
+	// Patched functions print like native ones
+	const nativeNames = new WeakMap();
+	const originalToString = Function.prototype.toString;
+	const patchedToString = function toString() {
+		if (nativeNames.has(this)) {
+			return `function ${nativeNames.get(this)}() { [native code] }`;
+		}
+		return originalToString.call(this);
+	};
+	nativeNames.set(patchedToString, 'toString');
+	Function.prototype.toString = patchedToString;
+	const native = (fn, name) => {
+		nativeNames.set(fn, name);
+		return fn;
+	};
+
+	const defineGetters = (target, values) => {
+		for (const [key, value] of Object.entries(values)) {
+			if (value === null || value === undefined) continue;
+			const descriptor = Object.getOwnPropertyDescriptor(target, key) || {};
+			Object.defineProperty(target, key, {
+				get: native(() => value, `get ${key}`),
+				set: descriptor.set,
+				enumerable: descriptor.enumerable !== undefined ? descriptor.enumerable : true,
+				configurable: true,
+			});
+		}
+	};
+
+	const nav = fp.navigator;
+	defineGetters(Navigator.prototype, {
+		userAgent: nav.userAgent,
+		appCodeName: nav.appCodeName,
+		appName: nav.appName,
+		appVersion: nav.appVersion,
+		platform: nav.platform,
+		product: nav.product,
+		productSub: nav.productSub,
+		vendor: nav.vendor,
+		vendorSub: nav.vendorSub,
+		language: nav.language,
+		languages: nav.languages ? Object.freeze([...nav.languages]) : null,
+		hardwareConcurrency: nav.hardwareConcurrency,
+		deviceMemory: 'deviceMemory' in Navigator.prototype ? nav.deviceMemory : null,
+		maxTouchPoints: nav.maxTouchPoints,
+		oscpu: 'oscpu' in Navigator.prototype ? nav.oscpu || null : null,
+		doNotTrack: nav.doNotTrack,
+		globalPrivacyControl: 'globalPrivacyControl' in Navigator.prototype ? nav.globalPrivacyControl : null,
+		webdriver: false,
+	});
+
+	const zone = fp.timezone;
+	if (fp.locale || zone) {
+		// Intl formats with the locale and DateTimeFormat in the time zone of the fingerprint when none is given
+		for (const name of ['Collator', 'DateTimeFormat', 'ListFormat', 'NumberFormat', 'PluralRules', 'RelativeTimeFormat']) {
+			const Original = Intl[name];
+			if (!Original) continue;
+			const zoned = zone && name === 'DateTimeFormat';
+			const patched = native(function (locales, options) {
+				if (locales === undefined && fp.locale) locales = fp.locale;
+				if (zoned && options === undefined) {
+					options = { timeZone: zone.name };
+				} else if (zoned && options !== null && typeof options === 'object' && options.timeZone === undefined) {
+					options = { __proto__: options, timeZone: zone.name };
+				}
+				const args = [locales, options];
+				return new.target ? Reflect.construct(Original, args, new.target) : Original(...args);
+			}, name);
+			Object.defineProperty(patched, 'name', { value: name });
+			patched.prototype = Original.prototype;
+			patched.supportedLocalesOf = Original.supportedLocalesOf;
+			Object.defineProperty(Original.prototype, 'constructor', { value: patched, writable: true, configurable: true });
+			Intl[name] = patched;
+		}
+	}
+
+	if (zone) {
+		// The offset at a time is the one of the last transition before it
+		const transitions = zone.transitions || [];
+		const offsetAt = (time) => {
+			if (transitions.length === 0) return zone.offset;
+			let low = 0;
+			let high = transitions.length - 1;
+			while (low < high) {
+				const mid = (low + high + 1) >> 1;
+				if (transitions[mid].at <= time) low = mid;
+				else high = mid - 1;
+			}
+			return transitions[low].offset;
+		};
+		const getTime = Date.prototype.getTime;
+		Date.prototype.getTimezoneOffset = native(function getTimezoneOffset() {
+			const time = getTime.call(this);
+			return Number.isNaN(time) ? NaN : offsetAt(time);
+		}, 'getTimezoneOffset');
+	}
+
+	if (nav.userAgentData && 'userAgentData' in Navigator.prototype) {
+		const data = nav.userAgentData;
+		const brands = Object.freeze(data.brands.map((brand) => Object.freeze({ ...brand })));
+		const userAgentData = Object.create(NavigatorUAData.prototype);
+		defineGetters(NavigatorUAData.prototype, { brands, mobile: data.mobile, platform: data.platform });
+		const highEntropy = {
+			architecture: data.architecture,
+			bitness: data.bitness,
+			model: data.model,
+			platformVersion: data.platformVersion,
+			uaFullVersion: data.uaFullVersion,
+			fullVersionList: data.fullVersionList,
+			wow64: false,
+		};
+		NavigatorUAData.prototype.getHighEntropyValues = native(async function getHighEntropyValues(hints) {
+			const values = { brands, mobile: data.mobile, platform: data.platform };
+			for (const hint of hints || []) {
+				if (hint in highEntropy) values[hint] = highEntropy[hint];
+			}
+			return values;
+		}, 'getHighEntropyValues');
+		NavigatorUAData.prototype.toJSON = native(function toJSON() {
+			return { brands, mobile: data.mobile, platform: data.platform };
+		}, 'toJSON');
+		defineGetters(Navigator.prototype, { userAgentData });
+	}
+
+	const s = fp.screen;
+	defineGetters(Screen.prototype, {
+		width: s.width,
+		height: s.height,
+		availWidth: s.availWidth,
+		availHeight: s.availHeight,
+		availTop: s.availTop,
+		availLeft: s.availLeft,
+		colorDepth: s.colorDepth,
+		pixelDepth: s.pixelDepth,
+	});
+	defineGetters(window, {
+		devicePixelRatio: s.devicePixelRatio,
+		outerWidth: s.outerWidth,
+		outerHeight: s.outerHeight,
+		screenX: s.screenX,
+		screenLeft: s.screenX,
+	});
+
+	if (fp.videoCard) {
+		// UNMASKED_VENDOR_WEBGL and UNMASKED_RENDERER_WEBGL of WEBGL_debug_renderer_info
+		const contexts = [window.WebGLRenderingContext, window.WebGL2RenderingContext].filter(Boolean);
+		for (const context of contexts) {
+			const originalGetParameter = context.prototype.getParameter;
+			context.prototype.getParameter = native(function getParameter(parameter) {
+				if (parameter === 37445) return fp.videoCard.vendor;
+				if (parameter === 37446) return fp.videoCard.renderer;
+				return originalGetParameter.call(this, parameter);
+			}, 'getParameter');
+		}
+	}
+
+	if (fp.battery && Navigator.prototype.getBattery) {
+		const b = fp.battery;
+		const battery = {
+			charging: b.charging,
+			chargingTime: b.chargingTime === null ? Infinity : b.chargingTime,
+			dischargingTime: b.dischargingTime === null ? Infinity : b.dischargingTime,
+			level: b.level,
+			onchargingchange: null,
+			onchargingtimechange: null,
+			ondischargingtimechange: null,
+			onlevelchange: null,
+			addEventListener: native(function addEventListener() {}, 'addEventListener'),
+			removeEventListener: native(function removeEventListener() {}, 'removeEventListener'),
+			dispatchEvent: native(function dispatchEvent() { return true; }, 'dispatchEvent'),
+		};
+		if (window.BatteryManager) Object.setPrototypeOf(battery, BatteryManager.prototype);
+		Navigator.prototype.getBattery = native(async function getBattery() {
+			return battery;
+		}, 'getBattery');
+	}
+
+	if (fp.pluginsData && fp.pluginsData.plugins && fp.pluginsData.plugins.length > 0) {
+		const mimeTypes = [];
+		const plugins = fp.pluginsData.plugins.map((data) => {
+			const plugin = Object.create(Plugin.prototype);
+			const types = (data.mimeTypes || []).map((mime) => {
+				const mimeType = Object.create(MimeType.prototype);
+				defineGetters(mimeType, { type: mime.type, suffixes: mime.suffixes, description: mime.description, enabledPlugin: plugin });
+				// Plugins share the PDF mime types, navigator.mimeTypes lists each once
+				if (!mimeTypes.some((existing) => existing.type === mime.type)) mimeTypes.push(mimeType);
+				return mimeType;
+			});
+			defineGetters(plugin, { name: data.name, description: data.description, filename: data.filename, length: types.length });
+			types.forEach((mimeType, i) => defineGetters(plugin, { [i]: mimeType }));
+			return plugin;
+		});
+		const makeArray = (items, proto, key) => {
+			const array = Object.create(proto);
+			items.forEach((item, i) => defineGetters(array, { [i]: item }));
+			defineGetters(array, { length: items.length });
+			array.item = native(function item(i) { return items[i] || null; }, 'item');
+			array.namedItem = native(function namedItem(name) {
+				return items.find((item) => item[key] === name) || null;
+			}, 'namedItem');
+			array[Symbol.iterator] = native(function* values() { yield* items; }, 'values');
+			return array;
+		};
+		const pluginArray = makeArray(plugins, PluginArray.prototype, 'name');
+		pluginArray.refresh = native(function refresh() {}, 'refresh');
+		const mimeTypeArray = makeArray(mimeTypes, MimeTypeArray.prototype, 'type');
+		defineGetters(Navigator.prototype, { plugins: pluginArray, mimeTypes: mimeTypeArray });
+	}
+
+	if (fp.geolocation && window.Geolocation) {
+		// navigator.geolocation reports the position of the fingerprint, as once the permission is granted
+		const g = fp.geolocation;
+		const defineValues = (target, values) => {
+			for (const [key, value] of Object.entries(values)) {
+				Object.defineProperty(target, key, { get: native(() => value, `get ${key}`), enumerable: true, configurable: true });
+			}
+			target.toJSON = native(function toJSON() { return { ...values }; }, 'toJSON');
+			return target;
+		};
+		const makePosition = () => {
+			const coords = defineValues(Object.create(window.GeolocationCoordinates ? GeolocationCoordinates.prototype : Object.prototype), {
+				latitude: g.latitude,
+				longitude: g.longitude,
+				altitude: null,
+				accuracy: g.accuracy,
+				altitudeAccuracy: null,
+				heading: null,
+				speed: null,
+			});
+			const position = Object.create(window.GeolocationPosition ? GeolocationPosition.prototype : Object.prototype);
+			return defineValues(position, { coords, timestamp: Date.now() });
+		};
+		let watchID = 0;
+		Geolocation.prototype.getCurrentPosition = native(function getCurrentPosition(success) {
+			setTimeout(() => success(makePosition()), 0);
+		}, 'getCurrentPosition');
+		Geolocation.prototype.watchPosition = native(function watchPosition(success) {
+			setTimeout(() => success(makePosition()), 0);
+			return ++watchID;
+		}, 'watchPosition');
+		Geolocation.prototype.clearWatch = native(function clearWatch() {}, 'clearWatch');
+
+		if (window.Permissions && Permissions.prototype.query) {
+			const originalQuery = Permissions.prototype.query;
+			Permissions.prototype.query = native(function query(descriptor) {
+				if (!descriptor || descriptor.name !== 'geolocation') return originalQuery.call(this, descriptor);
+				const status = Object.create(window.PermissionStatus ? PermissionStatus.prototype : Object.prototype);
+				defineGetters(status, { name: 'geolocation', state: 'granted' });
+				status.onchange = null;
+				return Promise.resolve(status);
+			}, 'query');
+		}
+	}
+
+	if (fp.noise) {
+		// Seeded noise keeps the readings of an identity stable across pages while telling identities apart
+		const mix = (seed, value) => {
+			let x = (seed ^ Math.imul(value, 0x9e3779b1)) >>> 0;
+			x = Math.imul(x ^ (x >>> 16), 0x85ebca6b);
+			x = Math.imul(x ^ (x >>> 13), 0xc2b2ae35);
+			return (x ^ (x >>> 16)) >>> 0;
+		};
+
+		// A pixel is shifted depending on its color only, so any region of a drawing reads the same noise.
+		// Transparent pixels are left untouched, blank canvases stay blank.
+		const noisePixels = (data) => {
+			for (let i = 0; i < data.length; i += 4) {
+				if (data[i + 3] === 0) continue;
+				const bits = mix(fp.noise.canvas, (data[i] << 16) | (data[i + 1] << 8) | data[i + 2]);
+				if ((bits & 15) === 0) data[i + 2] ^= 1;
+			}
+		};
+		const originalGetImageData = CanvasRenderingContext2D.prototype.getImageData;
+		CanvasRenderingContext2D.prototype.getImageData = native(function getImageData(...args) {
+			const image = originalGetImageData.apply(this, args);
+			noisePixels(image.data);
+			return image;
+		}, 'getImageData');
+		// Exports are read from a noised copy, leaving the canvas of the page untouched
+		const noisedCopy = (canvas) => {
+			if (!canvas.width || !canvas.height) return canvas;
+			const copy = document.createElement('canvas');
+			copy.width = canvas.width;
+			copy.height = canvas.height;
+			const context = copy.getContext('2d');
+			context.drawImage(canvas, 0, 0);
+			context.putImageData(context.getImageData(0, 0, copy.width, copy.height), 0, 0);
+			return copy;
+		};
+		for (const name of ['toDataURL', 'toBlob']) {
+			const original = HTMLCanvasElement.prototype[name];
+			const patched = native(function (...args) {
+				return original.apply(noisedCopy(this), args);
+			}, name);
+			Object.defineProperty(patched, 'name', { value: name });
+			HTMLCanvasElement.prototype[name] = patched;
+		}
+
+		if (window.AudioBuffer) {
+			const noised = new WeakSet();
+			const originalGetChannelData = AudioBuffer.prototype.getChannelData;
+			AudioBuffer.prototype.getChannelData = native(function getChannelData(channel) {
+				const data = originalGetChannelData.call(this, channel);
+				if (!noised.has(data)) {
+					noised.add(data);
+					for (let i = 0; i < data.length; i++) {
+						const bits = mix(fp.noise.audio, i);
+						if ((bits & 63) === 0) data[i] += ((bits >>> 6) & 1 ? 1 : -1) * 1e-7;
+					}
+				}
+				return data;
+			}, 'getChannelData');
+			const originalCopyFromChannel = AudioBuffer.prototype.copyFromChannel;
+			AudioBuffer.prototype.copyFromChannel = native(function copyFromChannel(destination, channel, ...rest) {
+				this.getChannelData(channel);
+				return originalCopyFromChannel.call(this, destination, channel, ...rest);
+			}, 'copyFromChannel');
+		}
+	}
+
+	if (fp.timingJitter) {
+		// performance.now() is coarsened to the resolution of the browser, jittering within each step
+		const resolution = fp.timingJitter.resolution;
+		const originalNow = Performance.prototype.now;
+		let last = 0;
+		Performance.prototype.now = native(function now() {
+			const time = originalNow.call(this);
+			last = Math.max(last, Math.min(time, (Math.floor(time / resolution) + Math.random()) * resolution));
+			return last;
+		}, 'now');
+	}
+
+	// canPlayType answers from the codec support of the fingerprint
+	const codecTypes = {
+		'video/ogg; codecs="theora"': ['video', 'ogg'],
+		'video/mp4; codecs="avc1.42E01E"': ['video', 'h264'],
+		'video/webm; codecs="vp8, vorbis"': ['video', 'webm'],
+		'audio/ogg; codecs="vorbis"': ['audio', 'ogg'],
+		'audio/mpeg;': ['audio', 'mp3'],
+		'audio/wav; codecs="1"': ['audio', 'wav'],
+		'audio/x-m4a;': ['audio', 'm4a'],
+		'audio/aac;': ['audio', 'aac'],
+	};
+	const codecs = { video: fp.videoCodecs || {}, audio: fp.audioCodecs || {} };
+	const originalCanPlayType = HTMLMediaElement.prototype.canPlayType;
+	HTMLMediaElement.prototype.canPlayType = native(function canPlayType(type) {
+		const codec = codecTypes[String(type).trim()];
+		if (codec && codec[1] in codecs[codec[0]]) return codecs[codec[0]][codec[1]];
+		return originalCanPlayType.call(this, type);
+	}, 'canPlayType');
+
+	if (fp.multimediaDevices && navigator.mediaDevices) {
+		const d = fp.multimediaDevices;
+		const devices = [...(d.micros || []), ...(d.webcams || []), ...(d.speakers || [])].map((data) => {
+			const proto = data.kind !== 'audiooutput' && window.InputDeviceInfo ? InputDeviceInfo.prototype : MediaDeviceInfo.prototype;
+			const device = Object.create(proto);
+			defineGetters(device, { deviceId: data.deviceId, kind: data.kind, label: data.label, groupId: data.groupId });
+			device.toJSON = native(function toJSON() { return { ...data }; }, 'toJSON');
+			return device;
+		});
+		MediaDevices.prototype.enumerateDevices = native(async function enumerateDevices() {
+			return [...devices];
+		}, 'enumerateDevices');
+	}
+})();
@@ -0,0 +1,72 @@
+package injector
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ta0uf19/forgeron"
+)
+
+func TestScript(t *testing.T) {
+	fp := &forgeron.Fingerprint{
+		Navigator: forgeron.NavigatorFingerprint{
+			UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36",
+			Platform:  "Win32",
+			Language:  "en-US",
+			Languages: []string{"en-US", "en"},
+		},
+		Locale:    "en-US",
+		Timezone:  &forgeron.Timezone{Name: "Europe/Paris", Offset: -60, StandardOffset: -60, DST: true},
+		VideoCard: &forgeron.VideoCard{Vendor: "Google Inc. (NVIDIA)", Renderer: "ANGLE (NVIDIA, <script>)"},
+	}
+	script, err := Script(fp)
+	if err != nil {
+		t.Fatalf("Script() error = %v", err)
+	}
+	if strings.Contains(script, fingerprintPlaceholder) {
+		t.Error("Script() left the fingerprint placeholder")
+	}
+	for _, want := range []string{fp.Navigator.UserAgent, "Google Inc. (NVIDIA)", "getHighEntropyValues", "enumerateDevices", "DateTimeFormat", "getTimezoneOffset", "Europe/Paris", "getChannelData", "Performance.prototype.now", "getCurrentPosition"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("Script() does not contain %q", want)
+		}
+	}
+	if strings.Contains(script, "<script>") {
+		t.Error("Script() does not escape markup in the fingerprint")
+	}
+}
+
+func TestScriptRequiresFingerprint(t *testing.T) {
+	if _, err := Script(nil); err == nil {
+		t.Error("Script(nil) error = nil")
+	}
+}
+
+func TestTemplateHasSinglePlaceholder(t *testing.T) {
+	if n := strings.Count(template, fingerprintPlaceholder); n != 1 {
+		t.Errorf("template contains %d placeholders, want 1", n)
+	}
+}
+
+func TestExtraHeaders(t *testing.T) {
+	fp := &forgeron.Fingerprint{Headers: map[string]string{
+		"Accept-Language":           "de-DE,de;q=0.9",
+		"User-Agent":                "Mozilla/5.0",
+		"Accept":                    "text/html",
+		"sec-ch-ua":                 `"Chromium";v="144"`,
+		"Sec-Fetch-Mode":            "navigate",
+		"Upgrade-Insecure-Requests": "1",
+		"DNT":                       "1",
+		"Sec-GPC":                   "1",
+	}}
+	got := ExtraHeaders(fp)
+	want := map[string]string{"Accept-Language": "de-DE,de;q=0.9", "DNT": "1", "Sec-GPC": "1"}
+	if len(got) != len(want) {
+		t.Fatalf("ExtraHeaders() = %v, want %v", got, want)
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("ExtraHeaders()[%s] = %q, want %q", name, got[name], value)
+		}
+	}
+}
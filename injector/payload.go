@@ -0,0 +1,122 @@
+package injector
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ta0uf19/forgeron"
+)
+
+// PayloadVersion is the version of the payload contract. It is bumped when a field is removed or changes meaning,
+// new fields keep the version, so workers must ignore the fields they do not know.
+const PayloadVersion = 1
+
+// Payload is a fingerprint ready to be applied by Playwright or Puppeteer workers written in other languages,
+// so mixed-language fleets can generate identities in a Go service. The context fields follow the names of the
+// Playwright context options, and the script is the standalone JavaScript returned by Script.
+type Payload struct {
+	// Version is the contract version, PayloadVersion when written by this package
+	Version           int                `json:"version"`
+	UserAgent         string             `json:"userAgent"`
+	Locale            string             `json:"locale"`
+	Viewport          Size               `json:"viewport"`
+	Screen            Size               `json:"screen"`
+	DeviceScaleFactor float64            `json:"deviceScaleFactor"`
+	IsMobile          bool               `json:"isMobile"`
+	HasTouch          bool               `json:"hasTouch"`
+	ExtraHTTPHeaders  map[string]string  `json:"extraHTTPHeaders"`
+	UserAgentMetadata *UserAgentMetadata `json:"userAgentMetadata,omitempty"`
+	// TimezoneID is the IANA time zone of the fingerprint, empty when it has none
+	TimezoneID string `json:"timezoneId,omitempty"`
+	// Geolocation is the position of the fingerprint, nil unless generated with WithGeo. Contexts also need the
+	// geolocation permission.
+	Geolocation *forgeron.Geolocation `json:"geolocation,omitempty"`
+	// Script must run before any page script, e.g. with add_init_script or evaluateOnNewDocument
+	Script string `json:"script"`
+	// Fingerprint is the fingerprint the payload was built from
+	Fingerprint *forgeron.Fingerprint `json:"fingerprint"`
+}
+
+// Size is a width and height in CSS pixels
+type Size struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// UserAgentMetadata is the client hints metadata of Emulation.setUserAgentOverride, also taken by Puppeteer's
+// page.setUserAgent. Only Chromium fingerprints have one.
+type UserAgentMetadata struct {
+	Brands          []forgeron.UserAgentBrand `json:"brands"`
+	FullVersionList []forgeron.UserAgentBrand `json:"fullVersionList"`
+	FullVersion     string                    `json:"fullVersion"`
+	Platform        string                    `json:"platform"`
+	PlatformVersion string                    `json:"platformVersion"`
+	Architecture    string                    `json:"architecture"`
+	Model           string                    `json:"model"`
+	Mobile          bool                      `json:"mobile"`
+	Bitness         string                    `json:"bitness"`
+}
+
+// NewPayload returns the payload applying the fingerprint
+func NewPayload(fp *forgeron.Fingerprint) (*Payload, error) {
+	script, err := Script(fp)
+	if err != nil {
+		return nil, err
+	}
+	payload := &Payload{
+		Version:           PayloadVersion,
+		UserAgent:         fp.Navigator.UserAgent,
+		Locale:            fp.Navigator.Language,
+		Viewport:          Size{Width: fp.Screen.InnerWidth, Height: fp.Screen.InnerHeight},
+		Screen:            Size{Width: fp.Screen.Width, Height: fp.Screen.Height},
+		DeviceScaleFactor: fp.Screen.DevicePixelRatio,
+		IsMobile:          strings.Contains(fp.Navigator.UserAgent, "Mobile"),
+		HasTouch:          fp.Navigator.MaxTouchPoints > 0,
+		ExtraHTTPHeaders:  ExtraHeaders(fp),
+		Geolocation:       fp.Geolocation,
+		Script:            script,
+		Fingerprint:       fp,
+	}
+	if fp.Timezone != nil {
+		payload.TimezoneID = fp.Timezone.Name
+	}
+	if data := fp.Navigator.UserAgentData; data != nil {
+		payload.IsMobile = data.Mobile
+		payload.UserAgentMetadata = &UserAgentMetadata{
+			Brands:          data.Brands,
+			FullVersionList: data.FullVersionList,
+			FullVersion:     data.UAFullVersion,
+			Platform:        data.Platform,
+			PlatformVersion: data.PlatformVersion,
+			Architecture:    data.Architecture,
+			Model:           data.Model,
+			Mobile:          data.Mobile,
+			Bitness:         data.Bitness,
+		}
+	}
+	return payload, nil
+}
+
+// ParsePayload decodes a payload, refusing payloads of a newer contract and inconsistent fingerprints
+func ParsePayload(data []byte) (*Payload, error) {
+	var raw struct {
+		Payload
+		Fingerprint json.RawMessage `json:"fingerprint"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode payload: %w", err)
+	}
+	if raw.Version < 1 || raw.Version > PayloadVersion {
+		return nil, fmt.Errorf("unsupported payload version %d, this release reads version %d", raw.Version, PayloadVersion)
+	}
+	payload := raw.Payload
+	if len(raw.Fingerprint) > 0 && string(raw.Fingerprint) != "null" {
+		fp, err := forgeron.UnmarshalFingerprint(raw.Fingerprint)
+		if err != nil {
+			return nil, err
+		}
+		payload.Fingerprint = fp
+	}
+	return &payload, nil
+}
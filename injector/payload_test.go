@@ -0,0 +1,152 @@
+package injector
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/ta0uf19/forgeron"
+)
+
+// generatePayload returns the payload of a generated fingerprint of the browser
+func generatePayload(t *testing.T, browser forgeron.Browser, devices ...forgeron.Device) *Payload {
+	t.Helper()
+	gen, err := forgeron.NewFingerprintGenerator(forgeron.WithHeaderConstraints(forgeron.HeaderConstraints{
+		Browsers: []forgeron.Browser{browser},
+		Devices:  devices,
+	}))
+	if err != nil {
+		t.Fatalf("NewFingerprintGenerator() error = %v", err)
+	}
+	fp, err := gen.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	payload, err := NewPayload(fp)
+	if err != nil {
+		t.Fatalf("NewPayload() error = %v", err)
+	}
+	return payload
+}
+
+func TestNewPayload(t *testing.T) {
+	payload := generatePayload(t, forgeron.Chrome, forgeron.Desktop)
+	fp := payload.Fingerprint
+	if payload.Version != PayloadVersion {
+		t.Errorf("Version = %d, want %d", payload.Version, PayloadVersion)
+	}
+	if payload.UserAgent != fp.Navigator.UserAgent || payload.Locale != fp.Navigator.Language {
+		t.Errorf("UserAgent, Locale = %q, %q", payload.UserAgent, payload.Locale)
+	}
+	if fp.Timezone == nil || payload.TimezoneID != fp.Timezone.Name {
+		t.Errorf("TimezoneID = %q, want the time zone of the fingerprint", payload.TimezoneID)
+	}
+	if payload.Geolocation != nil {
+		t.Errorf("Geolocation = %+v without WithGeo", payload.Geolocation)
+	}
+	if payload.Viewport.Width != fp.Screen.InnerWidth || payload.Screen.Width != fp.Screen.Width {
+		t.Errorf("Viewport, Screen = %+v, %+v", payload.Viewport, payload.Screen)
+	}
+	if payload.IsMobile {
+		t.Error("IsMobile = true for a desktop fingerprint")
+	}
+	if payload.UserAgentMetadata == nil || payload.UserAgentMetadata.FullVersion != fp.Navigator.UserAgentData.UAFullVersion {
+		t.Errorf("UserAgentMetadata = %+v", payload.UserAgentMetadata)
+	}
+	script, _ := Script(fp)
+	if payload.Script != script {
+		t.Error("Script differs from the standalone script")
+	}
+
+	payload = generatePayload(t, forgeron.Safari, forgeron.Mobile)
+	if !payload.IsMobile || payload.UserAgentMetadata != nil {
+		t.Errorf("IsMobile, UserAgentMetadata = %v, %+v for %s", payload.IsMobile, payload.UserAgentMetadata, payload.UserAgent)
+	}
+}
+
+func TestPayloadGeolocation(t *testing.T) {
+	gen, err := forgeron.NewFingerprintGenerator(forgeron.WithGeo("JP"))
+	if err != nil {
+		t.Fatalf("NewFingerprintGenerator() error = %v", err)
+	}
+	fp, err := gen.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	payload, err := NewPayload(fp)
+	if err != nil {
+		t.Fatalf("NewPayload() error = %v", err)
+	}
+	if payload.Geolocation == nil || *payload.Geolocation != *fp.Geolocation || payload.TimezoneID != "Asia/Tokyo" {
+		t.Errorf("Geolocation, TimezoneID = %+v, %q", payload.Geolocation, payload.TimezoneID)
+	}
+}
+
+func TestPayloadRoundTrip(t *testing.T) {
+	for _, browser := range []forgeron.Browser{forgeron.Chrome, forgeron.Firefox, forgeron.Safari} {
+		payload := generatePayload(t, browser)
+		data, err := json.Marshal(payload)
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		parsed, err := ParsePayload(data)
+		if err != nil {
+			t.Fatalf("ParsePayload() error = %v", err)
+		}
+		again, err := json.Marshal(parsed)
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		if !bytes.Equal(data, again) {
+			t.Errorf("%s payload changed after a round trip", browser)
+		}
+	}
+}
+
+// TestPayloadContract guards the documented fields of the version 1 contract, removing or renaming one
+// requires bumping PayloadVersion
+func TestPayloadContract(t *testing.T) {
+	data, err := json.Marshal(generatePayload(t, forgeron.Chrome))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{
+		"version", "userAgent", "locale", "viewport", "screen", "deviceScaleFactor", "isMobile", "hasTouch",
+		"extraHTTPHeaders", "userAgentMetadata", "timezoneId", "script", "fingerprint",
+	} {
+		if _, ok := fields[name]; !ok {
+			t.Errorf("payload has no %q field", name)
+		}
+	}
+	var metadata map[string]json.RawMessage
+	if err := json.Unmarshal(fields["userAgentMetadata"], &metadata); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"brands", "fullVersionList", "fullVersion", "platform", "platformVersion", "architecture", "model", "mobile", "bitness"} {
+		if _, ok := metadata[name]; !ok {
+			t.Errorf("userAgentMetadata has no %q field", name)
+		}
+	}
+}
+
+func TestParsePayloadRejects(t *testing.T) {
+	data, err := json.Marshal(generatePayload(t, forgeron.Firefox))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string][]byte{
+		"newer version":       bytes.Replace(data, []byte(`"version":1`), []byte(`"version":2`), 1),
+		"missing version":     bytes.Replace(data, []byte(`"version":1,`), nil, 1),
+		"invalid fingerprint": bytes.Replace(data, []byte(`"languages":[`), []byte(`"languages":[],"x":[`), 1),
+		"invalid json":        data[:len(data)/2],
+	}
+	for name, data := range tests {
+		if _, err := ParsePayload(data); err == nil {
+			t.Errorf("ParsePayload() with %s error = nil", name)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+// Writes the fingerprint-suite corpus checked by TestFingerprintSuiteCorpus, one file per case:
+//
+//	npm install fingerprint-generator && node generate.mjs
+import { FingerprintGenerator } from 'fingerprint-generator';
+import { writeFileSync } from 'node:fs';
+
+const cases = {
+    chrome_windows: { browsers: ['chrome'], operatingSystems: ['windows'], devices: ['desktop'] },
+    edge_macos: { browsers: ['edge'], operatingSystems: ['macos'], devices: ['desktop'] },
+    firefox_linux: { browsers: ['firefox'], operatingSystems: ['linux'], devices: ['desktop'] },
+    chrome_android: { browsers: ['chrome'], operatingSystems: ['android'], devices: ['mobile'] },
+    safari_ios: { browsers: ['safari'], operatingSystems: ['ios'], devices: ['mobile'] },
+};
+
+const generator = new FingerprintGenerator();
+for (const [name, options] of Object.entries(cases)) {
+    const { fingerprint, headers } = generator.getFingerprint(options);
+    const file = new URL(`${name}.json`, import.meta.url);
+    writeFileSync(file, JSON.stringify({ fingerprint, headers }, null, 2) + '\n');
+}
@@ -1,6 +1,7 @@
 package forgeron
 
 import (
+	"errors"
 	"strings"
 	"testing"
 )
@@ -53,7 +54,7 @@ func TestGeneratePerBrowser(t *testing.T) {
 		t.Run(browser, func(t *testing.T) {
 			gen := newGeneratorOrFatal(t)
 			fp, err := gen.Generate(WithHeaderConstraints(HeaderConstraints{
-				Browsers: []string{browser},
+				Browsers: BrowsersOf(browser),
 			}))
 			if err != nil {
 				t.Fatalf("Generate() error = %v", err)
@@ -88,7 +89,7 @@ func TestGeneratePerOS(t *testing.T) {
 		t.Run(os, func(t *testing.T) {
 			gen := newGeneratorOrFatal(t)
 			fp, err := gen.Generate(WithHeaderConstraints(HeaderConstraints{
-				OS: []string{os},
+				OS: OSesOf(os),
 			}))
 			if err != nil {
 				t.Fatalf("Generate() for OS %q error = %v", os, err)
@@ -120,16 +121,16 @@ func TestGenerateDesktopVsMobile(t *testing.T) {
 	gen := newGeneratorOrFatal(t)
 
 	desktop, err := gen.Generate(WithHeaderConstraints(HeaderConstraints{
-		Devices: []string{"desktop"},
+		Devices: DevicesOf("desktop"),
 	}))
 	if err != nil {
 		t.Fatalf("Generate(desktop) error = %v", err)
 	}
 
 	mobile, err := gen.Generate(WithHeaderConstraints(HeaderConstraints{
-		Devices:  []string{"mobile"},
-		Browsers: []string{"chrome"},
-		OS:       []string{"android"},
+		Devices:  DevicesOf("mobile"),
+		Browsers: BrowsersOf("chrome"),
+		OS:       OSesOf("android"),
 	}))
 	if err != nil {
 		t.Fatalf("Generate(mobile) error = %v", err)
@@ -167,11 +168,53 @@ func TestGenerateMultipleFingerprintsAreUnique(t *testing.T) {
 	}
 }
 
-// TestGenerateScreenConstraints verifies screen dimension constraints are respected.
-// NOTE: screen constraint filtering is not yet implemented (TODO in fingerprint_generator.go).
-// This test is skipped until the feature is built.
+// TestGenerateScreenConstraints verifies screen dimension constraints are respected
 func TestGenerateScreenConstraints(t *testing.T) {
-	t.Skip("screen constraint filtering not yet implemented (see TODO in fingerprint_generator.go)")
+	minW, maxW := 1280, 1920
+	minDPR := 2.0
+	gen := newGeneratorOrFatal(t)
+	for i := 0; i < 5; i++ {
+		fp, err := gen.Generate(WithConstraints(Constraints{
+			HeaderConstraints: HeaderConstraints{Browsers: []Browser{"chrome"}, OS: []OS{"macos"}},
+			Screen:            &Screen{MinWidth: &minW, MaxWidth: &maxW, MinDevicePixelRatio: &minDPR},
+		}))
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if fp.Screen.Width < minW || fp.Screen.Width > maxW {
+			t.Errorf("Width %d outside [%d, %d]", fp.Screen.Width, minW, maxW)
+		}
+		if fp.Screen.DevicePixelRatio < minDPR {
+			t.Errorf("DevicePixelRatio %v below %v", fp.Screen.DevicePixelRatio, minDPR)
+		}
+	}
+}
+
+// TestGenerateUnsatisfiableScreen verifies screens matching no data fail in strict mode and are dropped otherwise
+func TestGenerateUnsatisfiableScreen(t *testing.T) {
+	minW := 100000
+	gen := newGeneratorOrFatal(t)
+	screen := &Screen{MinWidth: &minW}
+
+	_, err := gen.Generate(WithScreen(screen), WithStrict(true))
+	var constraintErr *ConstraintError
+	if !errors.As(err, &constraintErr) || constraintErr.Constraint != "screen" || !strings.Contains(err.Error(), "no screen") {
+		t.Errorf("Generate() error = %v, want a screen error in strict mode", err)
+	}
+
+	fp, err := gen.Generate(WithScreen(screen), WithStrict(false))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if fp.Screen.Width == 0 {
+		t.Error("expected a fingerprint with a screen after dropping the constraints")
+	}
+
+	// Constraints without Strict turn off the strict mode of the generator
+	strict := newGeneratorOrFatal(t, WithStrict(true))
+	if _, err := strict.Generate(WithConstraints(Constraints{Screen: screen})); err != nil {
+		t.Errorf("Generate() error = %v, want the screen dropped with non-strict constraints", err)
+	}
 }
 
 // TestGenerateMockWebRTC verifies the MockWebRTC flag is reflected in output
@@ -231,7 +274,7 @@ func TestGenerateLocale(t *testing.T) {
 func TestGenerateChromeHasUserAgentData(t *testing.T) {
 	gen := newGeneratorOrFatal(t)
 	fp, err := gen.Generate(WithHeaderConstraints(HeaderConstraints{
-		Browsers: []string{"chrome"},
+		Browsers: []Browser{"chrome"},
 	}))
 	if err != nil {
 		t.Fatalf("Generate() error = %v", err)
@@ -252,7 +295,7 @@ func TestGenerateFirefoxHasNoUserAgentData(t *testing.T) {
 	gen := newGeneratorOrFatal(t)
 	for i := 0; i < 5; i++ {
 		fp, err := gen.Generate(WithHeaderConstraints(HeaderConstraints{
-			Browsers: []string{"firefox"},
+			Browsers: []Browser{"firefox"},
 		}))
 		if err != nil {
 			t.Fatalf("Generate() error = %v", err)
@@ -293,12 +336,12 @@ func TestHeaderGeneratorStandalone(t *testing.T) {
 		constraints HeaderConstraints
 	}{
 		{"defaults", HeaderConstraints{}},
-		{"chrome only", HeaderConstraints{Browsers: []string{"chrome"}}},
-		{"firefox only", HeaderConstraints{Browsers: []string{"firefox"}}},
-		{"safari only", HeaderConstraints{Browsers: []string{"safari"}}},
-		{"edge only", HeaderConstraints{Browsers: []string{"edge"}}},
-		{"windows", HeaderConstraints{OS: []string{"windows"}}},
-		{"macos", HeaderConstraints{OS: []string{"macos"}}},
+		{"chrome only", HeaderConstraints{Browsers: []Browser{"chrome"}}},
+		{"firefox only", HeaderConstraints{Browsers: []Browser{"firefox"}}},
+		{"safari only", HeaderConstraints{Browsers: []Browser{"safari"}}},
+		{"edge only", HeaderConstraints{Browsers: []Browser{"edge"}}},
+		{"windows", HeaderConstraints{OS: []OS{"windows"}}},
+		{"macos", HeaderConstraints{OS: []OS{"macos"}}},
 	}
 
 	for _, tt := range tests {
@@ -322,7 +365,7 @@ func TestHeaderGeneratorStandalone(t *testing.T) {
 func TestInvalidBrowserReturnsError(t *testing.T) {
 	gen := newGeneratorOrFatal(t)
 	_, err := gen.Generate(WithHeaderConstraints(HeaderConstraints{
-		Browsers: []string{"netscape"},
+		Browsers: []Browser{"netscape"},
 	}))
 	if err == nil {
 		t.Fatal("expected an error for unsupported browser, got nil")
@@ -340,3 +383,71 @@ func TestScreenValidation(t *testing.T) {
 		t.Error("expected Validate() to return error for minWidth > maxWidth")
 	}
 }
+
+// skipWithMinimalData skips a test of identities the minimal data of forgeron_minimal builds lacks
+func skipWithMinimalData(t *testing.T, reason string) {
+	t.Helper()
+	if dataDir == "data_minimal" {
+		t.Skipf("the minimal data %s", reason)
+	}
+}
+
+// TestGenerateChromeOS verifies ChromeOS fingerprints carry the CrOS token and consistent platform values
+func TestGenerateChromeOS(t *testing.T) {
+	skipWithMinimalData(t, "has no ChromeOS identities")
+	gen := newGeneratorOrFatal(t)
+	for i := 0; i < 5; i++ {
+		fp, err := gen.Generate(WithHeaderConstraints(HeaderConstraints{
+			Browsers: []Browser{"chrome"},
+			OS:       []OS{ChromeOS},
+			Strict:   true,
+		}))
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if !strings.Contains(fp.Navigator.UserAgent, "CrOS") {
+			t.Fatalf("expected CrOS user agent, got: %s", fp.Navigator.UserAgent)
+		}
+		if fp.Navigator.Platform != "Linux x86_64" {
+			t.Errorf("Platform = %q, want Linux x86_64", fp.Navigator.Platform)
+		}
+		if fp.Navigator.UserAgentData != nil && fp.Navigator.UserAgentData.Platform != "Chrome OS" {
+			t.Errorf("UserAgentData.Platform = %q, want Chrome OS", fp.Navigator.UserAgentData.Platform)
+		}
+	}
+}
+
+// TestGenerateTablet verifies tablet fingerprints use tablet user agents, touch support and tablet screens
+func TestGenerateTablet(t *testing.T) {
+	skipWithMinimalData(t, "only has Chrome tablets")
+	gen := newGeneratorOrFatal(t)
+	for i := 0; i < 5; i++ {
+		fp, err := gen.Generate(WithHeaderConstraints(HeaderConstraints{
+			Devices: []Device{"tablet"},
+		}))
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if !isTabletUserAgent(fp.Navigator.UserAgent) {
+			t.Fatalf("expected tablet user agent, got: %s", fp.Navigator.UserAgent)
+		}
+		if fp.Navigator.MaxTouchPoints == 0 {
+			t.Error("expected touch support for a tablet")
+		}
+	}
+}
+
+// TestGeneratePhone verifies mobile fingerprints never use tablet user agents without reporting the relaxation
+func TestGeneratePhone(t *testing.T) {
+	gen := newGeneratorOrFatal(t)
+	for i := 0; i < 200; i++ {
+		report := &RelaxationReport{}
+		fp, err := gen.Generate(WithHeaderConstraints(HeaderConstraints{Devices: []Device{Mobile}}), WithRelaxationReport(report))
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if isTabletUserAgent(fp.Navigator.UserAgent) && !report.Dropped("devices") {
+			t.Fatalf("expected phone user agent, got: %s", fp.Navigator.UserAgent)
+		}
+	}
+}
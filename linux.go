@@ -0,0 +1,79 @@
+package forgeron
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// LinuxFlavor describes optional distribution and display server hints for Linux identities
+type LinuxFlavor struct {
+	// Distro is the distribution name, e.g. "ubuntu" or "fedora"
+	Distro string
+	// DisplayServer is either "x11" or "wayland"
+	DisplayServer string
+}
+
+// WithLinuxFlavor sets the distribution and display server hints added to Linux fingerprints
+func WithLinuxFlavor(flavor LinuxFlavor) FingerprintOption {
+	return func(g *FingerprintGenerator) {
+		g.linuxFlavor = &flavor
+	}
+}
+
+// linuxArchPattern extracts the architecture from a desktop Linux user agent
+var linuxArchPattern = regexp.MustCompile(`X11;(?: [^;)]+;)* Linux ([^;)]+)`)
+
+// windowsOnlyFonts lists fonts shipped with Windows that are not found on Linux installs
+var windowsOnlyFonts = []string{
+	"Calibri", "Cambria", "Candara", "Consolas", "Constantia", "Corbel", "Ebrima", "Franklin Gothic",
+	"Gabriola", "Leelawadee", "Marlett", "Microsoft Uighur", "MS Gothic", "MS Mincho", "MS Outlook",
+	"MS PGothic", "MS Reference Specialty", "MS UI Gothic", "Nirmala UI", "Segoe UI", "Segoe UI Light",
+	"Segoe UI Semibold", "Segoe UI Symbol", "Sitka", "Yu Gothic",
+}
+
+// linuxArch returns the architecture of a desktop Linux user agent, or an empty string for other user agents
+func linuxArch(userAgent string) string {
+	if strings.Contains(userAgent, "Android") || strings.Contains(userAgent, "CrOS") {
+		return ""
+	}
+	match := linuxArchPattern.FindStringSubmatch(userAgent)
+	if match == nil {
+		return ""
+	}
+	return strings.TrimSpace(match[1])
+}
+
+// applyLinuxConsistency aligns platform, oscpu and fonts of Linux fingerprints with the user agent
+func applyLinuxConsistency(fp *Fingerprint, flavor *LinuxFlavor) {
+	arch := linuxArch(fp.Navigator.UserAgent)
+	if arch == "" {
+		return
+	}
+
+	platform := "Linux " + arch
+	fp.Navigator.Platform = platform
+	// Only Gecko exposes navigator.oscpu
+	if strings.Contains(fp.Navigator.UserAgent, "Firefox/") {
+		fp.Navigator.OSCpu = &platform
+	} else {
+		fp.Navigator.OSCpu = nil
+	}
+
+	fp.Fonts = slices.DeleteFunc(fp.Fonts, func(font string) bool {
+		return slices.Contains(windowsOnlyFonts, font)
+	})
+
+	if flavor == nil {
+		return
+	}
+	if fp.Navigator.ExtraProperties == nil {
+		fp.Navigator.ExtraProperties = make(map[string]any)
+	}
+	if flavor.Distro != "" {
+		fp.Navigator.ExtraProperties["linuxDistro"] = strings.ToLower(flavor.Distro)
+	}
+	if flavor.DisplayServer != "" {
+		fp.Navigator.ExtraProperties["displayServer"] = strings.ToLower(flavor.DisplayServer)
+	}
+}
@@ -0,0 +1,49 @@
+package forgeron
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLinuxArch(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		want      string
+	}{
+		{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36", "x86_64"},
+		{"Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:147.0) Gecko/20100101 Firefox/147.0", "x86_64"},
+		{"Mozilla/5.0 (X11; Linux aarch64; rv:147.0) Gecko/20100101 Firefox/147.0", "aarch64"},
+		{"Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Mobile Safari/537.36", ""},
+		{"Mozilla/5.0 (X11; CrOS x86_64 14541.0.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/143.0.0.0 Safari/537.36", ""},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36", ""},
+	}
+	for _, tt := range tests {
+		if got := linuxArch(tt.userAgent); got != tt.want {
+			t.Errorf("linuxArch(%q) = %q, want %q", tt.userAgent, got, tt.want)
+		}
+	}
+}
+
+func TestApplyLinuxConsistency(t *testing.T) {
+	fp := &Fingerprint{
+		Navigator: NavigatorFingerprint{
+			UserAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:147.0) Gecko/20100101 Firefox/147.0",
+			Platform:  "Linux armv81",
+		},
+		Fonts: []string{"Arial", "Segoe UI", "DejaVu Sans", "Calibri"},
+	}
+	applyLinuxConsistency(fp, &LinuxFlavor{Distro: "Fedora", DisplayServer: "wayland"})
+
+	if fp.Navigator.Platform != "Linux x86_64" {
+		t.Errorf("Platform = %q, want Linux x86_64", fp.Navigator.Platform)
+	}
+	if fp.Navigator.OSCpu == nil || *fp.Navigator.OSCpu != "Linux x86_64" {
+		t.Errorf("OSCpu = %v, want Linux x86_64", fp.Navigator.OSCpu)
+	}
+	if !slices.Equal(fp.Fonts, []string{"Arial", "DejaVu Sans"}) {
+		t.Errorf("Fonts = %v, want Windows fonts removed", fp.Fonts)
+	}
+	if fp.Navigator.ExtraProperties["linuxDistro"] != "fedora" || fp.Navigator.ExtraProperties["displayServer"] != "wayland" {
+		t.Errorf("unexpected flavor hints: %v", fp.Navigator.ExtraProperties)
+	}
+}
@@ -3,26 +3,103 @@ package forgeron
 import (
 	"archive/zip"
 	"bytes"
-	"embed"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"sync"
 )
 
-//go:embed data_points/*.json data_points/*.zip
-var dataFiles embed.FS
+//go:generate go run ./cmd/forgeron-data -data data_points -minimal data_minimal
 
-// loadNetworkFromZip loads a Bayesian network from a zip file in the data_points directory
-func loadNetworkFromZip(filename string) (*bayesianNetwork, error) {
-	zipData, err := dataFiles.ReadFile("data_points/" + filename)
+// embeddedData returns the data directory embedded in the binary: data_points, or data_minimal in builds with
+// the forgeron_minimal tag
+func embeddedData() fs.FS {
+	data, err := fs.Sub(dataFiles, dataDir)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// overlayFS reads files from a primary file system, falling back to another one when they are missing
+type overlayFS struct {
+	primary  fs.FS
+	fallback fs.FS
+}
+
+// Open opens the named file from the primary file system, or from the fallback if it does not exist
+func (o overlayFS) Open(name string) (fs.File, error) {
+	file, err := o.primary.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return o.fallback.Open(name)
+	}
+	return file, err
+}
+
+// dataSourceFS returns the data files of source, falling back to the embedded data for files it does not contain
+func dataSourceFS(source fs.FS) fs.FS {
+	if source == nil {
+		return embeddedData()
+	}
+	return overlayFS{primary: source, fallback: embeddedData()}
+}
+
+// loadNetworkFromZip loads a Bayesian network from a zip file in the data directory
+func loadNetworkFromZip(data fs.FS, filename string) (*bayesianNetwork, error) {
+	file, err := data.Open(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read %s: %v", filename, err)
 	}
+	defer file.Close()
+
+	// Files with random access, such as embedded and local ones, are read in place in low memory builds
+	var (
+		zipData io.ReaderAt
+		size    int64
+	)
+	if readerAt, ok := file.(io.ReaderAt); ok && lowMemory {
+		info, err := file.Stat()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", filename, err)
+		}
+		zipData, size = readerAt, info.Size()
+	} else {
+		content, err := io.ReadAll(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", filename, err)
+		}
+		zipData, size = bytes.NewReader(content), int64(len(content))
+	}
 
-	zipReader, err := zip.NewReader(bytes.NewReader(zipData), int64(len(zipData)))
+	network, err := readNetworkZip(zipData, size)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return network, nil
+}
+
+// parseNetworkZip parses a network definition zip, refusing data of a newer schema
+func parseNetworkZip(zipData []byte) (*bayesianNetwork, error) {
+	return readNetworkZip(bytes.NewReader(zipData), int64(len(zipData)))
+}
+
+// readNetworkZip reads a network definition zip of the given size, refusing data of a newer schema. Low memory
+// builds decode the definition while decompressing it instead of reading it whole first.
+func readNetworkZip(zipData io.ReaderAt, size int64) (*bayesianNetwork, error) {
+	zipReader, err := zip.NewReader(zipData, size)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create zip reader: %v", err)
 	}
 
+	schema, err := parseDataSchema(zipReader.Comment)
+	if err == nil {
+		err = schema.check()
+	}
+	if err != nil {
+		return nil, err
+	}
+
 	if len(zipReader.File) == 0 {
 		return nil, fmt.Errorf("no files found in zip")
 	}
@@ -33,15 +110,75 @@ func loadNetworkFromZip(filename string) (*bayesianNetwork, error) {
 	}
 	defer file.Close()
 
-	networkData, err := io.ReadAll(file)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read file contents: %v", err)
-	}
-
 	network := newBayesianNetwork()
-	if err := network.loadNetwork(networkData); err != nil {
+	if lowMemory {
+		err = network.decodeNetwork(file)
+	} else {
+		var networkData []byte
+		if networkData, err = io.ReadAll(file); err != nil {
+			return nil, fmt.Errorf("failed to read file contents: %v", err)
+		}
+		err = network.loadNetwork(networkData)
+	}
+	if err != nil {
 		return nil, fmt.Errorf("failed to load network: %v", err)
 	}
+	network.schema = schema
 
 	return network, nil
 }
+
+// sharedNetwork is a network of the embedded data, parsed once on first use
+type sharedNetwork struct {
+	once    sync.Once
+	network *bayesianNetwork
+	err     error
+}
+
+var (
+	sharedNetworksMu sync.Mutex
+	sharedNetworks   = make(map[string]*sharedNetwork)
+)
+
+// loadSharedNetwork loads a network of the embedded data from the package cache, parsing it on first use.
+// The nodes are shared, each call returns its own network so the logger and random source are set per generator.
+func loadSharedNetwork(filename string) (*bayesianNetwork, error) {
+	sharedNetworksMu.Lock()
+	shared, ok := sharedNetworks[filename]
+	if !ok {
+		shared = &sharedNetwork{}
+		sharedNetworks[filename] = shared
+	}
+	sharedNetworksMu.Unlock()
+
+	shared.once.Do(func() {
+		shared.network, shared.err = loadNetworkFromZip(embeddedData(), filename)
+	})
+	if shared.err != nil {
+		return nil, shared.err
+	}
+	network := *shared.network
+	return &network, nil
+}
+
+// networkLoader loads the networks of a generator
+type networkLoader struct {
+	data fs.FS
+	// shared loads the networks of the embedded data from the package cache
+	shared bool
+	// networks replace the data files of the same name
+	networks map[string]*bayesianNetwork
+}
+
+// load loads the network of a data file. Given and shared networks are copied so the logger and random source
+// are set per generator.
+func (l networkLoader) load(filename string) (*bayesianNetwork, error) {
+	if network, ok := l.networks[filename]; ok {
+		network := *network
+		return &network, nil
+	}
+	if l.shared {
+		return loadSharedNetwork(filename)
+	}
+	return loadNetworkFromZip(l.data, filename)
+}
@@ -0,0 +1,6 @@
+//go:build !forgeron_lowmem
+
+package forgeron
+
+// lowMemory reports whether networks are loaded in bounded memory, see loader_lowmem.go
+const lowMemory = false
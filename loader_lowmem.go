@@ -0,0 +1,9 @@
+//go:build forgeron_lowmem
+
+package forgeron
+
+// lowMemory reports whether networks are loaded in bounded memory. Builds with the forgeron_lowmem tag read the
+// network zips in place and decode their definitions while decompressing them, compiling each node as it is
+// decoded, so the peak memory of a load is the compiled network and a single raw node instead of the whole
+// decompressed JSON. Loading is a little slower, for small devices where the default load runs out of memory.
+const lowMemory = true
@@ -0,0 +1,46 @@
+package forgeron
+
+import "slices"
+
+// localeModel is the locale settings of an identity: the locales preferred in the settings of the browser of a user
+// agent. navigator.language and navigator.languages, the default Intl locale and Accept-Language are all derived
+// from it, so they cannot disagree.
+type localeModel struct {
+	engine browserEngine
+	// locales are the canonical preferred locales, the first one being the locale of the browser
+	locales []string
+}
+
+// newLocaleModel returns the locale model of the browser of userAgent preferring locales
+func newLocaleModel(userAgent string, locales []string) localeModel {
+	return localeModel{engine: engineOf(userAgent), locales: locales}
+}
+
+// languages returns navigator.languages, the languages of Accept-Language except for Safari which only exposes
+// its first locale
+func (m localeModel) languages() []string {
+	if m.engine == webKitEngine {
+		return slices.Clone(m.locales[:min(len(m.locales), 1)])
+	}
+	return m.acceptLanguages()
+}
+
+// intlLocale returns the locale Intl formats with when none is given, the locale of the browser
+func (m localeModel) intlLocale() string {
+	if len(m.locales) == 0 {
+		return ""
+	}
+	return m.locales[0]
+}
+
+// apply sets the languages and the Intl locale of a fingerprint, replacing the ones sampled with the fingerprint
+// or read back from its headers
+func (m localeModel) apply(fp *Fingerprint) {
+	languages := m.languages()
+	if len(languages) == 0 {
+		return
+	}
+	fp.Navigator.Languages = languages
+	fp.Navigator.Language = languages[0]
+	fp.Locale = m.intlLocale()
+}
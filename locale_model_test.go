@@ -0,0 +1,67 @@
+package forgeron
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLocaleModelLanguages(t *testing.T) {
+	locales := []string{"de-CH", "fr-CH", "en-US"}
+	tests := []struct {
+		name      string
+		userAgent string
+		want      []string
+	}{
+		{"chromium", "Chrome/144.0.0.0", []string{"de-CH", "de", "fr-CH", "fr", "en-US", "en"}},
+		{"firefox", "Firefox/147.0", []string{"de-CH", "de", "fr-CH", "fr", "en-US", "en"}},
+		{"safari", "AppleWebKit/605.1.15 (KHTML, like Gecko) Version/26.2 Safari/605.1.15", []string{"de-CH"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := newLocaleModel(tt.userAgent, locales)
+			if got := model.languages(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("languages() = %v, want %v", got, tt.want)
+			}
+			if got := model.intlLocale(); got != "de-CH" {
+				t.Errorf("intlLocale() = %q, want de-CH", got)
+			}
+			first, _, _ := strings.Cut(model.acceptLanguage(), ",")
+			if first != model.languages()[0] {
+				t.Errorf("Accept-Language %q does not start with %q", model.acceptLanguage(), model.languages()[0])
+			}
+		})
+	}
+
+	// Without locales the sampled languages are kept
+	fp := &Fingerprint{Navigator: NavigatorFingerprint{Language: "en-US", Languages: []string{"en-US"}}}
+	newLocaleModel("Chrome/144.0.0.0", nil).apply(fp)
+	if fp.Navigator.Language != "en-US" || fp.Locale != "" {
+		t.Errorf("apply() without locales changed the fingerprint: %+v", fp.Navigator)
+	}
+}
+
+func TestGenerateMultipleLocales(t *testing.T) {
+	gen := newGeneratorOrFatal(t)
+	for _, browser := range []Browser{Chrome, Firefox, Safari} {
+		t.Run(string(browser), func(t *testing.T) {
+			fp, err := gen.Generate(WithHeaderConstraints(HeaderConstraints{
+				Browsers: []Browser{browser},
+				Locales:  []string{"nl-BE", "fr-BE", "en-GB"},
+			}))
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			model := newLocaleModel(fp.Navigator.UserAgent, []string{"nl-BE", "fr-BE", "en-GB"})
+			if got := headerValue(fp.Headers, "accept-language"); got != model.acceptLanguage() {
+				t.Errorf("Accept-Language = %q, want %q", got, model.acceptLanguage())
+			}
+			if !reflect.DeepEqual(fp.Navigator.Languages, model.languages()) {
+				t.Errorf("Languages = %v, want %v", fp.Navigator.Languages, model.languages())
+			}
+			if fp.Navigator.Language != "nl-BE" || fp.Locale != "nl-BE" {
+				t.Errorf("Language = %q and Locale = %q, want nl-BE", fp.Navigator.Language, fp.Locale)
+			}
+		})
+	}
+}
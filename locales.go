@@ -0,0 +1,137 @@
+package forgeron
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+//go:embed region_locales.json
+var regionLocalesJSON []byte
+
+// regionLocales maps regions to the locale chain browsers there usually send, secondary languages included,
+// e.g. BE gives [nl-BE nl fr-BE fr en-US en]
+var regionLocales = sync.OnceValue(func() map[string][]string {
+	var chains map[string][]string
+	if err := json.Unmarshal(regionLocalesJSON, &chains); err != nil {
+		panic("forgeron: invalid region_locales.json: " + err.Error())
+	}
+	return chains
+})
+
+// localeChain builds a realistic locale chain from a language and/or a region,
+// e.g. ("pt", "BR") gives [pt-BR pt en-US en]. A region alone, such as the country of a proxy exit, gives the
+// languages typical of the region when it is known, e.g. ("", "CH") gives [de-CH de fr-CH fr en-US en].
+func localeChain(lang, region string) ([]string, error) {
+	if lang == "" && region == "" {
+		return nil, nil
+	}
+
+	var base language.Base
+	var reg language.Region
+	var err error
+
+	if region != "" {
+		if reg, err = language.ParseRegion(region); err != nil {
+			return nil, newFieldError("Region", fmt.Errorf("invalid region '%s': %v", region, err), region)
+		}
+	}
+	if lang != "" {
+		if base, err = language.ParseBase(lang); err != nil {
+			return nil, newFieldError("Language", fmt.Errorf("invalid language '%s': %v", lang, err), lang)
+		}
+	}
+
+	if lang == "" {
+		if chain, ok := regionLocales()[reg.String()]; ok {
+			return slices.Clone(chain), nil
+		}
+	}
+
+	// Infer the missing part from the likely subtags
+	if lang == "" {
+		tag, err := language.Compose(reg)
+		if err != nil {
+			return nil, newFieldError("Region", fmt.Errorf("invalid region '%s': %v", region, err), region)
+		}
+		base, _ = tag.Base()
+	}
+	if region == "" {
+		tag, err := language.Compose(base)
+		if err != nil {
+			return nil, newFieldError("Language", fmt.Errorf("invalid language '%s': %v", lang, err), lang)
+		}
+		reg, _ = tag.Region()
+	}
+
+	chain := []string{base.String() + "-" + reg.String(), base.String()}
+	if base.String() != "en" {
+		chain = append(chain, "en-US", "en")
+	}
+	return chain, nil
+}
+
+// canonicalLocales validates the locales and canonicalizes them to BCP 47 as browsers send them, e.g. "en_us" gives
+// en-US and "iw" gives he. Extensions and variants are dropped, duplicates are removed.
+func canonicalLocales(locales []string) ([]string, error) {
+	canonical := make([]string, 0, len(locales))
+	var invalid []string
+	for _, locale := range locales {
+		tag, err := language.Parse(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+		base, confidence := tag.Base()
+		if err != nil || confidence != language.Exact || base.String() == "und" {
+			invalid = append(invalid, locale)
+			continue
+		}
+		script, scriptConfidence := tag.Script()
+		region, regionConfidence := tag.Region()
+		name := base.String()
+		if scriptConfidence == language.Exact {
+			name += "-" + script.String()
+		}
+		if regionConfidence == language.Exact {
+			name += "-" + region.String()
+		}
+		if !slices.Contains(canonical, name) {
+			canonical = append(canonical, name)
+		}
+	}
+	if len(invalid) > 0 {
+		return nil, newFieldError("Locales", fmt.Errorf("invalid locales %v", invalid), invalid...)
+	}
+	return canonical, nil
+}
+
+// expandLocales expands canonical locales into the chain a browser configured with them sends, each locale with
+// its likely region followed by its language, e.g. [fr] gives [fr-FR fr en-US en]
+func expandLocales(locales []string) []string {
+	var chain []string
+	add := func(locale string) {
+		if !slices.Contains(chain, locale) {
+			chain = append(chain, locale)
+		}
+	}
+	english := false
+	for _, locale := range locales {
+		tag := language.Make(locale)
+		base, _ := tag.Base()
+		region, _ := tag.Region()
+		if _, confidence := tag.Script(); confidence == language.Exact {
+			add(locale)
+		}
+		add(base.String() + "-" + region.String())
+		add(base.String())
+		english = english || base.String() == "en"
+	}
+	// Browsers fall back to English last
+	if !english {
+		add("en-US")
+		add("en")
+	}
+	return chain
+}
@@ -0,0 +1,159 @@
+package forgeron
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestLocaleChain(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		region   string
+		want     []string
+	}{
+		{"language and region", "pt", "BR", []string{"pt-BR", "pt", "en-US", "en"}},
+		{"region only", "", "NL", []string{"nl-NL", "nl", "en-US", "en"}},
+		{"language only", "de", "", []string{"de-DE", "de", "en-US", "en"}},
+		{"english", "en", "GB", []string{"en-GB", "en"}},
+		{"lowercase region", "fr", "ca", []string{"fr-CA", "fr", "en-US", "en"}},
+		{"multilingual region", "", "be", []string{"nl-BE", "nl", "fr-BE", "fr", "en-US", "en"}},
+		{"region without language", "", "JP", []string{"ja", "en-US", "en"}},
+		{"region not in the dataset", "", "KE", []string{"sw-KE", "sw", "en-US", "en"}},
+		{"language overrides the region languages", "fr", "CH", []string{"fr-CH", "fr", "en-US", "en"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := localeChain(tt.language, tt.region)
+			if err != nil {
+				t.Fatalf("localeChain() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("localeChain() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := localeChain("", "not-a-region"); err == nil {
+		t.Error("expected error for invalid region")
+	}
+}
+
+func TestRegionLocalesCanonical(t *testing.T) {
+	for region, chain := range regionLocales() {
+		if _, err := language.ParseRegion(region); err != nil || strings.ToUpper(region) != region {
+			t.Errorf("region %q is not a canonical region", region)
+		}
+		canonical, err := canonicalLocales(chain)
+		if err != nil {
+			t.Errorf("%s: canonicalLocales() error = %v", region, err)
+			continue
+		}
+		if !reflect.DeepEqual(canonical, chain) {
+			t.Errorf("%s: chain %v is not canonical, want %v", region, chain, canonical)
+		}
+		if len(chain) > maxAcceptLanguages {
+			t.Errorf("%s: chain %v has more than %d locales", region, chain, maxAcceptLanguages)
+		}
+	}
+}
+
+func TestGenerateHeadersFromLanguageAndRegion(t *testing.T) {
+	hgen, err := NewHeaderGenerator()
+	if err != nil {
+		t.Fatalf("NewHeaderGenerator() error = %v", err)
+	}
+	headers, err := hgen.GenerateHeaders(HeaderConstraints{Browsers: []Browser{Chrome}, Language: "pt", Region: "BR"})
+	if err != nil {
+		t.Fatalf("GenerateHeaders() error = %v", err)
+	}
+	want := "pt-BR,pt;q=0.9,en-US;q=0.8,en;q=0.7"
+	if got := headers["Accept-Language"]; got != want {
+		t.Errorf("Accept-Language = %q, want %q", got, want)
+	}
+
+	// A proxy exit country alone gives the languages of the region
+	headers, err = hgen.GenerateHeaders(HeaderConstraints{Browsers: []Browser{Chrome}, Region: "CH"})
+	if err != nil {
+		t.Fatalf("GenerateHeaders() error = %v", err)
+	}
+	want = "de-CH,de;q=0.9,fr-CH;q=0.8,fr;q=0.7,en-US;q=0.6,en;q=0.5"
+	if got := headers["Accept-Language"]; got != want {
+		t.Errorf("Accept-Language = %q, want %q", got, want)
+	}
+}
+
+func TestCanonicalLocales(t *testing.T) {
+	tests := []struct {
+		name    string
+		locales []string
+		want    []string
+		invalid []string
+	}{
+		{"canonical", []string{"de-DE", "de"}, []string{"de-DE", "de"}, nil},
+		{"case and separator", []string{"EN_us", "pt-br"}, []string{"en-US", "pt-BR"}, nil},
+		{"script", []string{"zh-hant-tw"}, []string{"zh-Hant-TW"}, nil},
+		{"deprecated code", []string{"iw"}, []string{"he"}, nil},
+		{"extensions dropped", []string{"en-US-u-ca-gregory", "en-US"}, []string{"en-US"}, nil},
+		{"malformed", []string{"en-US", "english", "*"}, nil, []string{"english", "*"}},
+		{"undetermined", []string{"und"}, nil, []string{"und"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := canonicalLocales(tt.locales)
+			if tt.invalid != nil {
+				var fieldErr *FieldError
+				if !errors.As(err, &fieldErr) || fieldErr.Field != "Locales" || !reflect.DeepEqual(fieldErr.Values, tt.invalid) {
+					t.Fatalf("canonicalLocales() error = %v, want the invalid locales %v", err, tt.invalid)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("canonicalLocales() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("canonicalLocales() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandLocales(t *testing.T) {
+	tests := []struct {
+		locales []string
+		want    []string
+	}{
+		{[]string{"fr"}, []string{"fr-FR", "fr", "en-US", "en"}},
+		{[]string{"de-CH", "fr"}, []string{"de-CH", "de", "fr-FR", "fr", "en-US", "en"}},
+		{[]string{"en-GB"}, []string{"en-GB", "en"}},
+		{[]string{"zh-Hant-TW"}, []string{"zh-Hant-TW", "zh-TW", "zh", "en-US", "en"}},
+	}
+	for _, tt := range tests {
+		if got := expandLocales(tt.locales); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("expandLocales(%v) = %v, want %v", tt.locales, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateHeadersCanonicalLocales(t *testing.T) {
+	hgen, err := NewHeaderGenerator()
+	if err != nil {
+		t.Fatalf("NewHeaderGenerator() error = %v", err)
+	}
+	headers, err := hgen.GenerateHeaders(HeaderConstraints{Browsers: []Browser{Chrome}, Locales: []string{"fr_ca"}, ExpandLocales: true})
+	if err != nil {
+		t.Fatalf("GenerateHeaders() error = %v", err)
+	}
+	want := "fr-CA,fr;q=0.9,en-US;q=0.8,en;q=0.7"
+	if got := headers["Accept-Language"]; got != want {
+		t.Errorf("Accept-Language = %q, want %q", got, want)
+	}
+
+	if _, err := hgen.GenerateHeaders(HeaderConstraints{Locales: []string{"english"}}); err == nil {
+		t.Error("GenerateHeaders() error = nil, want an invalid locale error")
+	}
+}
@@ -0,0 +1,93 @@
+package forgeron
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// maxTracedBans caps the number of banned values reported for a single sampling
+const maxTracedBans = 32
+
+// maxTracedValueLength truncates long values such as stringified screens in backtracking statistics
+const maxTracedValueLength = 64
+
+// backtrackStats records the values banned while the constrained sampler backtracks
+type backtrackStats struct {
+	banned   []string
+	bans     int
+	deadEnds int
+	pruned   int
+}
+
+// ban records a value whose subtree could not satisfy the constraints
+func (t *backtrackStats) ban(nodeName, value string) {
+	if t == nil {
+		return
+	}
+	t.bans++
+	if len(t.banned) < maxTracedBans {
+		if len(value) > maxTracedValueLength {
+			value = value[:maxTracedValueLength] + "..."
+		}
+		t.banned = append(t.banned, fmt.Sprintf("%s=%s", nodeName, value))
+	}
+}
+
+// deadEnd records a node that had no value left to try
+func (t *backtrackStats) deadEnd() {
+	if t != nil {
+		t.deadEnds++
+	}
+}
+
+// prune records a branch skipped because it was already found to have no consistent sample
+func (t *backtrackStats) prune() {
+	if t != nil {
+		t.pruned++
+	}
+}
+
+// debugEnabled reports whether the logger emits debug records
+func debugEnabled(logger *slog.Logger) bool {
+	return logger != nil && logger.Enabled(context.Background(), slog.LevelDebug)
+}
+
+// logDebug emits a debug record when a logger is set
+func logDebug(logger *slog.Logger, msg string, args ...any) {
+	if debugEnabled(logger) {
+		logger.Debug(msg, args...)
+	}
+}
+
+// networkLogger returns a logger tagging records with the network name, or nil without logger
+func networkLogger(logger *slog.Logger, name string) *slog.Logger {
+	if logger == nil {
+		return nil
+	}
+	return logger.With("network", name)
+}
+
+// SetLogger sets the logger receiving debug records about constraint relaxation and sampler backtracking
+func (g *HeaderGenerator) SetLogger(logger *slog.Logger) {
+	g.logger = logger
+	if g.inputGeneratorNetwork != nil {
+		g.inputGeneratorNetwork.logger = networkLogger(logger, "input")
+	}
+	if g.headerGeneratorNetwork != nil {
+		g.headerGeneratorNetwork.logger = networkLogger(logger, "header")
+	}
+}
+
+// WithLogger sets the logger receiving debug records about constraint relaxation and sampler backtracking
+func WithLogger(logger *slog.Logger) FingerprintOption {
+	return func(g *FingerprintGenerator) {
+		g.logger = logger
+		if g.headerGenerator != nil {
+			g.headerGenerator.SetLogger(logger)
+		}
+		if g.network != nil {
+			g.network.logger = networkLogger(logger, "fingerprint")
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package forgeron
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSamplerLogsBacktracking(t *testing.T) {
+	var buf bytes.Buffer
+	network := createTestNetwork()
+	network.logger = slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	if _, ok := network.generateConsistentSampleWhenPossible(map[string][]string{"B": {"b3"}}); ok {
+		t.Fatal("expected sampling to fail")
+	}
+	out := buf.String()
+	for _, want := range []string{"constrained sampling backtracked", "success=false", "bans=2", "deadEnds=3", "A=a1", "A=a2"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log %q does not contain %q", out, want)
+		}
+	}
+
+	// Nothing is recorded above debug level
+	buf.Reset()
+	network.logger = slog.New(slog.NewTextHandler(&buf, nil))
+	network.generateConsistentSampleWhenPossible(map[string][]string{"B": {"b3"}})
+	if buf.Len() != 0 {
+		t.Errorf("unexpected log at info level: %s", buf.String())
+	}
+}
+
+func TestWithLoggerPropagatesToNetworks(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+	gen := newGeneratorOrFatal(t, WithLogger(logger))
+	for name, network := range map[string]*bayesianNetwork{
+		"input":       gen.headerGenerator.inputGeneratorNetwork,
+		"header":      gen.headerGenerator.headerGeneratorNetwork,
+		"fingerprint": gen.network,
+	} {
+		if network.logger == nil {
+			t.Errorf("%s network has no logger", name)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package forgeron
+
+// missingValueToken marks a value the recorded browser did not expose
+const missingValueToken = "*MISSING_VALUE*"
+
+// stringifiedPrefix marks a JSON encoded value in the fingerprint network
+const stringifiedPrefix = "*STRINGIFIED*"
+
+// isMissingValue reports whether a sampled value is the missing value token
+func isMissingValue(value string) bool {
+	return value == missingValueToken
+}
+
+// normalizeRawValues strips the stringified prefix of sampled values and removes the values the browser did not
+// expose, missing values and JSON nulls alike, so absence is told apart from a recorded empty string
+func normalizeRawValues(raw map[string]string) {
+	for key, value := range raw {
+		if len(value) >= len(stringifiedPrefix) && value[:len(stringifiedPrefix)] == stringifiedPrefix {
+			value = value[len(stringifiedPrefix):]
+			raw[key] = value
+			if value == "null" {
+				delete(raw, key)
+			}
+			continue
+		}
+		if isMissingValue(value) {
+			delete(raw, key)
+		}
+	}
+}
+
+// optionalString returns the value of key, or nil when the browser did not expose it
+func optionalString(raw map[string]string, key string) *string {
+	value, ok := raw[key]
+	if !ok {
+		return nil
+	}
+	return &value
+}
+
+// optionalInt returns the integer value of key, or nil when the browser did not expose it
+func optionalInt(raw map[string]string, key string) *int {
+	value, ok := raw[key]
+	if !ok {
+		return nil
+	}
+	i := atoi(value)
+	return &i
+}
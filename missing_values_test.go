@@ -0,0 +1,62 @@
+package forgeron
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeRawValues(t *testing.T) {
+	raw := map[string]string{
+		"doNotTrack":   missingValueToken,
+		"deviceMemory": "*STRINGIFIED*8",
+		"battery":      "*STRINGIFIED*null",
+		"vendorSub":    "",
+		"vendor":       "Google Inc.",
+	}
+	normalizeRawValues(raw)
+	want := map[string]string{"deviceMemory": "8", "vendorSub": "", "vendor": "Google Inc."}
+	if !reflect.DeepEqual(raw, want) {
+		t.Errorf("normalizeRawValues() = %v, want %v", raw, want)
+	}
+
+	if got := optionalString(raw, "doNotTrack"); got != nil {
+		t.Errorf("optionalString() of a missing value = %q, want nil", *got)
+	}
+	if got := optionalString(raw, "vendorSub"); got == nil || *got != "" {
+		t.Errorf("optionalString() of a recorded empty string = %v, want a pointer to it", got)
+	}
+	if got := optionalInt(raw, "deviceMemory"); got == nil || *got != 8 {
+		t.Errorf("optionalInt() = %v, want 8", got)
+	}
+	if got := optionalInt(raw, "battery"); got != nil {
+		t.Errorf("optionalInt() of a null = %d, want nil", *got)
+	}
+}
+
+func TestOptionalFieldsJSON(t *testing.T) {
+	data, err := json.Marshal(NavigatorFingerprint{UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 18_7 like Mac OS X) Version/26.2 Mobile/15E148 Safari/604.1"})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	navigator := string(data)
+	// navigator.doNotTrack is null when unset, while Safari has no navigator.oscpu, deviceMemory or userAgentData
+	if !strings.Contains(navigator, `"doNotTrack":null`) {
+		t.Errorf("expected doNotTrack to be null, got %s", navigator)
+	}
+	for _, field := range []string{"oscpu", "deviceMemory", "userAgentData"} {
+		if strings.Contains(navigator, `"`+field+`"`) {
+			t.Errorf("expected %s to be omitted, got %s", field, navigator)
+		}
+	}
+
+	oscpu := "Linux x86_64"
+	data, err = json.Marshal(NavigatorFingerprint{OSCpu: &oscpu})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"oscpu":"Linux x86_64"`) {
+		t.Errorf("expected oscpu to be kept, got %s", data)
+	}
+}
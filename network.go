@@ -0,0 +1,67 @@
+package forgeron
+
+import (
+	"fmt"
+	"io"
+)
+
+// Data files of the networks
+const (
+	headerNetworkFile      = "header-network-definition.zip"
+	inputNetworkFile       = "input-network-definition.zip"
+	fingerprintNetworkFile = "fingerprint-network-definition.zip"
+)
+
+// Network is a network definition loaded with LoadNetworkFrom, e.g. a custom-trained network to A/B test
+type Network struct {
+	file    string
+	network *bayesianNetwork
+}
+
+// LoadNetworkFrom reads a network definition zip, such as one downloaded at runtime. Header, input and fingerprint
+// networks are told apart by their nodes, and networks of a newer data schema fail with ErrIncompatibleData.
+func LoadNetworkFrom(r io.Reader) (*Network, error) {
+	zipData, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read network: %w", err)
+	}
+	network, err := parseNetworkZip(zipData)
+	if err != nil {
+		return nil, err
+	}
+	file, err := networkFile(network)
+	if err != nil {
+		return nil, err
+	}
+	return &Network{file: file, network: network}, nil
+}
+
+// File returns the data file the network replaces, e.g. "fingerprint-network-definition.zip"
+func (n *Network) File() string {
+	return n.file
+}
+
+// networkFile returns the data file of a network, recognized by the nodes only it has
+func networkFile(network *bayesianNetwork) (string, error) {
+	switch {
+	case network.NodesByName["userAgent"] != nil:
+		return fingerprintNetworkFile, nil
+	case network.NodesByName["*BROWSER_HTTP"] != nil:
+		return inputNetworkFile, nil
+	case network.NodesByName["*HTTP_VERSION"] != nil:
+		return headerNetworkFile, nil
+	default:
+		return "", fmt.Errorf("unknown network definition")
+	}
+}
+
+// WithNetworks makes the generator use the networks instead of loading them from its data. It only applies when
+// creating a generator.
+func WithNetworks(networks ...*Network) FingerprintOption {
+	return func(g *FingerprintGenerator) {
+		g.networks = make(map[string]*bayesianNetwork, len(networks))
+		for _, network := range networks {
+			g.networks[network.file] = network.network
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package forgeron
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadNetworkFrom(t *testing.T) {
+	for _, file := range []string{headerNetworkFile, inputNetworkFile, fingerprintNetworkFile} {
+		data, err := fs.ReadFile(embeddedData(), file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		network, err := LoadNetworkFrom(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("LoadNetworkFrom(%s) error = %v", file, err)
+		}
+		if network.File() != file {
+			t.Errorf("File() = %q, want %q", network.File(), file)
+		}
+	}
+
+	if _, err := LoadNetworkFrom(bytes.NewReader(zipNetwork(t, ""))); err == nil {
+		t.Error("LoadNetworkFrom() error = nil for a network without known nodes")
+	}
+	if _, err := LoadNetworkFrom(bytes.NewReader(zipNetwork(t, "forgeron-data schema=2"))); !errors.Is(err, ErrIncompatibleData) {
+		t.Errorf("LoadNetworkFrom() error = %v, want ErrIncompatibleData", err)
+	}
+	if _, err := LoadNetworkFrom(bytes.NewReader([]byte("not a zip"))); err == nil {
+		t.Error("LoadNetworkFrom() error = nil for invalid data")
+	}
+}
+
+func TestWithNetworks(t *testing.T) {
+	data, err := fs.ReadFile(embeddedData(), fingerprintNetworkFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	network, err := LoadNetworkFrom(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("LoadNetworkFrom() error = %v", err)
+	}
+	first := newGeneratorOrFatal(t, WithNetworks(network))
+	second := newGeneratorOrFatal(t, WithNetworks(network), WithRandomSource(NewSeededSource(1)))
+	if reflect.ValueOf(first.network.NodesByName).Pointer() != reflect.ValueOf(network.network.NodesByName).Pointer() {
+		t.Error("generator does not use the given network")
+	}
+	if first.network == second.network {
+		t.Error("generators share the network instead of a copy")
+	}
+	if _, err := first.Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+}
+
+func TestWithDataSource(t *testing.T) {
+	source := fstest.MapFS{fingerprintNetworkFile: {Data: zipNetwork(t, "forgeron-data schema=2")}}
+	if _, err := NewFingerprintGenerator(WithDataSource(source)); !errors.Is(err, ErrIncompatibleData) {
+		t.Errorf("NewFingerprintGenerator() error = %v, want the data source network to be loaded", err)
+	}
+	// Files missing from the source fall back to the embedded data
+	newGeneratorOrFatal(t, WithDataSource(fstest.MapFS{}))
+}
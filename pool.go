@@ -0,0 +1,160 @@
+package forgeron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+var (
+	// ErrPoolFull is returned by TryGet when the queue is full, servers should answer 429 Too Many Requests
+	ErrPoolFull = errors.New("generation queue is full")
+	// ErrPoolClosed is returned when generating with a closed pool
+	ErrPoolClosed = errors.New("generation pool is closed")
+)
+
+// PoolConfig configures a Pool
+type PoolConfig struct {
+	// Workers is the number of concurrent generations, it defaults to GOMAXPROCS
+	Workers int
+	// QueueSize is the number of requests waiting for a worker, it defaults to 4 per worker
+	QueueSize int
+}
+
+// poolJob is a queued generation request
+type poolJob struct {
+	ctx    context.Context
+	opts   []FingerprintOption
+	result chan poolResult
+}
+
+// poolResult is the outcome of a generation request
+type poolResult struct {
+	fp  *Fingerprint
+	err error
+}
+
+// Pool generates fingerprints with a fixed number of workers and a bounded queue, so bursts of
+// identity requests apply backpressure instead of sampling without limit
+type Pool struct {
+	jobs   chan poolJob
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewPool starts the pool workers. newProvider is called once per worker, so providers that are not
+// safe for concurrent use can be pooled.
+func NewPool(newProvider func() (FingerprintProvider, error), config PoolConfig) (*Pool, error) {
+	if newProvider == nil {
+		return nil, fmt.Errorf("provider constructor is required")
+	}
+	if config.Workers < 0 || config.QueueSize < 0 {
+		return nil, fmt.Errorf("pool workers and queue size cannot be negative")
+	}
+	if config.Workers == 0 {
+		config.Workers = runtime.GOMAXPROCS(0)
+	}
+	if config.QueueSize == 0 {
+		config.QueueSize = 4 * config.Workers
+	}
+
+	providers := make([]FingerprintProvider, config.Workers)
+	for i := range providers {
+		provider, err := newProvider()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create provider: %w", err)
+		}
+		providers[i] = provider
+	}
+
+	p := &Pool{jobs: make(chan poolJob, config.QueueSize)}
+	for _, provider := range providers {
+		p.wg.Add(1)
+		go p.work(provider)
+	}
+	return p, nil
+}
+
+// Get queues a generation and waits for its fingerprint. It blocks while the queue is full,
+// until ctx is done.
+func (p *Pool) Get(ctx context.Context, opts ...FingerprintOption) (*Fingerprint, error) {
+	return p.submit(ctx, opts, true)
+}
+
+// TryGet queues a generation and waits for its fingerprint, it returns ErrPoolFull at once when the queue is full
+func (p *Pool) TryGet(ctx context.Context, opts ...FingerprintOption) (*Fingerprint, error) {
+	return p.submit(ctx, opts, false)
+}
+
+// Generate implements FingerprintProvider, blocking until a worker is available
+func (p *Pool) Generate(opts ...FingerprintOption) (*Fingerprint, error) {
+	return p.Get(context.Background(), opts...)
+}
+
+// Pending returns the number of queued requests waiting for a worker
+func (p *Pool) Pending() int {
+	return len(p.jobs)
+}
+
+// Close stops accepting requests, lets the workers finish the queued ones and waits for them
+func (p *Pool) Close() {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.jobs)
+	}
+	p.mu.Unlock()
+	p.wg.Wait()
+}
+
+// submit queues a job, waiting for room when wait is set, and returns its result
+func (p *Pool) submit(ctx context.Context, opts []FingerprintOption, wait bool) (*Fingerprint, error) {
+	job := poolJob{ctx: ctx, opts: opts, result: make(chan poolResult, 1)}
+
+	// The read lock keeps Close from closing the queue while sending to it
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return nil, ErrPoolClosed
+	}
+	if wait {
+		select {
+		case p.jobs <- job:
+		case <-ctx.Done():
+			p.mu.RUnlock()
+			return nil, ctx.Err()
+		}
+	} else {
+		select {
+		case p.jobs <- job:
+		default:
+			p.mu.RUnlock()
+			return nil, ErrPoolFull
+		}
+	}
+	p.mu.RUnlock()
+
+	select {
+	case result := <-job.result:
+		return result.fp, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// work runs the queued jobs with its own provider until the queue is closed
+func (p *Pool) work(provider FingerprintProvider) {
+	defer p.wg.Done()
+	for job := range p.jobs {
+		// Skip the requests abandoned while queued
+		if err := job.ctx.Err(); err != nil {
+			job.result <- poolResult{err: err}
+			continue
+		}
+		fp, err := provider.Generate(job.opts...)
+		job.result <- poolResult{fp: fp, err: err}
+	}
+}
@@ -0,0 +1,77 @@
+package forgeron
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// blockingProvider generates a fingerprint each time release receives a value
+type blockingProvider struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (p *blockingProvider) Generate(opts ...FingerprintOption) (*Fingerprint, error) {
+	p.started <- struct{}{}
+	<-p.release
+	return &Fingerprint{}, nil
+}
+
+func TestPoolBackpressure(t *testing.T) {
+	provider := &blockingProvider{started: make(chan struct{}, 10), release: make(chan struct{})}
+	pool, err := NewPool(func() (FingerprintProvider, error) { return provider, nil }, PoolConfig{Workers: 1, QueueSize: 1})
+	if err != nil {
+		t.Fatalf("NewPool() error = %v", err)
+	}
+	defer pool.Close()
+
+	// One request runs, the next one waits in the queue
+	results := make(chan error, 2)
+	go func() {
+		_, err := pool.Get(context.Background())
+		results <- err
+	}()
+	<-provider.started
+	go func() {
+		_, err := pool.Get(context.Background())
+		results <- err
+	}()
+	for pool.Pending() != 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if _, err := pool.TryGet(context.Background()); !errors.Is(err, ErrPoolFull) {
+		t.Errorf("TryGet() error = %v, want ErrPoolFull", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := pool.Get(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Get() error = %v, want a deadline while the queue is full", err)
+	}
+
+	provider.release <- struct{}{}
+	<-provider.started
+	provider.release <- struct{}{}
+	for i := 0; i < 2; i++ {
+		if err := <-results; err != nil {
+			t.Errorf("Get() error = %v", err)
+		}
+	}
+}
+
+func TestPoolClose(t *testing.T) {
+	pool, err := NewPool(func() (FingerprintProvider, error) { return &countingProvider{}, nil }, PoolConfig{Workers: 2})
+	if err != nil {
+		t.Fatalf("NewPool() error = %v", err)
+	}
+	fp, err := pool.Generate()
+	if err != nil || fp == nil {
+		t.Fatalf("Generate() = %v, %v", fp, err)
+	}
+	pool.Close()
+	if _, err := pool.Get(context.Background()); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Get() error = %v, want ErrPoolClosed", err)
+	}
+}
@@ -0,0 +1,17 @@
+package forgeron
+
+// postProcess applies consistency rules that are not guaranteed by the recorded data
+func (g *FingerprintGenerator) postProcess(fp *Fingerprint) {
+	applyEngineConsistency(fp)
+	applyPlatformConsistency(fp)
+	applyLinuxConsistency(fp, g.linuxFlavor)
+	applyChromeOSConsistency(fp)
+	applyTabletConsistency(fp)
+	applyClientHintConsistency(fp)
+	applyPrivacySignals(fp, g.privacySignals)
+	// Embedded profiles and in-app browsers decorate the final user agent, so they run last
+	applyEmbeddedProfile(fp, g.embedded)
+	applyInAppBrowser(fp, g.inApp)
+	// An Accept override is kept as given
+	applyFeatureConsistency(fp, g.headerConstraints.Accept == "")
+}
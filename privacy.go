@@ -0,0 +1,92 @@
+package forgeron
+
+import (
+	"slices"
+	"strings"
+)
+
+// PrivacySignals are the privacy preferences turned on in the browser of the identity
+type PrivacySignals struct {
+	// DoNotTrack sends DNT: 1 and sets navigator.doNotTrack, on every browser but Safari which dropped it
+	DoNotTrack bool `json:"doNotTrack,omitempty"`
+	// GlobalPrivacyControl sends Sec-GPC: 1 and sets navigator.globalPrivacyControl, on Firefox and Brave, the
+	// browsers implementing it
+	GlobalPrivacyControl bool `json:"globalPrivacyControl,omitempty"`
+}
+
+// WithPrivacySignals sets the Do Not Track and Global Privacy Control preferences of the fingerprints, keeping
+// their headers and navigator properties in sync. Without it, DNT is left as recorded and GPC is not sent.
+func WithPrivacySignals(signals PrivacySignals) FingerprintOption {
+	return func(g *FingerprintGenerator) {
+		g.privacySignals = &signals
+	}
+}
+
+// applyPrivacySignals sets the DNT and Sec-GPC headers and the matching navigator properties of the browsers
+// supporting them, and removes them elsewhere
+func applyPrivacySignals(fp *Fingerprint, signals *PrivacySignals) {
+	if signals == nil {
+		return
+	}
+	engine := engineOf(fp.Navigator.UserAgent)
+
+	fp.deleteHeader("DNT")
+	switch {
+	case engine == webKitEngine:
+	case signals.DoNotTrack:
+		fp.setHeader("DNT", "1")
+		enabled := "1"
+		fp.Navigator.DoNotTrack = &enabled
+	case engine == geckoEngine:
+		unspecified := "unspecified"
+		fp.Navigator.DoNotTrack = &unspecified
+	default:
+		fp.Navigator.DoNotTrack = nil
+	}
+
+	fp.deleteHeader("Sec-GPC")
+	fp.Navigator.GlobalPrivacyControl = nil
+	if engine == geckoEngine || isBrave(fp.Navigator.UserAgentData) {
+		enabled := signals.GlobalPrivacyControl
+		fp.Navigator.GlobalPrivacyControl = &enabled
+		if enabled {
+			fp.setHeader("Sec-GPC", "1")
+		}
+	}
+}
+
+// isBrave reports whether the user agent data is the one of Brave, whose user agent is the one of Chrome
+func isBrave(data *UserAgentData) bool {
+	return data != nil && slices.ContainsFunc(data.Brands, func(b UserAgentBrand) bool { return b.Brand == "Brave" })
+}
+
+// setHeader sets a header of the fingerprint, replacing the one of any case. Names are lowercased for HTTP/2.
+func (f *Fingerprint) setHeader(name, value string) {
+	f.deleteHeader(name)
+	if f.httpVersion() == HTTP2 {
+		name = strings.ToLower(name)
+	}
+	if f.Headers == nil {
+		f.Headers = make(map[string]string)
+	}
+	f.Headers[name] = value
+}
+
+// header returns a header of the fingerprint ignoring case
+func (f *Fingerprint) header(name string) (string, bool) {
+	for key, value := range f.Headers {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// deleteHeader removes a header of the fingerprint ignoring case
+func (f *Fingerprint) deleteHeader(name string) {
+	for key := range f.Headers {
+		if strings.EqualFold(key, name) {
+			delete(f.Headers, key)
+		}
+	}
+}
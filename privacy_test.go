@@ -0,0 +1,78 @@
+package forgeron
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestApplyPrivacySignals(t *testing.T) {
+	const (
+		chrome  = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36"
+		firefox = "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:147.0) Gecko/20100101 Firefox/147.0"
+		safari  = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/18.3 Safari/605.1.15"
+	)
+	brave := &UserAgentData{Brands: []UserAgentBrand{{Brand: "Brave", Version: "144"}, {Brand: "Chromium", Version: "144"}}}
+	tests := []struct {
+		name      string
+		userAgent string
+		uaData    *UserAgentData
+		signals   PrivacySignals
+		wantDNT   string
+		wantGPC   string
+		// wantNavGPC is navigator.globalPrivacyControl, "" when not exposed
+		wantNavGPC string
+	}{
+		{"chrome both", chrome, nil, PrivacySignals{DoNotTrack: true, GlobalPrivacyControl: true}, "1", "", ""},
+		{"chrome none", chrome, nil, PrivacySignals{}, "", "", ""},
+		{"brave gpc", chrome, brave, PrivacySignals{GlobalPrivacyControl: true}, "", "1", "true"},
+		{"firefox both", firefox, nil, PrivacySignals{DoNotTrack: true, GlobalPrivacyControl: true}, "1", "1", "true"},
+		{"firefox none", firefox, nil, PrivacySignals{}, "", "", "false"},
+		{"safari both", safari, nil, PrivacySignals{DoNotTrack: true, GlobalPrivacyControl: true}, "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, http1 := range []bool{true, false} {
+				headers := map[string]string{"dnt": "1", "sec-gpc": "1"}
+				if http1 {
+					headers = map[string]string{"Connection": "keep-alive", "DNT": "1"}
+				}
+				fp := &Fingerprint{Headers: headers, Navigator: NavigatorFingerprint{UserAgent: tt.userAgent, UserAgentData: tt.uaData}}
+				applyPrivacySignals(fp, &tt.signals)
+
+				if got := headerValue(fp.Headers, "DNT"); got != tt.wantDNT {
+					t.Errorf("DNT = %q, want %q", got, tt.wantDNT)
+				}
+				if got := headerValue(fp.Headers, "Sec-GPC"); got != tt.wantGPC {
+					t.Errorf("Sec-GPC = %q, want %q", got, tt.wantGPC)
+				}
+				if tt.wantGPC != "" && http1 && fp.Headers["Sec-GPC"] == "" {
+					t.Errorf("headers = %v, want Sec-GPC in the HTTP/1 case", fp.Headers)
+				}
+				if tt.wantDNT != "" && (fp.Navigator.DoNotTrack == nil || *fp.Navigator.DoNotTrack != tt.wantDNT) {
+					t.Errorf("navigator.doNotTrack = %v, want %q", fp.Navigator.DoNotTrack, tt.wantDNT)
+				}
+				var navGPC string
+				if gpc := fp.Navigator.GlobalPrivacyControl; gpc != nil {
+					navGPC = strconv.FormatBool(*gpc)
+				}
+				if navGPC != tt.wantNavGPC {
+					t.Errorf("navigator.globalPrivacyControl = %q, want %q", navGPC, tt.wantNavGPC)
+				}
+			}
+		})
+	}
+}
+
+func TestGeneratePrivacySignals(t *testing.T) {
+	gen := newGeneratorOrFatal(t, WithPrivacySignals(PrivacySignals{DoNotTrack: true, GlobalPrivacyControl: true}))
+	fp, err := gen.Generate(WithHeaderConstraints(HeaderConstraints{Browsers: []Browser{Firefox}, HTTPVersion: HTTP2}))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if fp.Headers["dnt"] != "1" || fp.Headers["sec-gpc"] != "1" {
+		t.Errorf("headers = %v, want dnt and sec-gpc set to 1", fp.Headers)
+	}
+	if fp.Navigator.GlobalPrivacyControl == nil || !*fp.Navigator.GlobalPrivacyControl {
+		t.Errorf("navigator.globalPrivacyControl = %v, want true", fp.Navigator.GlobalPrivacyControl)
+	}
+}
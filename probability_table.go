@@ -0,0 +1,123 @@
+package forgeron
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// probabilityTable is a conditional probability table compiled from the nested JSON maps of a node, so sampling
+// walks typed branches instead of asserting interface values and copying distributions
+type probabilityTable struct {
+	// deeper holds the tables below each value of the next parent, nil on leaves
+	deeper map[string]*probabilityTable
+	// skip is the table used for parent values missing from deeper
+	skip *probabilityTable
+	// values are the indexes of the leaf values in node.values, ascending so they follow the order of the values
+	values        []int32
+	probabilities []float64
+}
+
+// compile compiles the conditional probabilities of the node into its probability table and drops the JSON maps
+func (n *node) compile() error {
+	leafValues := make(map[string]bool)
+	if err := collectLeafValues(n.ConditionalProbs, leafValues); err != nil {
+		return fmt.Errorf("node %s: %w", n.Name, err)
+	}
+	for _, value := range n.PossibleValues {
+		leafValues[value] = true
+	}
+	n.values = slices.Sorted(maps.Keys(leafValues))
+	n.valueIndex = make(map[string]int32, len(n.values))
+	for i, value := range n.values {
+		n.valueIndex[value] = int32(i)
+	}
+	n.table = n.compileTable(n.ConditionalProbs)
+	n.ConditionalProbs = nil
+	return nil
+}
+
+// collectLeafValues adds the values of the leaf distributions below probabilities to values
+func collectLeafValues(probabilities map[string]any, values map[string]bool) error {
+	if deeper, ok := probabilities["deeper"]; ok {
+		branches, ok := deeper.(map[string]any)
+		if !ok {
+			return fmt.Errorf("invalid deeper branches")
+		}
+		for _, next := range branches {
+			nextProbabilities, ok := next.(map[string]any)
+			if !ok {
+				return fmt.Errorf("invalid branch")
+			}
+			if err := collectLeafValues(nextProbabilities, values); err != nil {
+				return err
+			}
+		}
+		if skip, ok := probabilities["skip"].(map[string]any); ok {
+			return collectLeafValues(skip, values)
+		}
+		return nil
+	}
+	for value, probability := range probabilities {
+		if _, ok := probability.(float64); ok {
+			values[value] = true
+		}
+	}
+	return nil
+}
+
+// compileTable compiles a level of conditional probabilities, whose structure was checked by collectLeafValues
+func (n *node) compileTable(probabilities map[string]any) *probabilityTable {
+	table := &probabilityTable{}
+	if deeper, ok := probabilities["deeper"].(map[string]any); ok {
+		table.deeper = make(map[string]*probabilityTable, len(deeper))
+		for parentValue, next := range deeper {
+			table.deeper[parentValue] = n.compileTable(next.(map[string]any))
+		}
+		if skip, ok := probabilities["skip"].(map[string]any); ok {
+			table.skip = n.compileTable(skip)
+		}
+		return table
+	}
+	// Values are indexed in sorted order, so visiting them sorted keeps the leaf ascending
+	for _, value := range slices.Sorted(maps.Keys(probabilities)) {
+		if p, ok := probabilities[value].(float64); ok {
+			table.values = append(table.values, n.valueIndex[value])
+			table.probabilities = append(table.probabilities, p)
+		}
+	}
+	return table
+}
+
+// lookup returns the leaf distribution of the node given the parent values, nil when a parent value has neither
+// a branch nor a skip branch
+func (n *node) lookup(parentValues map[string]string) *probabilityTable {
+	table := n.table
+	for _, parentName := range n.ParentNames {
+		if table == nil || table.deeper == nil {
+			break
+		}
+		if next, ok := table.deeper[parentValues[parentName]]; ok {
+			table = next
+		} else {
+			table = table.skip
+		}
+	}
+	return table
+}
+
+// probability returns the probability of the value in the leaf, and whether the leaf has the value
+func (t *probabilityTable) probability(n *node, value string) (float64, bool) {
+	if t == nil {
+		return 0, false
+	}
+	index, ok := n.valueIndex[value]
+	if !ok {
+		return 0, false
+	}
+	i, found := slices.BinarySearch(t.values, index)
+	if !found {
+		return 0, false
+	}
+	return t.probabilities[i], true
+}
@@ -0,0 +1,78 @@
+package forgeron
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompileProbabilityTable(t *testing.T) {
+	n := &node{
+		Name:           "C",
+		ParentNames:    []string{"A", "B"},
+		PossibleValues: []string{"c3"},
+		ConditionalProbs: map[string]interface{}{
+			"deeper": map[string]interface{}{
+				"a1": map[string]interface{}{
+					"deeper": map[string]interface{}{
+						"b1": map[string]interface{}{"c2": 0.25, "c1": 0.75},
+					},
+					"skip": map[string]interface{}{"c1": 1.0},
+				},
+			},
+			"skip": map[string]interface{}{"c2": 1.0},
+		},
+	}
+	if err := n.compile(); err != nil {
+		t.Fatalf("compile() error = %v", err)
+	}
+	if want := []string{"c1", "c2", "c3"}; !reflect.DeepEqual(n.values, want) {
+		t.Errorf("values = %v, want %v", n.values, want)
+	}
+	if n.ConditionalProbs != nil {
+		t.Error("ConditionalProbs kept after compiling")
+	}
+
+	tests := []struct {
+		name         string
+		parentValues map[string]string
+		want         map[string]float64
+	}{
+		{"branch", map[string]string{"A": "a1", "B": "b1"}, map[string]float64{"c1": 0.75, "c2": 0.25}},
+		{"inner skip", map[string]string{"A": "a1", "B": "b2"}, map[string]float64{"c1": 1}},
+		{"outer skip", map[string]string{"A": "a2"}, map[string]float64{"c2": 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leaf := n.lookup(tt.parentValues)
+			got := make(map[string]float64)
+			for _, value := range n.values {
+				if p, ok := leaf.probability(n, value); ok {
+					got[value] = p
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lookup() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileProbabilityTableRejectsInvalidBranches(t *testing.T) {
+	n := &node{Name: "B", ConditionalProbs: map[string]interface{}{"deeper": map[string]interface{}{"a1": 0.5}}}
+	if err := n.compile(); err == nil {
+		t.Error("compile() error = nil for a branch that is not a table")
+	}
+}
+
+func TestSampleDoesNotAllocate(t *testing.T) {
+	network := createTestNetwork()
+	nodeB := network.NodesByName["B"]
+	parentValues := map[string]string{"A": "a1"}
+	allocs := testing.AllocsPerRun(100, func() {
+		nodeB.sample(network.random, parentValues)
+		nodeB.sampleAccordingToRestrictions(network.random, parentValues, nodeB.PossibleValues, []string{"b1"})
+	})
+	if allocs > 0 {
+		t.Errorf("sampling allocated %v times", allocs)
+	}
+}
@@ -0,0 +1,232 @@
+// Package probes provides common headless-detection probes as test vectors for forgeron fingerprints.
+//
+// Each probe is a JavaScript expression evaluated in the page together with the value it is expected
+// to return once a fingerprint has been applied. The probes can be run against any browser automation
+// library by supplying an Evaluator.
+package probes
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ta0uf19/forgeron"
+)
+
+// Probe represents a single detection probe
+type Probe struct {
+	// Name identifies the probe in reports
+	Name string
+	// Expression is the JavaScript expression evaluated in the page
+	Expression string
+	// Expected returns the value the expression must evaluate to for the given fingerprint
+	Expected func(fp *forgeron.Fingerprint) any
+	// Applies reports whether the probe is meaningful for the given fingerprint, nil means always
+	Applies func(fp *forgeron.Fingerprint) bool
+}
+
+// Vector is a probe resolved against a fingerprint
+type Vector struct {
+	Name       string
+	Expression string
+	Expected   any
+}
+
+// Result is the outcome of evaluating a single vector
+type Result struct {
+	Vector
+	Actual any
+	Err    error
+}
+
+// Passed returns true if the vector evaluated to the expected value
+func (r Result) Passed() bool {
+	return r.Err == nil && equal(r.Expected, r.Actual)
+}
+
+// Evaluator evaluates a JavaScript expression in the page and returns its JSON-compatible value
+type Evaluator func(expression string) (any, error)
+
+// isChromium returns true if the fingerprint claims a Chromium based browser
+func isChromium(fp *forgeron.Fingerprint) bool {
+	return fp.Navigator.UserAgentData != nil || strings.Contains(fp.Navigator.UserAgent, "Chrome/")
+}
+
+// All returns the built-in probes
+func All() []Probe {
+	return []Probe{
+		{
+			Name:       "webdriver",
+			Expression: "navigator.webdriver",
+			Expected:   func(fp *forgeron.Fingerprint) any { return false },
+		},
+		{
+			Name:       "headless-user-agent",
+			Expression: "/Headless/.test(navigator.userAgent)",
+			Expected:   func(fp *forgeron.Fingerprint) any { return false },
+		},
+		{
+			Name:       "user-agent",
+			Expression: "navigator.userAgent",
+			Expected:   func(fp *forgeron.Fingerprint) any { return fp.Navigator.UserAgent },
+		},
+		{
+			Name:       "app-version",
+			Expression: "navigator.appVersion",
+			Expected:   func(fp *forgeron.Fingerprint) any { return fp.Navigator.AppVersion },
+		},
+		{
+			Name:       "platform",
+			Expression: "navigator.platform",
+			Expected:   func(fp *forgeron.Fingerprint) any { return fp.Navigator.Platform },
+		},
+		{
+			Name:       "vendor",
+			Expression: "navigator.vendor",
+			Expected:   func(fp *forgeron.Fingerprint) any { return fp.Navigator.Vendor },
+		},
+		{
+			Name:       "languages",
+			Expression: "navigator.languages",
+			Expected:   func(fp *forgeron.Fingerprint) any { return fp.Navigator.Languages },
+		},
+		{
+			Name:       "language",
+			Expression: "navigator.language",
+			Expected:   func(fp *forgeron.Fingerprint) any { return fp.Navigator.Language },
+		},
+		{
+			Name:       "hardware-concurrency",
+			Expression: "navigator.hardwareConcurrency",
+			Expected:   func(fp *forgeron.Fingerprint) any { return fp.Navigator.HardwareConcurrency },
+		},
+		{
+			Name:       "device-memory",
+			Expression: "navigator.deviceMemory",
+			Expected:   func(fp *forgeron.Fingerprint) any { return *fp.Navigator.DeviceMemory },
+			Applies:    func(fp *forgeron.Fingerprint) bool { return fp.Navigator.DeviceMemory != nil },
+		},
+		{
+			Name:       "max-touch-points",
+			Expression: "navigator.maxTouchPoints",
+			Expected:   func(fp *forgeron.Fingerprint) any { return fp.Navigator.MaxTouchPoints },
+		},
+		{
+			Name:       "plugins-length",
+			Expression: "navigator.plugins.length",
+			Expected:   func(fp *forgeron.Fingerprint) any { return len(fp.PluginsData.Plugins) },
+		},
+		{
+			Name:       "screen-size",
+			Expression: "[screen.width, screen.height]",
+			Expected:   func(fp *forgeron.Fingerprint) any { return []int{fp.Screen.Width, fp.Screen.Height} },
+		},
+		{
+			Name:       "screen-avail-size",
+			Expression: "[screen.availWidth, screen.availHeight]",
+			Expected:   func(fp *forgeron.Fingerprint) any { return []int{fp.Screen.AvailWidth, fp.Screen.AvailHeight} },
+		},
+		{
+			Name:       "color-depth",
+			Expression: "screen.colorDepth",
+			Expected:   func(fp *forgeron.Fingerprint) any { return fp.Screen.ColorDepth },
+		},
+		{
+			Name:       "device-pixel-ratio",
+			Expression: "window.devicePixelRatio",
+			Expected:   func(fp *forgeron.Fingerprint) any { return fp.Screen.DevicePixelRatio },
+		},
+		{
+			Name:       "window-chrome",
+			Expression: "typeof window.chrome === 'object'",
+			Expected:   func(fp *forgeron.Fingerprint) any { return true },
+			Applies:    isChromium,
+		},
+		{
+			Name:       "user-agent-data-platform",
+			Expression: "navigator.userAgentData && navigator.userAgentData.platform",
+			Expected:   func(fp *forgeron.Fingerprint) any { return fp.Navigator.UserAgentData.Platform },
+			Applies:    func(fp *forgeron.Fingerprint) bool { return fp.Navigator.UserAgentData != nil },
+		},
+		{
+			Name: "webgl-vendor",
+			Expression: `(() => {
+	const gl = document.createElement('canvas').getContext('webgl');
+	const ext = gl && gl.getExtension('WEBGL_debug_renderer_info');
+	return ext ? gl.getParameter(ext.UNMASKED_VENDOR_WEBGL) : null;
+})()`,
+			Expected: func(fp *forgeron.Fingerprint) any { return fp.VideoCard.Vendor },
+			Applies:  func(fp *forgeron.Fingerprint) bool { return fp.VideoCard != nil },
+		},
+		{
+			Name: "webgl-renderer",
+			Expression: `(() => {
+	const gl = document.createElement('canvas').getContext('webgl');
+	const ext = gl && gl.getExtension('WEBGL_debug_renderer_info');
+	return ext ? gl.getParameter(ext.UNMASKED_RENDERER_WEBGL) : null;
+})()`,
+			Expected: func(fp *forgeron.Fingerprint) any { return fp.VideoCard.Renderer },
+			Applies:  func(fp *forgeron.Fingerprint) bool { return fp.VideoCard != nil },
+		},
+	}
+}
+
+// Vectors resolves the built-in probes against a fingerprint, skipping the ones that do not apply
+func Vectors(fp *forgeron.Fingerprint) []Vector {
+	probes := All()
+	vectors := make([]Vector, 0, len(probes))
+	for _, p := range probes {
+		if p.Applies != nil && !p.Applies(fp) {
+			continue
+		}
+		vectors = append(vectors, Vector{
+			Name:       p.Name,
+			Expression: p.Expression,
+			Expected:   p.Expected(fp),
+		})
+	}
+	return vectors
+}
+
+// Run evaluates every applicable probe with the given evaluator and returns the results
+func Run(fp *forgeron.Fingerprint, eval Evaluator) []Result {
+	vectors := Vectors(fp)
+	results := make([]Result, 0, len(vectors))
+	for _, v := range vectors {
+		actual, err := eval(v.Expression)
+		results = append(results, Result{Vector: v, Actual: actual, Err: err})
+	}
+	return results
+}
+
+// Failures returns an error describing every failed result, or nil if all passed
+func Failures(results []Result) error {
+	var failed []string
+	for _, r := range results {
+		if r.Passed() {
+			continue
+		}
+		if r.Err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", r.Name, r.Err))
+			continue
+		}
+		failed = append(failed, fmt.Sprintf("%s: got %v, want %v", r.Name, r.Actual, r.Expected))
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("probes failed: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// equal compares two values by their JSON representation, since evaluators return decoded JS values
+func equal(expected, actual any) bool {
+	e, err := json.Marshal(expected)
+	if err != nil {
+		return false
+	}
+	a, err := json.Marshal(actual)
+	if err != nil {
+		return false
+	}
+	return string(e) == string(a)
+}
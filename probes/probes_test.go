@@ -0,0 +1,69 @@
+package probes
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ta0uf19/forgeron"
+)
+
+// testFingerprint returns a minimal Chromium fingerprint
+func testFingerprint() *forgeron.Fingerprint {
+	memory := 8
+	return &forgeron.Fingerprint{
+		Screen: forgeron.ScreenFingerprint{Width: 1920, Height: 1080, AvailWidth: 1920, AvailHeight: 1040, ColorDepth: 24, DevicePixelRatio: 1},
+		Navigator: forgeron.NavigatorFingerprint{
+			UserAgent:           "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36",
+			UserAgentData:       &forgeron.UserAgentData{Platform: "Windows"},
+			Platform:            "Win32",
+			Vendor:              "Google Inc.",
+			Language:            "en-US",
+			Languages:           []string{"en-US"},
+			DeviceMemory:        &memory,
+			HardwareConcurrency: 8,
+		},
+	}
+}
+
+func TestVectorsSkipsInapplicableProbes(t *testing.T) {
+	fp := testFingerprint()
+	names := make(map[string]bool)
+	for _, v := range Vectors(fp) {
+		names[v.Name] = true
+	}
+	if !names["window-chrome"] {
+		t.Error("expected window-chrome probe for a Chromium fingerprint")
+	}
+	if names["webgl-vendor"] {
+		t.Error("expected webgl-vendor probe to be skipped without a video card")
+	}
+}
+
+func TestRunComparesDecodedValues(t *testing.T) {
+	fp := testFingerprint()
+	values := map[string]any{}
+	for _, v := range Vectors(fp) {
+		values[v.Expression] = v.Expected
+	}
+	// JS numbers and arrays come back decoded as float64 and []any
+	values["navigator.hardwareConcurrency"] = float64(8)
+	values["[screen.width, screen.height]"] = []any{float64(1920), float64(1080)}
+
+	results := Run(fp, func(expression string) (any, error) {
+		return values[expression], nil
+	})
+	if err := Failures(results); err != nil {
+		t.Fatalf("Failures() = %v", err)
+	}
+
+	values["navigator.webdriver"] = true
+	results = Run(fp, func(expression string) (any, error) {
+		if expression == "navigator.platform" {
+			return nil, errors.New("evaluation failed")
+		}
+		return values[expression], nil
+	})
+	if err := Failures(results); err == nil {
+		t.Fatal("expected failures for webdriver and platform probes")
+	}
+}
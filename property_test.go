@@ -0,0 +1,165 @@
+package forgeron
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"testing/quick"
+	"time"
+)
+
+var propertySeed = flag.Int64("property.seed", 0, "seed of the property tests, random when zero")
+
+// propertyLocales are the locales random constraints pick from
+var propertyLocales = []string{"en-US", "en-GB", "de-DE", "fr-FR", "fr", "pt-BR", "ja-JP", "es-MX"}
+
+// randomConstraints are random fingerprint constraints for property tests
+type randomConstraints struct {
+	Constraints
+}
+
+// Generate implements quick.Generator, each field is left to the defaults half of the time
+func (randomConstraints) Generate(r *rand.Rand, _ int) reflect.Value {
+	var c Constraints
+	c.Browsers = randomSubset(r, SupportedBrowsers)
+	c.OS = randomSubset(r, SupportedOS)
+	c.Devices = randomSubset(r, SupportedDevices)
+	c.Locales = randomSubset(r, propertyLocales)
+	if r.Intn(4) == 0 {
+		c.HTTPVersion = SupportedHTTP[r.Intn(len(SupportedHTTP))]
+	}
+	if r.Intn(4) == 0 {
+		minWidth := 800 + 200*r.Intn(10)
+		c.Screen = &Screen{MinWidth: &minWidth}
+	}
+	c.Strict = r.Intn(2) == 0
+	return reflect.ValueOf(randomConstraints{c})
+}
+
+// String describes the constraints in failure messages
+func (c randomConstraints) String() string {
+	screen := "any"
+	if c.Screen != nil {
+		screen = c.Screen.String()
+	}
+	return fmt.Sprintf("browsers=%v os=%v devices=%v locales=%v http=%q screen=%s strict=%v",
+		c.Browsers, c.OS, c.Devices, c.Locales, c.HTTPVersion, screen, c.Strict)
+}
+
+// randomSubset returns a random non-empty subset of values half of the time, nil otherwise
+func randomSubset[T any](r *rand.Rand, values []T) []T {
+	if r.Intn(2) == 0 {
+		return nil
+	}
+	var subset []T
+	for _, i := range r.Perm(len(values))[:1+r.Intn(len(values))] {
+		subset = append(subset, values[i])
+	}
+	return subset
+}
+
+// propertyConfig runs fewer cases in short mode. Cases are generated from a logged seed, rerun a failure with
+// -property.seed.
+func propertyConfig(t *testing.T) *quick.Config {
+	seed := *propertySeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	t.Logf("property seed %d", seed)
+	config := &quick.Config{MaxCount: 300, Rand: rand.New(rand.NewSource(seed))}
+	if testing.Short() {
+		config.MaxCount = 30
+	}
+	return config
+}
+
+// checkProperty generates a fingerprint for random constraints and checks it with property, which also gets
+// the relaxations of the generation
+func checkProperty(t *testing.T, property func(c randomConstraints, fp *Fingerprint, report *RelaxationReport) error) {
+	t.Helper()
+	gen := newGeneratorOrFatal(t)
+	f := func(c randomConstraints) bool {
+		report := &RelaxationReport{}
+		fp, err := gen.Generate(WithConstraints(c.Constraints), WithRelaxationReport(report))
+		if err != nil {
+			if !c.Strict {
+				t.Logf("%v: Generate() error = %v outside strict mode", c, err)
+				return false
+			}
+			return true
+		}
+		if err := property(c, fp, report); err != nil {
+			t.Logf("%v: %v", c, err)
+			return false
+		}
+		return true
+	}
+	if err := quick.Check(f, propertyConfig(t)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPropertyScreenFitsAvailableArea(t *testing.T) {
+	checkProperty(t, func(_ randomConstraints, fp *Fingerprint, _ *RelaxationReport) error {
+		if s := fp.Screen; s.AvailWidth > s.Width || s.AvailHeight > s.Height {
+			return fmt.Errorf("available area %dx%d exceeds screen %dx%d", s.AvailWidth, s.AvailHeight, s.Width, s.Height)
+		}
+		return nil
+	})
+}
+
+func TestPropertyLanguagesMatchAcceptLanguage(t *testing.T) {
+	checkProperty(t, func(_ randomConstraints, fp *Fingerprint, _ *RelaxationReport) error {
+		languages := fp.Navigator.Languages
+		if len(languages) == 0 || fp.Navigator.Language != languages[0] {
+			return fmt.Errorf("language %q is not the first of %v", fp.Navigator.Language, languages)
+		}
+		first, _, _ := strings.Cut(headerValue(fp.Headers, "accept-language"), ",")
+		if first, _, _ = strings.Cut(first, ";"); first != languages[0] {
+			return fmt.Errorf("first Accept-Language locale %q is not navigator.language %q", first, languages[0])
+		}
+		return nil
+	})
+}
+
+func TestPropertyUserAgentMatchesConstraints(t *testing.T) {
+	checkProperty(t, func(c randomConstraints, fp *Fingerprint, report *RelaxationReport) error {
+		info := parseUserAgent(fp.Navigator.UserAgent)
+		if c.Browsers != nil && !report.Dropped("browsers") && !slices.Contains(c.Browsers, info.Browser) {
+			return fmt.Errorf("user agent %q is not one of the browsers", fp.Navigator.UserAgent)
+		}
+		if c.OS != nil && !report.Dropped("os") && !slices.Contains(c.OS, info.OS) {
+			return fmt.Errorf("user agent %q is not on one of the OS", fp.Navigator.UserAgent)
+		}
+		if c.Devices != nil && !report.Dropped("devices") && !slices.Contains(c.Devices, info.Device) {
+			return fmt.Errorf("user agent %q is not one of the devices", fp.Navigator.UserAgent)
+		}
+		return nil
+	})
+}
+
+func TestPropertyClientHintsOnlyForChromium(t *testing.T) {
+	checkProperty(t, func(_ randomConstraints, fp *Fingerprint, _ *RelaxationReport) error {
+		chromium := engineOf(fp.Navigator.UserAgent) == blinkEngine
+		if !chromium && headerValue(fp.Headers, "sec-ch-ua") != "" {
+			return fmt.Errorf("user agent %q sends sec-ch-ua", fp.Navigator.UserAgent)
+		}
+		if !chromium && fp.Navigator.UserAgentData != nil {
+			return fmt.Errorf("user agent %q has userAgentData", fp.Navigator.UserAgent)
+		}
+		return nil
+	})
+}
+
+func TestPropertyStrictNeverRelaxes(t *testing.T) {
+	checkProperty(t, func(c randomConstraints, _ *Fingerprint, report *RelaxationReport) error {
+		if c.Strict && report.Relaxed() {
+			return fmt.Errorf("strict generation relaxed %s", report)
+		}
+		return nil
+	})
+}
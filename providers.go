@@ -0,0 +1,18 @@
+package forgeron
+
+// HeaderProvider generates HTTP headers, it is implemented by HeaderGenerator and test fakes
+type HeaderProvider interface {
+	GenerateHeaders(options HeaderConstraints) (map[string]string, error)
+}
+
+// FingerprintProvider generates fingerprints, it is implemented by FingerprintGenerator and test fakes
+type FingerprintProvider interface {
+	Generate(opts ...FingerprintOption) (*Fingerprint, error)
+}
+
+var (
+	_ HeaderProvider      = (*HeaderGenerator)(nil)
+	_ FingerprintProvider = (*FingerprintGenerator)(nil)
+	_ FingerprintProvider = (*Pool)(nil)
+	_ FingerprintProvider = (*FixedGenerator)(nil)
+)
@@ -0,0 +1,174 @@
+package forgeron
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"math/rand"
+	"sync"
+)
+
+// RandomSource supplies the random numbers used for sampling. *math/rand.Rand satisfies it, and a nil
+// source uses the global math/rand functions.
+type RandomSource interface {
+	// Float64 returns a number in [0.0, 1.0)
+	Float64() float64
+}
+
+// randomFloat returns a number in [0.0, 1.0) from src, or from the global math/rand source when src is nil
+func randomFloat(src RandomSource) float64 {
+	if src == nil {
+		return rand.Float64()
+	}
+	return src.Float64()
+}
+
+// randomIntn returns a number in [0, n) from src, or from the global math/rand source when src is nil
+func randomIntn(src RandomSource, n int) int {
+	if src == nil {
+		return rand.Intn(n)
+	}
+	i := int(src.Float64() * float64(n))
+	// Guards against sources returning 1.0 through rounding
+	if i >= n {
+		i = n - 1
+	}
+	return i
+}
+
+// seededSource is a math/rand source safe for concurrent use
+type seededSource struct {
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// NewSeededSource returns a fast math/rand source, generators sharing a seed sample the same values
+func NewSeededSource(seed int64) RandomSource {
+	return &seededSource{rnd: rand.New(rand.NewSource(seed))}
+}
+
+// Float64 implements RandomSource
+func (s *seededSource) Float64() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rnd.Float64()
+}
+
+// cryptoSource draws every number from crypto/rand
+type cryptoSource struct{}
+
+// NewCryptoSource returns a source reading crypto/rand, slower than math/rand but unpredictable
+func NewCryptoSource() RandomSource {
+	return cryptoSource{}
+}
+
+// Float64 implements RandomSource
+func (cryptoSource) Float64() float64 {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		// crypto/rand only fails when the OS entropy source is broken
+		panic("forgeron: failed to read crypto/rand: " + err.Error())
+	}
+	// The top 53 bits fill the float64 mantissa
+	return float64(binary.BigEndian.Uint64(b[:])>>11) / (1 << 53)
+}
+
+// RecordingSource records the numbers drawn from another source, so a generation can be replayed
+// with NewReplaySource
+type RecordingSource struct {
+	mu    sync.Mutex
+	src   RandomSource
+	draws []float64
+}
+
+// NewRecordingSource records the numbers drawn from src, or from the global math/rand source when src is nil
+func NewRecordingSource(src RandomSource) *RecordingSource {
+	return &RecordingSource{src: src}
+}
+
+// Float64 implements RandomSource
+func (s *RecordingSource) Float64() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f := randomFloat(s.src)
+	s.draws = append(s.draws, f)
+	return f
+}
+
+// Draws returns a copy of the numbers drawn so far
+func (s *RecordingSource) Draws() []float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]float64(nil), s.draws...)
+}
+
+// ReplaySource returns recorded numbers in order
+type ReplaySource struct {
+	mu    sync.Mutex
+	draws []float64
+	next  int
+}
+
+// NewReplaySource replays draws, usually recorded with a RecordingSource
+func NewReplaySource(draws []float64) *ReplaySource {
+	return &ReplaySource{draws: append([]float64(nil), draws...)}
+}
+
+// Float64 implements RandomSource, it returns 0 once every recorded number was replayed
+func (s *ReplaySource) Float64() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.next >= len(s.draws) {
+		s.next++
+		return 0
+	}
+	f := s.draws[s.next]
+	s.next++
+	return f
+}
+
+// Exhausted reports whether more numbers were drawn than recorded, i.e. the replay diverged
+func (s *ReplaySource) Exhausted() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.next > len(s.draws)
+}
+
+// SetRandomSource sets the source used to sample headers, nil restores the global math/rand source
+func (g *HeaderGenerator) SetRandomSource(src RandomSource) {
+	g.random = src
+	if g.inputGeneratorNetwork != nil {
+		g.inputGeneratorNetwork.random = src
+	}
+	if g.headerGeneratorNetwork != nil {
+		g.headerGeneratorNetwork.random = src
+	}
+}
+
+// WithRandomSource sets the source used to sample fingerprints and their headers, e.g. NewSeededSource
+// for reproducible tests or NewCryptoSource for unpredictable identities
+func WithRandomSource(src RandomSource) FingerprintOption {
+	return func(g *FingerprintGenerator) {
+		g.random = src
+		if g.headerGenerator != nil {
+			g.headerGenerator.SetRandomSource(src)
+		}
+		if g.network != nil {
+			g.network.random = src
+		}
+	}
+}
+
+// pickWeighted picks an item with a probability proportional to its weight, the weights summing to 1
+func pickWeighted[T any](items []T, weight func(T) float64, src RandomSource) T {
+	if len(items) == 1 {
+		return items[0]
+	}
+	draw := randomFloat(src)
+	for _, item := range items {
+		if draw < weight(item) {
+			return item
+		}
+		draw -= weight(item)
+	}
+	return items[len(items)-1]
+}
@@ -0,0 +1,108 @@
+package forgeron
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSeededSourceIsDeterministic(t *testing.T) {
+	generate := func() *Fingerprint {
+		gen, err := NewFingerprintGenerator(WithRandomSource(NewSeededSource(42)))
+		if err != nil {
+			t.Fatalf("Failed to create generator: %v", err)
+		}
+		fp, err := gen.Generate()
+		if err != nil {
+			t.Fatalf("Failed to generate fingerprint: %v", err)
+		}
+		return fp
+	}
+
+	first, second := generate(), generate()
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Expected the same seed to generate the same fingerprint, got %s and %s",
+			first.Navigator.UserAgent, second.Navigator.UserAgent)
+	}
+}
+
+func TestReplaySource(t *testing.T) {
+	recording := NewRecordingSource(NewSeededSource(7))
+	gen, err := NewFingerprintGenerator(WithRandomSource(recording))
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	recorded, err := gen.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate fingerprint: %v", err)
+	}
+	if len(recording.Draws()) == 0 {
+		t.Fatal("Expected the recording source to record draws")
+	}
+
+	replay := NewReplaySource(recording.Draws())
+	gen, err = NewFingerprintGenerator(WithRandomSource(replay))
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	replayed, err := gen.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate fingerprint: %v", err)
+	}
+	if !reflect.DeepEqual(recorded, replayed) {
+		t.Errorf("Expected the replay to generate the recorded fingerprint")
+	}
+	if replay.Exhausted() {
+		t.Error("Expected the replay to draw no more than the recorded numbers")
+	}
+
+	replay.Float64()
+	if !replay.Exhausted() {
+		t.Error("Expected the replay to be exhausted after an extra draw")
+	}
+}
+
+func TestRandomSources(t *testing.T) {
+	tests := []struct {
+		name string
+		src  RandomSource
+	}{
+		{"global", nil},
+		{"seeded", NewSeededSource(1)},
+		{"crypto", NewCryptoSource()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 1000; i++ {
+				if f := randomFloat(tt.src); f < 0 || f >= 1 {
+					t.Fatalf("Expected a number in [0, 1), got %v", f)
+				}
+				if n := randomIntn(tt.src, 3); n < 0 || n >= 3 {
+					t.Fatalf("Expected a number in [0, 3), got %v", n)
+				}
+			}
+		})
+	}
+}
+
+func TestFingerprintGeneratorHeaderGenerator(t *testing.T) {
+	gen, err := NewFingerprintGenerator(WithRandomSource(NewSeededSource(9)))
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	generate := func() map[string]string {
+		hgen := gen.HeaderGenerator()
+		hgen.SetRandomSource(NewSeededSource(3))
+		headers, err := hgen.GenerateHeaders(HeaderConstraints{})
+		if err != nil {
+			t.Fatalf("Failed to generate headers: %v", err)
+		}
+		return headers
+	}
+
+	if first, second := generate(), generate(); !reflect.DeepEqual(first, second) {
+		t.Errorf("Expected the same seed to generate the same headers, got %v and %v", first, second)
+	}
+	if gen.headerGenerator.random != gen.random || gen.headerGenerator.inputGeneratorNetwork.random != gen.random {
+		t.Error("Expected setting the header generator source to leave the generator untouched")
+	}
+}
@@ -0,0 +1,107 @@
+package forgeron
+
+import (
+	"fmt"
+	"math"
+)
+
+// Realism is a single knob trading setup complexity and performance against stealth strength, by toggling groups
+// of features instead of setting every option
+type Realism string
+
+const (
+	// RealismLow only keeps the low-entropy client hints, for the cheapest identities
+	RealismLow Realism = "low"
+	// RealismMedium adds the high-entropy client hints and the TLS and HTTP/2 profiles, the default
+	RealismMedium Realism = "medium"
+	// RealismHigh adds canvas and audio noise and timing jitter, applied by the injector script
+	RealismHigh Realism = "high"
+)
+
+// SupportedRealism lists the realism levels
+var SupportedRealism = []Realism{RealismLow, RealismMedium, RealismHigh}
+
+// RealismFeatures are the groups of features enabled by a realism level
+type RealismFeatures struct {
+	// ClientHints keeps the high-entropy client hints of Chromium fingerprints: architecture, bitness, model,
+	// platform version and full versions
+	ClientHints bool
+	// TransportProfiles sets the TLS and HTTP/2 profiles of fingerprints, for clients in other languages
+	TransportProfiles bool
+	// Noise sets the seeds perturbing canvas and audio readings
+	Noise bool
+	// TimingJitter coarsens performance.now() to the resolution of the browser, with jitter
+	TimingJitter bool
+}
+
+// Features returns the groups of features enabled by the level, the empty level being RealismMedium
+func (r Realism) Features() (RealismFeatures, error) {
+	switch r {
+	case RealismLow:
+		return RealismFeatures{}, nil
+	case RealismMedium, "":
+		return RealismFeatures{ClientHints: true, TransportProfiles: true}, nil
+	case RealismHigh:
+		return RealismFeatures{ClientHints: true, TransportProfiles: true, Noise: true, TimingJitter: true}, nil
+	}
+	return RealismFeatures{}, fmt.Errorf("invalid realism: %w", validateAgainstSupported(r, SupportedRealism))
+}
+
+// Noise holds the seeds the injector script perturbs canvas and audio readings with, so an identity reads the same
+// values on every page while differing from other identities
+type Noise struct {
+	Canvas uint32 `json:"canvas"`
+	Audio  uint32 `json:"audio"`
+}
+
+// TimingJitter is the precision of the timers of a browser
+type TimingJitter struct {
+	// Resolution is the step performance.now() is coarsened to, in milliseconds
+	Resolution float64 `json:"resolution"`
+}
+
+// TransportProfile is the TLS ClientHello and HTTP/2 preface of the browser of a fingerprint
+type TransportProfile struct {
+	TLS   TLSFingerprint `json:"tls"`
+	HTTP2 HTTP2Profile   `json:"http2"`
+}
+
+// WithRealism sets the realism level of generated identities:
+//
+//	low:    low-entropy client hints only
+//	medium: high-entropy client hints, TLS and HTTP/2 profiles (default)
+//	high:   medium, canvas and audio noise, timing jitter
+func WithRealism(realism Realism) FingerprintOption {
+	return func(g *FingerprintGenerator) {
+		g.realism = realism
+	}
+}
+
+// applyRealism sets or removes the features of a fingerprint toggled by the realism level
+func (g *FingerprintGenerator) applyRealism(fp *Fingerprint, features RealismFeatures) {
+	if data := fp.Navigator.UserAgentData; data != nil && !features.ClientHints {
+		fp.Navigator.UserAgentData = &UserAgentData{Brands: data.Brands, Mobile: data.Mobile, Platform: data.Platform}
+	}
+	if features.TransportProfiles {
+		fp.Transport = &TransportProfile{TLS: fp.TLSFingerprint(), HTTP2: fp.HTTP2Profile()}
+	}
+	if features.Noise {
+		fp.Noise = &Noise{Canvas: randomUint32(g.random), Audio: randomUint32(g.random)}
+	}
+	if features.TimingJitter {
+		fp.TimingJitter = &TimingJitter{Resolution: timerResolution(fp.Navigator.UserAgent)}
+	}
+}
+
+// timerResolution returns the resolution of performance.now() in the browser of the user agent, in milliseconds
+func timerResolution(userAgent string) float64 {
+	if engineOf(userAgent) == blinkEngine {
+		return 0.1
+	}
+	return 1
+}
+
+// randomUint32 returns a random uint32 from src
+func randomUint32(src RandomSource) uint32 {
+	return uint32(randomFloat(src) * math.MaxUint32)
+}
@@ -0,0 +1,84 @@
+package forgeron
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRealismFeatures(t *testing.T) {
+	tests := []struct {
+		realism Realism
+		want    RealismFeatures
+	}{
+		{RealismLow, RealismFeatures{}},
+		{"", RealismFeatures{ClientHints: true, TransportProfiles: true}},
+		{RealismMedium, RealismFeatures{ClientHints: true, TransportProfiles: true}},
+		{RealismHigh, RealismFeatures{ClientHints: true, TransportProfiles: true, Noise: true, TimingJitter: true}},
+	}
+	for _, tt := range tests {
+		got, err := tt.realism.Features()
+		if err != nil {
+			t.Fatalf("Features(%q) error = %v", tt.realism, err)
+		}
+		if got != tt.want {
+			t.Errorf("Features(%q) = %+v, want %+v", tt.realism, got, tt.want)
+		}
+	}
+	if _, err := Realism("extreme").Features(); err == nil {
+		t.Error("Features() error = nil for an unknown level")
+	}
+}
+
+func TestGenerateRealism(t *testing.T) {
+	gen := newGeneratorOrFatal(t, WithHeaderConstraints(HeaderConstraints{Browsers: []Browser{Chrome}, Devices: []Device{Desktop}}))
+
+	low, err := gen.Generate(WithRealism(RealismLow))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if data := low.Navigator.UserAgentData; data == nil || len(data.Brands) == 0 || data.Platform == "" {
+		t.Errorf("low realism UserAgentData = %+v, want the low-entropy hints", data)
+	} else if data.PlatformVersion != "" || data.UAFullVersion != "" || data.FullVersionList != nil {
+		t.Errorf("low realism kept the high-entropy hints: %+v", data)
+	}
+	if low.Transport != nil || low.Noise != nil || low.TimingJitter != nil {
+		t.Error("low realism set transport profiles, noise or jitter")
+	}
+
+	medium, err := gen.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if medium.Navigator.UserAgentData == nil || medium.Navigator.UserAgentData.UAFullVersion == "" {
+		t.Errorf("medium realism UserAgentData = %+v, want the high-entropy hints", medium.Navigator.UserAgentData)
+	}
+	if medium.Transport == nil || !reflect.DeepEqual(medium.Transport.TLS, medium.TLSFingerprint()) ||
+		!reflect.DeepEqual(medium.Transport.HTTP2, medium.HTTP2Profile()) {
+		t.Errorf("medium realism Transport = %+v, want the profiles of the browser", medium.Transport)
+	}
+	if medium.Noise != nil || medium.TimingJitter != nil {
+		t.Error("medium realism set noise or jitter")
+	}
+
+	high, err := gen.Generate(WithRealism(RealismHigh), WithRandomSource(NewSeededSource(3)))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if high.Noise == nil || high.Noise.Canvas == high.Noise.Audio {
+		t.Errorf("high realism Noise = %+v", high.Noise)
+	}
+	if high.TimingJitter == nil || high.TimingJitter.Resolution != 0.1 {
+		t.Errorf("high realism TimingJitter = %+v, want the Chromium resolution", high.TimingJitter)
+	}
+	again, err := gen.Generate(WithRealism(RealismHigh), WithRandomSource(NewSeededSource(3)))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if !reflect.DeepEqual(high.Noise, again.Noise) {
+		t.Error("noise seeds differ for the same seed")
+	}
+
+	if _, err := gen.Generate(WithRealism("extreme")); err == nil {
+		t.Error("Generate() error = nil for an unknown realism level")
+	}
+}
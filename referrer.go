@@ -0,0 +1,158 @@
+package forgeron
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ReferrerPolicy is the Referrer-Policy of the page making a request, it decides how much of its URL the Referer
+// header reveals
+type ReferrerPolicy string
+
+// Referrer policies
+const (
+	NoReferrer                  ReferrerPolicy = "no-referrer"
+	NoReferrerWhenDowngrade     ReferrerPolicy = "no-referrer-when-downgrade"
+	SameOriginReferrer          ReferrerPolicy = "same-origin"
+	OriginReferrer              ReferrerPolicy = "origin"
+	StrictOriginReferrer        ReferrerPolicy = "strict-origin"
+	OriginWhenCrossOrigin       ReferrerPolicy = "origin-when-cross-origin"
+	StrictOriginWhenCrossOrigin ReferrerPolicy = "strict-origin-when-cross-origin"
+	UnsafeURLReferrer           ReferrerPolicy = "unsafe-url"
+)
+
+// SupportedReferrerPolicies lists the referrer policies headers can be generated for
+var SupportedReferrerPolicies = []ReferrerPolicy{NoReferrer, NoReferrerWhenDowngrade, SameOriginReferrer, OriginReferrer,
+	StrictOriginReferrer, OriginWhenCrossOrigin, StrictOriginWhenCrossOrigin, UnsafeURLReferrer}
+
+// maxRefererLength is the length above which browsers send the origin of the referrer instead of its URL
+const maxRefererLength = 4096
+
+// parseRequestURL parses an absolute HTTP URL of the URL or Referrer constraint
+func parseRequestURL(field, value string) (*url.URL, error) {
+	u, err := url.Parse(value)
+	if err == nil && (u.Scheme != "http" && u.Scheme != "https" || u.Host == "") {
+		err = fmt.Errorf("an absolute http or https URL is required")
+	}
+	if err != nil {
+		return nil, newFieldError(field, fmt.Errorf("invalid URL '%s': %w", value, err), value)
+	}
+	return u, nil
+}
+
+// referrerHeaders returns the Referer and Origin headers and the Sec-Fetch-Site value of a request to target made
+// from referrer in the request context, following the Referrer Policy and Fetch specs. Empty values are not sent,
+// and an empty site keeps the one of the request context, as without a referrer.
+func referrerHeaders(context RequestContext, policy ReferrerPolicy, target, referrer *url.URL) (referer, origin, site string) {
+	if referrer == nil {
+		return "", "", ""
+	}
+	site = fetchSite(target, referrer)
+	referer = refererFor(policy, target, referrer)
+	// CORS requests to another origin carry the origin of the page, whatever the referrer policy
+	if (context == FirstPartySubresource || context == ThirdPartySubresource) && site != "same-origin" {
+		origin = urlOrigin(referrer)
+	}
+	return referer, origin, site
+}
+
+// refererFor returns the Referer a request to target made from referrer sends under the policy, the empty policy
+// being strict-origin-when-cross-origin, the default of every current browser
+func refererFor(policy ReferrerPolicy, target, referrer *url.URL) string {
+	stripped := *referrer
+	stripped.User = nil
+	stripped.Fragment = ""
+	stripped.RawFragment = ""
+	if stripped.Path == "" {
+		stripped.Path = "/"
+	}
+	full := stripped.String()
+	origin := urlOrigin(referrer) + "/"
+	if len(full) > maxRefererLength {
+		full = origin
+	}
+	sameOrigin := urlOrigin(target) == urlOrigin(referrer)
+	downgrade := referrer.Scheme == "https" && target.Scheme == "http"
+
+	switch policy {
+	case NoReferrer:
+		return ""
+	case NoReferrerWhenDowngrade:
+		return pick(!downgrade, full)
+	case SameOriginReferrer:
+		return pick(sameOrigin, full)
+	case OriginReferrer:
+		return origin
+	case StrictOriginReferrer:
+		return pick(!downgrade, origin)
+	case OriginWhenCrossOrigin:
+		if sameOrigin {
+			return full
+		}
+		return origin
+	case UnsafeURLReferrer:
+		return full
+	}
+	if sameOrigin {
+		return full
+	}
+	return pick(!downgrade, origin)
+}
+
+// fetchSite returns the Sec-Fetch-Site value of a request to target made from referrer. Sites are told apart by
+// their last two host labels, or three under the second-level domains of country codes such as co.uk.
+func fetchSite(target, referrer *url.URL) string {
+	switch {
+	case urlOrigin(target) == urlOrigin(referrer):
+		return "same-origin"
+	case target.Scheme == referrer.Scheme && registrableDomain(target.Hostname()) == registrableDomain(referrer.Hostname()):
+		return "same-site"
+	}
+	return "cross-site"
+}
+
+// registrableDomain approximates the registrable domain of a host, e.g. shop.example.co.uk gives example.co.uk
+func registrableDomain(host string) string {
+	labels := strings.Split(strings.ToLower(host), ".")
+	n := 2
+	if len(labels) >= 3 && len(labels[len(labels)-1]) == 2 {
+		switch labels[len(labels)-2] {
+		case "co", "com", "org", "net", "gov", "ac", "edu", "ne", "or":
+			n = 3
+		}
+	}
+	if len(labels) <= n {
+		return strings.Join(labels, ".")
+	}
+	return strings.Join(labels[len(labels)-n:], ".")
+}
+
+// urlOrigin serializes the origin of a URL, e.g. https://example.com:8443, without the default port of the scheme
+func urlOrigin(u *url.URL) string {
+	scheme, host := strings.ToLower(u.Scheme), strings.ToLower(u.Hostname())
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port := u.Port(); port != "" && !(scheme == "http" && port == "80") && !(scheme == "https" && port == "443") {
+		host += ":" + port
+	}
+	return scheme + "://" + host
+}
+
+// pick returns value when ok, else ""
+func pick(ok bool, value string) string {
+	if ok {
+		return value
+	}
+	return ""
+}
+
+// referrerValues returns the Referer, Origin and Sec-Fetch-Site values of the validated URL and Referrer
+// constraints, keyed by lowercase name and empty when not sent
+func (c HeaderConstraints) referrerValues() map[string]string {
+	target, _ := url.Parse(c.URL)
+	referrer, _ := url.Parse(c.Referrer)
+	referer, origin, site := referrerHeaders(c.RequestContext, c.ReferrerPolicy, target, referrer)
+	return map[string]string{"referer": referer, "origin": origin, "sec-fetch-site": site}
+}
@@ -0,0 +1,154 @@
+package forgeron
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("url.Parse(%q) error = %v", raw, err)
+	}
+	return u
+}
+
+func TestRefererFor(t *testing.T) {
+	page := "https://user@shop.example.com/cart?id=1#total"
+	tests := []struct {
+		policy ReferrerPolicy
+		target string
+		want   string
+	}{
+		{"", "https://shop.example.com/pay", "https://shop.example.com/cart?id=1"},
+		{"", "https://cdn.example.net/app.js", "https://shop.example.com/"},
+		{"", "http://cdn.example.net/app.js", ""},
+		{StrictOriginWhenCrossOrigin, "https://shop.example.com:443/pay", "https://shop.example.com/cart?id=1"},
+		{NoReferrer, "https://shop.example.com/pay", ""},
+		{NoReferrerWhenDowngrade, "https://cdn.example.net/app.js", "https://shop.example.com/cart?id=1"},
+		{NoReferrerWhenDowngrade, "http://cdn.example.net/app.js", ""},
+		{SameOriginReferrer, "https://shop.example.com/pay", "https://shop.example.com/cart?id=1"},
+		{SameOriginReferrer, "https://cdn.example.net/app.js", ""},
+		{OriginReferrer, "https://shop.example.com/pay", "https://shop.example.com/"},
+		{StrictOriginReferrer, "http://cdn.example.net/app.js", ""},
+		{OriginWhenCrossOrigin, "http://cdn.example.net/app.js", "https://shop.example.com/"},
+		{UnsafeURLReferrer, "http://cdn.example.net/app.js", "https://shop.example.com/cart?id=1"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy)+" "+tt.target, func(t *testing.T) {
+			if got := refererFor(tt.policy, mustParseURL(t, tt.target), mustParseURL(t, page)); got != tt.want {
+				t.Errorf("refererFor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchSite(t *testing.T) {
+	tests := []struct {
+		target, referrer string
+		want             string
+	}{
+		{"https://example.com/a", "https://example.com:443/b", "same-origin"},
+		{"https://api.example.com/", "https://www.example.com/", "same-site"},
+		{"https://shop.example.co.uk/", "https://example.co.uk/", "same-site"},
+		{"https://a.co.uk/", "https://b.co.uk/", "cross-site"},
+		{"http://www.example.com/", "https://www.example.com/", "cross-site"},
+		{"https://example.org/", "https://example.com/", "cross-site"},
+	}
+	for _, tt := range tests {
+		if got := fetchSite(mustParseURL(t, tt.target), mustParseURL(t, tt.referrer)); got != tt.want {
+			t.Errorf("fetchSite(%q, %q) = %q, want %q", tt.target, tt.referrer, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateHeadersReferrer(t *testing.T) {
+	gen, err := NewHeaderGenerator()
+	if err != nil {
+		t.Fatalf("NewHeaderGenerator() error = %v", err)
+	}
+	tests := []struct {
+		name        string
+		constraints HeaderConstraints
+		wantReferer string
+		wantOrigin  string
+		wantSite    string
+	}{
+		{
+			name:        "typed navigation",
+			constraints: HeaderConstraints{URL: "https://shop.example.com/"},
+			wantSite:    "none",
+		},
+		{
+			name:        "link from another site",
+			constraints: HeaderConstraints{URL: "https://shop.example.com/", Referrer: "https://www.google.com/search?q=shop"},
+			wantReferer: "https://www.google.com/",
+			wantSite:    "cross-site",
+		},
+		{
+			name:        "same-origin fetch",
+			constraints: HeaderConstraints{URL: "https://shop.example.com/api", Referrer: "https://shop.example.com/cart", RequestContext: FirstPartySubresource},
+			wantReferer: "https://shop.example.com/cart",
+			wantSite:    "same-origin",
+		},
+		{
+			name:        "cross-origin fetch",
+			constraints: HeaderConstraints{URL: "https://api.example.com/v1", Referrer: "https://shop.example.com/cart", RequestContext: ThirdPartySubresource},
+			wantReferer: "https://shop.example.com/",
+			wantOrigin:  "https://shop.example.com",
+			wantSite:    "same-site",
+		},
+		{
+			name:        "no-referrer policy",
+			constraints: HeaderConstraints{URL: "https://api.example.com/v1", Referrer: "https://shop.example.com/cart", ReferrerPolicy: NoReferrer, RequestContext: ThirdPartySubresource},
+			wantOrigin:  "https://shop.example.com",
+			wantSite:    "same-site",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, httpVersion := range []HTTPVersion{HTTP1, HTTP2} {
+				tt.constraints.Browsers = []Browser{Chrome}
+				tt.constraints.HTTPVersion = httpVersion
+				headers, err := gen.GenerateHeaders(tt.constraints)
+				if err != nil {
+					t.Fatalf("GenerateHeaders() error = %v", err)
+				}
+				if got := headerValue(headers, "referer"); got != tt.wantReferer {
+					t.Errorf("%s Referer = %q, want %q", httpVersion, got, tt.wantReferer)
+				}
+				if got := headerValue(headers, "origin"); got != tt.wantOrigin {
+					t.Errorf("%s Origin = %q, want %q", httpVersion, got, tt.wantOrigin)
+				}
+				if got := headerValue(headers, "sec-fetch-site"); got != tt.wantSite {
+					t.Errorf("%s Sec-Fetch-Site = %q, want %q", httpVersion, got, tt.wantSite)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateHeadersInvalidReferrer(t *testing.T) {
+	gen, err := NewHeaderGenerator()
+	if err != nil {
+		t.Fatalf("NewHeaderGenerator() error = %v", err)
+	}
+	tests := []struct {
+		constraints HeaderConstraints
+		field       string
+	}{
+		{HeaderConstraints{URL: "/relative"}, "URL"},
+		{HeaderConstraints{URL: "ftp://example.com/file"}, "URL"},
+		{HeaderConstraints{Referrer: "https://example.com/"}, "Referrer"},
+		{HeaderConstraints{URL: "https://example.com/", ReferrerPolicy: "always"}, "ReferrerPolicy"},
+	}
+	for _, tt := range tests {
+		_, err := gen.GenerateHeaders(tt.constraints)
+		var fieldErr *FieldError
+		if !errors.As(err, &fieldErr) || fieldErr.Field != tt.field {
+			t.Errorf("GenerateHeaders(%+v) error = %v, want a %s field error", tt.constraints, err, tt.field)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package forgeron
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Relaxation is a constraint dropped or altered so a generation could succeed
+type Relaxation struct {
+	// Constraint is the name of the constraint, e.g. "locales", "devices", "os", "browsers", "httpVersion", "screen", "timezone" or "geo"
+	Constraint string `json:"constraint"`
+	// Requested is the value asked for
+	Requested string `json:"requested,omitempty"`
+	// Used is the value generated with instead, empty when the constraint was dropped
+	Used string `json:"used,omitempty"`
+	// Reason tells why the constraint was relaxed
+	Reason string `json:"reason"`
+}
+
+// RelaxationReport lists the constraints relaxed by a generation in non-strict mode.
+// A report without relaxations means every constraint was honored.
+type RelaxationReport struct {
+	Relaxations []Relaxation `json:"relaxations"`
+}
+
+// add records a relaxation, doing nothing on a nil report
+func (r *RelaxationReport) add(relaxation Relaxation) {
+	if r != nil {
+		r.Relaxations = append(r.Relaxations, relaxation)
+	}
+}
+
+// reset empties the report, doing nothing on a nil report
+func (r *RelaxationReport) reset() {
+	if r != nil {
+		r.Relaxations = nil
+	}
+}
+
+// Relaxed reports whether any constraint was relaxed
+func (r *RelaxationReport) Relaxed() bool {
+	return r != nil && len(r.Relaxations) > 0
+}
+
+// Dropped reports whether the constraint was dropped or altered
+func (r *RelaxationReport) Dropped(constraint string) bool {
+	if r == nil {
+		return false
+	}
+	for _, relaxation := range r.Relaxations {
+		if relaxation.Constraint == constraint {
+			return true
+		}
+	}
+	return false
+}
+
+// String renders the report one relaxation per line, e.g. "locales: dropped [xx-XX] (no input sample)"
+func (r *RelaxationReport) String() string {
+	if !r.Relaxed() {
+		return "no constraint relaxed"
+	}
+	var b strings.Builder
+	for _, relaxation := range r.Relaxations {
+		b.WriteString(relaxation.Constraint + ": ")
+		if relaxation.Used != "" {
+			fmt.Fprintf(&b, "%s -> %s", relaxation.Requested, relaxation.Used)
+		} else {
+			fmt.Fprintf(&b, "dropped [%s]", relaxation.Requested)
+		}
+		fmt.Fprintf(&b, " (%s)\n", relaxation.Reason)
+	}
+	return b.String()
+}
+
+// ConstraintError is the failure of a generation on a constraint no data satisfies in strict mode, named like the
+// relaxation it would have been outside strict mode
+type ConstraintError struct {
+	// Constraint is the name of the constraint, as in Relaxation, or "headers" when the header constraints cannot
+	// be met together
+	Constraint string
+	// Reason tells why the constraint could not be met
+	Reason string
+	err    error
+}
+
+// newConstraintError returns the failure of a generation on the constraint
+func newConstraintError(constraint, reason string, err error) *ConstraintError {
+	return &ConstraintError{Constraint: constraint, Reason: reason, err: err}
+}
+
+// Error implements error
+func (e *ConstraintError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *ConstraintError) Unwrap() error {
+	return e.err
+}
+
+// joinValues joins constraint values for a relaxation
+func joinValues[T ~string](values []T) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = string(value)
+	}
+	return strings.Join(parts, ",")
+}
+
+// WithRelaxationReport fills report with the constraints relaxed by a generation. It is only accepted by Generate,
+// NewFingerprintGenerator refuses it as concurrent generations would share the report:
+//
+//	var report forgeron.RelaxationReport
+//	fp, err := gen.Generate(forgeron.WithRelaxationReport(&report))
+func WithRelaxationReport(report *RelaxationReport) FingerprintOption {
+	return func(g *FingerprintGenerator) {
+		g.relaxationReport = report
+	}
+}
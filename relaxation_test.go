@@ -0,0 +1,144 @@
+package forgeron
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGenerateHeadersWithReport(t *testing.T) {
+	gen, err := NewHeaderGenerator()
+	if err != nil {
+		t.Fatalf("NewHeaderGenerator() error = %v", err)
+	}
+
+	// iOS has no desktop devices, so the devices and locales are dropped
+	headers, report, err := gen.GenerateHeadersWithReport(HeaderConstraints{
+		OS:      []OS{IOS},
+		Devices: []Device{Desktop},
+		Locales: []string{"fr-FR"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateHeadersWithReport() error = %v", err)
+	}
+	if len(headers) == 0 {
+		t.Fatal("no headers generated")
+	}
+	want := map[string]Relaxation{
+		"locales": {Constraint: "locales", Requested: "fr-FR", Used: "en-US", Reason: "no input sample"},
+		"devices": {Constraint: "devices", Requested: "desktop", Used: "desktop,mobile,tablet", Reason: "no input sample"},
+	}
+	if len(report.Relaxations) != len(want) {
+		t.Fatalf("relaxations = %+v, want %d", report.Relaxations, len(want))
+	}
+	for _, relaxation := range report.Relaxations {
+		if relaxation != want[relaxation.Constraint] {
+			t.Errorf("relaxation = %+v, want %+v", relaxation, want[relaxation.Constraint])
+		}
+	}
+
+	_, report, err = gen.GenerateHeadersWithReport(HeaderConstraints{Browsers: []Browser{Chrome}, Locales: []string{"fr-FR"}})
+	if err != nil {
+		t.Fatalf("GenerateHeadersWithReport() error = %v", err)
+	}
+	if report.Relaxed() {
+		t.Errorf("report = %v, want no relaxation", report)
+	}
+}
+
+func TestGenerateHeadersIncompatibleConstraints(t *testing.T) {
+	gen, err := NewHeaderGenerator()
+	if err != nil {
+		t.Fatalf("NewHeaderGenerator() error = %v", err)
+	}
+
+	// Safari does not run on Windows, so the OS is dropped after the locales and devices
+	headers, report, err := gen.GenerateHeadersWithReport(HeaderConstraints{Browsers: []Browser{Safari}, OS: []OS{Windows}})
+	if err != nil {
+		t.Fatalf("GenerateHeadersWithReport() error = %v", err)
+	}
+	if !report.Dropped("os") {
+		t.Errorf("report does not list os:\n%v", report)
+	}
+	if info := parseUserAgent(headerValue(headers, "user-agent")); info.Browser != Safari {
+		t.Errorf("browser = %q, want safari", info.Browser)
+	}
+
+	_, err = gen.GenerateHeaders(HeaderConstraints{Browsers: []Browser{Safari}, OS: []OS{Windows}, Strict: true})
+	var constraintErr *ConstraintError
+	if !errors.As(err, &constraintErr) || constraintErr.Constraint != "headers" {
+		t.Errorf("GenerateHeaders() in strict mode error = %v, want a headers constraint error", err)
+	}
+}
+
+func TestGenerateHeadersStrictHTTP1(t *testing.T) {
+	gen, err := NewHeaderGenerator()
+	if err != nil {
+		t.Fatalf("NewHeaderGenerator() error = %v", err)
+	}
+	// The OS has no HTTP/1 sample, strict mode fails rather than falling back to HTTP/2
+	constraints := HeaderConstraints{OS: []OS{IOS}, Devices: []Device{Tablet}, HTTPVersion: HTTP1, Strict: true}
+	if _, report, err := gen.GenerateHeadersWithReport(constraints); err == nil {
+		t.Errorf("GenerateHeadersWithReport() relaxed %v, want error", report)
+	}
+	constraints.Strict = false
+	_, report, err := gen.GenerateHeadersWithReport(constraints)
+	if err != nil {
+		t.Fatalf("GenerateHeadersWithReport() error = %v", err)
+	}
+	if !report.Dropped("httpVersion") {
+		t.Errorf("report does not list httpVersion:\n%v", report)
+	}
+}
+
+func TestGenerateRelaxationReport(t *testing.T) {
+	gen := newGeneratorOrFatal(t)
+	minWidth := 100000
+
+	var report RelaxationReport
+	fp, err := gen.Generate(
+		WithHeaderConstraints(HeaderConstraints{OS: []OS{IOS}, Devices: []Device{Desktop}}),
+		WithScreen(&Screen{MinWidth: &minWidth}),
+		WithRelaxationReport(&report),
+	)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if fp.Navigator.UserAgent == "" {
+		t.Error("UserAgent is empty")
+	}
+	for _, constraint := range []string{"devices", "screen"} {
+		if !report.Dropped(constraint) {
+			t.Errorf("report does not list %s:\n%v", constraint, &report)
+		}
+	}
+	if !strings.Contains(report.String(), "screen: dropped [minWidth=100000]") {
+		t.Errorf("String() = %q", report.String())
+	}
+
+	// The report only covers the last generation
+	if _, err := gen.Generate(WithRelaxationReport(&report)); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if report.Relaxed() {
+		t.Errorf("report = %v, want no relaxation", &report)
+	}
+}
+
+func TestRelaxationReportPerCall(t *testing.T) {
+	var report RelaxationReport
+	if _, err := NewFingerprintGenerator(WithRelaxationReport(&report)); err == nil {
+		t.Error("NewFingerprintGenerator() error = nil with a relaxation report")
+	}
+}
+
+func TestRelaxationReportNil(t *testing.T) {
+	var report *RelaxationReport
+	report.add(Relaxation{Constraint: "screen"})
+	if report.Relaxed() || report.Dropped("screen") {
+		t.Error("nil report has relaxations")
+	}
+	if got := report.String(); got != "no constraint relaxed" {
+		t.Errorf("String() = %q", got)
+	}
+}
@@ -0,0 +1,222 @@
+//go:build !js
+
+package forgeron
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDebounce is how long the reloader waits for file events to settle before reloading
+const reloadDebounce = 200 * time.Millisecond
+
+// ReloaderConfig configures the constraint profiles and network data watched by a Reloader
+type ReloaderConfig struct {
+	// ConfigPath is the constraints config file loaded with LoadConstraints
+	ConfigPath string
+	// DataDir overrides the embedded network data, and can be empty
+	DataDir string
+	// OnError is called when a reload fails, the previous profiles and generator are kept
+	OnError func(error)
+}
+
+// Reloader keeps constraint profiles and the fingerprint generator up to date with files on disk,
+// so a long-running service picks up new profiles or network data without a restart. Network data is reloaded
+// into the same generator, so generators returned by Generator before a reload use the new data too. WebAssembly
+// builds have no Reloader, they cannot watch files.
+type Reloader struct {
+	config   ReloaderConfig
+	opts     []FingerprintOption
+	mu       sync.RWMutex
+	profiles ConstraintProfiles
+	// files are the absolute paths of the config file and the files it includes
+	files []string
+	gen   *FingerprintGenerator
+}
+
+// NewReloader loads the constraint profiles and the generator created with the options
+func NewReloader(config ReloaderConfig, opts ...FingerprintOption) (*Reloader, error) {
+	if config.ConfigPath == "" {
+		return nil, fmt.Errorf("config path is required")
+	}
+	r := &Reloader{config: config, opts: opts}
+	if err := r.reloadProfiles(); err != nil {
+		return nil, err
+	}
+	if err := r.reloadData(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Profiles returns the current constraint profiles
+func (r *Reloader) Profiles() ConstraintProfiles {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.profiles
+}
+
+// Generator returns the fingerprint generator
+func (r *Reloader) Generator() *FingerprintGenerator {
+	return r.gen
+}
+
+// Generate generates a fingerprint using the named constraint profile
+func (r *Reloader) Generate(profile string, opts ...FingerprintOption) (*Fingerprint, error) {
+	constraints, err := r.Profiles().Get(profile)
+	if err != nil {
+		return nil, err
+	}
+	return r.gen.Generate(append([]FingerprintOption{WithConstraints(constraints)}, opts...)...)
+}
+
+// Watch reloads profiles and network data when their files change, until ctx is done
+func (r *Reloader) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	// Watch directories rather than files, editors often replace files on save
+	if err := r.watchConfigFiles(watcher); err != nil {
+		return err
+	}
+	if r.config.DataDir != "" {
+		if err := watcher.Add(r.config.DataDir); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", r.config.DataDir, err)
+		}
+	}
+
+	timer := time.NewTimer(reloadDebounce)
+	timer.Stop()
+	var profilesChanged, dataChanged bool
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			// The config files come first, they may live in the data directory
+			switch dir := filepath.Dir(event.Name); {
+			case r.isConfigFile(event.Name):
+				profilesChanged = true
+			case r.config.DataDir != "" && sameDir(dir, r.config.DataDir):
+				dataChanged = true
+			default:
+				continue
+			}
+			timer.Reset(reloadDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			r.reportError(fmt.Errorf("watcher error: %w", err))
+		case <-timer.C:
+			if profilesChanged {
+				r.reportError(r.reloadProfiles())
+				// The reloaded config can include new files
+				r.reportError(r.watchConfigFiles(watcher))
+			}
+			if dataChanged {
+				r.reportError(r.reloadData())
+			}
+			profilesChanged, dataChanged = false, false
+		}
+	}
+}
+
+// reloadProfiles reloads the constraint profiles from the config file and the files it includes
+func (r *Reloader) reloadProfiles() error {
+	profiles, files, err := loadConstraints(r.config.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load constraint profiles: %w", err)
+	}
+	r.mu.Lock()
+	r.profiles = profiles
+	r.files = files
+	r.mu.Unlock()
+	return nil
+}
+
+// configFiles returns the config file and the files it includes, the config file alone until profiles are loaded
+func (r *Reloader) configFiles() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.files) == 0 {
+		return []string{r.config.ConfigPath}
+	}
+	return r.files
+}
+
+// watchConfigFiles watches the directories of the config file and the files it includes
+func (r *Reloader) watchConfigFiles(watcher *fsnotify.Watcher) error {
+	for _, file := range r.configFiles() {
+		dir := filepath.Dir(file)
+		if err := watcher.Add(dir); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", dir, err)
+		}
+	}
+	return nil
+}
+
+// isConfigFile reports whether path is the config file or one of the files it includes
+func (r *Reloader) isConfigFile(path string) bool {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	for _, file := range r.configFiles() {
+		if absFile, err := filepath.Abs(file); err == nil && absFile == absPath {
+			return true
+		}
+	}
+	return false
+}
+
+// reloadData loads the network data into the generator, swapping it in for generations in progress elsewhere
+func (r *Reloader) reloadData() error {
+	if r.gen == nil {
+		gen, err := NewFingerprintGenerator(append([]FingerprintOption{WithDataDir(r.config.DataDir)}, r.opts...)...)
+		if err != nil {
+			return fmt.Errorf("failed to load network data: %w", err)
+		}
+		r.gen = gen
+		return nil
+	}
+	var data fs.FS
+	if r.config.DataDir != "" {
+		data = os.DirFS(r.config.DataDir)
+	}
+	if err := r.gen.Reload(data); err != nil {
+		return fmt.Errorf("failed to load network data: %w", err)
+	}
+	return nil
+}
+
+// reportError passes a reload error to the configured handler
+func (r *Reloader) reportError(err error) {
+	if err != nil && r.config.OnError != nil {
+		r.config.OnError(err)
+	}
+}
+
+// sameDir reports whether two directory paths point to the same directory
+func sameDir(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
@@ -0,0 +1,215 @@
+//go:build !js
+
+package forgeron
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestReloaderPicksUpProfileChanges(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "constraints.yaml")
+	if err := os.WriteFile(path, []byte("profiles:\n  default:\n    browsers: [chrome]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewReloader(ReloaderConfig{
+		ConfigPath: path,
+		OnError:    func(err error) { t.Errorf("reload error = %v", err) },
+	})
+	if err != nil {
+		t.Fatalf("NewReloader() error = %v", err)
+	}
+	if _, err := r.Generate("default"); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Watch(ctx)
+	// Give the watcher time to register before writing
+	time.Sleep(50 * time.Millisecond)
+
+	if err := os.WriteFile(path, []byte("profiles:\n  default:\n    browsers: [firefox]\n  mobile:\n    devices: [mobile]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := r.Profiles().Get("mobile"); err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	def, err := r.Profiles().Get("default")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if len(def.Browsers) != 1 || def.Browsers[0] != "firefox" {
+		t.Fatalf("profile was not reloaded, Browsers = %v", def.Browsers)
+	}
+}
+
+func TestReloaderConfigInDataDir(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "constraints.yaml")
+	if err := os.WriteFile(path, []byte("profiles:\n  default:\n    browsers: [chrome]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewReloader(ReloaderConfig{
+		ConfigPath: path,
+		DataDir:    dir,
+		OnError:    func(err error) { t.Errorf("reload error = %v", err) },
+	})
+	if err != nil {
+		t.Fatalf("NewReloader() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Watch(ctx)
+	time.Sleep(50 * time.Millisecond)
+
+	if err := os.WriteFile(path, []byte("profiles:\n  mobile:\n    devices: [mobile]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := r.Profiles().Get("mobile"); err == nil {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatal("config file in the data directory was not reloaded")
+}
+
+func TestReloaderPicksUpIncludedFileChanges(t *testing.T) {
+	dir := t.TempDir()
+	included := filepath.Join(dir, "shared", "base.yaml")
+	if err := os.MkdirAll(filepath.Dir(included), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(included, []byte("profiles:\n  base:\n    browsers: [chrome]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "constraints.yaml")
+	if err := os.WriteFile(path, []byte("include: [shared/base.yaml]\nprofiles:\n  default:\n    browsers: [chrome]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewReloader(ReloaderConfig{
+		ConfigPath: path,
+		OnError:    func(err error) { t.Errorf("reload error = %v", err) },
+	})
+	if err != nil {
+		t.Fatalf("NewReloader() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Watch(ctx)
+	time.Sleep(50 * time.Millisecond)
+
+	if err := os.WriteFile(included, []byte("profiles:\n  base:\n    browsers: [firefox]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if base, err := r.Profiles().Get("base"); err == nil && len(base.Browsers) == 1 && base.Browsers[0] == Firefox {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatal("included file was not reloaded")
+}
+
+func TestReloaderKeepsProfilesOnInvalidConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "constraints.yaml")
+	if err := os.WriteFile(path, []byte("profiles:\n  default:\n    browsers: [chrome]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewReloader(ReloaderConfig{ConfigPath: path})
+	if err != nil {
+		t.Fatalf("NewReloader() error = %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("profiles: ["), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.reloadProfiles(); err == nil {
+		t.Fatal("expected error for an invalid config")
+	}
+	if _, err := r.Profiles().Get("default"); err != nil {
+		t.Errorf("previous profiles were dropped: %v", err)
+	}
+}
+
+func TestWithDataDirFallsBackToEmbeddedData(t *testing.T) {
+	gen := newGeneratorOrFatal(t, WithDataDir(t.TempDir()))
+	if _, err := gen.Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+}
+
+func TestReloadSwapsDataForCopies(t *testing.T) {
+	gen := newGeneratorOrFatal(t, WithRandomSource(NewSeededSource(1)))
+	previous := gen.latest.Load()
+
+	if err := gen.Reload(nil); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	data := gen.latest.Load()
+	if data == previous {
+		t.Fatal("Reload() kept the previous data")
+	}
+	// Generations made from the generator, including with options, use the reloaded data
+	current := gen.withOptions([]FingerprintOption{WithSlim(true)}).current()
+	if current.network == data.network || current.loaded != data || current.network.random != gen.random {
+		t.Error("current() does not use the reloaded data with the generator random source")
+	}
+}
+
+func TestReloadKeepsDataOnFailure(t *testing.T) {
+	gen := newGeneratorOrFatal(t)
+	previous := gen.latest.Load()
+
+	invalid := fstest.MapFS{fingerprintNetworkFile: {Data: zipNetwork(t, "forgeron-data schema=1")}}
+	if err := gen.Reload(invalid); err == nil {
+		t.Fatal("Reload() error = nil, want data without nodes to be rejected")
+	}
+	if gen.latest.Load() != previous {
+		t.Error("Reload() swapped in data that cannot generate fingerprints")
+	}
+	if _, err := gen.Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+}
+
+func TestReloadDuringGenerate(t *testing.T) {
+	gen := newGeneratorOrFatal(t, WithSharedNetworks(true))
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				if _, err := gen.Generate(WithSlim(true)); err != nil {
+					t.Errorf("Generate() error = %v", err)
+					return
+				}
+			}
+		}()
+	}
+	for range 3 {
+		if err := gen.Reload(nil); err != nil {
+			t.Errorf("Reload() error = %v", err)
+		}
+	}
+	wg.Wait()
+}
@@ -0,0 +1,55 @@
+package forgeron
+
+// GenerateRequest is the serializable form of fingerprint generation options,
+// used to send generation requests to a remote forgeron service
+type GenerateRequest struct {
+	Constraints Constraints      `json:"constraints"`
+	Strict      bool             `json:"strict,omitempty"`
+	MockWebRTC  bool             `json:"mockWebRTC,omitempty"`
+	Slim        bool             `json:"slim,omitempty"`
+	LinuxFlavor *LinuxFlavor     `json:"linuxFlavor,omitempty"`
+	Embedded    *EmbeddedOptions `json:"embedded,omitempty"`
+	InApp       InAppBrowser     `json:"inApp,omitempty"`
+	Realism     Realism          `json:"realism,omitempty"`
+	Geo         string           `json:"geo,omitempty"`
+	Privacy     *PrivacySignals  `json:"privacy,omitempty"`
+}
+
+// NewGenerateRequest captures the given options in a GenerateRequest.
+// Options that only affect the local generator, such as WithDataDir, are dropped.
+func NewGenerateRequest(opts ...FingerprintOption) GenerateRequest {
+	g := &FingerprintGenerator{}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return GenerateRequest{
+		Constraints: g.Constraints(),
+		Strict:      g.strict,
+		MockWebRTC:  g.mockWebRTC,
+		Slim:        g.slim,
+		LinuxFlavor: g.linuxFlavor,
+		Embedded:    g.embedded,
+		InApp:       g.inApp,
+		Realism:     g.realism,
+		Geo:         g.geoCountry,
+		Privacy:     g.privacySignals,
+	}
+}
+
+// Options returns the fingerprint options described by the request
+func (r GenerateRequest) Options() []FingerprintOption {
+	return []FingerprintOption{
+		WithConstraints(r.Constraints),
+		WithStrict(r.Strict || r.Constraints.Strict),
+		WithMockWebRTC(r.MockWebRTC),
+		WithSlim(r.Slim),
+		WithRealism(r.Realism),
+		WithGeo(r.Geo),
+		func(g *FingerprintGenerator) {
+			g.linuxFlavor = r.LinuxFlavor
+			g.embedded = r.Embedded
+			g.inApp = r.InApp
+			g.privacySignals = r.Privacy
+		},
+	}
+}
@@ -0,0 +1,35 @@
+package forgeron
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestGenerateRequestRoundTrip(t *testing.T) {
+	minWidth := 1280
+	want := NewGenerateRequest(
+		WithConstraints(Constraints{
+			HeaderConstraints: HeaderConstraints{Browsers: []Browser{"firefox"}, OS: []OS{"linux"}},
+			Screen:            &Screen{MinWidth: &minWidth},
+		}),
+		WithLinuxFlavor(LinuxFlavor{Distro: "fedora", DisplayServer: "wayland"}),
+		WithSlim(true),
+		WithRealism(RealismHigh),
+		WithGeo("DE"),
+		WithPrivacySignals(PrivacySignals{GlobalPrivacyControl: true}),
+		WithDataDir("ignored"),
+	)
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var decoded GenerateRequest
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if got := NewGenerateRequest(decoded.Options()...); !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip changed the request\n got: %+v\nwant: %+v", got, want)
+	}
+}
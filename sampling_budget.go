@@ -0,0 +1,86 @@
+package forgeron
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrSamplingBudgetExhausted is returned by strict generators when constrained sampling runs out of its budget
+var ErrSamplingBudgetExhausted = errors.New("sampling budget exhausted")
+
+// samplingBudget bounds the constrained fingerprint sampling of a Generate call, zero values are unlimited
+type samplingBudget struct {
+	maxBacktracks int
+	maxDuration   time.Duration
+}
+
+// WithSamplingBudget bounds the constrained fingerprint sampling of each Generate call to maxBacktracks backtracking
+// steps and maxDuration, zero leaves a bound unlimited. Once the budget is exhausted the constraints are relaxed as if
+// no consistent sample existed, trading fidelity for latency; strict generators fail with ErrSamplingBudgetExhausted.
+func WithSamplingBudget(maxBacktracks int, maxDuration time.Duration) FingerprintOption {
+	return func(g *FingerprintGenerator) {
+		g.samplingBudget = samplingBudget{maxBacktracks: maxBacktracks, maxDuration: maxDuration}
+	}
+}
+
+// validate validates the sampling budget
+func (b samplingBudget) validate() error {
+	if b.maxBacktracks < 0 {
+		return fmt.Errorf("max backtracks cannot be negative")
+	}
+	if b.maxDuration < 0 {
+		return fmt.Errorf("max duration cannot be negative")
+	}
+	return nil
+}
+
+// start returns the limit of a single Generate call, or nil when the budget is unlimited
+func (b samplingBudget) start() *samplingLimit {
+	if b.maxBacktracks == 0 && b.maxDuration == 0 {
+		return nil
+	}
+	limit := &samplingLimit{remaining: -1}
+	if b.maxBacktracks > 0 {
+		limit.remaining = b.maxBacktracks
+	}
+	if b.maxDuration > 0 {
+		limit.deadline = time.Now().Add(b.maxDuration)
+	}
+	return limit
+}
+
+// samplingLimit tracks the budget left to a Generate call, a nil limit is unlimited
+type samplingLimit struct {
+	// remaining is the number of backtracks left, or -1 when unlimited
+	remaining int
+	deadline  time.Time
+	// spent is set once the limit is exhausted, so later samplings of the call give up at once
+	spent bool
+}
+
+// backtrack records a backtracking step
+func (l *samplingLimit) backtrack() {
+	if l != nil && l.remaining > 0 {
+		l.remaining--
+	}
+}
+
+// exhausted reports whether the sampling must give up
+func (l *samplingLimit) exhausted() bool {
+	if l == nil {
+		return false
+	}
+	if !l.spent {
+		l.spent = l.remaining == 0 || (!l.deadline.IsZero() && !time.Now().Before(l.deadline))
+	}
+	return l.spent
+}
+
+// samplingFailure returns why a constrained sampling failed, for relaxation logs
+func samplingFailure(limit *samplingLimit, reason string) string {
+	if limit.exhausted() {
+		return "sampling budget exhausted"
+	}
+	return reason
+}
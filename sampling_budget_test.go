@@ -0,0 +1,85 @@
+package forgeron
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSamplingLimit(t *testing.T) {
+	var unlimited *samplingLimit
+	unlimited.backtrack()
+	if unlimited.exhausted() {
+		t.Error("nil limit is exhausted")
+	}
+	if limit := (samplingBudget{}).start(); limit != nil {
+		t.Errorf("start() = %+v for an unlimited budget, want nil", limit)
+	}
+
+	limit := samplingBudget{maxBacktracks: 2}.start()
+	limit.backtrack()
+	if limit.exhausted() {
+		t.Error("limit exhausted after 1 of 2 backtracks")
+	}
+	limit.backtrack()
+	if !limit.exhausted() {
+		t.Error("limit not exhausted after 2 of 2 backtracks")
+	}
+
+	limit = samplingBudget{maxDuration: time.Nanosecond}.start()
+	time.Sleep(time.Millisecond)
+	if !limit.exhausted() {
+		t.Error("limit not exhausted past its deadline")
+	}
+}
+
+func TestSamplerStopsWhenBudgetIsExhausted(t *testing.T) {
+	var buf bytes.Buffer
+	network := createTestNetwork()
+	network.logger = slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	limit := samplingBudget{maxBacktracks: 1}.start()
+	if _, ok := network.generateConsistentSampleWithinLimit(map[string][]string{"B": {"b3"}}, limit); ok {
+		t.Fatal("expected sampling to fail")
+	}
+	out := buf.String()
+	for _, want := range []string{"bans=1", "budgetExhausted=true"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log %q does not contain %q", out, want)
+		}
+	}
+}
+
+func TestGenerateWithSamplingBudget(t *testing.T) {
+	gen := newGeneratorOrFatal(t)
+	minWidth := 1920
+	screen := WithScreen(&Screen{MinWidth: &minWidth})
+
+	if _, err := gen.Generate(screen, WithStrict(true), WithSamplingBudget(0, time.Nanosecond)); !errors.Is(err, ErrSamplingBudgetExhausted) {
+		t.Errorf("Generate() strict error = %v, want ErrSamplingBudgetExhausted", err)
+	}
+
+	// Without strict mode the constraints are relaxed instead
+	fp, err := gen.Generate(screen, WithSamplingBudget(0, time.Nanosecond))
+	if err != nil || fp == nil {
+		t.Errorf("Generate() = %v, %v, want a relaxed fingerprint", fp, err)
+	}
+
+	// A generous budget does not change the outcome, on Windows desktops which all have such screens
+	desktopWidth := 1280
+	windows := WithHeaderConstraints(HeaderConstraints{OS: []OS{Windows}, Devices: []Device{Desktop}})
+	fp, err = gen.Generate(WithScreen(&Screen{MinWidth: &desktopWidth}), windows, WithStrict(true), WithSamplingBudget(10_000, time.Minute))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if fp.Screen.Width < 1280 {
+		t.Errorf("screen width = %d, want at least 1280", fp.Screen.Width)
+	}
+
+	if _, err := gen.Generate(WithSamplingBudget(-1, 0)); err == nil {
+		t.Error("Generate() error = nil, want an error for a negative budget")
+	}
+}
@@ -0,0 +1,306 @@
+package forgeron
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// SchedulerConfig configures the identity scheduler
+type SchedulerConfig struct {
+	// DomainConcurrency is the maximum number of in-flight requests per domain
+	DomainConcurrency int
+	// IdentityBudget is the number of requests an identity can make before cooling down
+	IdentityBudget int
+	// Cooldown is how long an identity rests once its budget is spent
+	Cooldown time.Duration
+	// MaxIdentities caps the number of identities generated by the scheduler
+	MaxIdentities int
+	// Proxies are assigned round-robin to new identities, and can be empty
+	Proxies []string
+	// Composition is the target mix of identities, shares must add up to 1. Empty means no target.
+	Composition []CompositionTarget
+}
+
+// CompositionTarget is the share of identities generated with the given constraints
+type CompositionTarget struct {
+	Share       float64
+	Constraints Constraints
+}
+
+// Validate validates the scheduler configuration
+func (c SchedulerConfig) Validate() error {
+	if c.DomainConcurrency <= 0 {
+		return fmt.Errorf("domain concurrency must be positive")
+	}
+	if c.IdentityBudget <= 0 {
+		return fmt.Errorf("identity budget must be positive")
+	}
+	if c.MaxIdentities <= 0 {
+		return fmt.Errorf("max identities must be positive")
+	}
+	if c.Cooldown < 0 {
+		return fmt.Errorf("cooldown cannot be negative")
+	}
+	if len(c.Composition) > 0 {
+		total := 0.0
+		for i, target := range c.Composition {
+			if target.Share <= 0 {
+				return fmt.Errorf("composition target %d must have a positive share", i)
+			}
+			if err := target.Constraints.Validate(); err != nil {
+				return fmt.Errorf("composition target %d: %w", i, err)
+			}
+			total += target.Share
+		}
+		if math.Abs(total-1) > 1e-6 {
+			return fmt.Errorf("composition shares must add up to 1, got %v", total)
+		}
+	}
+	return nil
+}
+
+// scheduledIdentity is an identity managed by the scheduler
+type scheduledIdentity struct {
+	fingerprint   *Fingerprint
+	proxy         string
+	used          int
+	cooldownUntil time.Time
+	// target is the index of the composition target of the identity, or -1
+	target int
+}
+
+// Lease is an (identity, proxy) pair handed out for a single request
+type Lease struct {
+	Fingerprint *Fingerprint
+	Proxy       string
+	Domain      string
+	once        sync.Once
+	release     func()
+	retire      func()
+}
+
+// Release returns the domain slot to the scheduler, it must be called once the request is done
+func (l *Lease) Release() {
+	l.once.Do(l.release)
+}
+
+// Retire returns the domain slot and removes the identity from the scheduler, e.g. once it got blocked.
+// A replacement is generated on demand, keeping the configured composition.
+func (l *Lease) Retire() {
+	l.once.Do(l.retire)
+}
+
+// Scheduler hands out identities and proxies while enforcing per-domain concurrency,
+// per-identity request budgets and cooldowns
+type Scheduler struct {
+	generator  FingerprintProvider
+	opts       []FingerprintOption
+	config     SchedulerConfig
+	now        func() time.Time
+	mu         sync.Mutex
+	identities []*scheduledIdentity
+	next       int
+	inFlight   map[string]int
+	changed    chan struct{}
+	generated  int
+	// pending are the composition targets of the identities being generated
+	pending []int
+}
+
+// NewScheduler creates a scheduler generating identities with the given generator and options
+func NewScheduler(generator FingerprintProvider, config SchedulerConfig, opts ...FingerprintOption) (*Scheduler, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid scheduler config: %w", err)
+	}
+	return &Scheduler{
+		generator: generator,
+		opts:      opts,
+		config:    config,
+		now:       time.Now,
+		inFlight:  make(map[string]int),
+		changed:   make(chan struct{}),
+	}, nil
+}
+
+// Acquire blocks until a domain slot and a rested identity are available, or the context is done
+func (s *Scheduler) Acquire(ctx context.Context, domain string) (*Lease, error) {
+	for {
+		s.mu.Lock()
+		wait := time.Duration(-1)
+		if s.inFlight[domain] < s.config.DomainConcurrency {
+			identity, retryIn := s.pickIdentity()
+			if identity != nil {
+				s.inFlight[domain]++
+				s.mu.Unlock()
+				return s.lease(domain, identity), nil
+			}
+			if len(s.identities)+len(s.pending) < s.config.MaxIdentities {
+				// Reserve the domain slot and the identity, then generate without holding the lock
+				target := s.pickTarget()
+				s.pending = append(s.pending, target)
+				s.inFlight[domain]++
+				s.mu.Unlock()
+				return s.generate(domain, target)
+			}
+			wait = retryIn
+		}
+		changed := s.changed
+		s.mu.Unlock()
+
+		if err := waitForChange(ctx, changed, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// lease returns a lease of an identity holding a slot of the domain
+func (s *Scheduler) lease(domain string, identity *scheduledIdentity) *Lease {
+	return &Lease{
+		Fingerprint: identity.fingerprint,
+		Proxy:       identity.proxy,
+		Domain:      domain,
+		release:     func() { s.release(domain) },
+		retire:      func() { s.retire(domain, identity) },
+	}
+}
+
+// generate generates an identity of the composition target for a reserved domain slot, freeing the slot when the
+// generation fails
+func (s *Scheduler) generate(domain string, target int) (*Lease, error) {
+	opts := s.opts
+	if target >= 0 {
+		opts = append(slices.Clip(opts), WithConstraints(s.config.Composition[target].Constraints))
+	}
+	fp, err := s.generator.Generate(opts...)
+
+	s.mu.Lock()
+	i := slices.Index(s.pending, target)
+	s.pending = slices.Delete(s.pending, i, i+1)
+	if err != nil {
+		s.mu.Unlock()
+		s.release(domain)
+		return nil, fmt.Errorf("failed to generate identity: %w", err)
+	}
+	identity := &scheduledIdentity{fingerprint: fp, target: target}
+	if len(s.config.Proxies) > 0 {
+		identity.proxy = s.config.Proxies[s.generated%len(s.config.Proxies)]
+	}
+	s.generated++
+	s.identities = append(s.identities, identity)
+	s.use(identity, s.now())
+	// The identity may have budget left for waiting callers
+	s.notify()
+	s.mu.Unlock()
+	return s.lease(domain, identity), nil
+}
+
+// waitForChange waits until the state changes, the wait duration elapses (when not negative) or the context is done
+func waitForChange(ctx context.Context, changed <-chan struct{}, wait time.Duration) error {
+	var timeout <-chan time.Time
+	if wait >= 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-changed:
+	case <-timeout:
+	}
+	return nil
+}
+
+// pickIdentity returns a rested identity, or the time until one is rested, negative when none is.
+// It must be called with the lock held.
+func (s *Scheduler) pickIdentity() (*scheduledIdentity, time.Duration) {
+	now := s.now()
+	var earliest time.Time
+	for i := range s.identities {
+		identity := s.identities[(s.next+i)%len(s.identities)]
+		if now.Before(identity.cooldownUntil) {
+			if earliest.IsZero() || identity.cooldownUntil.Before(earliest) {
+				earliest = identity.cooldownUntil
+			}
+			continue
+		}
+		s.next = (s.next + i + 1) % len(s.identities)
+		s.use(identity, now)
+		return identity, 0
+	}
+	if earliest.IsZero() {
+		return nil, -1
+	}
+	return nil, earliest.Sub(now)
+}
+
+// pickTarget returns the composition target furthest below its share, or -1 without composition.
+// It must be called with the lock held.
+func (s *Scheduler) pickTarget() int {
+	if len(s.config.Composition) == 0 {
+		return -1
+	}
+	counts := make([]int, len(s.config.Composition))
+	for _, identity := range s.identities {
+		if identity.target >= 0 {
+			counts[identity.target]++
+		}
+	}
+	for _, target := range s.pending {
+		counts[target]++
+	}
+	next := float64(len(s.identities) + len(s.pending) + 1)
+	best, bestDeficit := 0, math.Inf(-1)
+	for i, target := range s.config.Composition {
+		if deficit := target.Share*next - float64(counts[i]); deficit > bestDeficit {
+			best, bestDeficit = i, deficit
+		}
+	}
+	return best
+}
+
+// use records a request made by an identity and starts its cooldown once the budget is spent
+func (s *Scheduler) use(identity *scheduledIdentity, now time.Time) {
+	identity.used++
+	if identity.used >= s.config.IdentityBudget {
+		identity.used = 0
+		identity.cooldownUntil = now.Add(s.config.Cooldown)
+	}
+}
+
+// release frees a domain slot and wakes up waiting callers
+func (s *Scheduler) release(domain string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inFlight[domain]--
+	if s.inFlight[domain] <= 0 {
+		delete(s.inFlight, domain)
+	}
+	s.notify()
+}
+
+// notify wakes up waiting callers, it must be called with the lock held
+func (s *Scheduler) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// retire removes an identity and frees its domain slot
+func (s *Scheduler) retire(domain string, identity *scheduledIdentity) {
+	s.mu.Lock()
+	if i := slices.Index(s.identities, identity); i >= 0 {
+		s.identities = slices.Delete(s.identities, i, i+1)
+		if s.next > i {
+			s.next--
+		}
+		if s.next >= len(s.identities) {
+			s.next = 0
+		}
+	}
+	s.mu.Unlock()
+	s.release(domain)
+}
@@ -0,0 +1,210 @@
+package forgeron
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSchedulerEnforcesDomainConcurrency(t *testing.T) {
+	gen := newGeneratorOrFatal(t)
+	s, err := NewScheduler(gen, SchedulerConfig{
+		DomainConcurrency: 1,
+		IdentityBudget:    10,
+		MaxIdentities:     2,
+		Proxies:           []string{"http://proxy-a", "http://proxy-b"},
+	})
+	if err != nil {
+		t.Fatalf("NewScheduler() error = %v", err)
+	}
+
+	lease, err := s.Acquire(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	if lease.Proxy != "http://proxy-a" {
+		t.Errorf("Proxy = %q, want http://proxy-a", lease.Proxy)
+	}
+
+	// A second request to the same domain must wait for the first one
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := s.Acquire(ctx, "example.com"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Acquire() error = %v, want deadline exceeded", err)
+	}
+
+	// Other domains are not affected
+	other, err := s.Acquire(context.Background(), "example.org")
+	if err != nil {
+		t.Fatalf("Acquire() other domain error = %v", err)
+	}
+	other.Release()
+
+	lease.Release()
+	lease.Release()
+	again, err := s.Acquire(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("Acquire() after release error = %v", err)
+	}
+	again.Release()
+}
+
+func TestSchedulerEnforcesCooldown(t *testing.T) {
+	gen := newGeneratorOrFatal(t)
+	s, err := NewScheduler(gen, SchedulerConfig{
+		DomainConcurrency: 10,
+		IdentityBudget:    1,
+		Cooldown:          100 * time.Millisecond,
+		MaxIdentities:     1,
+	})
+	if err != nil {
+		t.Fatalf("NewScheduler() error = %v", err)
+	}
+
+	first, err := s.Acquire(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	first.Release()
+
+	start := time.Now()
+	second, err := s.Acquire(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	second.Release()
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("identity reused after %v, expected a cooldown", elapsed)
+	}
+	if first.Fingerprint != second.Fingerprint {
+		t.Error("expected the single identity to be reused after its cooldown")
+	}
+}
+
+func TestSchedulerStats(t *testing.T) {
+	gen := newGeneratorOrFatal(t)
+	s, err := NewScheduler(gen, SchedulerConfig{DomainConcurrency: 2, IdentityBudget: 1, Cooldown: time.Hour, MaxIdentities: 2},
+		WithHeaderConstraints(HeaderConstraints{Browsers: []Browser{"firefox"}, Strict: true}))
+	if err != nil {
+		t.Fatalf("NewScheduler() error = %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		lease, err := s.Acquire(context.Background(), "example.com")
+		if err != nil {
+			t.Fatalf("Acquire() error = %v", err)
+		}
+		defer lease.Release()
+	}
+	stats := s.Stats()
+	if stats.Total != 2 || stats.Browsers["firefox"] != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+// browserProvider returns fingerprints whose user agent names the requested browser
+type browserProvider struct{}
+
+func (browserProvider) Generate(opts ...FingerprintOption) (*Fingerprint, error) {
+	request := NewGenerateRequest(opts...)
+	return &Fingerprint{Navigator: NavigatorFingerprint{UserAgent: string(request.Constraints.Browsers[0])}}, nil
+}
+
+func TestSchedulerKeepsComposition(t *testing.T) {
+	target := func(share float64, browser Browser) CompositionTarget {
+		return CompositionTarget{Share: share, Constraints: Constraints{HeaderConstraints: HeaderConstraints{Browsers: []Browser{browser}}}}
+	}
+	s, err := NewScheduler(browserProvider{}, SchedulerConfig{
+		DomainConcurrency: 10,
+		IdentityBudget:    1,
+		Cooldown:          time.Hour,
+		MaxIdentities:     4,
+		Composition:       []CompositionTarget{target(0.5, "chrome"), target(0.25, "firefox"), target(0.25, "safari")},
+	})
+	if err != nil {
+		t.Fatalf("NewScheduler() error = %v", err)
+	}
+
+	counts := func() map[string]int {
+		counts := make(map[string]int)
+		for _, identity := range s.identities {
+			counts[identity.fingerprint.Navigator.UserAgent]++
+		}
+		return counts
+	}
+	leases := make(map[string]*Lease)
+	for i := 0; i < 4; i++ {
+		lease, err := s.Acquire(context.Background(), "example.com")
+		if err != nil {
+			t.Fatalf("Acquire() error = %v", err)
+		}
+		leases[lease.Fingerprint.Navigator.UserAgent] = lease
+	}
+	if got := counts(); got["chrome"] != 2 || got["firefox"] != 1 || got["safari"] != 1 {
+		t.Fatalf("unexpected composition: %v", got)
+	}
+
+	// Retiring the firefox identity replaces it with another firefox identity
+	leases["firefox"].Retire()
+	lease, err := s.Acquire(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	if lease.Fingerprint.Navigator.UserAgent != "firefox" {
+		t.Errorf("replacement is %q, want firefox", lease.Fingerprint.Navigator.UserAgent)
+	}
+	if got := counts(); got["chrome"] != 2 || got["firefox"] != 1 || got["safari"] != 1 {
+		t.Errorf("unexpected composition after replacement: %v", got)
+	}
+}
+
+func TestSchedulerConfigValidatesComposition(t *testing.T) {
+	config := SchedulerConfig{DomainConcurrency: 1, IdentityBudget: 1, MaxIdentities: 1,
+		Composition: []CompositionTarget{{Share: 0.5}, {Share: 0.2}}}
+	if err := config.Validate(); err == nil {
+		t.Error("expected error for shares not adding up to 1")
+	}
+}
+
+func TestSchedulerGeneratesOutsideLock(t *testing.T) {
+	provider := &blockingProvider{started: make(chan struct{}, 2), release: make(chan struct{})}
+	s, err := NewScheduler(provider, SchedulerConfig{DomainConcurrency: 1, IdentityBudget: 10, MaxIdentities: 2})
+	if err != nil {
+		t.Fatalf("NewScheduler() error = %v", err)
+	}
+	results := make(chan error, 2)
+	acquire := func(domain string) {
+		lease, err := s.Acquire(context.Background(), domain)
+		if err == nil {
+			lease.Release()
+		}
+		results <- err
+	}
+	go acquire("example.com")
+	<-provider.started
+
+	// A slow generation blocks neither the stats nor generations for other domains
+	if stats := s.Stats(); stats.Total != 0 {
+		t.Errorf("Stats().Total = %d, want 0", stats.Total)
+	}
+	go acquire("example.org")
+	<-provider.started
+
+	// The generations hold their domain slot
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := s.Acquire(ctx, "example.com"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Acquire() error = %v, want deadline exceeded", err)
+	}
+
+	provider.release <- struct{}{}
+	provider.release <- struct{}{}
+	for range 2 {
+		if err := <-results; err != nil {
+			t.Fatalf("Acquire() error = %v", err)
+		}
+	}
+	if got := len(s.identities); got != 2 {
+		t.Errorf("generated %d identities, want 2", got)
+	}
+}
@@ -0,0 +1,226 @@
+package forgeron
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"strings"
+)
+
+// parseVersion splits a dotted version into its numeric parts
+func parseVersion(version string) []int {
+	parts := strings.Split(version, ".")
+	numbers := make([]int, len(parts))
+	for i, part := range parts {
+		numbers[i] = atoi(part)
+	}
+	return numbers
+}
+
+// versionAtLeast reports whether version is greater than or equal to min, missing parts count as 0
+func versionAtLeast(version, min []int) bool {
+	for i := 0; i < len(version) || i < len(min); i++ {
+		var v, m int
+		if i < len(version) {
+			v = version[i]
+		}
+		if i < len(min) {
+			m = min[i]
+		}
+		if v != m {
+			return v > m
+		}
+	}
+	return true
+}
+
+// loadSecFetchSupport loads the first version of each browser sending Sec-Fetch headers from sec-fetch-support.json,
+// so new browser behavior only needs a data change. Browsers missing from the file never send them.
+func (g *HeaderGenerator) loadSecFetchSupport() error {
+	data, err := fs.ReadFile(g.data, "sec-fetch-support.json")
+	if err != nil {
+		return fmt.Errorf("failed to read sec-fetch-support.json: %w", err)
+	}
+	var versions map[string]string
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return fmt.Errorf("failed to parse sec-fetch-support.json: %w", err)
+	}
+	g.secFetchSupport = make(map[string][]int, len(versions))
+	for browser, version := range versions {
+		g.secFetchSupport[strings.ToLower(browser)] = parseVersion(version)
+	}
+	return nil
+}
+
+// shouldAddSecFetch determines if Sec-Fetch headers should be added for the browser version
+func (g *HeaderGenerator) shouldAddSecFetch(browser *httpBrowser) bool {
+	if browser == nil || browser.Name == nil || len(browser.Version) == 0 {
+		return false
+	}
+	min, ok := g.secFetchSupport[*browser.Name]
+	return ok && versionAtLeast(browser.Version, min)
+}
+
+// RequestContext is the context a request is made in, browsers derive the Sec-Fetch metadata headers from it
+type RequestContext string
+
+const (
+	// TopLevelNavigation is a user navigating the top-level browsing context, e.g. by typing a URL or opening a bookmark
+	TopLevelNavigation RequestContext = "navigation"
+	// IframeNavigation is a document loaded in a cross-site iframe
+	IframeNavigation RequestContext = "iframe"
+	// FirstPartySubresource is a fetch or XMLHttpRequest call to the origin of the page
+	FirstPartySubresource RequestContext = "first-party"
+	// ThirdPartySubresource is a fetch or XMLHttpRequest call to another site
+	ThirdPartySubresource RequestContext = "third-party"
+	// ImageSubresource is an image loaded by a page from its origin, its Accept header lists the image formats the
+	// browser decodes
+	ImageSubresource RequestContext = "image"
+	// ScriptSubresource is a classic script loaded by a page from its origin
+	ScriptSubresource RequestContext = "script"
+	// StylesheetSubresource is a stylesheet loaded by a page from its origin
+	StylesheetSubresource RequestContext = "stylesheet"
+)
+
+// SupportedRequestContexts lists the request contexts headers can be generated for
+var SupportedRequestContexts = []RequestContext{TopLevelNavigation, IframeNavigation, FirstPartySubresource, ThirdPartySubresource, ImageSubresource, ScriptSubresource, StylesheetSubresource}
+
+// FetchSite is a Sec-Fetch-Site value, the relation between the origin initiating a request and its target
+type FetchSite string
+
+const (
+	// FetchSiteNone is a navigation the user started, e.g. by typing a URL or opening a bookmark
+	FetchSiteNone FetchSite = "none"
+	// FetchSiteSameOrigin is a request to the origin of the page making it
+	FetchSiteSameOrigin FetchSite = "same-origin"
+	// FetchSiteSameSite is a request to another origin of the site of the page, e.g. www.example.com to api.example.com
+	FetchSiteSameSite FetchSite = "same-site"
+	// FetchSiteCrossSite is a request to another site
+	FetchSiteCrossSite FetchSite = "cross-site"
+)
+
+// SupportedFetchSites lists the Sec-Fetch-Site values headers can be generated with
+var SupportedFetchSites = []FetchSite{FetchSiteNone, FetchSiteSameOrigin, FetchSiteSameSite, FetchSiteCrossSite}
+
+// FetchUser decides whether the Sec-Fetch-User header is sent
+type FetchUser string
+
+const (
+	// FetchUserActivated sends Sec-Fetch-User: ?1, for navigations the user activated, e.g. by clicking a link
+	FetchUserActivated FetchUser = "?1"
+	// FetchUserAbsent sends no Sec-Fetch-User, as for navigations started by scripts and redirects
+	FetchUserAbsent FetchUser = "absent"
+)
+
+// SupportedFetchUsers lists the Sec-Fetch-User settings headers can be generated with
+var SupportedFetchUsers = []FetchUser{FetchUserActivated, FetchUserAbsent}
+
+// validateSecFetch checks the Sec-Fetch-Site and Sec-Fetch-User values are possible in the request context: only
+// top-level navigations are started by the user without a page, and only navigations are user activated
+func validateSecFetch(context RequestContext, site FetchSite, user FetchUser) []error {
+	var errs []error
+	if site != "" {
+		if err := validateAgainstSupported(site, SupportedFetchSites); err != nil {
+			errs = append(errs, newFieldError("SecFetchSite", err, site))
+		} else if site == FetchSiteNone && context != "" && context != TopLevelNavigation {
+			err := fmt.Errorf("sec-fetch-site 'none' is only sent on top-level navigations, not in the %s context", context)
+			errs = append(errs, newFieldError("SecFetchSite", err, site))
+		}
+	}
+	if user != "" {
+		if err := validateAgainstSupported(user, SupportedFetchUsers); err != nil {
+			errs = append(errs, newFieldError("SecFetchUser", err, user))
+		} else if user == FetchUserActivated && context.requestType() != Navigation {
+			err := fmt.Errorf("sec-fetch-user is only sent on navigations, not in the %s context", context)
+			errs = append(errs, newFieldError("SecFetchUser", err, user))
+		}
+	}
+	return errs
+}
+
+// secFetchHeaders returns the lowercase Sec-Fetch headers a browser sends in the request context, following
+// the Fetch Metadata spec. An empty site or user keeps the one of the context. Sec-Fetch-User is only sent on
+// user activated navigations, by default the top-level ones, and never by Safari.
+func secFetchHeaders(context RequestContext, site FetchSite, user FetchUser, browser Browser) map[string]string {
+	var contextSite FetchSite
+	var mode, dest string
+	userActivated := false
+	switch context {
+	case IframeNavigation:
+		contextSite, mode, dest = FetchSiteCrossSite, "navigate", "iframe"
+	case FirstPartySubresource:
+		contextSite, mode, dest = FetchSiteSameOrigin, "cors", "empty"
+	case ThirdPartySubresource:
+		contextSite, mode, dest = FetchSiteCrossSite, "cors", "empty"
+	case ImageSubresource:
+		contextSite, mode, dest = FetchSiteSameOrigin, "no-cors", "image"
+	case ScriptSubresource:
+		contextSite, mode, dest = FetchSiteSameOrigin, "no-cors", "script"
+	case StylesheetSubresource:
+		contextSite, mode, dest = FetchSiteSameOrigin, "no-cors", "style"
+	default:
+		contextSite, mode, dest = FetchSiteNone, "navigate", "document"
+		userActivated = true
+	}
+	if site == "" {
+		site = contextSite
+	}
+	switch user {
+	case FetchUserActivated:
+		userActivated = mode == "navigate"
+	case FetchUserAbsent:
+		userActivated = false
+	}
+	headers := map[string]string{
+		"sec-fetch-site": string(site),
+		"sec-fetch-mode": mode,
+		"sec-fetch-dest": dest,
+	}
+	if userActivated && browser != Safari {
+		headers["sec-fetch-user"] = string(FetchUserActivated)
+	}
+	return headers
+}
+
+// requestType returns the request type whose header order applies to the request context
+func (c RequestContext) requestType() RequestType {
+	if c == "" || c == TopLevelNavigation || c == IframeNavigation {
+		return Navigation
+	}
+	return Fetch
+}
+
+// accept returns the Accept header the browser of the user agent sends in the request context, or "" to keep the
+// sampled document one
+func (c RequestContext) accept(userAgent string) string {
+	switch c {
+	case ImageSubresource:
+		return imageAccept(userAgent)
+	case StylesheetSubresource:
+		return "text/css,*/*;q=0.1"
+	case FirstPartySubresource, ThirdPartySubresource, ScriptSubresource:
+		return "*/*"
+	}
+	return ""
+}
+
+// Referer returns the Referer header a request made in the context from page sends, under the
+// strict-origin-when-cross-origin policy browsers default to: none for top-level navigations, the page without its
+// fragment for same-origin subresources and the origin of the page for cross-site requests. Targets downgrading
+// HTTPS to HTTP get no Referer, as with any policy but unsafe-url.
+func (c RequestContext) Referer(page string) string {
+	u, err := url.Parse(page)
+	if err != nil || u.Scheme == "" || u.Host == "" || c == "" || c == TopLevelNavigation {
+		return ""
+	}
+	if c == IframeNavigation || c == ThirdPartySubresource {
+		return u.Scheme + "://" + u.Host + "/"
+	}
+	u.User = nil
+	u.Fragment = ""
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u.String()
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ta0uf19/forgeron"
+)
+
+// defaultRecent is the number of recent identities summarized when none is configured
+const defaultRecent = 1000
+
+// GenerationStats are the generation counters of an Inspector
+type GenerationStats struct {
+	Generated int    `json:"generated"`
+	Failed    int    `json:"failed"`
+	LastError string `json:"lastError,omitempty"`
+	// Recent is the composition of the last generated identities
+	Recent forgeron.BatchStats `json:"recent"`
+}
+
+// Inspector generates fingerprints while recording the recent generations, and serves an inspection page with
+// the loaded data, the generation stats and a button generating a sample identity. It is safe for concurrent use.
+type Inspector struct {
+	gen     *forgeron.FingerprintGenerator
+	started time.Time
+	mu      sync.Mutex
+	// recent is a ring of the last generated identities, next is where the next one is written
+	recent []*forgeron.Fingerprint
+	next   int
+	stats  GenerationStats
+}
+
+// NewInspector creates an inspector of the generator summarizing the last recent identities, or 1000 when zero
+func NewInspector(gen *forgeron.FingerprintGenerator, recent int) *Inspector {
+	if recent <= 0 {
+		recent = defaultRecent
+	}
+	return &Inspector{gen: gen, started: time.Now(), recent: make([]*forgeron.Fingerprint, 0, recent)}
+}
+
+// Generate generates a fingerprint with the generator and records it
+func (i *Inspector) Generate(opts ...forgeron.FingerprintOption) (*forgeron.Fingerprint, error) {
+	return i.record(i.gen.Generate(opts...))
+}
+
+// record records the outcome of a generation
+func (i *Inspector) record(fp *forgeron.Fingerprint, err error) (*forgeron.Fingerprint, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if err != nil {
+		i.stats.Failed++
+		i.stats.LastError = err.Error()
+		return nil, err
+	}
+	i.stats.Generated++
+	if len(i.recent) < cap(i.recent) {
+		i.recent = append(i.recent, fp)
+	} else {
+		i.recent[i.next] = fp
+	}
+	i.next = (i.next + 1) % cap(i.recent)
+	return fp, nil
+}
+
+// Stats returns the generation counters and the composition of the recent identities
+func (i *Inspector) Stats() GenerationStats {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	stats := i.stats
+	stats.Recent = forgeron.Summarize(i.recent)
+	return stats
+}
+
+// debugPage is the data of the inspection page, also served as JSON with ?format=json
+type debugPage struct {
+	Data forgeron.DataInfo `json:"data"`
+	// ReadsSchema is the data schema read by this release
+	ReadsSchema int             `json:"readsSchema"`
+	Uptime      string          `json:"uptime"`
+	Stats       GenerationStats `json:"stats"`
+	Sample      string          `json:"sample,omitempty"`
+	Error       string          `json:"error,omitempty"`
+}
+
+// ServeHTTP serves the inspection page, a POST generates a sample identity shown on the page
+func (i *Inspector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	page := debugPage{
+		Data:        i.gen.DataInfo(),
+		ReadsSchema: forgeron.DataSchemaVersion,
+		Uptime:      time.Since(i.started).Round(time.Second).String(),
+	}
+	if r.Method == http.MethodPost {
+		fp, err := i.Generate()
+		if err == nil {
+			var sample []byte
+			sample, err = json.MarshalIndent(fp, "", "  ")
+			page.Sample = string(sample)
+		}
+		if err != nil {
+			page.Error = err.Error()
+		}
+	}
+	page.Stats = i.Stats()
+
+	if r.URL.Query().Get("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(page)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	debugTemplate.Execute(w, page)
+}
+
+var debugTemplate = template.Must(template.New("debug").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>forgeron</title></head>
+<body>
+<h1>forgeron</h1>
+<h2>Data</h2>
+<p>Schema {{.Data.Schema}}{{with .Data.Requires}}, requires forgeron {{.}}{{end}} (this release reads schema {{.ReadsSchema}}), up {{.Uptime}}</p>
+<p>Browsers: {{range .Data.Browsers}}{{.}} {{end}}</p>
+<p>OS: {{range .Data.OS}}{{.}} {{end}}</p>
+<p>Devices: {{range .Data.Devices}}{{.}} {{end}}</p>
+<h2>Generations</h2>
+<p>{{.Stats.Generated}} generated, {{.Stats.Failed}} failed{{with .Stats.LastError}}, last error: {{.}}{{end}}</p>
+<pre>{{.Stats.Recent}}</pre>
+<form method="post"><button type="submit">Generate sample</button></form>
+{{with .Error}}<p>Error: {{.}}</p>{{end}}
+{{with .Sample}}<pre>{{.}}</pre>{{end}}
+</body>
+</html>
+`))
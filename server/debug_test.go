@@ -0,0 +1,69 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ta0uf19/forgeron"
+)
+
+func newInspector(t *testing.T, recent int) *Inspector {
+	t.Helper()
+	gen, err := forgeron.NewFingerprintGenerator()
+	if err != nil {
+		t.Fatalf("NewFingerprintGenerator() error = %v", err)
+	}
+	return NewInspector(gen, recent)
+}
+
+func TestInspectorStats(t *testing.T) {
+	inspector := newInspector(t, 2)
+	for i := 0; i < 3; i++ {
+		if _, err := inspector.Generate(); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+	}
+	strict := forgeron.WithHeaderConstraints(forgeron.HeaderConstraints{Browsers: []forgeron.Browser{"netscape"}, Strict: true})
+	if _, err := inspector.Generate(strict); err == nil {
+		t.Fatal("Generate() error = nil for an unknown browser")
+	}
+	stats := inspector.Stats()
+	if stats.Generated != 3 || stats.Failed != 1 || stats.LastError == "" {
+		t.Errorf("Stats() = %+v, want 3 generated and 1 failure", stats)
+	}
+	if stats.Recent.Total != 2 {
+		t.Errorf("Recent.Total = %d, want the last 2 identities", stats.Recent.Total)
+	}
+}
+
+func TestInspectorPage(t *testing.T) {
+	inspector := newInspector(t, 0)
+
+	rec := httptest.NewRecorder()
+	inspector.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Generate sample") {
+		t.Fatalf("GET /debug = %d %s", rec.Code, rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	inspector.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug?format=json", nil))
+	var page debugPage
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatalf("POST /debug?format=json body %s: %v", rec.Body, err)
+	}
+	if page.Data.Schema != forgeron.DataSchemaVersion || len(page.Data.Browsers) == 0 {
+		t.Errorf("Data = %+v", page.Data)
+	}
+	if page.Stats.Generated != 1 || !strings.Contains(page.Sample, "userAgent") {
+		t.Errorf("Stats = %+v, Sample = %q, want a generated sample", page.Stats, page.Sample)
+	}
+
+	rec = httptest.NewRecorder()
+	inspector.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/debug", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("DELETE /debug = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/ta0uf19/forgeron"
+)
+
+// constraintOutcome is a constraint with the reason it failed or was relaxed
+type constraintOutcome struct {
+	constraint string
+	reason     string
+}
+
+// Metrics counts the generations of a server and the constraints they failed on or relaxed, so operators notice
+// when their constraints stop matching the data, e.g. after a data refresh. It serves the counters in the
+// Prometheus text format and is safe for concurrent use.
+type Metrics struct {
+	mu          sync.Mutex
+	generated   int
+	failed      int
+	relaxations map[constraintOutcome]int
+	failures    map[constraintOutcome]int
+}
+
+// NewMetrics creates empty metrics
+func NewMetrics() *Metrics {
+	return &Metrics{relaxations: make(map[constraintOutcome]int), failures: make(map[constraintOutcome]int)}
+}
+
+// Record counts a generation with the constraints it relaxed, or the constraint it failed on when err is not nil
+func (m *Metrics) Record(report *forgeron.RelaxationReport, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil {
+		m.failed++
+		m.failures[failureOutcome(err)]++
+		return
+	}
+	m.generated++
+	if report == nil {
+		return
+	}
+	for _, relaxation := range report.Relaxations {
+		m.relaxations[constraintOutcome{relaxation.Constraint, relaxation.Reason}]++
+	}
+}
+
+// failureOutcome returns the constraint a generation failed on, with the constraint names of relaxations
+func failureOutcome(err error) constraintOutcome {
+	var constraintErr *forgeron.ConstraintError
+	if errors.As(err, &constraintErr) {
+		return constraintOutcome{constraintErr.Constraint, constraintErr.Reason}
+	}
+	var fieldErr *forgeron.FieldError
+	if errors.As(err, &fieldErr) {
+		return constraintOutcome{constraintName(fieldErr.Field), "invalid value"}
+	}
+	return constraintOutcome{"none", "other"}
+}
+
+// constraintName turns a HeaderConstraints field into the name relaxations use, e.g. HTTPVersion gives httpVersion
+func constraintName(field string) string {
+	runes := []rune(field)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	// The last capital of an acronym starts the next word
+	if upper > 1 && upper < len(runes) {
+		upper--
+	}
+	for i := range max(upper, 1) {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// ServeHTTP serves the counters in the Prometheus text format
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// WriteTo writes the counters in the Prometheus text format
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var b strings.Builder
+	b.WriteString("# HELP forgeron_generations_total Generations by result.\n")
+	b.WriteString("# TYPE forgeron_generations_total counter\n")
+	fmt.Fprintf(&b, "forgeron_generations_total{result=\"generated\"} %d\n", m.generated)
+	fmt.Fprintf(&b, "forgeron_generations_total{result=\"failed\"} %d\n", m.failed)
+	writeOutcomes(&b, "forgeron_constraint_relaxations_total", "Constraints relaxed by generations, by constraint and reason.", m.relaxations)
+	writeOutcomes(&b, "forgeron_constraint_failures_total", "Failed generations by constraint and reason.", m.failures)
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// writeOutcomes writes a counter labeled by constraint and reason, in a stable order
+func writeOutcomes(b *strings.Builder, name, help string, counts map[constraintOutcome]int) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	outcomes := make([]constraintOutcome, 0, len(counts))
+	for outcome := range counts {
+		outcomes = append(outcomes, outcome)
+	}
+	slices.SortFunc(outcomes, func(a, b constraintOutcome) int {
+		return cmp.Or(cmp.Compare(a.constraint, b.constraint), cmp.Compare(a.reason, b.reason))
+	})
+	for _, outcome := range outcomes {
+		fmt.Fprintf(b, "%s{constraint=\"%s\",reason=\"%s\"} %d\n", name, escapeLabel(outcome.constraint), escapeLabel(outcome.reason), counts[outcome])
+	}
+}
+
+// escapeLabel escapes a Prometheus label value
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ta0uf19/forgeron"
+	"github.com/ta0uf19/forgeron/client"
+)
+
+func TestConstraintName(t *testing.T) {
+	tests := map[string]string{
+		"Browsers":    "browsers",
+		"OS":          "os",
+		"HTTPVersion": "httpVersion",
+		"Locales":     "locales",
+	}
+	for field, want := range tests {
+		if got := constraintName(field); got != want {
+			t.Errorf("constraintName(%q) = %q, want %q", field, got, want)
+		}
+	}
+}
+
+func TestMetricsRecord(t *testing.T) {
+	m := NewMetrics()
+	report := &forgeron.RelaxationReport{Relaxations: []forgeron.Relaxation{
+		{Constraint: "locales", Reason: "no input sample"},
+		{Constraint: "devices", Reason: "no input sample"},
+	}}
+	m.Record(report, nil)
+	m.Record(report, nil)
+	m.Record(nil, errors.New("unexpected"))
+
+	var b strings.Builder
+	m.WriteTo(&b)
+	for _, want := range []string{
+		`forgeron_generations_total{result="generated"} 2`,
+		`forgeron_generations_total{result="failed"} 1`,
+		`forgeron_constraint_relaxations_total{constraint="devices",reason="no input sample"} 2`,
+		`forgeron_constraint_relaxations_total{constraint="locales",reason="no input sample"} 2`,
+		`forgeron_constraint_failures_total{constraint="none",reason="other"} 1`,
+		"# TYPE forgeron_constraint_failures_total counter",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("metrics do not contain %q:\n%s", want, b.String())
+		}
+	}
+}
+
+func TestServerMetrics(t *testing.T) {
+	server := newTestServer(t, Config{})
+	safariOnWindows := forgeron.HeaderConstraints{Browsers: []forgeron.Browser{forgeron.Safari}, OS: []forgeron.OS{forgeron.Windows}}
+
+	var fingerprints client.FingerprintResponse
+	if status := post(t, server.URL+"/fingerprint", client.FingerprintRequest{
+		Request: forgeron.GenerateRequest{Constraints: forgeron.Constraints{HeaderConstraints: safariOnWindows}},
+	}, &fingerprints); status != http.StatusOK {
+		t.Fatalf("POST /fingerprint status = %d", status)
+	}
+	var failed client.ErrorResponse
+	strict := safariOnWindows
+	strict.Strict = true
+	if status := post(t, server.URL+"/headers", client.HeadersRequest{Constraints: strict}, &failed); status != http.StatusUnprocessableEntity {
+		t.Fatalf("POST /headers status = %d", status)
+	}
+	if status := post(t, server.URL+"/headers", client.HeadersRequest{Constraints: forgeron.HeaderConstraints{Browsers: []forgeron.Browser{"netscape"}}}, &failed); status != http.StatusBadRequest {
+		t.Fatalf("POST /headers status = %d", status)
+	}
+
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics error = %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain") {
+		t.Errorf("Content-Type = %q", resp.Header.Get("Content-Type"))
+	}
+	for _, want := range []string{
+		`forgeron_generations_total{result="generated"} 1`,
+		`forgeron_generations_total{result="failed"} 2`,
+		`forgeron_constraint_relaxations_total{constraint="os",reason="no input sample"} 1`,
+		`forgeron_constraint_failures_total{constraint="headers",reason="no input sample"} 1`,
+		`forgeron_constraint_failures_total{constraint="browsers",reason="invalid value"} 1`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics do not contain %q:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// rateLimiter limits the requests of every client with a token bucket refilled at rate tokens per second
+type rateLimiter struct {
+	rate  float64
+	burst float64
+	now   func() time.Time
+	mu    sync.Mutex
+	// buckets are the buckets of the clients seen since the last prune
+	buckets   map[string]*bucket
+	lastPrune time.Time
+}
+
+// bucket is the token bucket of a client
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter returns a limiter allowing rate requests per second and bursts of burst requests per client
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{rate: rate, burst: float64(burst), now: time.Now, buckets: make(map[string]*bucket)}
+}
+
+// allow takes a token of the client, returning how long to wait for one when the bucket is empty
+func (l *rateLimiter) allow(client string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.prune(now)
+
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[client] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// prune drops the buckets refilled to the burst, which behave like the bucket of a new client, so clients seen
+// once are not kept forever
+func (l *rateLimiter) prune(now time.Time) {
+	refill := time.Duration(l.burst / l.rate * float64(time.Second))
+	if now.Sub(l.lastPrune) < refill {
+		return
+	}
+	l.lastPrune = now
+	for client, b := range l.buckets {
+		if now.Sub(b.last) >= refill {
+			delete(l.buckets, client)
+		}
+	}
+}
+
+// clientAddr returns the address identifying the client of a request
+func clientAddr(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,219 @@
+// Package server implements a forgeron HTTP service, so services written in other languages can generate
+// fingerprints and headers from a central process. It serves the protocol of the client package:
+//
+//	POST /fingerprint  {"request": GenerateRequest, "count": n, "seed": s}  ->  {"fingerprints": [...]}
+//	POST /headers      {"constraints": HeaderConstraints, "seed": s}        ->  {"headers": {...}}
+//	GET  /debug        inspection page of the loaded data and the recent generations
+//	GET  /metrics      generations and failed or relaxed constraints in the Prometheus text format
+//
+// A non-zero seed generates the same response for the same request. Errors are reported with a non-2xx status
+// and a {"error": "..."} body.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/ta0uf19/forgeron"
+	"github.com/ta0uf19/forgeron/client"
+)
+
+const (
+	// defaultMaxCount is the number of fingerprints a request can ask for when none is configured
+	defaultMaxCount = 100
+	// maxRequestSize bounds the size of a request body
+	maxRequestSize = 1 << 20
+)
+
+// Config configures a Server
+type Config struct {
+	// Generator generates the fingerprints and headers, nil creates one with the embedded data. Data reloaded
+	// into it with Reload is served at once.
+	Generator *forgeron.FingerprintGenerator
+	// RateLimit is the number of requests per second allowed per client address, zero disables rate limiting
+	RateLimit float64
+	// Burst is the number of requests a client can make at once above the rate limit, at least 1
+	Burst int
+	// MaxCount bounds the fingerprints generated per request, zero allows 100
+	MaxCount int
+	// Recent is the number of recent identities summarized by the inspection page, zero summarizes 1000
+	Recent int
+	// Pool generates the fingerprints when set, bounding the concurrent generations. Requests arriving while its
+	// queue is full are answered with 429 Too Many Requests. Its workers should generate with Generator, so that
+	// reloaded data is served.
+	Pool *forgeron.Pool
+}
+
+// Server serves fingerprints and headers over HTTP. It is safe for concurrent use.
+type Server struct {
+	gen       *forgeron.FingerprintGenerator
+	inspector *Inspector
+	metrics   *Metrics
+	limiter   *rateLimiter
+	pool      *forgeron.Pool
+	maxCount  int
+	mux       *http.ServeMux
+}
+
+// New creates a server, creating the generator when the config has none
+func New(config Config) (*Server, error) {
+	if config.RateLimit < 0 || math.IsNaN(config.RateLimit) {
+		return nil, fmt.Errorf("invalid rate limit %v", config.RateLimit)
+	}
+	if config.MaxCount < 0 {
+		return nil, fmt.Errorf("max count cannot be negative")
+	}
+	if config.MaxCount == 0 {
+		config.MaxCount = defaultMaxCount
+	}
+	if config.Generator == nil {
+		gen, err := forgeron.NewFingerprintGenerator()
+		if err != nil {
+			return nil, err
+		}
+		config.Generator = gen
+	}
+
+	s := &Server{
+		gen:       config.Generator,
+		inspector: NewInspector(config.Generator, config.Recent),
+		metrics:   NewMetrics(),
+		pool:      config.Pool,
+		maxCount:  config.MaxCount,
+		mux:       http.NewServeMux(),
+	}
+	if config.RateLimit > 0 {
+		s.limiter = newRateLimiter(config.RateLimit, config.Burst)
+	}
+	s.mux.HandleFunc("POST /fingerprint", s.serveFingerprint)
+	s.mux.HandleFunc("POST /headers", s.serveHeaders)
+	s.mux.Handle("/debug", s.inspector)
+	s.mux.Handle("/metrics", s.metrics)
+	return s, nil
+}
+
+// Inspector returns the inspector recording the generations of the server
+func (s *Server) Inspector() *Inspector {
+	return s.inspector
+}
+
+// Metrics returns the metrics of the generations of the server
+func (s *Server) Metrics() *Metrics {
+	return s.metrics
+}
+
+// ServeHTTP serves the endpoints of the server, rejecting clients above the rate limit
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.limiter != nil {
+		if ok, wait := s.limiter.allow(clientAddr(r)); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			writeError(w, http.StatusTooManyRequests, errors.New("rate limit exceeded"))
+			return
+		}
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// serveFingerprint generates the fingerprints of a /fingerprint request
+func (s *Server) serveFingerprint(w http.ResponseWriter, r *http.Request) {
+	var req client.FingerprintRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if req.Count == 0 {
+		req.Count = 1
+	}
+	if req.Count < 0 || req.Count > s.maxCount {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("count must be between 1 and %d", s.maxCount))
+		return
+	}
+	var report forgeron.RelaxationReport
+	opts := append(req.Request.Options(), forgeron.WithRelaxationReport(&report))
+	if req.Seed != 0 {
+		opts = append(opts, forgeron.WithRandomSource(forgeron.NewSeededSource(req.Seed)))
+	}
+
+	response := client.FingerprintResponse{Fingerprints: make([]*forgeron.Fingerprint, 0, req.Count)}
+	for range req.Count {
+		fp, err := s.generate(r.Context(), opts)
+		s.metrics.Record(&report, err)
+		if err != nil {
+			if errors.Is(err, forgeron.ErrPoolFull) {
+				w.Header().Set("Retry-After", "1")
+			}
+			writeError(w, generationStatus(err), err)
+			return
+		}
+		response.Fingerprints = append(response.Fingerprints, fp)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// generate generates a fingerprint with the pool, failing at once when its queue is full, or with the generator
+// without a pool
+func (s *Server) generate(ctx context.Context, opts []forgeron.FingerprintOption) (*forgeron.Fingerprint, error) {
+	if s.pool == nil {
+		return s.inspector.Generate(opts...)
+	}
+	fp, err := s.pool.TryGet(ctx, opts...)
+	return s.inspector.record(fp, err)
+}
+
+// serveHeaders generates the headers of a /headers request
+func (s *Server) serveHeaders(w http.ResponseWriter, r *http.Request) {
+	var req client.HeadersRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	gen := s.gen.HeaderGenerator()
+	if req.Seed != 0 {
+		gen.SetRandomSource(forgeron.NewSeededSource(req.Seed))
+	}
+	headers, report, err := gen.GenerateHeadersWithReport(req.Constraints)
+	s.metrics.Record(report, err)
+	if err != nil {
+		writeError(w, generationStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, client.HeadersResponse{Headers: headers})
+}
+
+// decodeRequest decodes the JSON body of a request, reporting a bad request when it cannot
+func decodeRequest(w http.ResponseWriter, r *http.Request, out any) bool {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(out); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return false
+	}
+	return true
+}
+
+// generationStatus is the status of a failed generation, invalid constraints are the client's fault
+func generationStatus(err error) int {
+	var fieldErr *forgeron.FieldError
+	switch {
+	case errors.Is(err, forgeron.ErrPoolFull):
+		return http.StatusTooManyRequests
+	case errors.Is(err, forgeron.ErrPoolClosed):
+		return http.StatusServiceUnavailable
+	case errors.As(err, &fieldErr):
+		return http.StatusBadRequest
+	}
+	return http.StatusUnprocessableEntity
+}
+
+// writeError writes an error response
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, client.ErrorResponse{Error: err.Error()})
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,234 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ta0uf19/forgeron"
+	"github.com/ta0uf19/forgeron/client"
+)
+
+func newTestServer(t *testing.T, config Config) *httptest.Server {
+	t.Helper()
+	s, err := New(config)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// post sends a JSON request to the server, decoding the response into out
+func post(t *testing.T, url string, body, out any) int {
+	t.Helper()
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("POST %s error = %v", url, err)
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return resp.StatusCode
+}
+
+func TestServerServesClient(t *testing.T) {
+	server := newTestServer(t, Config{})
+	c, err := client.New(server.URL, client.WithBatchSize(2))
+	if err != nil {
+		t.Fatalf("client.New() error = %v", err)
+	}
+
+	fp, err := c.Generate(forgeron.WithHeaderConstraints(forgeron.HeaderConstraints{Browsers: []forgeron.Browser{"firefox"}}))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if fp.Navigator.UserAgent == "" || fp.Headers["User-Agent"] != fp.Navigator.UserAgent {
+		t.Errorf("Generate() = %+v, want a fingerprint with its headers", fp.Navigator)
+	}
+	headers, err := c.GenerateHeaders(forgeron.HeaderConstraints{Locales: []string{"fr-FR"}})
+	if err != nil {
+		t.Fatalf("GenerateHeaders() error = %v", err)
+	}
+	if headers["User-Agent"] == "" {
+		t.Errorf("GenerateHeaders() = %v, want a User-Agent", headers)
+	}
+}
+
+func TestServerSeedIsReproducible(t *testing.T) {
+	server := newTestServer(t, Config{})
+
+	var first, second client.FingerprintResponse
+	request := client.FingerprintRequest{Count: 2, Seed: 11}
+	post(t, server.URL+"/fingerprint", request, &first)
+	post(t, server.URL+"/fingerprint", request, &second)
+	if len(first.Fingerprints) != 2 || !reflect.DeepEqual(first, second) {
+		t.Error("/fingerprint generated different fingerprints for the same seed")
+	}
+
+	var firstHeaders, secondHeaders client.HeadersResponse
+	post(t, server.URL+"/headers", client.HeadersRequest{Seed: 11}, &firstHeaders)
+	post(t, server.URL+"/headers", client.HeadersRequest{Seed: 11}, &secondHeaders)
+	if len(firstHeaders.Headers) == 0 || !reflect.DeepEqual(firstHeaders, secondHeaders) {
+		t.Error("/headers generated different headers for the same seed")
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	server := newTestServer(t, Config{MaxCount: 5})
+	tests := []struct {
+		name string
+		path string
+		body any
+		want int
+	}{
+		{"count above the maximum", "/fingerprint", client.FingerprintRequest{Count: 6}, http.StatusBadRequest},
+		{"invalid constraints", "/headers", client.HeadersRequest{Constraints: forgeron.HeaderConstraints{OS: []forgeron.OS{"beos"}}}, http.StatusBadRequest},
+		{"invalid body", "/headers", []string{"constraints"}, http.StatusBadRequest},
+		{"unsatisfiable strict constraints", "/fingerprint", client.FingerprintRequest{Request: forgeron.GenerateRequest{
+			Constraints: forgeron.Constraints{Screen: &forgeron.Screen{MinWidth: ptr(100000)}},
+			Strict:      true,
+		}}, http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp client.ErrorResponse
+			if status := post(t, server.URL+tt.path, tt.body, &resp); status != tt.want || resp.Error == "" {
+				t.Errorf("status = %d, error = %q, want %d with an error", status, resp.Error, tt.want)
+			}
+		})
+	}
+
+	resp, err := http.Get(server.URL + "/fingerprint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET /fingerprint status = %d, want %d", resp.StatusCode, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestServerRateLimit(t *testing.T) {
+	server := newTestServer(t, Config{RateLimit: 0.001, Burst: 2})
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		resp, err := http.Get(server.URL + "/debug?format=json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("request %d status = %d, want %d", i, resp.StatusCode, want)
+		}
+		if want == http.StatusTooManyRequests && resp.Header.Get("Retry-After") == "" {
+			t.Error("rate limited response has no Retry-After header")
+		}
+	}
+}
+
+// blockingProvider generates with gen once released, signaling each generation it starts
+type blockingProvider struct {
+	gen     *forgeron.FingerprintGenerator
+	started chan struct{}
+	release chan struct{}
+}
+
+func (p blockingProvider) Generate(opts ...forgeron.FingerprintOption) (*forgeron.Fingerprint, error) {
+	p.started <- struct{}{}
+	<-p.release
+	return p.gen.Generate(opts...)
+}
+
+func TestServerPoolFull(t *testing.T) {
+	gen, err := forgeron.NewFingerprintGenerator()
+	if err != nil {
+		t.Fatalf("NewFingerprintGenerator() error = %v", err)
+	}
+	provider := blockingProvider{gen: gen, started: make(chan struct{}, 2), release: make(chan struct{})}
+	pool, err := forgeron.NewPool(func() (forgeron.FingerprintProvider, error) { return provider, nil }, forgeron.PoolConfig{Workers: 1, QueueSize: 1})
+	if err != nil {
+		t.Fatalf("NewPool() error = %v", err)
+	}
+	defer pool.Close()
+	server := newTestServer(t, Config{Generator: gen, Pool: pool})
+
+	// The first request takes the worker and the second one waits in the queue
+	send := func() (*http.Response, error) {
+		resp, err := http.Post(server.URL+"/fingerprint", "application/json", bytes.NewReader([]byte(`{}`)))
+		if err == nil {
+			resp.Body.Close()
+		}
+		return resp, err
+	}
+	statuses := make(chan int, 2)
+	queue := func() {
+		resp, err := send()
+		if err != nil {
+			statuses <- 0
+			return
+		}
+		statuses <- resp.StatusCode
+	}
+	go queue()
+	<-provider.started
+	go queue()
+	for pool.Pending() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	resp, err := send()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") == "" {
+		t.Errorf("status = %d, Retry-After = %q, want 429 with Retry-After", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+
+	close(provider.release)
+	for range 2 {
+		if status := <-statuses; status != http.StatusOK {
+			t.Errorf("queued request status = %d, want %d", status, http.StatusOK)
+		}
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	limiter := newRateLimiter(2, 1)
+	limiter.now = func() time.Time { return now }
+
+	if ok, _ := limiter.allow("a"); !ok {
+		t.Fatal("allow() = false for the first request")
+	}
+	if ok, wait := limiter.allow("a"); ok || wait != 500*time.Millisecond {
+		t.Errorf("allow() = %v, %v, want a 500ms wait", ok, wait)
+	}
+	if ok, _ := limiter.allow("b"); !ok {
+		t.Error("allow() = false for another client")
+	}
+	now = now.Add(500 * time.Millisecond)
+	if ok, _ := limiter.allow("a"); !ok {
+		t.Error("allow() = false after the bucket refilled")
+	}
+
+	// Refilled buckets are dropped
+	now = now.Add(time.Second)
+	limiter.allow("a")
+	if _, ok := limiter.buckets["b"]; ok {
+		t.Error("the refilled bucket of b was kept")
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,258 @@
+package forgeron
+
+import (
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+)
+
+// Session is a fingerprint bound to a key, e.g. an account or a proxy, so every request made
+// for the key presents the same identity
+type Session struct {
+	Key         string       `json:"key"`
+	Fingerprint *Fingerprint `json:"fingerprint"`
+	CreatedAt   time.Time    `json:"createdAt"`
+	// Uses is the number of times the session was handed out since its identity was generated
+	Uses int `json:"uses"`
+}
+
+// Headers returns a copy of the headers of the session identity
+func (s *Session) Headers() map[string]string {
+	return maps.Clone(s.Fingerprint.Headers)
+}
+
+// SessionStore persists sessions, so identities survive restarts or are shared between processes
+type SessionStore interface {
+	// Load returns the session stored for the key, or nil when there is none
+	Load(key string) (*Session, error)
+	Save(session *Session) error
+	Delete(key string) error
+}
+
+// SessionConfig configures a SessionManager
+type SessionConfig struct {
+	// TTL is how long a session keeps its identity before it is rotated, zero never expires sessions
+	TTL time.Duration
+	// MaxUses is the number of times a session is handed out before it is rotated, zero is unlimited
+	MaxUses int
+	// Store persists the sessions, nil keeps them in memory only
+	Store SessionStore
+}
+
+// Validate validates the session configuration
+func (c SessionConfig) Validate() error {
+	if c.TTL < 0 {
+		return fmt.Errorf("session TTL cannot be negative")
+	}
+	if c.MaxUses < 0 {
+		return fmt.Errorf("session max uses cannot be negative")
+	}
+	return nil
+}
+
+// SessionManager hands out sticky sessions, generating an identity the first time a key is seen
+// and rotating it according to the TTL and use policy. It is safe for concurrent use, keys are
+// generated, loaded and saved concurrently.
+type SessionManager struct {
+	generator FingerprintProvider
+	opts      []FingerprintOption
+	config    SessionConfig
+	now       func() time.Time
+	// mu guards the maps, sessions are replaced rather than modified so they can be read under mu only
+	mu       sync.Mutex
+	sessions map[string]*Session
+	locks    map[string]*keyLock
+}
+
+// keyLock serializes the operations on the session of a key
+type keyLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// NewSessionManager creates a session manager generating identities with the given generator and options
+func NewSessionManager(generator FingerprintProvider, config SessionConfig, opts ...FingerprintOption) (*SessionManager, error) {
+	if generator == nil {
+		return nil, fmt.Errorf("generator is required")
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid session config: %w", err)
+	}
+	return &SessionManager{
+		generator: generator,
+		opts:      opts,
+		config:    config,
+		now:       time.Now,
+		sessions:  make(map[string]*Session),
+		locks:     make(map[string]*keyLock),
+	}, nil
+}
+
+// Get returns the session of the key, loading it from the store or generating a new identity when missing,
+// and rotating it when expired or used up. The returned session is a snapshot.
+func (m *SessionManager) Get(key string) (*Session, error) {
+	unlock := m.lockKey(key)
+	defer unlock()
+
+	session, err := m.lookup(key)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || m.expired(session) {
+		if session, err = m.rotate(key); err != nil {
+			return nil, err
+		}
+	}
+	used := *session
+	used.Uses++
+	if m.config.MaxUses > 0 {
+		if err := m.save(&used); err != nil {
+			return nil, err
+		}
+	}
+	m.set(&used)
+	return used.snapshot(), nil
+}
+
+// Rotate replaces the identity of the key with a newly generated one, e.g. once it got blocked
+func (m *SessionManager) Rotate(key string) (*Session, error) {
+	unlock := m.lockKey(key)
+	defer unlock()
+
+	session, err := m.rotate(key)
+	if err != nil {
+		return nil, err
+	}
+	return session.snapshot(), nil
+}
+
+// Evict removes the session of the key, the next Get generates a new identity
+func (m *SessionManager) Evict(key string) error {
+	unlock := m.lockKey(key)
+	defer unlock()
+
+	m.mu.Lock()
+	delete(m.sessions, key)
+	m.mu.Unlock()
+	if m.config.Store != nil {
+		if err := m.config.Store.Delete(key); err != nil {
+			return fmt.Errorf("failed to delete session %q: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of sessions held in memory
+func (m *SessionManager) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.sessions)
+}
+
+// Prune removes the expired sessions from memory and returns how many were removed, stored sessions are kept
+func (m *SessionManager) Prune() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	pruned := 0
+	for key, session := range m.sessions {
+		if m.expired(session) {
+			delete(m.sessions, key)
+			pruned++
+		}
+	}
+	return pruned
+}
+
+// lookup returns the session of the key from memory or the store, or nil when there is none.
+// It must be called with the key locked.
+func (m *SessionManager) lookup(key string) (*Session, error) {
+	m.mu.Lock()
+	session, ok := m.sessions[key]
+	m.mu.Unlock()
+	if ok {
+		return session, nil
+	}
+	if m.config.Store == nil {
+		return nil, nil
+	}
+	session, err := m.config.Store.Load(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session %q: %w", key, err)
+	}
+	if session == nil || session.Fingerprint == nil {
+		return nil, nil
+	}
+	m.set(session)
+	return session, nil
+}
+
+// set replaces the session of its key in memory
+func (m *SessionManager) set(session *Session) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[session.Key] = session
+}
+
+// lockKey locks the sessions of the key without blocking other keys, and returns the function unlocking it
+func (m *SessionManager) lockKey(key string) func() {
+	m.mu.Lock()
+	lock, ok := m.locks[key]
+	if !ok {
+		lock = &keyLock{}
+		m.locks[key] = lock
+	}
+	lock.refs++
+	m.mu.Unlock()
+
+	lock.mu.Lock()
+	return func() {
+		lock.mu.Unlock()
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if lock.refs--; lock.refs == 0 {
+			delete(m.locks, key)
+		}
+	}
+}
+
+// expired reports whether the session must be rotated according to the TTL and use policy
+func (m *SessionManager) expired(session *Session) bool {
+	if m.config.TTL > 0 && !m.now().Before(session.CreatedAt.Add(m.config.TTL)) {
+		return true
+	}
+	return m.config.MaxUses > 0 && session.Uses >= m.config.MaxUses
+}
+
+// rotate generates a new identity for the key and saves it. It must be called with the key locked.
+func (m *SessionManager) rotate(key string) (*Session, error) {
+	fp, err := m.generator.Generate(m.opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate identity: %w", err)
+	}
+	session := &Session{Key: key, Fingerprint: fp, CreatedAt: m.now()}
+	if err := m.save(session); err != nil {
+		return nil, err
+	}
+	m.set(session)
+	return session, nil
+}
+
+// snapshot returns a copy of the session whose fingerprint can be modified without affecting the session
+func (s *Session) snapshot() *Session {
+	snapshot := *s
+	snapshot.Fingerprint = s.Fingerprint.Clone()
+	return &snapshot
+}
+
+// save saves a copy of the session to the store, if any
+func (m *SessionManager) save(session *Session) error {
+	if m.config.Store == nil {
+		return nil
+	}
+	snapshot := *session
+	if err := m.config.Store.Save(&snapshot); err != nil {
+		return fmt.Errorf("failed to save session %q: %w", session.Key, err)
+	}
+	return nil
+}
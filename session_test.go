@@ -0,0 +1,276 @@
+package forgeron
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memorySessionStore is a SessionStore keeping sessions in a map
+type memorySessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]Session
+	err      error
+}
+
+func newMemorySessionStore() *memorySessionStore {
+	return &memorySessionStore{sessions: make(map[string]Session)}
+}
+
+func (s *memorySessionStore) Load(key string) (*Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[key]
+	if !ok {
+		return nil, s.err
+	}
+	return &session, s.err
+}
+
+func (s *memorySessionStore) Save(session *Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil {
+		s.sessions[session.Key] = *session
+	}
+	return s.err
+}
+
+func (s *memorySessionStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, key)
+	return s.err
+}
+
+// sameIdentity reports whether two sessions hold the same generated identity, told apart by countingProvider
+func sameIdentity(a, b *Session) bool {
+	return a.Fingerprint.Navigator.HardwareConcurrency == b.Fingerprint.Navigator.HardwareConcurrency
+}
+
+func TestSessionManagerStickyIdentity(t *testing.T) {
+	provider := &countingProvider{}
+	m, err := NewSessionManager(provider, SessionConfig{})
+	if err != nil {
+		t.Fatalf("NewSessionManager() error = %v", err)
+	}
+
+	first, err := m.Get("account-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	again, err := m.Get("account-1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !sameIdentity(again, first) || again.Uses != 2 {
+		t.Errorf("Get() = %+v, want the same identity used twice", again)
+	}
+	other, err := m.Get("account-2")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if sameIdentity(other, first) {
+		t.Error("different keys share an identity")
+	}
+
+	rotated, err := m.Rotate("account-1")
+	if err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	if sameIdentity(rotated, first) || rotated.Uses != 0 {
+		t.Errorf("Rotate() = %+v, want a new unused identity", rotated)
+	}
+
+	if err := m.Evict("account-2"); err != nil {
+		t.Fatalf("Evict() error = %v", err)
+	}
+	if m.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", m.Len())
+	}
+	if provider.calls != 3 {
+		t.Errorf("generated %d identities, want 3", provider.calls)
+	}
+}
+
+func TestSessionManagerRotationPolicy(t *testing.T) {
+	provider := &countingProvider{}
+	m, err := NewSessionManager(provider, SessionConfig{TTL: time.Hour, MaxUses: 2})
+	if err != nil {
+		t.Fatalf("NewSessionManager() error = %v", err)
+	}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+
+	get := func() *Session {
+		t.Helper()
+		session, err := m.Get("proxy")
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		return session
+	}
+
+	first := get()
+	if !sameIdentity(get(), first) {
+		t.Fatal("identity rotated before reaching max uses")
+	}
+	used := get()
+	if sameIdentity(used, first) || used.Uses != 1 {
+		t.Errorf("Get() = %+v, want a rotated identity once max uses is reached", used)
+	}
+
+	now = now.Add(time.Hour)
+	if sameIdentity(get(), used) {
+		t.Error("identity not rotated once the TTL elapsed")
+	}
+
+	now = now.Add(2 * time.Hour)
+	if pruned := m.Prune(); pruned != 1 || m.Len() != 0 {
+		t.Errorf("Prune() = %d with %d sessions left, want 1 and 0", pruned, m.Len())
+	}
+}
+
+func TestSessionManagerStore(t *testing.T) {
+	store := newMemorySessionStore()
+	m, err := NewSessionManager(&countingProvider{}, SessionConfig{Store: store, MaxUses: 10})
+	if err != nil {
+		t.Fatalf("NewSessionManager() error = %v", err)
+	}
+	first, err := m.Get("account")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if saved := store.sessions["account"]; !reflect.DeepEqual(saved.Fingerprint, first.Fingerprint) || saved.Uses != 1 {
+		t.Errorf("saved session = %+v, want the handed out one", saved)
+	}
+
+	// A new manager sharing the store resumes the session
+	provider := &countingProvider{}
+	restarted, err := NewSessionManager(provider, SessionConfig{Store: store, MaxUses: 10})
+	if err != nil {
+		t.Fatalf("NewSessionManager() error = %v", err)
+	}
+	resumed, err := restarted.Get("account")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !sameIdentity(resumed, first) || resumed.Uses != 2 || provider.calls != 0 {
+		t.Errorf("Get() = %+v after %d generations, want the stored session", resumed, provider.calls)
+	}
+
+	if err := restarted.Evict("account"); err != nil {
+		t.Fatalf("Evict() error = %v", err)
+	}
+	if _, ok := store.sessions["account"]; ok {
+		t.Error("Evict() kept the stored session")
+	}
+
+	store.err = errors.New("store down")
+	if _, err := restarted.Get("account"); !errors.Is(err, store.err) {
+		t.Errorf("Get() error = %v, want the store error", err)
+	}
+}
+
+func TestSessionConfigValidate(t *testing.T) {
+	for _, config := range []SessionConfig{{TTL: -time.Second}, {MaxUses: -1}} {
+		if _, err := NewSessionManager(&countingProvider{}, config); err == nil {
+			t.Errorf("NewSessionManager(%+v) error = nil, want an error", config)
+		}
+	}
+	if _, err := NewSessionManager(nil, SessionConfig{}); err == nil {
+		t.Error("NewSessionManager(nil) error = nil, want an error")
+	}
+}
+
+func TestSessionHeaders(t *testing.T) {
+	m, err := NewSessionManager(newGeneratorOrFatal(t), SessionConfig{})
+	if err != nil {
+		t.Fatalf("NewSessionManager() error = %v", err)
+	}
+	session, err := m.Get("account")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	headers := session.Headers()
+	if headers["User-Agent"] == "" && headers["user-agent"] == "" {
+		t.Fatalf("Headers() = %v, want a user agent", headers)
+	}
+	headers["X-Test"] = "1"
+	if _, ok := session.Fingerprint.Headers["X-Test"]; ok {
+		t.Error("Headers() does not return a copy")
+	}
+}
+
+func TestSessionSnapshotsDoNotShareFingerprint(t *testing.T) {
+	m, err := NewSessionManager(&countingProvider{}, SessionConfig{})
+	if err != nil {
+		t.Fatalf("NewSessionManager() error = %v", err)
+	}
+	first, err := m.Get("account")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	first.Fingerprint.Navigator.UserAgent = "changed"
+	first.Fingerprint.Navigator.Languages[0] = "changed"
+	again, err := m.Get("account")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if again.Fingerprint.Navigator.UserAgent != "Mozilla/5.0" || again.Fingerprint.Navigator.Languages[0] != "en-US" {
+		t.Errorf("Get() = %+v, modified through a previous snapshot", again.Fingerprint.Navigator)
+	}
+}
+
+// blockingSessionStore blocks loading the slow key until unblocked
+type blockingSessionStore struct {
+	*memorySessionStore
+	loading chan struct{}
+	unblock chan struct{}
+}
+
+func (s *blockingSessionStore) Load(key string) (*Session, error) {
+	if key == "slow" {
+		close(s.loading)
+		<-s.unblock
+	}
+	return s.memorySessionStore.Load(key)
+}
+
+func TestSessionManagerLocksPerKey(t *testing.T) {
+	store := &blockingSessionStore{memorySessionStore: newMemorySessionStore(), loading: make(chan struct{}), unblock: make(chan struct{})}
+	m, err := NewSessionManager(&countingProvider{}, SessionConfig{Store: store})
+	if err != nil {
+		t.Fatalf("NewSessionManager() error = %v", err)
+	}
+	slow := make(chan error, 1)
+	go func() {
+		_, err := m.Get("slow")
+		slow <- err
+	}()
+	<-store.loading
+
+	// A slow store only blocks the key it is loading
+	done := make(chan error, 1)
+	go func() {
+		_, err := m.Get("fast")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Get() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("Get() of another key blocked on a slow store")
+	}
+	close(store.unblock)
+	if err := <-slow; err != nil {
+		t.Errorf("Get() error = %v", err)
+	}
+	if m.Len() != 2 || len(m.locks) != 0 {
+		t.Errorf("%d sessions and %d key locks left, want 2 and 0", m.Len(), len(m.locks))
+	}
+}
@@ -0,0 +1,53 @@
+package forgeron
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestWithSharedNetworks(t *testing.T) {
+	first := newGeneratorOrFatal(t, WithSharedNetworks(true), WithRandomSource(NewSeededSource(1)))
+	second := newGeneratorOrFatal(t, WithSharedNetworks(true), WithRandomSource(NewSeededSource(2)))
+	own := newGeneratorOrFatal(t)
+
+	for name, networks := range map[string][3]*bayesianNetwork{
+		"fingerprint": {first.network, second.network, own.network},
+		"header":      {first.headerGenerator.headerGeneratorNetwork, second.headerGenerator.headerGeneratorNetwork, own.headerGenerator.headerGeneratorNetwork},
+		"input":       {first.headerGenerator.inputGeneratorNetwork, second.headerGenerator.inputGeneratorNetwork, own.headerGenerator.inputGeneratorNetwork},
+	} {
+		shared, other, unshared := networks[0], networks[1], networks[2]
+		if reflect.ValueOf(shared.NodesByName).Pointer() != reflect.ValueOf(other.NodesByName).Pointer() {
+			t.Errorf("%s network nodes are not shared", name)
+		}
+		if reflect.ValueOf(shared.NodesByName).Pointer() == reflect.ValueOf(unshared.NodesByName).Pointer() {
+			t.Errorf("%s network nodes are shared without WithSharedNetworks", name)
+		}
+		if shared == other || shared.random == other.random {
+			t.Errorf("%s network random source is shared between generators", name)
+		}
+	}
+
+	// Generators sharing networks generate concurrently
+	var wg sync.WaitGroup
+	for _, gen := range []*FingerprintGenerator{first, second} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				if _, err := gen.Generate(); err != nil {
+					t.Errorf("Generate() error = %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestWithSharedNetworksIgnoresDataDir(t *testing.T) {
+	shared := newGeneratorOrFatal(t, WithSharedNetworks(true))
+	dir := newGeneratorOrFatal(t, WithSharedNetworks(true), WithDataDir(t.TempDir()))
+	if reflect.ValueOf(shared.network.NodesByName).Pointer() == reflect.ValueOf(dir.network.NodesByName).Pointer() {
+		t.Error("generator with a data directory uses the shared networks")
+	}
+}
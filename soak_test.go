@@ -0,0 +1,155 @@
+//go:build soak
+
+package forgeron
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// The soak test runs the generator the way long-running pool and server users do, and fails when the heap keeps
+// growing. Run it with: go test -tags soak -run TestSoak -timeout 0 -soak.identities 1000000 .
+var (
+	soakIdentities = flag.Int("soak.identities", 1_000_000, "number of identities generated by the soak test")
+	soakMaxGrowth  = flag.Int("soak.maxgrowth", 64, "heap growth in MiB tolerated after the warmup")
+)
+
+// soakConstraints are cycled through so every code path of the generator is exercised
+var soakConstraints = []HeaderConstraints{
+	{},
+	{Browsers: []Browser{Chrome}, OS: []OS{Windows}},
+	{Browsers: []Browser{Firefox}, OS: []OS{Linux}, Locales: []string{"de-DE", "de"}},
+	{Browsers: []Browser{Safari}, Devices: []Device{Mobile}},
+	{Browsers: []Browser{Edge}, HTTPVersion: HTTP1},
+	{OS: []OS{Android}, Locales: []string{"fr-FR"}},
+}
+
+// memorySample is the memory use of the process at a checkpoint
+type memorySample struct {
+	heapAlloc   uint64
+	heapObjects uint64
+	totalAlloc  uint64
+	mallocs     uint64
+	rss         uint64
+}
+
+// sampleMemory collects garbage and samples the memory use
+func sampleMemory() memorySample {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return memorySample{
+		heapAlloc:   stats.HeapAlloc,
+		heapObjects: stats.HeapObjects,
+		totalAlloc:  stats.TotalAlloc,
+		mallocs:     stats.Mallocs,
+		rss:         residentSetSize(),
+	}
+}
+
+// residentSetSize returns the resident set size of the process, or 0 where /proc is not available
+func residentSetSize() uint64 {
+	data, err := os.ReadFile("/proc/self/statm")
+	if err != nil {
+		return 0
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) < 2 {
+		return 0
+	}
+	pages, _ := strconv.ParseUint(fields[1], 10, 64)
+	return pages * uint64(os.Getpagesize())
+}
+
+func mib(bytes uint64) string {
+	return fmt.Sprintf("%.1fMiB", float64(bytes)/(1<<20))
+}
+
+func TestSoak(t *testing.T) {
+	pool, err := NewPool(func() (FingerprintProvider, error) {
+		return NewFingerprintGenerator()
+	}, PoolConfig{})
+	if err != nil {
+		t.Fatalf("NewPool() error = %v", err)
+	}
+	defer pool.Close()
+	sessions, err := NewSessionManager(pool, SessionConfig{MaxUses: 50})
+	if err != nil {
+		t.Fatalf("NewSessionManager() error = %v", err)
+	}
+
+	total := *soakIdentities
+	warmup := total / 20
+	checkpoints := 10
+	var generated, failed atomic.Int64
+	var baseline memorySample
+
+	work := func(i int) {
+		var err error
+		if i%4 == 0 {
+			// Sessions churn over a bounded key space, expired ones are pruned
+			_, err = sessions.Get(fmt.Sprintf("account-%d", i%10_000))
+		} else {
+			_, err = pool.Get(context.Background(), WithHeaderConstraints(soakConstraints[i%len(soakConstraints)]))
+		}
+		if err != nil {
+			failed.Add(1)
+		}
+		generated.Add(1)
+	}
+
+	workers := runtime.GOMAXPROCS(0)
+	run := func(from, to int) {
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := from + w; i < to; i += workers {
+					work(i)
+				}
+			}(w)
+		}
+		wg.Wait()
+	}
+
+	run(0, warmup)
+	sessions.Prune()
+	baseline = sampleMemory()
+	t.Logf("warmup: %d identities, heap %s, %d objects, rss %s", warmup, mib(baseline.heapAlloc), baseline.heapObjects, mib(baseline.rss))
+
+	step := (total - warmup) / checkpoints
+	last := baseline
+	for c := 1; c <= checkpoints; c++ {
+		from := warmup + (c-1)*step
+		to := from + step
+		if c == checkpoints {
+			to = total
+		}
+		run(from, to)
+		sessions.Prune()
+		sample := sampleMemory()
+		perIdentity := float64(sample.mallocs-last.mallocs) / float64(to-from)
+		t.Logf("%d identities: heap %s, %d objects, rss %s, %.0f allocs and %s allocated per identity, %d sessions",
+			to, mib(sample.heapAlloc), sample.heapObjects, mib(sample.rss), perIdentity,
+			mib((sample.totalAlloc-last.totalAlloc)/uint64(to-from)), sessions.Len())
+		last = sample
+	}
+
+	if failed.Load() > 0 {
+		t.Errorf("%d of %d generations failed", failed.Load(), generated.Load())
+	}
+	maxGrowth := uint64(*soakMaxGrowth) << 20
+	if last.heapAlloc > baseline.heapAlloc+maxGrowth {
+		t.Errorf("heap grew from %s to %s over %d identities, more than the %s tolerated",
+			mib(baseline.heapAlloc), mib(last.heapAlloc), total-warmup, mib(maxGrowth))
+	}
+}
@@ -0,0 +1,89 @@
+package forgeron
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// BatchStats summarizes the composition of a set of identities
+type BatchStats struct {
+	Total    int
+	Browsers map[string]int
+	OS       map[string]int
+	Devices  map[string]int
+	// Versions counts major versions per browser
+	Versions map[string]map[int]int
+}
+
+// Summarize returns the browser, OS, device and version spread of the fingerprints.
+// Values that cannot be recognized from the user agent are counted as "other".
+func Summarize(fingerprints []*Fingerprint) BatchStats {
+	stats := BatchStats{
+		Browsers: make(map[string]int),
+		OS:       make(map[string]int),
+		Devices:  make(map[string]int),
+		Versions: make(map[string]map[int]int),
+	}
+	for _, fp := range fingerprints {
+		if fp == nil {
+			continue
+		}
+		info := parseUserAgent(fp.Navigator.UserAgent)
+		browser := orOther(string(info.Browser))
+		stats.Total++
+		stats.Browsers[browser]++
+		stats.OS[orOther(string(info.OS))]++
+		stats.Devices[orOther(string(info.Device))]++
+		if stats.Versions[browser] == nil {
+			stats.Versions[browser] = make(map[int]int)
+		}
+		stats.Versions[browser][info.Version]++
+	}
+	return stats
+}
+
+// Share returns the fraction of identities counted in a histogram bucket
+func (s BatchStats) Share(count int) float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(count) / float64(s.Total)
+}
+
+// String renders the stats as a compact histogram, e.g. "browsers: chrome=70% firefox=30%"
+func (s BatchStats) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "total: %d\n", s.Total)
+	for _, dimension := range []struct {
+		name   string
+		counts map[string]int
+	}{{"browsers", s.Browsers}, {"os", s.OS}, {"devices", s.Devices}} {
+		b.WriteString(dimension.name + ":")
+		for _, key := range slices.Sorted(maps.Keys(dimension.counts)) {
+			fmt.Fprintf(&b, " %s=%.0f%%", key, 100*s.Share(dimension.counts[key]))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// orOther returns value, or "other" when it is empty
+func orOther(value string) string {
+	if value == "" {
+		return "other"
+	}
+	return value
+}
+
+// Stats returns the composition of the identities generated by the scheduler
+func (s *Scheduler) Stats() BatchStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fingerprints := make([]*Fingerprint, len(s.identities))
+	for i, identity := range s.identities {
+		fingerprints[i] = identity.fingerprint
+	}
+	return Summarize(fingerprints)
+}
@@ -0,0 +1,61 @@
+package forgeron
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseUserAgent(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		want      userAgentInfo
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36", userAgentInfo{"chrome", "windows", "desktop", 144}},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36 Edg/144.0.0.0", userAgentInfo{"edge", "windows", "desktop", 144}},
+		{"Mozilla/5.0 (X11; Linux x86_64; rv:147.0) Gecko/20100101 Firefox/147.0", userAgentInfo{"firefox", "linux", "desktop", 147}},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/26.2 Safari/605.1.15", userAgentInfo{"safari", "macos", "desktop", 26}},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 18_7 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/26.2 Mobile/15E148 Safari/604.1", userAgentInfo{"safari", "ios", "mobile", 26}},
+		{"Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Mobile Safari/537.36", userAgentInfo{"chrome", "android", "mobile", 144}},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/26.2 Mobile/15E148 Safari/604.1", userAgentInfo{"safari", "ios", "tablet", 26}},
+		{"Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36", userAgentInfo{"chrome", "android", "tablet", 144}},
+		{"Mozilla/5.0 (X11; CrOS x86_64 14541.0.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/143.0.0.0 Safari/537.36", userAgentInfo{"chrome", "chromeos", "desktop", 143}},
+		{"curl/8.5.0", userAgentInfo{}},
+	}
+	for _, tt := range tests {
+		if got := parseUserAgent(tt.userAgent); got != tt.want {
+			t.Errorf("parseUserAgent(%q) = %+v, want %+v", tt.userAgent, got, tt.want)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	fingerprint := func(userAgent string) *Fingerprint {
+		return &Fingerprint{Navigator: NavigatorFingerprint{UserAgent: userAgent}}
+	}
+	stats := Summarize([]*Fingerprint{
+		fingerprint("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36"),
+		fingerprint("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/143.0.0.0 Safari/537.36"),
+		fingerprint("Mozilla/5.0 (X11; Linux x86_64; rv:147.0) Gecko/20100101 Firefox/147.0"),
+		fingerprint("curl/8.5.0"),
+		nil,
+	})
+
+	if stats.Total != 4 {
+		t.Errorf("Total = %d, want 4", stats.Total)
+	}
+	if stats.Browsers["chrome"] != 2 || stats.Browsers["firefox"] != 1 || stats.Browsers["other"] != 1 {
+		t.Errorf("unexpected browsers: %v", stats.Browsers)
+	}
+	if stats.OS["windows"] != 2 || stats.Devices["desktop"] != 3 {
+		t.Errorf("unexpected OS/devices: %v %v", stats.OS, stats.Devices)
+	}
+	if stats.Versions["chrome"][144] != 1 || stats.Versions["chrome"][143] != 1 {
+		t.Errorf("unexpected chrome versions: %v", stats.Versions["chrome"])
+	}
+	if share := stats.Share(stats.Browsers["chrome"]); share != 0.5 {
+		t.Errorf("Share() = %v, want 0.5", share)
+	}
+	if !strings.Contains(stats.String(), "browsers: chrome=50% firefox=25% other=25%") {
+		t.Errorf("unexpected String():\n%s", stats)
+	}
+}
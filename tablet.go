@@ -0,0 +1,63 @@
+package forgeron
+
+import (
+	"slices"
+	"strings"
+)
+
+// minTabletScreenSide is the smallest CSS pixel size of the short screen side of a tablet
+const minTabletScreenSide = 600
+
+// isTabletUserAgent returns true for iPad and Android tablet user agents
+func isTabletUserAgent(userAgent string) bool {
+	// iPadOS Safari requests desktop sites with a Mac user agent, still carrying the Mobile build token
+	if strings.Contains(userAgent, "iPad") || strings.Contains(userAgent, "Macintosh") && strings.Contains(userAgent, "Mobile/") {
+		return true
+	}
+	// Android tablets omit the Mobile token
+	return strings.Contains(userAgent, "Android") && !strings.Contains(userAgent, "Mobile")
+}
+
+// tabletsOnly returns true if tablets are requested without phones
+func tabletsOnly(devices []Device) bool {
+	return slices.Contains(devices, Tablet) && !slices.Contains(devices, Mobile)
+}
+
+// networkDeviceValues maps public device names to the values recorded in the input network.
+// Tablets are recorded as mobile devices.
+func networkDeviceValues(devices []Device) []string {
+	mapped := make([]string, 0, len(devices))
+	for _, device := range devices {
+		if device == Tablet {
+			device = Mobile
+		}
+		if !slices.Contains(mapped, string(device)) {
+			mapped = append(mapped, string(device))
+		}
+	}
+	return mapped
+}
+
+// tabletScreen returns true if the screen is large enough for a tablet
+func tabletScreen(screen ScreenFingerprint) bool {
+	return min(screen.Width, screen.Height) >= minTabletScreenSide
+}
+
+// applyTabletConsistency aligns touch support and client hints of tablet fingerprints with the user agent
+func applyTabletConsistency(fp *Fingerprint) {
+	if !isTabletUserAgent(fp.Navigator.UserAgent) {
+		return
+	}
+	if fp.Navigator.MaxTouchPoints == 0 {
+		fp.Navigator.MaxTouchPoints = 5
+	}
+	// Tablets are not reported as mobile through client hints
+	if fp.Navigator.UserAgentData != nil {
+		fp.Navigator.UserAgentData.Mobile = false
+	}
+	for k := range fp.Headers {
+		if strings.EqualFold(k, "sec-ch-ua-mobile") {
+			fp.Headers[k] = "?0"
+		}
+	}
+}
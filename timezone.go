@@ -0,0 +1,189 @@
+package forgeron
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// timezoneTransitionYears is the number of years before and after the reference time whose transitions are listed
+const timezoneTransitionYears = 2
+
+//go:embed region_timezones.json
+var regionTimezonesJSON []byte
+
+// regionTimezone is a time zone of a region with the share of the population living in it
+type regionTimezone struct {
+	Zone   string  `json:"zone"`
+	Weight float64 `json:"weight"`
+}
+
+// regionTimezones maps regions to their time zones, e.g. US gives America/New_York, America/Chicago, ...
+var regionTimezones = sync.OnceValue(func() map[string][]regionTimezone {
+	var zones map[string][]regionTimezone
+	if err := json.Unmarshal(regionTimezonesJSON, &zones); err != nil {
+		panic("forgeron: invalid region_timezones.json: " + err.Error())
+	}
+	return zones
+})
+
+// Timezone is the time zone of an identity, with the values injectors need to override Date.getTimezoneOffset and
+// Intl consistently with the claimed location. Offsets follow getTimezoneOffset: minutes to add to the local time to
+// get UTC, e.g. -60 for UTC+1.
+type Timezone struct {
+	// Name is the IANA name, e.g. "Europe/Paris"
+	Name string `json:"name"`
+	// Offset is the offset at the reference time
+	Offset int `json:"offset"`
+	// StandardOffset is the offset outside of daylight saving time
+	StandardOffset int `json:"standardOffset"`
+	// DST reports whether the zone observes daylight saving time around the reference time
+	DST bool `json:"dst"`
+	// Transitions are the offsets from two years before to two years after the reference time, in order. The first
+	// one is the offset in effect two years before, from the time it took effect or 0 when it always applied, the
+	// others are the changes of offset.
+	Transitions []TimezoneTransition `json:"transitions"`
+}
+
+// TimezoneTransition is an offset of a time zone from a given time on
+type TimezoneTransition struct {
+	// At is the time in milliseconds since the Unix epoch, as Date.getTime() returns it
+	At int64 `json:"at"`
+	// Offset is the offset from that time on
+	Offset int `json:"offset"`
+	// DST reports whether daylight saving time is in effect from that time on
+	DST bool `json:"dst"`
+}
+
+// LoadTimezone returns the time zone of an IANA name around the reference time, e.g. the generation time. It reads
+// the time zone database of the system, programs running where there is none should import time/tzdata.
+func LoadTimezone(name string, at time.Time) (*Timezone, error) {
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("invalid timezone %q: an IANA name is required", name)
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", name, err)
+	}
+	at = at.In(location)
+	from := at.AddDate(-timezoneTransitionYears, 0, 0)
+	to := at.AddDate(timezoneTransitionYears, 0, 0)
+
+	tz := &Timezone{Name: name, Offset: jsOffset(at), StandardOffset: jsOffset(at)}
+	first := TimezoneTransition{Offset: jsOffset(from), DST: from.IsDST()}
+	if start, _ := from.ZoneBounds(); !start.IsZero() {
+		first.At = start.UnixMilli()
+	}
+	tz.Transitions = []TimezoneTransition{first}
+	for t := from; ; {
+		_, end := t.ZoneBounds()
+		if end.IsZero() || !end.Before(to) {
+			break
+		}
+		tz.Transitions = append(tz.Transitions, transitionAt(end))
+		t = end
+	}
+	for _, transition := range tz.Transitions {
+		if !transition.DST {
+			continue
+		}
+		tz.DST = true
+		// The standard offset is the one daylight saving time ends with
+		if at.IsDST() {
+			tz.StandardOffset = tz.standardOffsetAround(at)
+		}
+		break
+	}
+	return tz, nil
+}
+
+// standardOffsetAround returns the offset outside of daylight saving time closest before at, or after it when the
+// period starts in daylight saving time
+func (tz *Timezone) standardOffsetAround(at time.Time) int {
+	offset, found := tz.Offset, false
+	for _, transition := range tz.Transitions {
+		if transition.DST {
+			continue
+		}
+		if found && transition.At > at.UnixMilli() {
+			break
+		}
+		offset, found = transition.Offset, true
+	}
+	return offset
+}
+
+// OffsetAt returns the offset of the time zone at t, as Date.getTimezoneOffset() returns it. Times outside of the
+// listed period get the offset of its closest end.
+func (tz *Timezone) OffsetAt(t time.Time) int {
+	if len(tz.Transitions) == 0 {
+		return tz.Offset
+	}
+	offset := tz.Transitions[0].Offset
+	for _, transition := range tz.Transitions[1:] {
+		if t.UnixMilli() < transition.At {
+			break
+		}
+		offset = transition.Offset
+	}
+	return offset
+}
+
+// transitionAt returns the offset in effect at t
+func transitionAt(t time.Time) TimezoneTransition {
+	return TimezoneTransition{At: t.UnixMilli(), Offset: jsOffset(t), DST: t.IsDST()}
+}
+
+// jsOffset returns the offset of t as Date.getTimezoneOffset() returns it
+func jsOffset(t time.Time) int {
+	_, seconds := t.Zone()
+	return -seconds / 60
+}
+
+// timezoneRegion returns the region the time zone of an identity is sampled in: the requested region, such as the
+// country of a proxy exit, or else the region of the locale of the browser, e.g. fr gives FR
+func timezoneRegion(region string, locales []string) string {
+	if region != "" {
+		reg, err := language.ParseRegion(region)
+		if err != nil {
+			return ""
+		}
+		return reg.String()
+	}
+	if len(locales) == 0 {
+		return ""
+	}
+	reg, _ := language.Make(locales[0]).Region()
+	return reg.String()
+}
+
+// sampleTimezoneName picks a time zone of region weighted by population, "" when the region is not in the dataset
+func sampleTimezoneName(region string, src RandomSource) string {
+	zones := regionTimezones()[region]
+	if len(zones) == 0 {
+		return ""
+	}
+	return pickWeighted(zones, func(zone regionTimezone) float64 { return zone.Weight }, src).Zone
+}
+
+// setTimezone sets the time zone of a fingerprint, leaving it unset when name is empty
+func (g *FingerprintGenerator) setTimezone(fp *Fingerprint, name string) error {
+	if name == "" {
+		return nil
+	}
+	tz, err := LoadTimezone(name, time.Now())
+	if err != nil {
+		if g.strict {
+			return newConstraintError("timezone", "time zone missing from the time zone database", fmt.Errorf("failed to load the time zone: %w", err))
+		}
+		logDebug(g.logger, "relaxing fingerprint constraints", "reason", "time zone missing from the time zone database", "dropped", []string{"timezone"})
+		g.relaxationReport.add(Relaxation{Constraint: "timezone", Requested: name, Reason: "time zone missing from the time zone database"})
+		return nil
+	}
+	fp.Timezone = tz
+	return nil
+}
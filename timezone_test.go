@@ -0,0 +1,153 @@
+package forgeron
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestLoadTimezone(t *testing.T) {
+	summer := time.Date(2026, time.July, 1, 12, 0, 0, 0, time.UTC)
+	winter := time.Date(2026, time.January, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		at             time.Time
+		offset         int
+		standardOffset int
+		dst            bool
+	}{
+		{"Europe/Paris", summer, -120, -60, true},
+		{"Europe/Paris", winter, -60, -60, true},
+		{"America/New_York", summer, 240, 300, true},
+		{"Australia/Sydney", winter, -660, -600, true},
+		{"Asia/Tokyo", summer, -540, -540, false},
+		{"Asia/Kolkata", summer, -330, -330, false},
+		{"UTC", summer, 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tz, err := LoadTimezone(tt.name, tt.at)
+			if err != nil {
+				t.Fatalf("LoadTimezone() error = %v", err)
+			}
+			if tz.Offset != tt.offset || tz.StandardOffset != tt.standardOffset || tz.DST != tt.dst {
+				t.Errorf("LoadTimezone() = offset %d, standard %d, dst %v, want %d, %d, %v",
+					tz.Offset, tz.StandardOffset, tz.DST, tt.offset, tt.standardOffset, tt.dst)
+			}
+			if len(tz.Transitions) == 0 {
+				t.Fatal("no transitions")
+			}
+			// Two changes a year over four years for zones with daylight saving time
+			if tt.dst && len(tz.Transitions) != 9 {
+				t.Errorf("got %d transitions, want 9", len(tz.Transitions))
+			}
+			if got := tz.OffsetAt(tt.at); got != tt.offset {
+				t.Errorf("OffsetAt() = %d, want %d", got, tt.offset)
+			}
+		})
+	}
+}
+
+func TestTimezoneOffsetAt(t *testing.T) {
+	tz, err := LoadTimezone("Europe/Paris", time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("LoadTimezone() error = %v", err)
+	}
+	tests := []struct {
+		at   time.Time
+		want int
+	}{
+		// Summer time starts on the last Sunday of March at 01:00 UTC
+		{time.Date(2026, time.March, 29, 0, 59, 0, 0, time.UTC), -60},
+		{time.Date(2026, time.March, 29, 1, 0, 0, 0, time.UTC), -120},
+		{time.Date(2025, time.December, 25, 0, 0, 0, 0, time.UTC), -60},
+		{time.Date(2027, time.August, 1, 0, 0, 0, 0, time.UTC), -120},
+		// Outside of the listed period the offset of the closest end is used
+		{time.Date(2000, time.July, 1, 0, 0, 0, 0, time.UTC), -120},
+	}
+	for _, tt := range tests {
+		if got := tz.OffsetAt(tt.at); got != tt.want {
+			t.Errorf("OffsetAt(%v) = %d, want %d", tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestLoadTimezoneInvalid(t *testing.T) {
+	for _, name := range []string{"", "Local", "Mars/Olympus_Mons"} {
+		if _, err := LoadTimezone(name, time.Now()); err == nil {
+			t.Errorf("LoadTimezone(%q) error = nil", name)
+		}
+	}
+}
+
+func TestRegionTimezones(t *testing.T) {
+	for region, zones := range regionTimezones() {
+		if _, ok := regionLocales()[region]; !ok {
+			t.Errorf("region %s has time zones but no locales", region)
+		}
+		total := 0.0
+		for _, zone := range zones {
+			if _, err := time.LoadLocation(zone.Zone); err != nil {
+				t.Errorf("%s: %v", region, err)
+			}
+			total += zone.Weight
+		}
+		if total < 0.999 || total > 1.001 {
+			t.Errorf("%s: weights sum to %v, want 1", region, total)
+		}
+	}
+}
+
+func TestTimezoneRegion(t *testing.T) {
+	tests := []struct {
+		region  string
+		locales []string
+		want    string
+	}{
+		{"jp", []string{"en-US", "en"}, "JP"},
+		{"", []string{"fr-CA", "fr"}, "CA"},
+		{"", []string{"de"}, "DE"},
+		{"", []string{"zh-Hant-TW"}, "TW"},
+		{"", nil, ""},
+	}
+	for _, tt := range tests {
+		if got := timezoneRegion(tt.region, tt.locales); got != tt.want {
+			t.Errorf("timezoneRegion(%q, %v) = %q, want %q", tt.region, tt.locales, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateTimezone(t *testing.T) {
+	gen := newGeneratorOrFatal(t)
+	tests := []struct {
+		name        string
+		constraints HeaderConstraints
+		want        []string
+	}{
+		{"default locale", HeaderConstraints{}, []string{"America/New_York", "America/Chicago", "America/Los_Angeles", "America/Denver", "America/Phoenix"}},
+		{"locale", HeaderConstraints{Locales: []string{"fr-FR", "fr"}}, []string{"Europe/Paris"}},
+		{"region", HeaderConstraints{Region: "JP"}, []string{"Asia/Tokyo"}},
+		{"region over locale", HeaderConstraints{Locales: []string{"en-US"}, Region: "GB"}, []string{"Europe/London"}},
+		{"region not in the dataset", HeaderConstraints{Region: "KE"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fp, err := gen.Generate(WithHeaderConstraints(tt.constraints))
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if tt.want == nil {
+				if fp.Timezone != nil {
+					t.Errorf("Timezone = %q, want none", fp.Timezone.Name)
+				}
+				return
+			}
+			if fp.Timezone == nil || !slices.Contains(tt.want, fp.Timezone.Name) {
+				t.Fatalf("Timezone = %+v, want one of %v", fp.Timezone, tt.want)
+			}
+			if got := fp.Timezone.OffsetAt(time.Now()); got != fp.Timezone.Offset {
+				t.Errorf("OffsetAt(now) = %d, want %d", got, fp.Timezone.Offset)
+			}
+		})
+	}
+}
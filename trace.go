@@ -0,0 +1,133 @@
+package forgeron
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SamplingStep is a value drawn for a node of a network
+type SamplingStep struct {
+	// Network is the network of the node: "input" and "header" for the headers, "fingerprint" for the fingerprint
+	Network string `json:"network"`
+	// Node is the name of the node
+	Node string `json:"node"`
+	// Value is the value sampled
+	Value string `json:"value"`
+	// Probability is the probability of the value given the values of the parents of the node
+	Probability float64 `json:"probability"`
+	// Draw is the number drawn from the random source to pick the value
+	Draw float64 `json:"draw"`
+	// Rejected reports the value was given up because the nodes after it could not meet the constraints
+	Rejected bool `json:"rejected,omitempty"`
+}
+
+// SamplingTrace lists the values drawn by a generation in sampling order. Combined with a seeded source it tells
+// why a generation produced an identity, e.g. which unlikely value was drawn for which node.
+type SamplingTrace struct {
+	Steps []SamplingStep `json:"steps"`
+}
+
+// reset empties the trace, doing nothing on a nil trace
+func (t *SamplingTrace) reset() {
+	if t != nil {
+		t.Steps = nil
+	}
+}
+
+// String renders the trace one step per line, e.g. "header user-agent=Mozilla/5.0 ... p=0.0213 draw=0.4410"
+func (t *SamplingTrace) String() string {
+	if t == nil || len(t.Steps) == 0 {
+		return "no value sampled"
+	}
+	var b strings.Builder
+	for _, step := range t.Steps {
+		value := step.Value
+		if len(value) > maxTracedValueLength {
+			value = value[:maxTracedValueLength] + "..."
+		}
+		fmt.Fprintf(&b, "%s %s=%s p=%.4f draw=%.4f", step.Network, step.Node, value, step.Probability, step.Draw)
+		if step.Rejected {
+			b.WriteString(" (rejected)")
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// networkTracer records the values sampled by a network in a sampling trace
+type networkTracer struct {
+	trace   *SamplingTrace
+	network string
+}
+
+// source returns the source to sample from, which remembers its draws when a trace is recorded
+func (t networkTracer) source(src RandomSource) RandomSource {
+	if t.trace == nil {
+		return src
+	}
+	return &tracedSource{src: src}
+}
+
+// record adds the value sampled for a node to the trace and returns its index, -1 when no trace is recorded
+func (t networkTracer) record(n *node, value string, parentValues map[string]string, src RandomSource) int {
+	traced, ok := src.(*tracedSource)
+	if t.trace == nil || !ok {
+		return -1
+	}
+	probability, _ := n.lookup(parentValues).probability(n, value)
+	t.trace.Steps = append(t.trace.Steps, SamplingStep{
+		Network:     t.network,
+		Node:        n.Name,
+		Value:       value,
+		Probability: probability,
+		Draw:        traced.last,
+	})
+	return len(t.trace.Steps) - 1
+}
+
+// reject marks a recorded value as given up by the constrained sampler
+func (t networkTracer) reject(index int) {
+	if t.trace != nil && index >= 0 {
+		t.trace.Steps[index].Rejected = true
+	}
+}
+
+// tracedSource remembers the last number drawn from a source
+type tracedSource struct {
+	src  RandomSource
+	last float64
+}
+
+// Float64 implements RandomSource
+func (s *tracedSource) Float64() float64 {
+	s.last = randomFloat(s.src)
+	return s.last
+}
+
+// setSamplingTrace records the values sampled for the headers in trace, nil stops recording
+func (g *HeaderGenerator) setSamplingTrace(trace *SamplingTrace) {
+	if g.inputGeneratorNetwork != nil {
+		g.inputGeneratorNetwork.tracer = networkTracer{trace: trace, network: "input"}
+	}
+	if g.headerGeneratorNetwork != nil {
+		g.headerGeneratorNetwork.tracer = networkTracer{trace: trace, network: "header"}
+	}
+}
+
+// WithSamplingTrace fills trace with the values sampled by a generation, along with their probability and the
+// number drawn to pick them. Like a relaxation report, it is only accepted by Generate, NewFingerprintGenerator
+// refuses it as concurrent generations would share the trace:
+//
+//	var trace forgeron.SamplingTrace
+//	fp, err := gen.Generate(forgeron.WithRandomSource(forgeron.NewSeededSource(42)), forgeron.WithSamplingTrace(&trace))
+func WithSamplingTrace(trace *SamplingTrace) FingerprintOption {
+	return func(g *FingerprintGenerator) {
+		g.samplingTrace = trace
+		if g.headerGenerator != nil {
+			g.headerGenerator.setSamplingTrace(trace)
+		}
+		if g.network != nil {
+			g.network.tracer = networkTracer{trace: trace, network: "fingerprint"}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package forgeron
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGenerateSamplingTrace(t *testing.T) {
+	gen := newGeneratorOrFatal(t)
+
+	var trace SamplingTrace
+	fp, err := gen.Generate(WithRandomSource(NewSeededSource(7)), WithSamplingTrace(&trace))
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(trace.Steps) == 0 {
+		t.Fatal("trace has no steps")
+	}
+
+	networks := make(map[string]bool)
+	for _, step := range trace.Steps {
+		networks[step.Network] = true
+		if step.Draw < 0 || step.Draw >= 1 {
+			t.Errorf("step %+v draw out of [0, 1)", step)
+		}
+		if !step.Rejected && (step.Probability <= 0 || step.Probability > 1) {
+			t.Errorf("step %+v has probability out of (0, 1]", step)
+		}
+		if step.Network == "fingerprint" && step.Node == "userAgent" && !step.Rejected && step.Value != fp.Navigator.UserAgent {
+			t.Errorf("traced user agent %q, want %q", step.Value, fp.Navigator.UserAgent)
+		}
+	}
+	for _, network := range []string{"input", "header", "fingerprint"} {
+		if !networks[network] {
+			t.Errorf("trace has no step of the %s network", network)
+		}
+	}
+	if !strings.Contains(trace.String(), "fingerprint userAgent=") {
+		t.Errorf("String() = %q", trace.String())
+	}
+
+	// The same seed samples the same values
+	var again SamplingTrace
+	if _, err := gen.Generate(WithRandomSource(NewSeededSource(7)), WithSamplingTrace(&again)); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if !reflect.DeepEqual(trace, again) {
+		t.Error("traces of the same seed differ")
+	}
+}
+
+func TestSamplingTraceRejectedValues(t *testing.T) {
+	network := createTestNetwork()
+	// B is always b2 when A is a2, so B=b1 makes the sampler give up a2
+	nodeB := network.NodesByName["B"]
+	nodeB.ConditionalProbs = map[string]interface{}{
+		"deeper": map[string]interface{}{
+			"a1": map[string]interface{}{"b1": 0.7, "b2": 0.3},
+			"a2": map[string]interface{}{"b2": 1.0},
+		},
+	}
+	if err := nodeB.compile(); err != nil {
+		t.Fatalf("compile() error = %v", err)
+	}
+	var trace SamplingTrace
+	network.random = NewReplaySource([]float64{0.9, 0.0, 0.0})
+	network.tracer = networkTracer{trace: &trace, network: "test"}
+
+	if _, ok := network.generateConsistentSampleWhenPossible(map[string][]string{"B": {"b1"}}); !ok {
+		t.Fatal("generateConsistentSampleWhenPossible() failed")
+	}
+	want := []SamplingStep{
+		{Network: "test", Node: "A", Value: "a2", Probability: 0.4, Draw: 0.9, Rejected: true},
+		{Network: "test", Node: "A", Value: "a1", Probability: 0.6, Draw: 0.0},
+		{Network: "test", Node: "B", Value: "b1", Probability: 0.7, Draw: 0.0},
+	}
+	if !reflect.DeepEqual(trace.Steps, want) {
+		t.Errorf("Steps = %+v, want %+v", trace.Steps, want)
+	}
+	if !strings.Contains(trace.String(), "test A=a2 p=0.4000 draw=0.9000 (rejected)") {
+		t.Errorf("String() = %q", trace.String())
+	}
+}
+
+func TestSamplingTraceNotShared(t *testing.T) {
+	// Concurrent generations would share a trace given to the generator
+	var trace SamplingTrace
+	if _, err := NewFingerprintGenerator(WithSamplingTrace(&trace)); err == nil {
+		t.Error("NewFingerprintGenerator() error = nil with a sampling trace")
+	}
+
+	// Batches do not fill a trace either
+	gen := newGeneratorOrFatal(t)
+	if _, err := gen.GenerateBatch(4, WithSamplingTrace(&trace)); err != nil {
+		t.Fatalf("GenerateBatch() error = %v", err)
+	}
+	if len(trace.Steps) != 0 {
+		t.Errorf("trace has %d steps, want none", len(trace.Steps))
+	}
+
+	var nilTrace *SamplingTrace
+	if got := nilTrace.String(); got != "no value sampled" {
+		t.Errorf("String() = %q", got)
+	}
+}
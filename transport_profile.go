@@ -0,0 +1,288 @@
+package forgeron
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// TLSFingerprint describes the TLS ClientHello of the browser of a fingerprint, with IANA code points as in JA3
+type TLSFingerprint struct {
+	// ClientHelloID is the matching uTLS ClientHelloID, e.g. HelloChrome_Auto
+	ClientHelloID string `json:"clientHelloId"`
+	// Versions are the supported_versions offered, highest first
+	Versions     []uint16 `json:"versions"`
+	CipherSuites []uint16 `json:"cipherSuites"`
+	// Extensions are the extension types in the order the browser sends them, before any permutation
+	Extensions          []uint16 `json:"extensions"`
+	SupportedGroups     []uint16 `json:"supportedGroups"`
+	PointFormats        []uint8  `json:"pointFormats"`
+	SignatureAlgorithms []uint16 `json:"signatureAlgorithms"`
+	// CertCompression are the certificate compression algorithms (RFC 8879)
+	CertCompression []uint16 `json:"certCompression"`
+	ALPN            []string `json:"alpn"`
+	// GREASE is set when the browser sends GREASE values (RFC 8701)
+	GREASE bool `json:"grease"`
+	// PermuteExtensions is set when the browser shuffles its extensions on every connection
+	PermuteExtensions bool `json:"permuteExtensions"`
+}
+
+// HTTP2Setting is an HTTP/2 SETTINGS parameter
+type HTTP2Setting struct {
+	ID    uint16 `json:"id"`
+	Value uint32 `json:"value"`
+}
+
+// HTTP2Priority is an HTTP/2 stream priority, sent in PRIORITY frames or with the HEADERS frame.
+// Weight ranges from 1 to 256, it is sent on the wire minus one.
+type HTTP2Priority struct {
+	StreamID  uint32 `json:"streamId"`
+	DependsOn uint32 `json:"dependsOn"`
+	Exclusive bool   `json:"exclusive"`
+	Weight    uint16 `json:"weight"`
+}
+
+// HTTP2Profile describes the HTTP/2 connection preface of the browser of a fingerprint
+type HTTP2Profile struct {
+	Settings     []HTTP2Setting `json:"settings"`
+	WindowUpdate uint32         `json:"windowUpdate"`
+	// Priorities are the PRIORITY frames sent after the preface, current browsers send none
+	Priorities []HTTP2Priority `json:"priorities"`
+	// HeaderPriority is the priority sent with the HEADERS frame of requests, its StreamID is unused
+	HeaderPriority    HTTP2Priority `json:"headerPriority"`
+	PseudoHeaderOrder []string      `json:"pseudoHeaderOrder"`
+	// Akamai is the Akamai HTTP/2 fingerprint of the profile
+	Akamai string `json:"akamai"`
+}
+
+// TLS cipher suites, extensions and signature algorithms sent by browsers
+var (
+	chromeCipherSuites  = []uint16{4865, 4866, 4867, 49195, 49199, 49196, 49200, 52393, 52392, 49171, 49172, 156, 157, 47, 53}
+	firefoxCipherSuites = []uint16{4865, 4867, 4866, 49195, 49199, 52393, 52392, 49196, 49200, 49162, 49161, 49171, 49172, 156, 157, 47, 53}
+	safariCipherSuites  = []uint16{4865, 4866, 4867, 49196, 49195, 52393, 49200, 49199, 52392, 49162, 49161, 49172, 49171, 157, 156, 53, 47, 49160, 49170, 10}
+
+	chromeSignatureAlgorithms  = []uint16{1027, 2052, 1025, 1283, 2053, 1281, 2054, 1537}
+	firefoxSignatureAlgorithms = []uint16{1027, 1283, 1539, 2052, 2053, 2054, 1025, 1281, 1537, 515, 513}
+	safariSignatureAlgorithms  = []uint16{1027, 2052, 1025, 1283, 515, 2053, 2053, 1281, 2054, 1537, 513}
+
+	chromeExtensions  = []uint16{0, 23, 65281, 10, 11, 35, 16, 5, 13, 18, 51, 45, 43, 27}
+	firefoxExtensions = []uint16{0, 23, 65281, 10, 11, 35, 16, 5, 34, 51, 43, 13, 45, 28, 27, 65037}
+	safariExtensions  = []uint16{0, 23, 65281, 10, 11, 16, 5, 13, 18, 51, 45, 43, 27, 21}
+)
+
+// TLS versions, extensions and certificate compression algorithms
+const (
+	tls10 uint16 = 0x0301
+	tls11 uint16 = 0x0302
+	tls12 uint16 = 0x0303
+	tls13 uint16 = 0x0304
+
+	extensionServerName                                             uint16 = 0
+	extensionALPN                                                   uint16 = 16
+	extensionApplicationSettings                                    uint16 = 17513
+	extensionApplicationSettingsNew                                 uint16 = 17613
+	extensionEncryptedClientHello                                   uint16 = 65037
+	certCompressionZlib, certCompressionBrotli, certCompressionZstd uint16 = 1, 2, 3
+)
+
+// Post-quantum key exchange groups
+const (
+	x25519Kyber768Draft00 uint16 = 25497
+	x25519MLKEM768        uint16 = 4588
+)
+
+// TLSFingerprint returns the TLS ClientHello sent by the browser of the fingerprint, matching its user agent.
+// iOS browsers all use the Safari network stack.
+func (f *Fingerprint) TLSFingerprint() TLSFingerprint {
+	userAgent := f.Navigator.UserAgent
+	alpn := []string{"h2", "http/1.1"}
+	pointFormats := []uint8{0}
+	switch engineOf(userAgent) {
+	case geckoEngine:
+		groups := []uint16{29, 23, 24, 25, 256, 257}
+		if parseUserAgent(userAgent).Version >= 132 {
+			groups = append([]uint16{x25519MLKEM768}, groups...)
+		}
+		return TLSFingerprint{
+			ClientHelloID:       "HelloFirefox_Auto",
+			Versions:            []uint16{tls13, tls12},
+			CipherSuites:        slices.Clone(firefoxCipherSuites),
+			Extensions:          slices.Clone(firefoxExtensions),
+			SupportedGroups:     groups,
+			PointFormats:        pointFormats,
+			SignatureAlgorithms: slices.Clone(firefoxSignatureAlgorithms),
+			CertCompression:     []uint16{certCompressionZlib, certCompressionBrotli, certCompressionZstd},
+			ALPN:                alpn,
+		}
+	case webKitEngine:
+		id := "HelloSafari_Auto"
+		if parseUserAgent(userAgent).OS == IOS {
+			id = "HelloIOS_Auto"
+		}
+		return TLSFingerprint{
+			ClientHelloID:       id,
+			Versions:            []uint16{tls13, tls12, tls11, tls10},
+			CipherSuites:        slices.Clone(safariCipherSuites),
+			Extensions:          slices.Clone(safariExtensions),
+			SupportedGroups:     []uint16{29, 23, 24, 25},
+			PointFormats:        pointFormats,
+			SignatureAlgorithms: slices.Clone(safariSignatureAlgorithms),
+			CertCompression:     []uint16{certCompressionZlib},
+			ALPN:                alpn,
+			GREASE:              true,
+		}
+	default:
+		chrome := chromeMajorVersion(userAgent)
+		groups := []uint16{29, 23, 24}
+		switch {
+		case chrome >= 131:
+			groups = append([]uint16{x25519MLKEM768}, groups...)
+		case chrome >= 124:
+			groups = append([]uint16{x25519Kyber768Draft00}, groups...)
+		}
+		extensions := slices.Clone(chromeExtensions)
+		switch {
+		case chrome >= 133:
+			extensions = append(extensions, extensionApplicationSettingsNew)
+		default:
+			extensions = append(extensions, extensionApplicationSettings)
+		}
+		if chrome >= 117 {
+			extensions = append(extensions, extensionEncryptedClientHello)
+		}
+		return TLSFingerprint{
+			ClientHelloID:       "HelloChrome_Auto",
+			Versions:            []uint16{tls13, tls12},
+			CipherSuites:        slices.Clone(chromeCipherSuites),
+			Extensions:          extensions,
+			SupportedGroups:     groups,
+			PointFormats:        pointFormats,
+			SignatureAlgorithms: slices.Clone(chromeSignatureAlgorithms),
+			CertCompression:     []uint16{certCompressionBrotli},
+			ALPN:                alpn,
+			GREASE:              true,
+			PermuteExtensions:   chrome >= 110,
+		}
+	}
+}
+
+// JA3 returns the JA3 string of the ClientHello, GREASE values excluded. Browsers permuting their
+// extensions send them in a different order on every connection, the unpermuted order is used.
+func (t TLSFingerprint) JA3() string {
+	pointFormats := make([]uint16, len(t.PointFormats))
+	for i, format := range t.PointFormats {
+		pointFormats[i] = uint16(format)
+	}
+	return strings.Join([]string{
+		strconv.Itoa(int(tls12)),
+		joinCodePoints(t.CipherSuites, "%d", "-"),
+		joinCodePoints(t.Extensions, "%d", "-"),
+		joinCodePoints(t.SupportedGroups, "%d", "-"),
+		joinCodePoints(pointFormats, "%d", "-"),
+	}, ",")
+}
+
+// JA4 returns the JA4 fingerprint of the ClientHello sent over TCP, it does not depend on the extension order
+func (t TLSFingerprint) JA4() string {
+	version := "00"
+	if len(t.Versions) > 0 {
+		version = map[uint16]string{tls13: "13", tls12: "12", tls11: "11", tls10: "10"}[slices.Max(t.Versions)]
+	}
+	alpn := "00"
+	if len(t.ALPN) > 0 && t.ALPN[0] != "" {
+		first := t.ALPN[0]
+		alpn = first[:1] + first[len(first)-1:]
+	}
+	sni := "i"
+	if slices.Contains(t.Extensions, extensionServerName) {
+		sni = "d"
+	}
+	prefix := fmt.Sprintf("t%s%s%02d%02d%s", version, sni, min(len(t.CipherSuites), 99), min(len(t.Extensions), 99), alpn)
+
+	ciphers := slices.Sorted(slices.Values(t.CipherSuites))
+	extensions := slices.DeleteFunc(slices.Sorted(slices.Values(t.Extensions)), func(extension uint16) bool {
+		return extension == extensionServerName || extension == extensionALPN
+	})
+	extensionHash := joinCodePoints(extensions, "%04x", ",")
+	if len(t.SignatureAlgorithms) > 0 {
+		extensionHash += "_" + joinCodePoints(t.SignatureAlgorithms, "%04x", ",")
+	}
+	return prefix + "_" + truncatedHash(joinCodePoints(ciphers, "%04x", ",")) + "_" + truncatedHash(extensionHash)
+}
+
+// joinCodePoints formats and joins code points
+func joinCodePoints(values []uint16, format, sep string) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = fmt.Sprintf(format, value)
+	}
+	return strings.Join(parts, sep)
+}
+
+// truncatedHash returns the first 12 hex characters of the SHA-256 of s, as used by JA4
+func truncatedHash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// HTTP2Profile returns the HTTP/2 settings, window update, priorities and pseudo-header order sent by the browser of the fingerprint
+func (f *Fingerprint) HTTP2Profile() HTTP2Profile {
+	return http2ProfileFor(f.Navigator.UserAgent)
+}
+
+// HTTP2Profile returns the HTTP/2 profile of the browser sending the headers, taken from their User-Agent,
+// so the h2 frames of clients replaying generated headers match them
+func (h OrderedHeaders) HTTP2Profile() HTTP2Profile {
+	return http2ProfileFor(h.Get("user-agent"))
+}
+
+// http2ProfileFor returns the HTTP/2 profile of the browser of the user agent
+func http2ProfileFor(userAgent string) HTTP2Profile {
+	var profile HTTP2Profile
+	switch engineOf(userAgent) {
+	case geckoEngine:
+		profile.Settings = []HTTP2Setting{{1, 65536}, {2, 0}, {4, 131072}, {5, 16384}}
+		profile.WindowUpdate = 12517377
+		profile.HeaderPriority = HTTP2Priority{Weight: 42}
+	case webKitEngine:
+		profile.Settings = []HTTP2Setting{{2, 0}, {3, 100}, {4, 2097152}, {9, 1}}
+		profile.WindowUpdate = 10420225
+		profile.HeaderPriority = HTTP2Priority{Weight: 255}
+	default:
+		profile.Settings = []HTTP2Setting{{1, 65536}, {2, 0}, {4, 6291456}, {6, 262144}}
+		profile.WindowUpdate = 15663105
+		profile.HeaderPriority = HTTP2Priority{Exclusive: true, Weight: 256}
+	}
+	profile.Priorities = []HTTP2Priority{}
+	profile.PseudoHeaderOrder = pseudoHeaderOrderFor(userAgent)
+	profile.Akamai = profile.akamai()
+	return profile
+}
+
+// akamai formats the profile as an Akamai HTTP/2 fingerprint
+func (p HTTP2Profile) akamai() string {
+	settings := make([]string, len(p.Settings))
+	for i, setting := range p.Settings {
+		settings[i] = fmt.Sprintf("%d:%d", setting.ID, setting.Value)
+	}
+	priorities := "0"
+	if len(p.Priorities) > 0 {
+		frames := make([]string, len(p.Priorities))
+		for i, priority := range p.Priorities {
+			exclusive := 0
+			if priority.Exclusive {
+				exclusive = 1
+			}
+			frames[i] = fmt.Sprintf("%d:%d:%d:%d", priority.StreamID, exclusive, priority.DependsOn, priority.Weight)
+		}
+		priorities = strings.Join(frames, ",")
+	}
+	pseudo := make([]string, len(p.PseudoHeaderOrder))
+	for i, header := range p.PseudoHeaderOrder {
+		pseudo[i] = header[1:2]
+	}
+	return fmt.Sprintf("%s|%d|%s|%s", strings.Join(settings, ";"), p.WindowUpdate, priorities, strings.Join(pseudo, ","))
+}
@@ -0,0 +1,221 @@
+package forgeron
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTLSFingerprint(t *testing.T) {
+	tests := []struct {
+		name          string
+		userAgent     string
+		clientHelloID string
+		firstGroup    uint16
+		permute       bool
+	}{
+		{
+			name:          "chrome 144",
+			userAgent:     "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36",
+			clientHelloID: "HelloChrome_Auto",
+			firstGroup:    x25519MLKEM768,
+			permute:       true,
+		},
+		{
+			name:          "chrome 126",
+			userAgent:     "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36",
+			clientHelloID: "HelloChrome_Auto",
+			firstGroup:    x25519Kyber768Draft00,
+			permute:       true,
+		},
+		{
+			name:          "chrome 101",
+			userAgent:     "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/101.0.4951.54 Safari/537.36",
+			clientHelloID: "HelloChrome_Auto",
+			firstGroup:    29,
+		},
+		{
+			name:          "firefox",
+			userAgent:     "Mozilla/5.0 (X11; Linux x86_64; rv:147.0) Gecko/20100101 Firefox/147.0",
+			clientHelloID: "HelloFirefox_Auto",
+			firstGroup:    x25519MLKEM768,
+		},
+		{
+			name:          "safari",
+			userAgent:     "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/26.2 Safari/605.1.15",
+			clientHelloID: "HelloSafari_Auto",
+			firstGroup:    29,
+		},
+		{
+			name:          "chrome on iOS",
+			userAgent:     "Mozilla/5.0 (iPhone; CPU iPhone OS 18_7 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/144.0.7559.95 Mobile/15E148 Safari/604.1",
+			clientHelloID: "HelloIOS_Auto",
+			firstGroup:    29,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fp := &Fingerprint{Navigator: NavigatorFingerprint{UserAgent: tt.userAgent}}
+			profile := fp.TLSFingerprint()
+			if profile.ClientHelloID != tt.clientHelloID {
+				t.Errorf("ClientHelloID = %s, want %s", profile.ClientHelloID, tt.clientHelloID)
+			}
+			if profile.SupportedGroups[0] != tt.firstGroup {
+				t.Errorf("SupportedGroups = %v, want %d first", profile.SupportedGroups, tt.firstGroup)
+			}
+			if profile.PermuteExtensions != tt.permute {
+				t.Errorf("PermuteExtensions = %v, want %v", profile.PermuteExtensions, tt.permute)
+			}
+			if !slices.Equal(profile.ALPN, []string{"h2", "http/1.1"}) {
+				t.Errorf("ALPN = %v", profile.ALPN)
+			}
+		})
+	}
+}
+
+func TestTLSFingerprintIsNotShared(t *testing.T) {
+	for _, userAgent := range []string{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36",
+		"Mozilla/5.0 (X11; Linux x86_64; rv:147.0) Gecko/20100101 Firefox/147.0",
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/26.2 Safari/605.1.15",
+	} {
+		fp := &Fingerprint{Navigator: NavigatorFingerprint{UserAgent: userAgent}}
+		want := fp.TLSFingerprint().JA3()
+		profile := fp.TLSFingerprint()
+		for _, values := range [][]uint16{profile.CipherSuites, profile.Extensions, profile.SupportedGroups, profile.SignatureAlgorithms, profile.CertCompression} {
+			for i := range values {
+				values[i] = 0
+			}
+		}
+		if got := fp.TLSFingerprint().JA3(); got != want {
+			t.Errorf("JA3 after mutating a profile = %s, want %s", got, want)
+		}
+	}
+}
+
+func TestTLSFingerprintJA3AndJA4(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		ja3       string
+		ja4       string
+	}{
+		{
+			name:      "chrome 125",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/125.0.0.0 Safari/537.36",
+			ja3:       "771,4865-4866-4867-49195-49199-49196-49200-52393-52392-49171-49172-156-157-47-53,0-23-65281-10-11-35-16-5-13-18-51-45-43-27-17513-65037,25497-29-23-24,0",
+			ja4:       "t13d1516h2_8daaf6152771_02713d6af862",
+		},
+		{
+			name:      "safari",
+			userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/26.2 Safari/605.1.15",
+			ja3:       "771,4865-4866-4867-49196-49195-52393-49200-49199-52392-49162-49161-49172-49171-157-156-53-47-49160-49170-10,0-23-65281-10-11-16-5-13-18-51-45-43-27-21,29-23-24-25,0",
+			ja4:       "t13d2014h2_a09f3c656075_14788d8d241b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tls := (&Fingerprint{Navigator: NavigatorFingerprint{UserAgent: tt.userAgent}}).TLSFingerprint()
+			if got := tls.JA3(); got != tt.ja3 {
+				t.Errorf("JA3() = %s, want %s", got, tt.ja3)
+			}
+			if got := tls.JA4(); got != tt.ja4 {
+				t.Errorf("JA4() = %s, want %s", got, tt.ja4)
+			}
+		})
+	}
+
+	// Chrome switched the ALPS code point in 133
+	chrome := (&Fingerprint{Navigator: NavigatorFingerprint{
+		UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36",
+	}}).TLSFingerprint()
+	if !slices.Contains(chrome.Extensions, extensionApplicationSettingsNew) || slices.Contains(chrome.Extensions, extensionApplicationSettings) {
+		t.Errorf("Extensions = %v, want the new ALPS code point", chrome.Extensions)
+	}
+}
+
+func TestHTTP2Profile(t *testing.T) {
+	tests := []struct {
+		userAgent      string
+		akamai         string
+		headerPriority HTTP2Priority
+	}{
+		{
+			userAgent:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36",
+			akamai:         "1:65536;2:0;4:6291456;6:262144|15663105|0|m,a,s,p",
+			headerPriority: HTTP2Priority{Exclusive: true, Weight: 256},
+		},
+		{
+			userAgent:      "Mozilla/5.0 (X11; Linux x86_64; rv:147.0) Gecko/20100101 Firefox/147.0",
+			akamai:         "1:65536;2:0;4:131072;5:16384|12517377|0|m,p,a,s",
+			headerPriority: HTTP2Priority{Weight: 42},
+		},
+		{
+			userAgent:      "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/26.2 Safari/605.1.15",
+			akamai:         "2:0;3:100;4:2097152;9:1|10420225|0|m,s,a,p",
+			headerPriority: HTTP2Priority{Weight: 255},
+		},
+	}
+	for _, tt := range tests {
+		fp := &Fingerprint{Navigator: NavigatorFingerprint{UserAgent: tt.userAgent}}
+		profile := fp.HTTP2Profile()
+		if profile.Akamai != tt.akamai {
+			t.Errorf("Akamai = %s, want %s", profile.Akamai, tt.akamai)
+		}
+		if !slices.Equal(profile.PseudoHeaderOrder, fp.PseudoHeaderOrder()) {
+			t.Errorf("PseudoHeaderOrder = %v, want %v", profile.PseudoHeaderOrder, fp.PseudoHeaderOrder())
+		}
+		if profile.HeaderPriority != tt.headerPriority {
+			t.Errorf("HeaderPriority = %+v, want %+v", profile.HeaderPriority, tt.headerPriority)
+		}
+	}
+}
+
+func TestHTTP2ProfileAkamaiPriorities(t *testing.T) {
+	profile := HTTP2Profile{
+		Settings:          []HTTP2Setting{{1, 65536}, {4, 131072}, {5, 16384}},
+		WindowUpdate:      12517377,
+		Priorities:        []HTTP2Priority{{StreamID: 3, Weight: 201}, {StreamID: 9, DependsOn: 7, Exclusive: true, Weight: 1}},
+		PseudoHeaderOrder: []string{":method", ":path", ":authority", ":scheme"},
+	}
+	if got, want := profile.akamai(), "1:65536;4:131072;5:16384|12517377|3:0:0:201,9:1:7:1|m,p,a,s"; got != want {
+		t.Errorf("akamai() = %s, want %s", got, want)
+	}
+}
+
+func TestOrderedHeadersHTTP2Profile(t *testing.T) {
+	gen, err := NewHeaderGenerator()
+	if err != nil {
+		t.Fatalf("NewHeaderGenerator() error = %v", err)
+	}
+	headers, err := gen.GenerateOrderedHeaders(HeaderConstraints{Browsers: []Browser{Firefox}, HTTPVersion: HTTP2})
+	if err != nil {
+		t.Fatalf("GenerateOrderedHeaders() error = %v", err)
+	}
+	profile := headers.HTTP2Profile()
+	if want := []string{":method", ":path", ":authority", ":scheme"}; !slices.Equal(profile.PseudoHeaderOrder, want) {
+		t.Errorf("PseudoHeaderOrder = %v, want the Firefox order %v", profile.PseudoHeaderOrder, want)
+	}
+	if profile.WindowUpdate != 12517377 {
+		t.Errorf("WindowUpdate = %d, want the Firefox window update", profile.WindowUpdate)
+	}
+}
+
+func TestFingerprintOrderedHeaders(t *testing.T) {
+	fp := &Fingerprint{
+		Navigator: NavigatorFingerprint{UserAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:147.0) Gecko/20100101 Firefox/147.0"},
+		Headers: map[string]string{
+			"Accept-Language": "en-US",
+			"User-Agent":      "Mozilla/5.0 (X11; Linux x86_64; rv:147.0) Gecko/20100101 Firefox/147.0",
+			"Connection":      "keep-alive",
+			"X-Custom":        "1",
+		},
+	}
+	var names []string
+	for _, header := range fp.OrderedHeaders() {
+		names = append(names, header.Name)
+	}
+	want := []string{"User-Agent", "Accept-Language", "Connection", "X-Custom"}
+	if !slices.Equal(names, want) {
+		t.Errorf("OrderedHeaders() = %v, want %v", names, want)
+	}
+}
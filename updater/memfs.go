@@ -0,0 +1,48 @@
+package updater
+
+import (
+	"bytes"
+	"io/fs"
+	"time"
+)
+
+// memFS is a flat file system of downloaded files
+type memFS map[string][]byte
+
+// Open implements fs.FS
+func (m memFS) Open(name string) (fs.File, error) {
+	data, ok := m[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return &memFile{name: name, Reader: bytes.NewReader(data)}, nil
+}
+
+// memFile is an open file of a memFS
+type memFile struct {
+	name string
+	*bytes.Reader
+}
+
+// Stat implements fs.File
+func (f *memFile) Stat() (fs.FileInfo, error) {
+	return memFileInfo{name: f.name, size: f.Size()}, nil
+}
+
+// Close implements fs.File
+func (f *memFile) Close() error {
+	return nil
+}
+
+// memFileInfo describes a file of a memFS
+type memFileInfo struct {
+	name string
+	size int64
+}
+
+func (i memFileInfo) Name() string       { return i.name }
+func (i memFileInfo) Size() int64        { return i.size }
+func (i memFileInfo) Mode() fs.FileMode  { return 0o444 }
+func (i memFileInfo) ModTime() time.Time { return time.Time{} }
+func (i memFileInfo) IsDir() bool        { return false }
+func (i memFileInfo) Sys() any           { return nil }
@@ -0,0 +1,265 @@
+// Package updater keeps the network data of running generators up to date with a data release published at a URL,
+// so browser versions do not go stale between forgeron releases.
+//
+// A release is a directory served over HTTP:
+//
+//	manifest.json      {"version": "2026-10-01", "files": {"fingerprint-network-definition.zip": "<sha256>", ...}}
+//	manifest.json.sig  base64 ed25519 signature of manifest.json, required when a public key is configured
+//	<file>             every file listed in the manifest
+//
+// Files are verified against their SHA-256 checksum before a generator is created from them, and files missing
+// from the release fall back to the embedded data.
+package updater
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ta0uf19/forgeron"
+)
+
+// maxFileSize bounds the size of a downloaded file
+const maxFileSize = 64 << 20
+
+// ErrVerification is returned when a downloaded file does not match its checksum or the manifest its signature
+var ErrVerification = errors.New("data verification failed")
+
+// ErrDowngrade is returned when the release is older than the applied one, e.g. a replayed signed manifest
+var ErrDowngrade = errors.New("data release is older than the applied release")
+
+// Manifest lists the files of a data release
+type Manifest struct {
+	// Version identifies the release, an update is only applied when it is newer than the applied release.
+	// Versions compare by their numbers in order, e.g. 2026-10-01 or 1.2.3.
+	Version string `json:"version"`
+	// Files maps the data file names to their hex encoded SHA-256 checksum
+	Files map[string]string `json:"files"`
+}
+
+// Config configures an Updater
+type Config struct {
+	// URL is the base URL of the data release
+	URL string
+	// PublicKey verifies the signature of the manifest, nil only verifies the checksums
+	PublicKey ed25519.PublicKey
+	// HTTPClient downloads the release, nil uses http.DefaultClient
+	HTTPClient *http.Client
+	// OnError is called when a periodic update fails, the current data is kept
+	OnError func(error)
+}
+
+// DataVersion describes the data used by the generator of an Updater
+type DataVersion struct {
+	// Version is the version of the applied release, empty for the embedded data
+	Version   string            `json:"version"`
+	UpdatedAt time.Time         `json:"updatedAt"`
+	Data      forgeron.DataInfo `json:"data"`
+}
+
+// Updater holds a generator using the latest data release. Updates reload the data of the generator at once,
+// so concurrent generations use either the previous or the new data, never a mix. It is safe for concurrent use.
+type Updater struct {
+	config  Config
+	base    *url.URL
+	gen     *forgeron.FingerprintGenerator
+	mu      sync.RWMutex
+	version DataVersion
+}
+
+// New creates an updater whose generator is created with the options and starts with the embedded data
+func New(config Config, opts ...forgeron.FingerprintOption) (*Updater, error) {
+	base, err := url.Parse(config.URL)
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("invalid data release URL %q", config.URL)
+	}
+	if config.PublicKey != nil && len(config.PublicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key size %d", len(config.PublicKey))
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	gen, err := forgeron.NewFingerprintGenerator(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Updater{
+		config:  config,
+		base:    base.JoinPath("/"),
+		gen:     gen,
+		version: DataVersion{UpdatedAt: time.Now(), Data: gen.DataInfo()},
+	}, nil
+}
+
+// Generator returns the fingerprint generator, which keeps using the latest data release after updates
+func (u *Updater) Generator() *forgeron.FingerprintGenerator {
+	return u.gen
+}
+
+// Generate generates a fingerprint with the latest data release
+func (u *Updater) Generate(opts ...forgeron.FingerprintOption) (*forgeron.Fingerprint, error) {
+	return u.gen.Generate(opts...)
+}
+
+// Version returns the version of the data used by the current generator
+func (u *Updater) Version() DataVersion {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.version
+}
+
+// Update downloads the data release and reloads the generator with it, reporting whether a new release was
+// applied. The current data is kept when the release fails verification or loading.
+func (u *Updater) Update(ctx context.Context) (bool, error) {
+	manifestData, err := u.download(ctx, "manifest.json")
+	if err != nil {
+		return false, err
+	}
+	if u.config.PublicKey != nil {
+		if err := u.verifySignature(ctx, manifestData); err != nil {
+			return false, err
+		}
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return false, fmt.Errorf("failed to decode manifest: %w", err)
+	}
+	if manifest.Version == "" {
+		return false, fmt.Errorf("manifest has no version")
+	}
+	if newer, err := isNewer(manifest.Version, u.Version().Version); !newer {
+		return false, err
+	}
+
+	files := make(memFS, len(manifest.Files))
+	for name, checksum := range manifest.Files {
+		if !fs.ValidPath(name) || name == "." {
+			return false, fmt.Errorf("invalid file name %q in manifest", name)
+		}
+		data, err := u.download(ctx, name)
+		if err != nil {
+			return false, err
+		}
+		if sum := sha256.Sum256(data); hex.EncodeToString(sum[:]) != checksum {
+			return false, fmt.Errorf("%w: %s does not match its checksum", ErrVerification, name)
+		}
+		files[name] = data
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	// A concurrent update may have applied a newer release during the download
+	if newer, err := isNewer(manifest.Version, u.version.Version); !newer {
+		return false, err
+	}
+	if err := u.gen.Reload(files); err != nil {
+		return false, fmt.Errorf("failed to load data release %s: %w", manifest.Version, err)
+	}
+	u.version = DataVersion{Version: manifest.Version, UpdatedAt: time.Now(), Data: u.gen.DataInfo()}
+	return true, nil
+}
+
+// isNewer reports whether a release is newer than the applied one, an older release being an ErrDowngrade
+func isNewer(version, current string) (bool, error) {
+	if current == "" {
+		return true, nil
+	}
+	switch compareVersions(version, current) {
+	case 0:
+		return false, nil
+	case -1:
+		return false, fmt.Errorf("%w: %s is older than %s", ErrDowngrade, version, current)
+	}
+	return true, nil
+}
+
+// compareVersions compares two release versions by their numbers in order, then as strings when the numbers are
+// equal, returning -1, 0 or 1
+func compareVersions(a, b string) int {
+	if c := slices.Compare(versionNumbers(a), versionNumbers(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+// versionNumbers returns the numbers of a release version, e.g. [2026 10 1] for 2026-10-01
+func versionNumbers(version string) []int {
+	var numbers []int
+	for _, field := range strings.FieldsFunc(version, func(r rune) bool { return r < '0' || r > '9' }) {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			n = math.MaxInt
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers
+}
+
+// Run updates the data every interval until ctx is done, reporting failed updates to Config.OnError
+func (u *Updater) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := u.Update(ctx); err != nil && ctx.Err() == nil && u.config.OnError != nil {
+			u.config.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// verifySignature checks the manifest against its signature
+func (u *Updater) verifySignature(ctx context.Context, manifest []byte) error {
+	encoded, err := u.download(ctx, "manifest.json.sig")
+	if err != nil {
+		return err
+	}
+	signature, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(encoded)))
+	if err != nil || !ed25519.Verify(u.config.PublicKey, manifest, signature) {
+		return fmt.Errorf("%w: invalid manifest signature", ErrVerification)
+	}
+	return nil
+}
+
+// download returns the content of a file of the release
+func (u *Updater) download(ctx context.Context, name string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.base.JoinPath(name).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := u.config.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", name, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	if len(data) > maxFileSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", name, maxFileSize)
+	}
+	return data, nil
+}
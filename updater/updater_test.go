@@ -0,0 +1,214 @@
+package updater
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// releaseFiles are the files published by the test releases
+var releaseFiles = []string{"fingerprint-network-definition.zip", "header-network-definition.zip", "browser-helper-file.json"}
+
+// newRelease serves a data release of the repository data files, signed with key when not nil
+func newRelease(t *testing.T, version string, key ed25519.PrivateKey) (*httptest.Server, map[string][]byte) {
+	t.Helper()
+	files := make(map[string][]byte)
+	manifest := Manifest{Version: version, Files: make(map[string]string)}
+	for _, name := range releaseFiles {
+		data, err := os.ReadFile(filepath.Join("..", "data_points", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sum := sha256.Sum256(data)
+		manifest.Files[name] = hex.EncodeToString(sum[:])
+		files[name] = data
+	}
+	manifestData, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files["manifest.json"] = manifestData
+	if key != nil {
+		files["manifest.json.sig"] = []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(key, manifestData)))
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := files[filepath.Base(r.URL.Path)]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return server, files
+}
+
+func TestUpdate(t *testing.T) {
+	server, _ := newRelease(t, "2026-10-01", nil)
+	u, err := New(Config{URL: server.URL + "/release"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if version := u.Version(); version.Version != "" || version.Data.Schema == 0 {
+		t.Errorf("Version() = %+v, want the embedded data", version)
+	}
+	embedded := u.Generator()
+
+	updated, err := u.Update(context.Background())
+	if err != nil || !updated {
+		t.Fatalf("Update() = %v, %v, want the release applied", updated, err)
+	}
+	if u.Version().Version != "2026-10-01" {
+		t.Errorf("Version() = %+v, want the release swapped in", u.Version())
+	}
+	if u.Generator() != embedded {
+		t.Error("Generator() changed, want the release reloaded into the same generator")
+	}
+	if _, err := u.Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if updated, err := u.Update(context.Background()); err != nil || updated {
+		t.Errorf("Update() = %v, %v, want the same release skipped", updated, err)
+	}
+}
+
+func TestUpdateVerifiesChecksums(t *testing.T) {
+	server, files := newRelease(t, "2026-10-01", nil)
+	files["header-network-definition.zip"] = append([]byte(nil), files["fingerprint-network-definition.zip"]...)
+	u, err := New(Config{URL: server.URL})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	embedded := u.Generator()
+	if _, err := u.Update(context.Background()); !errors.Is(err, ErrVerification) {
+		t.Fatalf("Update() error = %v, want ErrVerification", err)
+	}
+	if u.Generator() != embedded || u.Version().Version != "" {
+		t.Error("failed update replaced the generator")
+	}
+}
+
+func TestUpdateVerifiesSignature(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, _ := newRelease(t, "2026-10-01", private)
+
+	u, err := New(Config{URL: server.URL, PublicKey: other})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := u.Update(context.Background()); !errors.Is(err, ErrVerification) {
+		t.Errorf("Update() with another key error = %v, want ErrVerification", err)
+	}
+
+	u, err = New(Config{URL: server.URL, PublicKey: public})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if updated, err := u.Update(context.Background()); err != nil || !updated {
+		t.Errorf("Update() = %v, %v, want the signed release applied", updated, err)
+	}
+}
+
+func TestUpdateRejectsDowngrade(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A genuinely signed older release, replayed after a newer one was applied
+	server, _ := newRelease(t, "2026-09-01", private)
+	u, err := New(Config{URL: server.URL, PublicKey: public})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	u.version.Version = "2026-10-01"
+	if updated, err := u.Update(context.Background()); !errors.Is(err, ErrDowngrade) || updated {
+		t.Errorf("Update() = %v, %v, want ErrDowngrade", updated, err)
+	}
+	if u.Version().Version != "2026-10-01" {
+		t.Errorf("Version() = %+v, want the applied release kept", u.Version())
+	}
+}
+
+// roundTripFunc is an http.RoundTripper calling a function
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestUpdateRejectsDowngradeDuringDownload(t *testing.T) {
+	server, _ := newRelease(t, "2026-09-01", nil)
+	var u *Updater
+	var once sync.Once
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		// A concurrent update applies a newer release while the files of this one are downloaded
+		if !strings.HasSuffix(req.URL.Path, "manifest.json") {
+			once.Do(func() {
+				u.mu.Lock()
+				u.version.Version = "2026-10-01"
+				u.mu.Unlock()
+			})
+		}
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+	u, err := New(Config{URL: server.URL, HTTPClient: client})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if updated, err := u.Update(context.Background()); !errors.Is(err, ErrDowngrade) || updated {
+		t.Errorf("Update() = %v, %v, want ErrDowngrade", updated, err)
+	}
+	if u.Version().Version != "2026-10-01" {
+		t.Errorf("Version() = %+v, want the newer release kept", u.Version())
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"2026-10-01", "2026-09-30", 1},
+		{"2026-09-30", "2026-10-01", -1},
+		{"2026-10-01", "2026-10-01", 0},
+		{"1.10.0", "1.9.2", 1},
+		{"1.2", "1.2.1", -1},
+		{"v2", "1.9", 1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestNewRejectsInvalidConfig(t *testing.T) {
+	for name, config := range map[string]Config{
+		"relative URL": {URL: "/release"},
+		"short key":    {URL: "https://cdn.example.com", PublicKey: ed25519.PublicKey{1}},
+	} {
+		if _, err := New(config); err == nil {
+			t.Errorf("New() with %s error = nil", name)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package forgeron
+
+import (
+	"slices"
+	"strings"
+)
+
+// userAgentClass identifies identities that the input network does not distinguish
+// and that are told apart by their user agent
+type userAgentClass int
+
+const (
+	chromeOSClass userAgentClass = iota
+	tabletClass
+	phoneClass
+)
+
+// userAgentClasses lists all user agent classes
+var userAgentClasses = []userAgentClass{chromeOSClass, tabletClass, phoneClass}
+
+// matches returns true if the user agent belongs to the class
+func (c userAgentClass) matches(userAgent string) bool {
+	switch c {
+	case chromeOSClass:
+		return isChromeOSUserAgent(userAgent)
+	case tabletClass:
+		return isTabletUserAgent(userAgent)
+	case phoneClass:
+		return !isTabletUserAgent(userAgent)
+	default:
+		return true
+	}
+}
+
+// classConstraint returns the constraint relaxed when no user agent of the class can be generated
+func classConstraint(class userAgentClass) string {
+	if class == chromeOSClass {
+		return "os"
+	}
+	return "devices"
+}
+
+// requestedClasses returns the user agent classes every identity must belong to given the constraints
+func requestedClasses(constraints HeaderConstraints) []userAgentClass {
+	var classes []userAgentClass
+	if len(constraints.OS) > 0 && !slices.ContainsFunc(constraints.OS, func(os OS) bool { return os != ChromeOS }) {
+		classes = append(classes, chromeOSClass)
+	}
+	if tabletsOnly(constraints.Devices) {
+		classes = append(classes, tabletClass)
+	}
+	return classes
+}
+
+// sampledClasses returns the user agent classes implied by the sampled input and the constraints
+func sampledClasses(inputSample map[string]string, constraints HeaderConstraints) []userAgentClass {
+	var classes []userAgentClass
+	if isMissingValue(inputSample["*OPERATING_SYSTEM"]) {
+		classes = append(classes, chromeOSClass)
+	}
+	if inputSample["*DEVICE"] == "mobile" {
+		if tabletsOnly(constraints.Devices) {
+			classes = append(classes, tabletClass)
+		} else if !slices.Contains(constraints.Devices, Tablet) {
+			classes = append(classes, phoneClass)
+		}
+	}
+	return classes
+}
+
+// loadClassBrowsers indexes the browsers able to produce a user agent of each class
+func (g *HeaderGenerator) loadClassBrowsers() {
+	g.classBrowsers = make(map[userAgentClass]map[string]bool)
+	for _, class := range userAgentClasses {
+		browsers := make(map[string]bool)
+		for _, name := range []string{"user-agent", "User-Agent"} {
+			node, ok := g.headerGeneratorNetwork.NodesByName[name]
+			if !ok {
+				continue
+			}
+			for browser := range node.parentValuesLeadingTo("*BROWSER", class.matches) {
+				browsers[browser] = true
+			}
+		}
+		g.classBrowsers[class] = browsers
+	}
+}
+
+// restrictBrowsersToClasses keeps only the browser HTTP values able to produce a user agent of every class
+func (g *HeaderGenerator) restrictBrowsersToClasses(inputConstraints map[string][]string, classes []userAgentClass) {
+	values, ok := inputConstraints["*BROWSER_HTTP"]
+	if !ok || len(classes) == 0 {
+		return
+	}
+	inputConstraints["*BROWSER_HTTP"] = slices.DeleteFunc(slices.Clone(values), func(value string) bool {
+		browserVersion, _, _ := strings.Cut(value, "|")
+		for _, class := range classes {
+			if !g.classBrowsers[class][browserVersion] {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// headerRestrictions returns the restrictions to apply when sampling the header network,
+// or nil when the input sample can be sampled freely
+func (g *HeaderGenerator) headerRestrictions(inputSample map[string]string, constraints HeaderConstraints) map[string][]string {
+	classes := sampledClasses(inputSample, constraints)
+	if len(classes) == 0 {
+		return nil
+	}
+
+	// Only keep matching user agents for the header casing used by the sampled HTTP version
+	userAgentNode := "user-agent"
+	if inputSample["*HTTP_VERSION"] == "_1.1_" {
+		userAgentNode = "User-Agent"
+	}
+	node, ok := g.headerGeneratorNetwork.NodesByName[userAgentNode]
+	if !ok {
+		return nil
+	}
+	userAgents := slices.DeleteFunc(slices.Clone(node.PossibleValues), func(value string) bool {
+		for _, class := range classes {
+			if !class.matches(value) {
+				return true
+			}
+		}
+		return false
+	})
+	if len(userAgents) == 0 {
+		return nil
+	}
+
+	restrictions := make(map[string][]string)
+	for k, v := range inputSample {
+		restrictions[k] = []string{v}
+	}
+	restrictions[userAgentNode] = userAgents
+	return restrictions
+}
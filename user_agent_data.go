@@ -0,0 +1,63 @@
+package forgeron
+
+import (
+	"fmt"
+	"slices"
+)
+
+// chromiumBrowsers are the browsers exposing navigator.userAgentData, except on iOS where they run on WebKit
+var chromiumBrowsers = []Browser{Chrome, Edge}
+
+// GenerateUserAgentData generates the navigator.userAgentData of a Chromium browser matching the constraints, for
+// custom browsers and extensions answering getHighEntropyValues() without the rest of a fingerprint. Browsers
+// default to Chrome and Edge, the other browsers and iOS are rejected as they expose no user agent data.
+func (g *FingerprintGenerator) GenerateUserAgentData(constraints HeaderConstraints) (*UserAgentData, error) {
+	constraints, err := userAgentDataConstraints(constraints)
+	if err != nil {
+		return nil, err
+	}
+	fp, err := g.Generate(WithHeaderConstraints(constraints))
+	if err != nil {
+		return nil, err
+	}
+	// Outside strict mode the browsers or the OS may have been relaxed to ones without user agent data
+	if fp.Navigator.UserAgentData == nil {
+		return nil, newConstraintError("browsers", "no input sample", fmt.Errorf("no Chromium browser matches the constraints"))
+	}
+	return fp.Navigator.UserAgentData, nil
+}
+
+// userAgentDataConstraints narrows the header constraints to the Chromium browsers exposing user agent data
+func userAgentDataConstraints(constraints HeaderConstraints) (HeaderConstraints, error) {
+	if len(constraints.Browsers) == 0 {
+		constraints.Browsers = chromiumBrowsers
+	} else {
+		browsers := slices.DeleteFunc(slices.Clone(constraints.Browsers), func(browser Browser) bool {
+			return !slices.Contains(chromiumBrowsers, browser)
+		})
+		if len(browsers) == 0 {
+			err := fmt.Errorf("only %v expose navigator.userAgentData", chromiumBrowsers)
+			return constraints, newFieldError("Browsers", err, constraints.Browsers...)
+		}
+		constraints.Browsers = browsers
+	}
+	if constraints.BrowserSpecs != nil {
+		specs := slices.DeleteFunc(slices.Clone(constraints.BrowserSpecs), func(spec *BrowserSpec) bool {
+			return !slices.Contains(chromiumBrowsers, spec.Name)
+		})
+		if len(specs) == 0 {
+			return constraints, newFieldError[Browser]("BrowserSpecs", fmt.Errorf("only %v expose navigator.userAgentData", chromiumBrowsers))
+		}
+		constraints.BrowserSpecs = specs
+	}
+
+	systems := constraints.OS
+	if len(systems) == 0 {
+		systems = SupportedOS
+	}
+	constraints.OS = slices.DeleteFunc(slices.Clone(systems), func(os OS) bool { return os == IOS })
+	if len(constraints.OS) == 0 {
+		return constraints, newFieldError("OS", fmt.Errorf("browsers on iOS expose no navigator.userAgentData"), IOS)
+	}
+	return constraints, nil
+}
@@ -0,0 +1,85 @@
+package forgeron
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestUserAgentDataConstraints(t *testing.T) {
+	tests := []struct {
+		name         string
+		constraints  HeaderConstraints
+		wantBrowsers []Browser
+		wantOS       []OS
+		wantField    string
+	}{
+		{
+			name:         "defaults",
+			wantBrowsers: []Browser{Chrome, Edge},
+			wantOS:       []OS{Windows, MacOS, Linux, Android, ChromeOS},
+		},
+		{
+			name:         "drops other browsers and ios",
+			constraints:  HeaderConstraints{Browsers: []Browser{Firefox, Edge}, OS: []OS{IOS, Android}},
+			wantBrowsers: []Browser{Edge},
+			wantOS:       []OS{Android},
+		},
+		{
+			name:        "only firefox",
+			constraints: HeaderConstraints{Browsers: []Browser{Firefox, Safari}},
+			wantField:   "Browsers",
+		},
+		{
+			name:        "only safari specs",
+			constraints: HeaderConstraints{BrowserSpecs: []*BrowserSpec{{Name: Safari}}},
+			wantField:   "BrowserSpecs",
+		},
+		{
+			name:        "only ios",
+			constraints: HeaderConstraints{OS: []OS{IOS}},
+			wantField:   "OS",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := userAgentDataConstraints(tt.constraints)
+			if tt.wantField != "" {
+				var fieldErr *FieldError
+				if !errors.As(err, &fieldErr) || fieldErr.Field != tt.wantField {
+					t.Fatalf("userAgentDataConstraints() error = %v, want a %s field error", err, tt.wantField)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("userAgentDataConstraints() error = %v", err)
+			}
+			if !slices.Equal(got.Browsers, tt.wantBrowsers) {
+				t.Errorf("Browsers = %v, want %v", got.Browsers, tt.wantBrowsers)
+			}
+			if !slices.Equal(got.OS, tt.wantOS) {
+				t.Errorf("OS = %v, want %v", got.OS, tt.wantOS)
+			}
+		})
+	}
+}
+
+func TestGenerateUserAgentData(t *testing.T) {
+	gen := newGeneratorOrFatal(t, WithRandomSource(NewSeededSource(7)))
+	for range 20 {
+		data, err := gen.GenerateUserAgentData(HeaderConstraints{OS: []OS{Windows, Android}})
+		if err != nil {
+			t.Fatalf("GenerateUserAgentData() error = %v", err)
+		}
+		if data.Platform != "Windows" && data.Platform != "Android" {
+			t.Errorf("Platform = %q, want Windows or Android", data.Platform)
+		}
+		if len(data.Brands) == 0 || data.UAFullVersion == "" {
+			t.Errorf("UserAgentData = %+v, want brands and a full version", data)
+		}
+	}
+
+	if _, err := gen.GenerateUserAgentData(HeaderConstraints{Browsers: []Browser{Firefox}}); err == nil {
+		t.Error("GenerateUserAgentData(firefox) error = nil, want an error")
+	}
+}
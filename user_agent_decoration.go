@@ -0,0 +1,71 @@
+package forgeron
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// chromeVersionPattern extracts the Chrome major version from a user agent
+var chromeVersionPattern = regexp.MustCompile(`Chrome/(\d+)`)
+
+// chromeMajorVersion returns the Chrome major version of a user agent, or 0 if absent
+func chromeMajorVersion(userAgent string) int {
+	match := chromeVersionPattern.FindStringSubmatch(userAgent)
+	if match == nil {
+		return 0
+	}
+	version, _ := strconv.Atoi(match[1])
+	return version
+}
+
+// rewriteUserAgent replaces the user agent of a fingerprint everywhere it is exposed
+func rewriteUserAgent(fp *Fingerprint, userAgent string) {
+	fp.Navigator.UserAgent = userAgent
+	fp.Navigator.AppVersion = strings.TrimPrefix(userAgent, "Mozilla/")
+	for k := range fp.Headers {
+		if strings.EqualFold(k, "user-agent") {
+			fp.Headers[k] = userAgent
+		}
+	}
+}
+
+// replaceBrand replaces or removes (when to is empty) a brand from the user agent data and the sec-ch-ua header
+func replaceBrand(fp *Fingerprint, from, to string) {
+	uaData := fp.Navigator.UserAgentData
+	if uaData == nil {
+		return
+	}
+	uaData.Brands = replaceBrandIn(uaData.Brands, from, to)
+	uaData.FullVersionList = replaceBrandIn(uaData.FullVersionList, from, to)
+	for k := range fp.Headers {
+		if strings.EqualFold(k, "sec-ch-ua") {
+			fp.Headers[k] = formatBrands(uaData.Brands)
+		}
+	}
+}
+
+// replaceBrandIn replaces or removes (when to is empty) a brand from a brand list
+func replaceBrandIn(brands []UserAgentBrand, from, to string) []UserAgentBrand {
+	result := make([]UserAgentBrand, 0, len(brands))
+	for _, brand := range brands {
+		if brand.Brand == from {
+			if to == "" {
+				continue
+			}
+			brand.Brand = to
+		}
+		result = append(result, brand)
+	}
+	return result
+}
+
+// formatBrands formats a brand list as a sec-ch-ua header value
+func formatBrands(brands []UserAgentBrand) string {
+	parts := make([]string, len(brands))
+	for i, brand := range brands {
+		parts[i] = fmt.Sprintf(`"%s";v="%s"`, brand.Brand, brand.Version)
+	}
+	return strings.Join(parts, ", ")
+}
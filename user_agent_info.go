@@ -0,0 +1,64 @@
+package forgeron
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// userAgentInfo is the browser, OS, device and major version described by a user agent
+type userAgentInfo struct {
+	Browser Browser
+	OS      OS
+	Device  Device
+	Version int
+}
+
+// browserVersionPatterns extracts the major version of each browser, in detection order
+var browserVersionPatterns = []struct {
+	browser Browser
+	pattern *regexp.Regexp
+}{
+	{Edge, regexp.MustCompile(`Edg(?:A|iOS)?/(\d+)`)},
+	{Firefox, regexp.MustCompile(`(?:Firefox|FxiOS)/(\d+)`)},
+	{Chrome, regexp.MustCompile(`(?:Chrome|CriOS)/(\d+)`)},
+	{Safari, regexp.MustCompile(`Version/(\d+).*Safari/`)},
+}
+
+// parseUserAgent returns the browser, OS, device and major version of a user agent.
+// Unknown values are empty.
+func parseUserAgent(userAgent string) userAgentInfo {
+	var info userAgentInfo
+	for _, p := range browserVersionPatterns {
+		if match := p.pattern.FindStringSubmatch(userAgent); match != nil {
+			info.Browser = p.browser
+			info.Version, _ = strconv.Atoi(match[1])
+			break
+		}
+	}
+
+	switch {
+	case strings.Contains(userAgent, "iPhone"), strings.Contains(userAgent, "iPod"), isTabletUserAgent(userAgent) && !strings.Contains(userAgent, "Android"):
+		info.OS = IOS
+	case strings.Contains(userAgent, "Android"):
+		info.OS = Android
+	case isChromeOSUserAgent(userAgent):
+		info.OS = ChromeOS
+	case strings.Contains(userAgent, "Windows"):
+		info.OS = Windows
+	case strings.Contains(userAgent, "Macintosh"):
+		info.OS = MacOS
+	case strings.Contains(userAgent, "Linux"), strings.Contains(userAgent, "X11"):
+		info.OS = Linux
+	}
+
+	switch {
+	case isTabletUserAgent(userAgent):
+		info.Device = Tablet
+	case info.OS == IOS, info.OS == Android, strings.Contains(userAgent, "Mobile"):
+		info.Device = Mobile
+	case info.OS != "":
+		info.Device = Desktop
+	}
+	return info
+}
@@ -49,25 +49,8 @@ func parseMap(s string) map[string]string {
 	return m
 }
 
-// parseString parses a string and returns a pointer to it
-func parseStringPtr(s string) *string {
-	if s == "" || s == "null" {
-		return nil
-	}
-	return &s
-}
-
 // parseInt parses a string and returns an integer
 func parseInt(s string) int {
 	i, _ := strconv.Atoi(s)
 	return i
 }
-
-// parseIntPtr parses a string and returns a pointer to an integer
-func parseIntPtr(s string) *int {
-	if s == "" || s == "null" {
-		return nil
-	}
-	i, _ := strconv.Atoi(s)
-	return &i
-}
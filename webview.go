@@ -0,0 +1,114 @@
+package forgeron
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// EmbeddedProfile identifies an embedded browser identity, as opposed to a standalone browser
+type EmbeddedProfile string
+
+const (
+	// AndroidWebView emulates an Android System WebView hosted in an app
+	AndroidWebView EmbeddedProfile = "android-webview"
+	// IOSWebView emulates a WKWebView hosted in an iOS app
+	IOSWebView EmbeddedProfile = "ios-webview"
+	// Electron emulates an Electron desktop application
+	Electron EmbeddedProfile = "electron"
+)
+
+// EmbeddedOptions configures an embedded browser identity
+type EmbeddedOptions struct {
+	Profile EmbeddedProfile
+	// AppName and AppVersion add an application token to Electron user agents, e.g. "Slack/4.41.0"
+	AppName    string
+	AppVersion string
+}
+
+// WithEmbedded enables an embedded WebView or Electron identity
+func WithEmbedded(options EmbeddedOptions) FingerprintOption {
+	return func(g *FingerprintGenerator) {
+		g.embedded = &options
+	}
+}
+
+// embeddedHeaderConstraints narrows the header constraints to the browsers able to host the embedded profile
+func embeddedHeaderConstraints(constraints HeaderConstraints, options *EmbeddedOptions) (HeaderConstraints, error) {
+	if options == nil {
+		return constraints, nil
+	}
+	switch options.Profile {
+	case AndroidWebView:
+		constraints.Browsers = []Browser{Chrome}
+		constraints.OS = []OS{Android}
+		constraints.Devices = []Device{Mobile}
+	case IOSWebView:
+		constraints.Browsers = []Browser{Safari}
+		constraints.OS = []OS{IOS}
+	case Electron:
+		constraints.Browsers = []Browser{Chrome}
+		constraints.Devices = []Device{Desktop}
+		constraints.OS = slices.DeleteFunc(slices.Clone(constraints.OS), func(os OS) bool {
+			return !slices.Contains([]OS{Windows, MacOS, Linux}, os)
+		})
+	default:
+		return constraints, fmt.Errorf("embedded profile '%s' is not supported", options.Profile)
+	}
+	constraints.BrowserSpecs = nil
+	return constraints, nil
+}
+
+var (
+	// androidModelPattern matches the platform part of an Android user agent
+	androidModelPattern = regexp.MustCompile(`\(Linux; Android [^)]+`)
+	// safariTokensPattern matches the Version and Safari tokens WKWebView omits
+	safariTokensPattern = regexp.MustCompile(` Version/[\d.]+| Safari/[\d.]+`)
+)
+
+// applyEmbeddedProfile rewrites a standalone browser fingerprint into an embedded one
+func applyEmbeddedProfile(fp *Fingerprint, options *EmbeddedOptions) {
+	if options == nil {
+		return
+	}
+	userAgent := fp.Navigator.UserAgent
+	switch options.Profile {
+	case AndroidWebView:
+		if !strings.Contains(userAgent, "; wv)") {
+			userAgent = androidModelPattern.ReplaceAllStringFunc(userAgent, func(platform string) string {
+				return platform + "; wv"
+			})
+		}
+		if !strings.Contains(userAgent, "Version/4.0") {
+			userAgent = strings.Replace(userAgent, " Chrome/", " Version/4.0 Chrome/", 1)
+		}
+		rewriteUserAgent(fp, userAgent)
+		replaceBrand(fp, "Google Chrome", "Android WebView")
+	case IOSWebView:
+		rewriteUserAgent(fp, safariTokensPattern.ReplaceAllString(userAgent, ""))
+	case Electron:
+		tokens := ""
+		if options.AppName != "" {
+			tokens = " " + options.AppName
+			if options.AppVersion != "" {
+				tokens += "/" + options.AppVersion
+			}
+		}
+		if chrome := chromeMajorVersion(userAgent); chrome > 0 {
+			userAgent = strings.Replace(userAgent, " Chrome/", tokens+" Chrome/", 1)
+			userAgent = strings.Replace(userAgent, " Safari/", fmt.Sprintf(" Electron/%s Safari/", electronVersion(chrome)), 1)
+		}
+		rewriteUserAgent(fp, userAgent)
+		replaceBrand(fp, "Google Chrome", "")
+	}
+}
+
+// electronVersion returns the Electron release shipping the given Chrome major version
+func electronVersion(chrome int) string {
+	// Since Electron 28 (Chrome 120), every Electron major bumps Chrome by two versions
+	if chrome < 120 {
+		return "27.3.11"
+	}
+	return fmt.Sprintf("%d.0.0", 28+(chrome-120)/2)
+}
@@ -0,0 +1,71 @@
+package forgeron
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplyEmbeddedProfile(t *testing.T) {
+	tests := []struct {
+		name      string
+		options   EmbeddedOptions
+		userAgent string
+		want      string
+	}{
+		{
+			name:      "android webview",
+			options:   EmbeddedOptions{Profile: AndroidWebView},
+			userAgent: "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Mobile Safari/537.36",
+			want:      "Mozilla/5.0 (Linux; Android 10; K; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/144.0.0.0 Mobile Safari/537.36",
+		},
+		{
+			name:      "ios webview",
+			options:   EmbeddedOptions{Profile: IOSWebView},
+			userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 18_7 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/26.2 Mobile/15E148 Safari/604.1",
+			want:      "Mozilla/5.0 (iPhone; CPU iPhone OS 18_7 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148",
+		},
+		{
+			name:      "electron",
+			options:   EmbeddedOptions{Profile: Electron, AppName: "Slack", AppVersion: "4.41.0"},
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/144.0.0.0 Safari/537.36",
+			want:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Slack/4.41.0 Chrome/144.0.0.0 Electron/40.0.0 Safari/537.36",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fp := &Fingerprint{
+				Navigator: NavigatorFingerprint{
+					UserAgent: tt.userAgent,
+					UserAgentData: &UserAgentData{Brands: []UserAgentBrand{
+						{Brand: "Not(A:Brand", Version: "8"}, {Brand: "Chromium", Version: "144"}, {Brand: "Google Chrome", Version: "144"},
+					}},
+				},
+				Headers: map[string]string{"User-Agent": tt.userAgent, "sec-ch-ua": "original"},
+			}
+			applyEmbeddedProfile(fp, &tt.options)
+			if fp.Navigator.UserAgent != tt.want {
+				t.Errorf("UserAgent = %q, want %q", fp.Navigator.UserAgent, tt.want)
+			}
+			if fp.Headers["User-Agent"] != tt.want {
+				t.Errorf("User-Agent header = %q, want %q", fp.Headers["User-Agent"], tt.want)
+			}
+			if fp.Navigator.AppVersion != strings.TrimPrefix(tt.want, "Mozilla/") {
+				t.Errorf("AppVersion = %q does not match the user agent", fp.Navigator.AppVersion)
+			}
+			if tt.options.Profile != IOSWebView && strings.Contains(fp.Headers["sec-ch-ua"], "Google Chrome") {
+				t.Errorf("sec-ch-ua still advertises Google Chrome: %s", fp.Headers["sec-ch-ua"])
+			}
+		})
+	}
+}
+
+func TestGenerateAndroidWebView(t *testing.T) {
+	gen := newGeneratorOrFatal(t, WithEmbedded(EmbeddedOptions{Profile: AndroidWebView}))
+	fp, err := gen.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if !strings.Contains(fp.Navigator.UserAgent, "; wv)") {
+		t.Errorf("expected wv token in user agent, got: %s", fp.Navigator.UserAgent)
+	}
+}